@@ -49,28 +49,27 @@ var (
 		Short: "Run rosetta-ethereum",
 		RunE:  runRunCmd,
 	}
+
+	// allowNoTrace starts the server in online mode even if
+	// the node can't trace transactions.
+	allowNoTrace bool
 )
 
+func init() {
+	runCmd.Flags().BoolVar(
+		&allowNoTrace,
+		"allow-no-trace",
+		false,
+		"start even if the node can't trace transactions, which blocks with internal calls need",
+	)
+}
+
 func runRunCmd(cmd *cobra.Command, args []string) error {
 	cfg, err := configuration.LoadConfiguration()
 	if err != nil {
 		return fmt.Errorf("%w: unable to load configuration", err)
 	}
 
-	// The asserter automatically rejects incorrectly formatted
-	// requests.
-	asserter, err := asserter.NewServer(
-		optimism.OperationTypes,
-		optimism.HistoricalBalanceSupported,
-		[]*types.NetworkIdentifier{cfg.Network},
-		optimism.CallMethods,
-		optimism.IncludeMempoolCoins,
-		"",
-	)
-	if err != nil {
-		return fmt.Errorf("%w: could not initialize server asserter", err)
-	}
-
 	// Start required services
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
@@ -87,11 +86,33 @@ func runRunCmd(cmd *cobra.Command, args []string) error {
 		}
 
 		opts := optimism.ClientOptions{
-			HTTPTimeout:         cfg.L2GethHTTPTimeout,
-			MaxTraceConcurrency: cfg.MaxConcurrentTraces,
-			EnableTraceCache:    cfg.EnableTraceCache,
-			EnableGethTracer:    cfg.EnableGethTracer,
-			SupportedTokens:     getSupportedTokens(cfg.Network.Network),
+			HTTPTimeout:           cfg.L2GethHTTPTimeout,
+			MaxTraceConcurrency:   cfg.MaxConcurrentTraces,
+			EnableTraceCache:      cfg.EnableTraceCache,
+			EnableGethTracer:      cfg.EnableGethTracer,
+			EnableNativeTracer:    cfg.EnableNativeTracer,
+			NativeTracerConfig:    cfg.NativeTracerConfig,
+			SupportedTokens:       getSupportedTokens(cfg.Network.Network),
+			LegacyBalanceMetadata: cfg.LegacyBalanceMetadata,
+			LegacyTxMetadata:      cfg.LegacyTxMetadata,
+			DisableGraphQL:        cfg.DisableGraphQL,
+			IndexAllTokens:        cfg.IndexAllTokens,
+			CurrencyCacheSize:     cfg.CurrencyCacheSize,
+			EnableCliqueSealer:    cfg.EnableCliqueSealer,
+			LenientTokenBalances:  cfg.LenientTokenBalances,
+			FeeRecipientOverrides: cfg.FeeRecipientOverrides,
+			FeeVaultHeight:        cfg.FeeVaultHeight,
+			BloomCheck:            cfg.BloomCheck,
+
+			MissingReceiptOverrides: cfg.MissingReceiptOverrides,
+			OmitMissingReceiptFee:   cfg.OmitMissingReceiptFee,
+			PreferBlockReceipts:     cfg.PreferBlockReceipts,
+			SkipAdminCalls:          cfg.SkipGethAdmin,
+			Dialect:                 cfg.NodeDialect,
+			MaxTraceDepth:           cfg.MaxTraceDepth,
+			DisableTracing:          cfg.DisableTracing,
+			SkipDegradedTraces:      cfg.SkipDegradedTraces,
+			CheckTransactionChainID: cfg.CheckTransactionChainID,
 		}
 		var err error
 		client, err = optimism.NewClient(cfg.GethURL, cfg.Params, opts)
@@ -99,11 +120,60 @@ func runRunCmd(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("%w: cannot initialize ethereum client", err)
 		}
 		defer client.Close()
+
+		if len(cfg.ChainConfigJSON) > 0 {
+			if _, err := client.WithChainConfigJSON(cfg.ChainConfigJSON); err != nil {
+				return fmt.Errorf("%w: cannot load chain config", err)
+			}
+		}
+
+		// Construction signs for the chain the node is on.
+		cfg.Params = client.ChainConfig()
+
+		client.WithHTTPHeaders(cfg.HTTPHeaders)
+		client.WithOldestBlockProbe(cfg.OldestBlockProbeCalls)
+		client.WithChainIDCheck(cfg.ChainIDCheckInterval)
+		if _, err := client.WithTipSource(cfg.TipSourceURL, cfg.TipSourceTolerance); err != nil {
+			return fmt.Errorf("%w: cannot initialize tip source", err)
+		}
+
+		if cfg.RateLimit > 0 {
+			log.Printf("limiting node requests to %d per second", cfg.RateLimit)
+			client.WithRateLimit(cfg.RateLimit, cfg.RateLimitBurst)
+		}
+
+		// The circuit breakers wrap the rate limit, so calls to a
+		// failing method fail fast without waiting for a token.
+		if cfg.CircuitBreakerThreshold > 0 {
+			log.Printf("opening circuit breakers after %d consecutive failures", cfg.CircuitBreakerThreshold)
+			client.WithCircuitBreaker(cfg.CircuitBreakerThreshold, cfg.CircuitBreakerCooldown)
+		}
+
+		if err := verifyCapabilities(ctx, client, allowNoTrace || cfg.DisableTracing); err != nil {
+			return err
+		}
+	}
+
+	networks := supportedNetworks(ctx, cfg, client)
+
+	// The asserter automatically rejects incorrectly formatted
+	// requests.
+	asserter, err := asserter.NewServer(
+		optimism.OperationTypes,
+		optimism.HistoricalBalanceSupported,
+		networks,
+		optimism.CallMethods,
+		optimism.IncludeMempoolCoins,
+		"",
+	)
+	if err != nil {
+		return fmt.Errorf("%w: could not initialize server asserter", err)
 	}
 
 	router := services.NewBlockchainRouter(cfg, client, asserter)
+	filteredRouter := services.NetworkFilter(networks, router)
 
-	loggedRouter := server.LoggerMiddleware(router)
+	loggedRouter := server.LoggerMiddleware(filteredRouter)
 	corsRouter := server.CorsMiddleware(loggedRouter)
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", cfg.Port),
@@ -135,6 +205,73 @@ func runRunCmd(cmd *cobra.Command, args []string) error {
 	return err
 }
 
+// verifyCapabilities logs the capabilities of the node and returns an
+// error if it can't trace transactions, unless allowNoTrace is set.
+// Other missing capabilities, and tracing that could not be verified
+// (e.g. because the node is still starting), are only logged.
+func verifyCapabilities(ctx context.Context, client *optimism.Client, allowNoTrace bool) error {
+	report, err := client.VerifyCapabilities(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: unable to verify node capabilities", err)
+	}
+
+	for capability, status := range report {
+		switch {
+		case status.Available:
+			log.Printf("node capability %s is available", capability)
+		case status.Unverified:
+			log.Printf("node capability %s could not be verified: %s", capability, status.Reason)
+		default:
+			log.Printf("node capability %s is unavailable: %s", capability, status.Reason)
+		}
+	}
+
+	trace := report[optimism.CapabilityTraceTransaction]
+	if !trace.Available && !trace.Unverified {
+		if allowNoTrace {
+			log.Println("starting without transaction tracing, as allowed by --allow-no-trace")
+			return nil
+		}
+
+		return fmt.Errorf(
+			"node can't trace transactions (%s), set --allow-no-trace to start anyway",
+			trace.Reason,
+		)
+	}
+
+	return nil
+}
+
+// supportedNetworks returns the networks served: the configured network
+// and, in online mode, the network the node reports being on, which may
+// add a sub-network. If the node can't be reached, only the configured
+// network is served.
+func supportedNetworks(
+	ctx context.Context,
+	cfg *configuration.Configuration,
+	client *optimism.Client,
+) []*types.NetworkIdentifier {
+	networks := []*types.NetworkIdentifier{cfg.Network}
+	if client == nil {
+		return networks
+	}
+
+	discovered, err := client.NetworkList(ctx)
+	if err != nil {
+		log.Printf("%s: serving the configured network only", err.Error())
+		return networks
+	}
+
+	for _, network := range discovered {
+		if types.Hash(network) != types.Hash(cfg.Network) {
+			log.Printf("node is on network %s", types.PrintStruct(network))
+			networks = append(networks, network)
+		}
+	}
+
+	return networks
+}
+
 func getSupportedTokens(network string) map[string]bool {
 	switch network {
 	case optimism.MainnetNetwork:
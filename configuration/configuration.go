@@ -15,11 +15,14 @@
 package configuration
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/coinbase/rosetta-ethereum/optimism"
@@ -93,6 +96,175 @@ const (
 
 	// Experimental: Use newly added built-in geth tracer
 	EnableGethTracer = "ENABLE_GETH_TRACER"
+
+	// EnableNativeTracerEnv is the environment variable read to trace
+	// with the native callTracer of the node instead of call_tracer.js.
+	// It cannot be combined with EnableGethTracer.
+	EnableNativeTracerEnv = "ENABLE_NATIVE_TRACER"
+
+	// NativeTracerConfigEnv is an optional environment variable holding
+	// the JSON tracerConfig of the native callTracer, e.g.
+	// {"onlyTopCall": true}.
+	NativeTracerConfigEnv = "NATIVE_TRACER_CONFIG"
+
+	// ChainConfigEnv is an optional environment variable
+	// pointing to a genesis file whose chain config replaces
+	// the built-in params for the network.
+	ChainConfigEnv = "CHAIN_CONFIG"
+
+	// LegacyBalanceMetadataEnv is the environment variable read to
+	// return the full account code in /account/balance metadata, as
+	// older releases did, instead of the code hash and contract flag.
+	LegacyBalanceMetadataEnv = "LEGACY_BALANCE_METADATA"
+
+	// LegacyTransactionMetadataEnv is the environment variable read
+	// to hex-encode the gas and fee values of transaction metadata,
+	// as older releases did, instead of returning decimal strings.
+	LegacyTransactionMetadataEnv = "LEGACY_TRANSACTION_METADATA"
+
+	// DisableGraphQLEnv is the environment variable read to skip
+	// connecting to the node's GraphQL endpoint. This is needed for
+	// hosted endpoints that only expose JSON-RPC.
+	DisableGraphQLEnv = "DISABLE_GRAPHQL"
+
+	// IndexAllTokensEnv is the environment variable read to emit
+	// operations for every ERC20 transfer, not only those of the
+	// network's supported tokens.
+	IndexAllTokensEnv = "INDEX_ALL_TOKENS"
+
+	// CurrencyCacheSizeEnv is the environment variable read to set
+	// how many ERC20 contracts have their symbol and decimals cached.
+	CurrencyCacheSizeEnv = "CURRENCY_CACHE_SIZE"
+
+	// EnableCliqueSealerEnv is the environment variable read to add
+	// the clique sealer of each block to /block metadata.
+	EnableCliqueSealerEnv = "ENABLE_CLIQUE_SEALER"
+
+	// LenientTokenBalancesEnv is the environment variable read to
+	// return a zero balance for token balanceOf calls that revert
+	// instead of failing the whole /account/balance request.
+	LenientTokenBalancesEnv = "LENIENT_TOKEN_BALANCES"
+
+	// FeeRecipientOverridesEnv is an optional environment variable
+	// pointing to a JSON file of height ranges whose fees are credited
+	// to a fixed recipient, used to correct historical blocks.
+	FeeRecipientOverridesEnv = "FEE_RECIPIENT_OVERRIDES"
+
+	// FeeVaultHeightEnv is the environment variable read to set the
+	// first block whose fees are credited to the sequencer fee vault.
+	FeeVaultHeightEnv = "FEE_VAULT_HEIGHT"
+
+	// BloomCheckEnv is the environment variable read to set how
+	// receipts' logsBloom are verified: warn (default), fail or
+	// disabled.
+	BloomCheckEnv = "BLOOM_CHECK"
+
+	// MissingReceiptOverridesEnv is an optional environment variable
+	// pointing to a JSON file of transactions, by block and tx hash,
+	// whose receipts the node cannot return.
+	MissingReceiptOverridesEnv = "MISSING_RECEIPT_OVERRIDES"
+
+	// OmitMissingReceiptFeeEnv is the environment variable read to skip
+	// fee operations for transactions with missing receipts instead of
+	// charging gasLimit*gasPrice.
+	OmitMissingReceiptFeeEnv = "OMIT_MISSING_RECEIPT_FEE"
+
+	// PreferBlockReceiptsEnv is the environment variable read to fetch
+	// the receipts of a block with a single eth_getBlockReceipts call.
+	PreferBlockReceiptsEnv = "PREFER_BLOCK_RECEIPTS"
+
+	// RateLimitEnv is the environment variable read to cap the
+	// requests per second made to the node. Requests are not
+	// limited if it is unset.
+	RateLimitEnv = "RATE_LIMIT"
+
+	// RateLimitBurstEnv is the environment variable read to set how
+	// many requests may exceed RateLimitEnv in a burst. Defaults to 1.
+	RateLimitBurstEnv = "RATE_LIMIT_BURST"
+
+	// CircuitBreakerThresholdEnv is the environment variable read to
+	// stop calling a node method after this many consecutive failures
+	// of it. Methods are always called if it is unset or 0.
+	CircuitBreakerThresholdEnv = "CIRCUIT_BREAKER_THRESHOLD"
+
+	// CircuitBreakerCooldownEnv is the environment variable read to
+	// set for how many seconds a method is not called once it reached
+	// CircuitBreakerThresholdEnv. Defaults to
+	// optimism.DefaultCircuitBreakerCooldown.
+	CircuitBreakerCooldownEnv = "CIRCUIT_BREAKER_COOLDOWN"
+
+	// HTTPHeadersEnv is an optional environment variable pointing to
+	// a JSON file of HTTP header names and values sent with every
+	// request to the node, e.g. a provider API key.
+	HTTPHeadersEnv = "HTTP_HEADERS"
+
+	// PrefetchBlocksEnv is the environment variable read to set how
+	// many blocks are fetched ahead of a sequential syncer. Blocks
+	// are not prefetched if it is unset or 0.
+	PrefetchBlocksEnv = "PREFETCH_BLOCKS"
+
+	// MaxSyncLagEnv is the environment variable read to set how many
+	// blocks the node may be behind its tip before requests for the
+	// current state are refused. 0 disables the check.
+	MaxSyncLagEnv = "MAX_SYNC_LAG"
+
+	// SkipGethAdminEnv is the environment variable read to stop
+	// listing peers with admin_peers, for nodes that don't expose
+	// the admin API.
+	SkipGethAdminEnv = "SKIP_GETH_ADMIN"
+
+	// NodeDialectEnv is the environment variable read to set the RPC
+	// dialect of the node: legacy (l2geth), bedrock (op-geth) or
+	// auto (default), which detects it.
+	NodeDialectEnv = "NODE_DIALECT"
+
+	// OldestBlockProbeCallsEnv is the environment variable read to
+	// report the oldest block a pruning node serves in /network/status,
+	// found with at most this many requests per probe. The oldest block
+	// is not probed if it is unset or 0.
+	OldestBlockProbeCallsEnv = "OLDEST_BLOCK_PROBE_CALLS"
+
+	// ChainIDCheckIntervalEnv is the environment variable read to
+	// compare the chain id of the node with the first one observed at
+	// most once per this many seconds, failing requests if it changed.
+	// The chain id is not checked if it is unset or 0.
+	ChainIDCheckIntervalEnv = "CHAIN_ID_CHECK_INTERVAL"
+
+	// MaxTraceDepthEnv is the environment variable read to drop the
+	// operations of internal calls nested deeper than this, except
+	// those moving value. Calls are not limited if it is unset or 0.
+	MaxTraceDepthEnv = "MAX_TRACE_DEPTH"
+
+	// TipSourceURLEnv is the environment variable read to report the
+	// head of another node, e.g. the sequencer of a verifier, as the
+	// target index in /network/status.
+	TipSourceURLEnv = "TIP_SOURCE_URL"
+
+	// TipSourceToleranceEnv is the environment variable read to set
+	// how many blocks the node may be behind the tip source while
+	// reported as synced. optimism.DefaultTipSourceTolerance is used
+	// if it is unset or 0.
+	TipSourceToleranceEnv = "TIP_SOURCE_TOLERANCE"
+
+	// DisableTracingEnv is the environment variable read to stop
+	// tracing transactions. Blocks only include the operations known
+	// from transactions and receipts, without internal calls or
+	// internal transfers.
+	DisableTracingEnv = "DISABLE_TRACING"
+
+	// SkipDegradedTracesEnv is the environment variable read to convert
+	// transactions whose trace timed out or was truncated without it,
+	// instead of failing the block.
+	SkipDegradedTracesEnv = "SKIP_DEGRADED_TRACES"
+
+	// CheckTransactionChainIDEnv is the environment variable read to
+	// reject submitted transactions signed for a chain id other than
+	// the net_version of the node before broadcasting them.
+	CheckTransactionChainIDEnv = "CHECK_TRANSACTION_CHAIN_ID"
+
+	// DefaultMaxSyncLag is the number of blocks the node may be
+	// behind its tip when MaxSyncLagEnv is not populated.
+	DefaultMaxSyncLag = 1000
 )
 
 // Configuration determines how
@@ -108,9 +280,46 @@ type Configuration struct {
 	MaxConcurrentTraces    int64
 	EnableTraceCache       bool
 	EnableGethTracer       bool
+	EnableNativeTracer     bool
+	NativeTracerConfig     optimism.CallTracerConfig
+	LegacyBalanceMetadata  bool
+	LegacyTxMetadata       bool
+	DisableGraphQL         bool
+	IndexAllTokens         bool
+	CurrencyCacheSize      int
+	EnableCliqueSealer     bool
+	LenientTokenBalances   bool
+	FeeRecipientOverrides  []optimism.FeeRecipientOverride
+	FeeVaultHeight         uint64
+	BloomCheck             optimism.BloomCheck
+
+	MissingReceiptOverrides []optimism.MissingReceiptOverride
+	OmitMissingReceiptFee   bool
+	PreferBlockReceipts     bool
+	RateLimit               int
+	RateLimitBurst          int
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+	HTTPHeaders             map[string]string
+	PrefetchBlocks          int
+	MaxSyncLag              int64
+	SkipGethAdmin           bool
+	NodeDialect             optimism.Dialect
+	OldestBlockProbeCalls   int
+	ChainIDCheckInterval    time.Duration
+	MaxTraceDepth           int
+	TipSourceURL            string
+	TipSourceTolerance      int64
+	DisableTracing          bool
+	SkipDegradedTraces      bool
+	CheckTransactionChainID bool
 
 	// Block Reward Data
 	Params *params.ChainConfig
+
+	// ChainConfigJSON is the raw genesis-style chain config
+	// loaded from ChainConfigEnv, if populated.
+	ChainConfigJSON []byte
 }
 
 // LoadConfiguration attempts to create a new Configuration
@@ -189,6 +398,15 @@ func LoadConfiguration() (*Configuration, error) {
 		config.MaxConcurrentTraces = int64(val)
 	}
 
+	envCurrencyCacheSize := os.Getenv(CurrencyCacheSizeEnv)
+	if len(envCurrencyCacheSize) > 0 {
+		val, err := strconv.Atoi(envCurrencyCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s envar %s", err, CurrencyCacheSizeEnv, envCurrencyCacheSize)
+		}
+		config.CurrencyCacheSize = val
+	}
+
 	portValue := os.Getenv(PortEnv)
 	if len(portValue) == 0 {
 		return nil, errors.New("PORT must be populated")
@@ -218,5 +436,343 @@ func LoadConfiguration() (*Configuration, error) {
 		config.EnableGethTracer = val
 	}
 
+	envEnableNativeTracer := os.Getenv(EnableNativeTracerEnv)
+	if len(envEnableNativeTracer) > 0 {
+		val, err := strconv.ParseBool(envEnableNativeTracer)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, EnableNativeTracerEnv, envEnableNativeTracer)
+		}
+		config.EnableNativeTracer = val
+	}
+	if config.EnableGethTracer && config.EnableNativeTracer {
+		return nil, fmt.Errorf("%s and %s cannot both be enabled", EnableGethTracer, EnableNativeTracerEnv)
+	}
+
+	envNativeTracerConfig := os.Getenv(NativeTracerConfigEnv)
+	if len(envNativeTracerConfig) > 0 {
+		decoder := json.NewDecoder(strings.NewReader(envNativeTracerConfig))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config.NativeTracerConfig); err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, NativeTracerConfigEnv, envNativeTracerConfig)
+		}
+	}
+
+	envLegacyBalanceMetadata := os.Getenv(LegacyBalanceMetadataEnv)
+	if len(envLegacyBalanceMetadata) > 0 {
+		val, err := strconv.ParseBool(envLegacyBalanceMetadata)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, LegacyBalanceMetadataEnv, envLegacyBalanceMetadata)
+		}
+		config.LegacyBalanceMetadata = val
+	}
+
+	envLegacyTxMetadata := os.Getenv(LegacyTransactionMetadataEnv)
+	if len(envLegacyTxMetadata) > 0 {
+		val, err := strconv.ParseBool(envLegacyTxMetadata)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, LegacyTransactionMetadataEnv, envLegacyTxMetadata)
+		}
+		config.LegacyTxMetadata = val
+	}
+
+	envDisableGraphQL := os.Getenv(DisableGraphQLEnv)
+	if len(envDisableGraphQL) > 0 {
+		val, err := strconv.ParseBool(envDisableGraphQL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, DisableGraphQLEnv, envDisableGraphQL)
+		}
+		config.DisableGraphQL = val
+	}
+
+	envIndexAllTokens := os.Getenv(IndexAllTokensEnv)
+	if len(envIndexAllTokens) > 0 {
+		val, err := strconv.ParseBool(envIndexAllTokens)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, IndexAllTokensEnv, envIndexAllTokens)
+		}
+		config.IndexAllTokens = val
+	}
+
+	envEnableCliqueSealer := os.Getenv(EnableCliqueSealerEnv)
+	if len(envEnableCliqueSealer) > 0 {
+		val, err := strconv.ParseBool(envEnableCliqueSealer)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, EnableCliqueSealerEnv, envEnableCliqueSealer)
+		}
+		config.EnableCliqueSealer = val
+	}
+
+	envLenientTokenBalances := os.Getenv(LenientTokenBalancesEnv)
+	if len(envLenientTokenBalances) > 0 {
+		val, err := strconv.ParseBool(envLenientTokenBalances)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, LenientTokenBalancesEnv, envLenientTokenBalances)
+		}
+		config.LenientTokenBalances = val
+	}
+
+	envFeeVaultHeight := os.Getenv(FeeVaultHeightEnv)
+	if len(envFeeVaultHeight) > 0 {
+		val, err := strconv.ParseUint(envFeeVaultHeight, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, FeeVaultHeightEnv, envFeeVaultHeight)
+		}
+		config.FeeVaultHeight = val
+	}
+
+	envFeeRecipientOverrides := os.Getenv(FeeRecipientOverridesEnv)
+	if len(envFeeRecipientOverrides) > 0 {
+		data, err := ioutil.ReadFile(envFeeRecipientOverrides) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read %s %s", err, FeeRecipientOverridesEnv, envFeeRecipientOverrides)
+		}
+		var overrides []optimism.FeeRecipientOverride
+		if err := json.Unmarshal(data, &overrides); err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, FeeRecipientOverridesEnv, envFeeRecipientOverrides)
+		}
+		config.FeeRecipientOverrides, err = optimism.CheckFeeRecipientOverrides(overrides)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid %s %s", err, FeeRecipientOverridesEnv, envFeeRecipientOverrides)
+		}
+	}
+
+	envMissingReceiptOverrides := os.Getenv(MissingReceiptOverridesEnv)
+	if len(envMissingReceiptOverrides) > 0 {
+		data, err := ioutil.ReadFile(envMissingReceiptOverrides) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read %s %s", err, MissingReceiptOverridesEnv, envMissingReceiptOverrides)
+		}
+		if err := json.Unmarshal(data, &config.MissingReceiptOverrides); err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, MissingReceiptOverridesEnv, envMissingReceiptOverrides)
+		}
+	}
+
+	envOmitMissingReceiptFee := os.Getenv(OmitMissingReceiptFeeEnv)
+	if len(envOmitMissingReceiptFee) > 0 {
+		val, err := strconv.ParseBool(envOmitMissingReceiptFee)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, OmitMissingReceiptFeeEnv, envOmitMissingReceiptFee)
+		}
+		config.OmitMissingReceiptFee = val
+	}
+
+	envPreferBlockReceipts := os.Getenv(PreferBlockReceiptsEnv)
+	if len(envPreferBlockReceipts) > 0 {
+		val, err := strconv.ParseBool(envPreferBlockReceipts)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, PreferBlockReceiptsEnv, envPreferBlockReceipts)
+		}
+		config.PreferBlockReceipts = val
+	}
+
+	envRateLimit := os.Getenv(RateLimitEnv)
+	if len(envRateLimit) > 0 {
+		val, err := strconv.Atoi(envRateLimit)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, RateLimitEnv, envRateLimit)
+		}
+		if val <= 0 {
+			return nil, fmt.Errorf("%s must be positive, got %s", RateLimitEnv, envRateLimit)
+		}
+		config.RateLimit = val
+	}
+
+	envRateLimitBurst := os.Getenv(RateLimitBurstEnv)
+	if len(envRateLimitBurst) > 0 {
+		val, err := strconv.Atoi(envRateLimitBurst)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, RateLimitBurstEnv, envRateLimitBurst)
+		}
+		if val <= 0 {
+			return nil, fmt.Errorf("%s must be positive, got %s", RateLimitBurstEnv, envRateLimitBurst)
+		}
+		config.RateLimitBurst = val
+	}
+
+	envCircuitBreakerThreshold := os.Getenv(CircuitBreakerThresholdEnv)
+	if len(envCircuitBreakerThreshold) > 0 {
+		val, err := strconv.Atoi(envCircuitBreakerThreshold)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to parse %s %s",
+				err,
+				CircuitBreakerThresholdEnv,
+				envCircuitBreakerThreshold,
+			)
+		}
+		config.CircuitBreakerThreshold = val
+	}
+
+	envCircuitBreakerCooldown := os.Getenv(CircuitBreakerCooldownEnv)
+	if len(envCircuitBreakerCooldown) > 0 {
+		val, err := strconv.Atoi(envCircuitBreakerCooldown)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to parse %s %s",
+				err,
+				CircuitBreakerCooldownEnv,
+				envCircuitBreakerCooldown,
+			)
+		}
+		config.CircuitBreakerCooldown = time.Second * time.Duration(val)
+	}
+
+	envPrefetchBlocks := os.Getenv(PrefetchBlocksEnv)
+	if len(envPrefetchBlocks) > 0 {
+		val, err := strconv.Atoi(envPrefetchBlocks)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, PrefetchBlocksEnv, envPrefetchBlocks)
+		}
+		if val < 0 {
+			return nil, fmt.Errorf("%s must be non-negative, got %s", PrefetchBlocksEnv, envPrefetchBlocks)
+		}
+		config.PrefetchBlocks = val
+	}
+
+	envOldestBlockProbeCalls := os.Getenv(OldestBlockProbeCallsEnv)
+	if len(envOldestBlockProbeCalls) > 0 {
+		val, err := strconv.Atoi(envOldestBlockProbeCalls)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, OldestBlockProbeCallsEnv, envOldestBlockProbeCalls)
+		}
+		if val < 0 {
+			return nil, fmt.Errorf("%s must be non-negative, got %s", OldestBlockProbeCallsEnv, envOldestBlockProbeCalls)
+		}
+		config.OldestBlockProbeCalls = val
+	}
+
+	envChainIDCheckInterval := os.Getenv(ChainIDCheckIntervalEnv)
+	if len(envChainIDCheckInterval) > 0 {
+		val, err := strconv.Atoi(envChainIDCheckInterval)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, ChainIDCheckIntervalEnv, envChainIDCheckInterval)
+		}
+		if val < 0 {
+			return nil, fmt.Errorf("%s must be non-negative, got %s", ChainIDCheckIntervalEnv, envChainIDCheckInterval)
+		}
+		config.ChainIDCheckInterval = time.Second * time.Duration(val)
+	}
+
+	envMaxTraceDepth := os.Getenv(MaxTraceDepthEnv)
+	if len(envMaxTraceDepth) > 0 {
+		val, err := strconv.Atoi(envMaxTraceDepth)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, MaxTraceDepthEnv, envMaxTraceDepth)
+		}
+		if val < 0 {
+			return nil, fmt.Errorf("%s must be non-negative, got %s", MaxTraceDepthEnv, envMaxTraceDepth)
+		}
+		config.MaxTraceDepth = val
+	}
+
+	config.TipSourceURL = os.Getenv(TipSourceURLEnv)
+	envTipSourceTolerance := os.Getenv(TipSourceToleranceEnv)
+	if len(envTipSourceTolerance) > 0 {
+		val, err := strconv.ParseInt(envTipSourceTolerance, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, TipSourceToleranceEnv, envTipSourceTolerance)
+		}
+		if val < 0 {
+			return nil, fmt.Errorf("%s must be non-negative, got %s", TipSourceToleranceEnv, envTipSourceTolerance)
+		}
+		config.TipSourceTolerance = val
+	}
+
+	config.MaxSyncLag = DefaultMaxSyncLag
+	envMaxSyncLag := os.Getenv(MaxSyncLagEnv)
+	if len(envMaxSyncLag) > 0 {
+		val, err := strconv.ParseInt(envMaxSyncLag, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, MaxSyncLagEnv, envMaxSyncLag)
+		}
+		if val < 0 {
+			return nil, fmt.Errorf("%s must be non-negative, got %s", MaxSyncLagEnv, envMaxSyncLag)
+		}
+		config.MaxSyncLag = val
+	}
+
+	envSkipGethAdmin := os.Getenv(SkipGethAdminEnv)
+	if len(envSkipGethAdmin) > 0 {
+		val, err := strconv.ParseBool(envSkipGethAdmin)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, SkipGethAdminEnv, envSkipGethAdmin)
+		}
+		config.SkipGethAdmin = val
+	}
+
+	envDisableTracing := os.Getenv(DisableTracingEnv)
+	if len(envDisableTracing) > 0 {
+		val, err := strconv.ParseBool(envDisableTracing)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, DisableTracingEnv, envDisableTracing)
+		}
+		config.DisableTracing = val
+	}
+
+	envSkipDegradedTraces := os.Getenv(SkipDegradedTracesEnv)
+	if len(envSkipDegradedTraces) > 0 {
+		val, err := strconv.ParseBool(envSkipDegradedTraces)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, SkipDegradedTracesEnv, envSkipDegradedTraces)
+		}
+		config.SkipDegradedTraces = val
+	}
+
+	envCheckTransactionChainID := os.Getenv(CheckTransactionChainIDEnv)
+	if len(envCheckTransactionChainID) > 0 {
+		val, err := strconv.ParseBool(envCheckTransactionChainID)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to parse %s %s",
+				err,
+				CheckTransactionChainIDEnv,
+				envCheckTransactionChainID,
+			)
+		}
+		config.CheckTransactionChainID = val
+	}
+
+	// The file holds secrets, so errors only include its path.
+	envHTTPHeaders := os.Getenv(HTTPHeadersEnv)
+	if len(envHTTPHeaders) > 0 {
+		data, err := ioutil.ReadFile(envHTTPHeaders) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read %s %s", err, HTTPHeadersEnv, envHTTPHeaders)
+		}
+		if err := json.Unmarshal(data, &config.HTTPHeaders); err != nil {
+			return nil, fmt.Errorf("unable to parse %s %s: expected a JSON object of strings", HTTPHeadersEnv, envHTTPHeaders)
+		}
+	}
+
+	envBloomCheck := os.Getenv(BloomCheckEnv)
+	switch optimism.BloomCheck(envBloomCheck) {
+	case "", optimism.BloomCheckWarn, optimism.BloomCheckFail, optimism.BloomCheckDisabled:
+		config.BloomCheck = optimism.BloomCheck(envBloomCheck)
+	default:
+		return nil, fmt.Errorf("%s is not a valid bloom check", envBloomCheck)
+	}
+
+	envNodeDialect := os.Getenv(NodeDialectEnv)
+	if _, err := optimism.ParseDialect(envNodeDialect); err != nil {
+		return nil, err
+	}
+	config.NodeDialect = optimism.Dialect(envNodeDialect)
+
+	envChainConfig := os.Getenv(ChainConfigEnv)
+	if len(envChainConfig) > 0 {
+		data, err := ioutil.ReadFile(envChainConfig) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to read %s %s", err, ChainConfigEnv, envChainConfig)
+		}
+
+		// Construction signs for the custom chain, including offline.
+		chainConfig, err := optimism.ParseChainConfigJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse %s %s", err, ChainConfigEnv, envChainConfig)
+		}
+		config.Params = chainConfig
+		config.ChainConfigJSON = data
+	}
+
 	return config, nil
 }
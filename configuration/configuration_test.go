@@ -34,6 +34,12 @@ func TestLoadConfiguration(t *testing.T) {
 		Port              string
 		Geth              string
 		L2GethHTTPTimeout string
+		MaxSyncLag        string
+		NodeDialect       string
+
+		EnableGethTracer   string
+		EnableNativeTracer string
+		NativeTracerConfig string
 
 		cfg *Configuration
 		err error
@@ -67,6 +73,7 @@ func TestLoadConfiguration(t *testing.T) {
 				GethURL:                DefaultGethURL,
 				GethArguments:          optimism.MainnetGethArguments,
 				L2GethHTTPTimeout:      time.Second * 100,
+				MaxSyncLag:             DefaultMaxSyncLag,
 			},
 		},
 		"all set (mainnet) + geth": {
@@ -88,6 +95,7 @@ func TestLoadConfiguration(t *testing.T) {
 				RemoteGeth:             true,
 				GethArguments:          optimism.MainnetGethArguments,
 				L2GethHTTPTimeout:      time.Second * 100,
+				MaxSyncLag:             DefaultMaxSyncLag,
 			},
 		},
 		"all set (goerli)": {
@@ -105,6 +113,7 @@ func TestLoadConfiguration(t *testing.T) {
 				Port:                   1000,
 				GethURL:                DefaultGethURL,
 				GethArguments:          optimism.GoerliGethArguments,
+				MaxSyncLag:             DefaultMaxSyncLag,
 			},
 		},
 		"all set (testnet)": {
@@ -122,8 +131,85 @@ func TestLoadConfiguration(t *testing.T) {
 				Port:                   1000,
 				GethURL:                DefaultGethURL,
 				GethArguments:          optimism.TestnetGethArguments,
+				MaxSyncLag:             DefaultMaxSyncLag,
+			},
+		},
+		"max sync lag": {
+			Mode:       string(Offline),
+			Network:    Goerli,
+			Port:       "1000",
+			MaxSyncLag: "0",
+			cfg: &Configuration{
+				Mode: Offline,
+				Network: &types.NetworkIdentifier{
+					Network:    optimism.GoerliNetwork,
+					Blockchain: optimism.Blockchain,
+				},
+				Params:                 params.GoerliChainConfig,
+				GenesisBlockIdentifier: optimism.GoerliGenesisBlockIdentifier,
+				Port:                   1000,
+				GethURL:                DefaultGethURL,
+				GethArguments:          optimism.GoerliGethArguments,
+			},
+		},
+		"node dialect": {
+			Mode:        string(Offline),
+			Network:     Goerli,
+			Port:        "1000",
+			NodeDialect: string(optimism.BedrockDialect),
+			cfg: &Configuration{
+				Mode: Offline,
+				Network: &types.NetworkIdentifier{
+					Network:    optimism.GoerliNetwork,
+					Blockchain: optimism.Blockchain,
+				},
+				Params:                 params.GoerliChainConfig,
+				GenesisBlockIdentifier: optimism.GoerliGenesisBlockIdentifier,
+				Port:                   1000,
+				GethURL:                DefaultGethURL,
+				GethArguments:          optimism.GoerliGethArguments,
+				MaxSyncLag:             DefaultMaxSyncLag,
+				NodeDialect:            optimism.BedrockDialect,
 			},
 		},
+		"native tracer": {
+			Mode:               string(Offline),
+			Network:            Goerli,
+			Port:               "1000",
+			EnableNativeTracer: "true",
+			NativeTracerConfig: `{"onlyTopCall": true}`,
+			cfg: &Configuration{
+				Mode: Offline,
+				Network: &types.NetworkIdentifier{
+					Network:    optimism.GoerliNetwork,
+					Blockchain: optimism.Blockchain,
+				},
+				Params:                 params.GoerliChainConfig,
+				GenesisBlockIdentifier: optimism.GoerliGenesisBlockIdentifier,
+				Port:                   1000,
+				GethURL:                DefaultGethURL,
+				GethArguments:          optimism.GoerliGethArguments,
+				MaxSyncLag:             DefaultMaxSyncLag,
+				EnableNativeTracer:     true,
+				NativeTracerConfig:     optimism.CallTracerConfig{OnlyTopCall: true},
+			},
+		},
+		"geth and native tracers": {
+			Mode:               string(Offline),
+			Network:            Goerli,
+			Port:               "1000",
+			EnableGethTracer:   "true",
+			EnableNativeTracer: "true",
+			err:                errors.New("ENABLE_GETH_TRACER and ENABLE_NATIVE_TRACER cannot both be enabled"),
+		},
+		"invalid native tracer config": {
+			Mode:               string(Offline),
+			Network:            Goerli,
+			Port:               "1000",
+			EnableNativeTracer: "true",
+			NativeTracerConfig: `{"diffMode": true}`,
+			err:                errors.New("unable to parse NATIVE_TRACER_CONFIG"),
+		},
 		"invalid mode": {
 			Mode:    "bad mode",
 			Network: Goerli,
@@ -148,6 +234,27 @@ func TestLoadConfiguration(t *testing.T) {
 			L2GethHTTPTimeout: "bad val",
 			err:               errors.New("unable to parse L2_GETH_HTTP_TIMEOUT"),
 		},
+		"invalid max sync lag": {
+			Mode:       string(Offline),
+			Network:    Goerli,
+			Port:       "1000",
+			MaxSyncLag: "-1",
+			err:        errors.New("MAX_SYNC_LAG must be non-negative, got -1"),
+		},
+		"unparseable max sync lag": {
+			Mode:       string(Offline),
+			Network:    Goerli,
+			Port:       "1000",
+			MaxSyncLag: "bad val",
+			err:        errors.New("unable to parse MAX_SYNC_LAG bad val"),
+		},
+		"invalid node dialect": {
+			Mode:        string(Offline),
+			Network:     Goerli,
+			Port:        "1000",
+			NodeDialect: "bad dialect",
+			err:         errors.New("bad dialect is not a valid dialect"),
+		},
 	}
 
 	for name, test := range tests {
@@ -157,6 +264,11 @@ func TestLoadConfiguration(t *testing.T) {
 			os.Setenv(PortEnv, test.Port)
 			os.Setenv(GethEnv, test.Geth)
 			os.Setenv(L2GethHTTPTimeoutEnv, test.L2GethHTTPTimeout)
+			os.Setenv(MaxSyncLagEnv, test.MaxSyncLag)
+			os.Setenv(NodeDialectEnv, test.NodeDialect)
+			os.Setenv(EnableGethTracer, test.EnableGethTracer)
+			os.Setenv(EnableNativeTracerEnv, test.EnableNativeTracer)
+			os.Setenv(NativeTracerConfigEnv, test.NativeTracerConfig)
 
 			cfg, err := LoadConfiguration()
 			if test.err != nil {
@@ -13,20 +13,20 @@ type GraphQL struct {
 	mock.Mock
 }
 
-// Query provides a mock function with given fields: ctx, input
-func (_m *GraphQL) Query(ctx context.Context, input string) (string, error) {
-	ret := _m.Called(ctx, input)
+// Query provides a mock function with given fields: ctx, input, variables
+func (_m *GraphQL) Query(ctx context.Context, input string, variables map[string]interface{}) (string, error) {
+	ret := _m.Called(ctx, input, variables)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, input)
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]interface{}) string); ok {
+		r0 = rf(ctx, input, variables)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, input)
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]interface{}) error); ok {
+		r1 = rf(ctx, input, variables)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -14,6 +14,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	optimism "github.com/coinbase/rosetta-ethereum/optimism"
+
 	types "github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -91,6 +93,29 @@ func (_m *Client) Call(ctx context.Context, request *types.CallRequest) (*types.
 	return r0, r1
 }
 
+// ClientVersion provides a mock function with given fields: _a0
+func (_m *Client) ClientVersion(_a0 context.Context) (*optimism.ClientVersionInfo, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *optimism.ClientVersionInfo
+	if rf, ok := ret.Get(0).(func(context.Context) *optimism.ClientVersionInfo); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*optimism.ClientVersionInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EstimateGas provides a mock function with given fields: ctx, msg
 func (_m *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
 	ret := _m.Called(ctx, msg)
@@ -112,6 +137,52 @@ func (_m *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64
 	return r0, r1
 }
 
+// NetworkList provides a mock function with given fields: _a0
+func (_m *Client) NetworkList(_a0 context.Context) ([]*types.NetworkIdentifier, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []*types.NetworkIdentifier
+	if rf, ok := ret.Get(0).(func(context.Context) []*types.NetworkIdentifier); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.NetworkIdentifier)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NetworkStatus provides a mock function with given fields: _a0
+func (_m *Client) NetworkStatus(_a0 context.Context) (*types.NetworkStatusResponse, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *types.NetworkStatusResponse
+	if rf, ok := ret.Get(0).(func(context.Context) *types.NetworkStatusResponse); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.NetworkStatusResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PendingNonceAt provides a mock function with given fields: _a0, _a1
 func (_m *Client) PendingNonceAt(_a0 context.Context, _a1 common.Address) (uint64, error) {
 	ret := _m.Called(_a0, _a1)
@@ -133,18 +204,27 @@ func (_m *Client) PendingNonceAt(_a0 context.Context, _a1 common.Address) (uint6
 	return r0, r1
 }
 
-// SendTransaction provides a mock function with given fields: ctx, tx
-func (_m *Client) SendTransaction(ctx context.Context, tx *coretypes.Transaction) error {
+// SendTransactionWithHash provides a mock function with given fields: ctx, tx
+func (_m *Client) SendTransactionWithHash(ctx context.Context, tx *coretypes.Transaction) (common.Hash, error) {
 	ret := _m.Called(ctx, tx)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction) error); ok {
+	var r0 common.Hash
+	if rf, ok := ret.Get(0).(func(context.Context, *coretypes.Transaction) common.Hash); ok {
 		r0 = rf(ctx, tx)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *coretypes.Transaction) error); ok {
+		r1 = rf(ctx, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // Status provides a mock function with given fields: _a0
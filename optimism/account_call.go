@@ -0,0 +1,71 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// AccountCallInput is the input to the eth_getBalance and eth_getCode
+// call methods. Without an index, the account is read at the current
+// block. Index may be one of the negative block indexes, such as
+// FinalizedBlockIndex, to read it at a block tag.
+type AccountCallInput struct {
+	Address string `json:"address"`
+	Index   *int64 `json:"index,omitempty"`
+}
+
+// accountCall calls method, eth_getBalance or eth_getCode, for the
+// account of params and returns its hex result under "data". The
+// returned bool is true if the account was read at a specific block,
+// so the result never changes.
+func (ec *Client) accountCall(
+	ctx context.Context,
+	method string,
+	params map[string]interface{},
+) (map[string]interface{}, bool, error) {
+	var input AccountCallInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	address, err := ValidateAddress("address", input.Address)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	blockArg := toBlockNumArg(nil)
+	pinned := false
+	if input.Index != nil {
+		if *input.Index < SafeBlockIndex {
+			return nil, false, fmt.Errorf("%w: invalid block index %d", ErrCallParametersInvalid, *input.Index)
+		}
+		blockArg = toBlockNumArg(big.NewInt(*input.Index))
+		pinned = *input.Index >= 0
+	}
+
+	var resp string
+	if err := ec.c.CallContext(ctx, &resp, method, address.Hex(), blockArg); err != nil {
+		return nil, false, historicalStateError(err)
+	}
+
+	return map[string]interface{}{
+		"data": resp,
+	}, pinned, nil
+}
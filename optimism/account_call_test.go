@@ -0,0 +1,151 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCall_AccountCall(t *testing.T) {
+	address := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+
+	tests := map[string]struct {
+		method string
+		params map[string]interface{}
+		result string
+
+		expectedBlock      string
+		expectedIdempotent bool
+	}{
+		"balance latest": {
+			method: "eth_getBalance",
+			params: map[string]interface{}{
+				"address": address,
+			},
+			result:        "0xde0b6b3a7640000",
+			expectedBlock: "latest",
+		},
+		"balance historical": {
+			method: "eth_getBalance",
+			params: map[string]interface{}{
+				"address": address,
+				"index":   1241187,
+			},
+			result:             "0xde0b6b3a7640000",
+			expectedBlock:      "0x12f063",
+			expectedIdempotent: true,
+		},
+		"code latest": {
+			method: "eth_getCode",
+			params: map[string]interface{}{
+				"address": address,
+			},
+			result:        "0x6080604052",
+			expectedBlock: "latest",
+		},
+		"code historical": {
+			method: "eth_getCode",
+			params: map[string]interface{}{
+				"address": address,
+				"index":   0,
+			},
+			result:             "0x",
+			expectedBlock:      "0x0",
+			expectedIdempotent: true,
+		},
+		"code finalized": {
+			method: "eth_getCode",
+			params: map[string]interface{}{
+				"address": address,
+				"index":   FinalizedBlockIndex,
+			},
+			result:        "0x6080604052",
+			expectedBlock: FinalizedBlockTag,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+			ctx := context.Background()
+
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				test.method,
+				address,
+				test.expectedBlock,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					*args.Get(1).(*string) = test.result
+				},
+			).Once()
+
+			resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+				Method:     test.method,
+				Parameters: test.params,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, &RosettaTypes.CallResponse{
+				Result: map[string]interface{}{
+					"data": test.result,
+				},
+				Idempotent: test.expectedIdempotent,
+			}, resp)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_AccountCall_InvalidInput(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"invalid address": {
+			"address": "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D5",
+		},
+		"missing address": {},
+		"invalid index": {
+			"address": "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			"index":   SafeBlockIndex - 1,
+		},
+	}
+
+	for name, params := range tests {
+		for _, method := range []string{"eth_getBalance", "eth_getCode"} {
+			t.Run(name+" "+method, func(t *testing.T) {
+				c := &Client{}
+
+				resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+					Method:     method,
+					Parameters: params,
+				})
+				assert.Nil(t, resp)
+				assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+			})
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	ethereum "github.com/ethereum-optimism/optimism/l2geth"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// AccountInfo is the state of an account at a block.
+type AccountInfo struct {
+	// BlockIdentifier is the block the state was read at. It is nil
+	// for state read at the pending block.
+	BlockIdentifier *RosettaTypes.BlockIdentifier
+
+	Balance *big.Int
+	Nonce   uint64
+	Code    []byte
+}
+
+// AccountInfo returns the balance, nonce and code of an account at a
+// *RosettaTypes.PartialBlockIdentifier. If neither the hash or index
+// is populated, the current block is used.
+func (ec *Client) AccountInfo(
+	ctx context.Context,
+	address common.Address,
+	block *RosettaTypes.PartialBlockIdentifier,
+) (*AccountInfo, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	head, err := ec.accountBlockHeader(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	blockNum := hexutil.EncodeUint64(head.Number.Uint64())
+	reqs, accountInfo := accountInfoRequests(address.Hex(), blockNum)
+	reqs, canonical := canonicalHeaderRequest(reqs, block, blockNum)
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, historicalStateError(err)
+	}
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			return nil, historicalStateError(reqs[i].Error)
+		}
+	}
+	if err := checkCanonicalHeader(block, head, *canonical); err != nil {
+		return nil, err
+	}
+
+	info, err := accountInfo()
+	if err != nil {
+		return nil, err
+	}
+	info.BlockIdentifier = &RosettaTypes.BlockIdentifier{
+		Hash:  head.Hash().Hex(),
+		Index: head.Number.Int64(),
+	}
+
+	return info, nil
+}
+
+// accountBlockHeader resolves the header account state is read at.
+func (ec *Client) accountBlockHeader(
+	ctx context.Context,
+	block *RosettaTypes.PartialBlockIdentifier,
+) (*types.Header, error) {
+	var raw json.RawMessage
+	if tag, ok := blockTag(block); ok {
+		if err := ec.c.CallContext(ctx, &raw, "eth_getBlockByNumber", tag, false); err != nil {
+			return nil, err
+		}
+	} else if block != nil {
+		if block.Hash != nil {
+			if err := ec.c.CallContext(ctx, &raw, "eth_getBlockByHash", block.Hash, false); err != nil {
+				return nil, err
+			}
+		}
+		if block.Hash == nil && block.Index != nil {
+			if err := ec.c.CallContext(
+				ctx,
+				&raw,
+				"eth_getBlockByNumber",
+				hexutil.EncodeUint64(uint64(*block.Index)),
+				false,
+			); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		if err := ec.c.CallContext(ctx, &raw, "eth_getBlockByNumber", toBlockNumArg(nil), false); err != nil {
+			return nil, err
+		}
+	}
+	if len(raw) == 0 {
+		return nil, ethereum.NotFound
+	}
+
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, err
+	}
+
+	return head, nil
+}
+
+// accountInfoRequests returns the batch requests reading the state of
+// address at blockNum, and a function converting their results into
+// an *AccountInfo once the batch has been executed.
+func accountInfoRequests(address string, blockNum string) ([]rpc.BatchElem, func() (*AccountInfo, error)) {
+	var (
+		balance *hexutil.Big
+		nonce   *hexutil.Uint64
+		code    *string
+	)
+
+	reqs := []rpc.BatchElem{
+		{Method: "eth_getBalance", Args: []interface{}{address, blockNum}, Result: &balance},
+		{Method: "eth_getTransactionCount", Args: []interface{}{address, blockNum}, Result: &nonce},
+		{Method: "eth_getCode", Args: []interface{}{address, blockNum}, Result: &code},
+	}
+
+	return reqs, func() (*AccountInfo, error) {
+		// Some nodes return null rather than zero values for an account
+		// that has never existed at a valid block. Treat it as empty.
+		info := &AccountInfo{
+			Balance: new(big.Int),
+			Code:    []byte{},
+		}
+		if balance != nil {
+			info.Balance = balance.ToInt()
+		}
+		if nonce != nil {
+			info.Nonce = uint64(*nonce)
+		}
+		if code != nil && len(*code) > 0 {
+			codeBytes, err := hexutil.Decode(*code)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unable to decode code", err)
+			}
+			info.Code = codeBytes
+		}
+
+		return info, nil
+	}
+}
+
+// canonicalHeaderRequest appends a request for the canonical header
+// at blockNum if block was requested by hash. State is read by number,
+// so such a block is only consistent with the state read if it is
+// still canonical.
+func canonicalHeaderRequest(
+	reqs []rpc.BatchElem,
+	block *RosettaTypes.PartialBlockIdentifier,
+	blockNum string,
+) ([]rpc.BatchElem, **types.Header) {
+	var canonical *types.Header
+	if requestedByHash(block) {
+		reqs = append(reqs, rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{blockNum, false},
+			Result: &canonical,
+		})
+	}
+
+	return reqs, &canonical
+}
+
+// checkCanonicalHeader returns ErrBlockOrphaned if block was requested
+// by hash and head is no longer the canonical header at its height.
+func checkCanonicalHeader(
+	block *RosettaTypes.PartialBlockIdentifier,
+	head *types.Header,
+	canonical *types.Header,
+) error {
+	if !requestedByHash(block) {
+		return nil
+	}
+	if canonical == nil || canonical.Hash() != head.Hash() {
+		return fmt.Errorf(
+			"%w: block %s is no longer canonical at height %d",
+			ErrBlockOrphaned,
+			head.Hash().Hex(),
+			head.Number.Uint64(),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestAccountInfo(t *testing.T) {
+	var tests = map[string]struct {
+		address string
+		balance *hexutil.Big
+		nonce   *hexutil.Uint64
+		code    *string
+
+		expectedInfo *AccountInfo
+	}{
+		"externally owned account": {
+			address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			balance: (*hexutil.Big)(big.NewInt(1000)),
+			nonce:   hexUint64(3),
+			code:    RosettaTypes.String("0x"),
+			expectedInfo: &AccountInfo{
+				Balance: big.NewInt(1000),
+				Nonce:   3,
+				Code:    []byte{},
+			},
+		},
+		"contract account": {
+			address: "0x4200000000000000000000000000000000000042",
+			balance: (*hexutil.Big)(big.NewInt(0)),
+			nonce:   hexUint64(1),
+			code:    RosettaTypes.String("0x6080604052"),
+			expectedInfo: &AccountInfo{
+				Balance: big.NewInt(0),
+				Nonce:   1,
+				Code:    []byte{0x60, 0x80, 0x60, 0x40, 0x52},
+			},
+		},
+		"null account": {
+			address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			expectedInfo: &AccountInfo{
+				Balance: big.NewInt(0),
+				Nonce:   0,
+				Code:    []byte{},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+
+			c := &Client{
+				c:              mockJSONRPC,
+				g:              mockGraphQL,
+				traceSemaphore: semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"latest",
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 3 && rpcs[0].Method == "eth_getBalance" && rpcs[1].Method == "eth_getTransactionCount" && rpcs[2].Method == "eth_getCode"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					for i := range r {
+						assert.Equal(t, []interface{}{test.address, "0x2af0"}, r[i].Args)
+					}
+
+					*(r[0].Result.(**hexutil.Big)) = test.balance
+					*(r[1].Result.(**hexutil.Uint64)) = test.nonce
+					*(r[2].Result.(**string)) = test.code
+				},
+			).Once()
+
+			info, err := c.AccountInfo(ctx, common.HexToAddress(test.address), nil)
+			assert.NoError(t, err)
+
+			test.expectedInfo.BlockIdentifier = &RosettaTypes.BlockIdentifier{
+				Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+				Index: 10992,
+			}
+			assert.Equal(t, test.expectedInfo, info)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func hexUint64(n uint64) *hexutil.Uint64 {
+	h := hexutil.Uint64(n)
+	return &h
+}
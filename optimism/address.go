@@ -15,21 +15,43 @@
 package optimism
 
 import (
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/ethereum-optimism/optimism/l2geth/common"
 )
 
+// ValidateAddress parses an Ethereum hex address supplied as param.
+// All-lowercase and all-uppercase addresses are accepted as is, while
+// mixed-case addresses must carry a valid EIP-55 checksum.
+func ValidateAddress(param string, address string) (common.Address, error) {
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("%w: %s %q is not a hex address", ErrInvalidAddress, param, address)
+	}
+
+	addr := common.HexToAddress(address)
+	digits := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return addr, nil
+	}
+	if digits != addr.Hex()[2:] {
+		return common.Address{}, fmt.Errorf("%w: %s %q has an invalid checksum", ErrInvalidAddress, param, address)
+	}
+
+	return addr, nil
+}
+
 // ChecksumAddress ensures an Ethereum hex address
 // is in Checksum Format. If the address cannot be converted,
 // it returns !ok.
 func ChecksumAddress(address string) (string, bool) {
-	addr, err := common.NewMixedcaseAddressFromString(address)
+	addr, err := ValidateAddress("address", address)
 	if err != nil {
 		return "", false
 	}
 
-	return addr.Address().Hex(), true
+	return addr.Hex(), true
 }
 
 // MustChecksum ensures an address can be converted
@@ -0,0 +1,145 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestValidateAddress(t *testing.T) {
+	var tests = map[string]struct {
+		address string
+
+		expectedAddress string
+		expectedErr     error
+	}{
+		"checksummed": {
+			address:         "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			expectedAddress: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+		},
+		"lowercase": {
+			address:         "0x2f93b2f047e05cdf602820ac4b3178efc2b43d55",
+			expectedAddress: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+		},
+		"uppercase": {
+			address:         "0x2F93B2F047E05CDF602820AC4B3178EFC2B43D55",
+			expectedAddress: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+		},
+		"invalid checksum": {
+			address:     "0x2F93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			expectedErr: ErrInvalidAddress,
+		},
+		"too short": {
+			address:     "0x2f93b2f047e05cdf602820ac4b3178efc2b43d5",
+			expectedErr: ErrInvalidAddress,
+		},
+		"not hex": {
+			address:     "not valid",
+			expectedErr: ErrInvalidAddress,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			addr, err := ValidateAddress("to", test.address)
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+				assert.Contains(t, err.Error(), "to")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedAddress, addr.Hex())
+
+			checksummed, ok := ChecksumAddress(test.address)
+			assert.True(t, ok)
+			assert.Equal(t, test.expectedAddress, checksummed)
+		})
+	}
+}
+
+func TestCall_InvalidAddress(t *testing.T) {
+	var tests = map[string]struct {
+		method string
+		params map[string]interface{}
+
+		expectedParam string
+	}{
+		"eth_call to": {
+			method: "eth_call",
+			params: map[string]interface{}{
+				"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538d",
+				"data": "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+			},
+			expectedParam: "to",
+		},
+		"eth_estimateGas to": {
+			method: "eth_estimateGas",
+			params: map[string]interface{}{
+				"from": "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
+				"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538d",
+				"data": "0xa9059cbb000000000000000000000000ae7e48ee0f758cd706b76cf7e2175d982800f9d5",
+			},
+			expectedParam: "to",
+		},
+		"eth_estimateGas from": {
+			method: "eth_estimateGas",
+			params: map[string]interface{}{
+				"from": "0xe550f300E477C60CE7e7172d12e5a27e9379D2e3",
+				"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
+				"data": "0xa9059cbb000000000000000000000000ae7e48ee0f758cd706b76cf7e2175d982800f9d5",
+			},
+			expectedParam: "from",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: cf,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			resp, err := c.Call(
+				context.Background(),
+				&RosettaTypes.CallRequest{
+					Method:     test.method,
+					Parameters: test.params,
+				},
+			)
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrInvalidAddress))
+			assert.Contains(t, err.Error(), test.expectedParam)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
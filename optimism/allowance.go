@@ -0,0 +1,171 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/coinbase/rosetta-ethereum/optimism/utilities/artifacts"
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// AllowanceMethod is the call method that returns the amount of
+// Token that Spender may transfer on behalf of Owner.
+const AllowanceMethod = "erc20_allowance"
+
+// AllowanceInput is the input to AllowanceMethod. The allowance
+// is read at Index, or at the current block if it is unset.
+type AllowanceInput struct {
+	Owner   string `json:"owner"`
+	Spender string `json:"spender"`
+	Token   string `json:"token"`
+	Index   *int64 `json:"index,omitempty"`
+}
+
+// allowanceCallParams returns the eth_call parameters
+// of allowance(owner, spender) on token.
+func allowanceCallParams(owner common.Address, spender common.Address, token common.Address) (map[string]string, error) {
+	data, err := artifacts.ERC20ABI.Pack("allowance", owner, spender)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"to":   token.Hex(),
+		"data": hexutil.Encode(data),
+	}, nil
+}
+
+// parseAllowance decodes the uint256 returned by allowance.
+func parseAllowance(result string) (*big.Int, error) {
+	data, err := hexutil.Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty allowance returned")
+	}
+
+	return parseIntReturn(artifacts.ERC20ABI, "allowance", data)
+}
+
+// allowance reads the ERC20 allowance described by params.
+func (ec *Client) allowance(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input AllowanceInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	if input.Index != nil && *input.Index < 0 {
+		return nil, fmt.Errorf("%w: index %d is negative", ErrCallParametersInvalid, *input.Index)
+	}
+
+	owner, err := ValidateAddress("owner", input.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	spender, err := ValidateAddress("spender", input.Spender)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	token, err := ValidateAddress("token", input.Token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	var block *RosettaTypes.PartialBlockIdentifier
+	if input.Index != nil {
+		block = &RosettaTypes.PartialBlockIdentifier{Index: input.Index}
+	}
+	head, err := ec.accountBlockHeader(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+	blockNum := hexutil.EncodeUint64(head.Number.Uint64())
+
+	callParams, err := allowanceCallParams(owner, spender, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		code   string
+		result string
+	)
+	reqs := []rpc.BatchElem{
+		{Method: "eth_getCode", Args: []interface{}{token.Hex(), blockNum}, Result: &code},
+		{Method: "eth_call", Args: []interface{}{callParams, blockNum}, Result: &result},
+	}
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, historicalStateError(err)
+	}
+	if reqs[0].Error != nil {
+		return nil, historicalStateError(reqs[0].Error)
+	}
+	if len(code) == 0 || code == "0x" {
+		return nil, fmt.Errorf("%w: token %s is not a contract", ErrCallParametersInvalid, token.Hex())
+	}
+	if reqs[1].Error != nil {
+		if isRevert(reqs[1].Error) {
+			return nil, fmt.Errorf(
+				"%w: token %s does not implement allowance: %s",
+				ErrCallParametersInvalid,
+				token.Hex(),
+				reqs[1].Error.Error(),
+			)
+		}
+		return nil, historicalStateError(reqs[1].Error)
+	}
+
+	amount, err := parseAllowance(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: token %s: %s", ErrCallParametersInvalid, token.Hex(), err.Error())
+	}
+
+	currency, err := ec.currencyFetcher.FetchCurrency(ctx, head.Number.Uint64(), token.Hex())
+	if err != nil {
+		if errors.Is(err, ErrNotERC20) {
+			return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+		}
+
+		// If an error is encountered while fetching currency details, return a default value and let the client handle it.
+		log.Printf("error while fetching currency details for currency: %s: %v", token.Hex(), err)
+		currency = &RosettaTypes.Currency{
+			Symbol:   defaultERC20Symbol,
+			Decimals: defaultERC20Decimals,
+			Metadata: map[string]interface{}{
+				ContractAddressKey: token.Hex(),
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"allowance": amount.String(),
+		"currency":  currency,
+		"block_identifier": &RosettaTypes.BlockIdentifier{
+			Hash:  head.Hash().Hex(),
+			Index: head.Number.Int64(),
+		},
+	}, nil
+}
@@ -0,0 +1,288 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	allowanceOwner   = "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+	allowanceSpender = "0x7492ce19d83b3a0BaC1BEBC9706ce0dF4ADD105F"
+	allowanceToken   = "0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1"
+)
+
+func TestAllowanceCallParams(t *testing.T) {
+	params, err := allowanceCallParams(
+		common.HexToAddress(allowanceOwner),
+		common.HexToAddress(allowanceSpender),
+		common.HexToAddress(allowanceToken),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"to": allowanceToken,
+		"data": "0xdd62ed3e" +
+			"0000000000000000000000002f93b2f047e05cdf602820ac4b3178efc2b43d55" +
+			"0000000000000000000000007492ce19d83b3a0bac1bebc9706ce0df4add105f",
+	}, params)
+}
+
+func TestParseAllowance(t *testing.T) {
+	var tests = map[string]struct {
+		result string
+
+		expectedAllowance *big.Int
+		expectedErr       bool
+	}{
+		"allowance": {
+			result:            "0x00000000000000000000000000000000000000000000000000000000000003e8",
+			expectedAllowance: big.NewInt(1000),
+		},
+		"infinite allowance": {
+			result:            "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			expectedAllowance: maxUint256,
+		},
+		"empty": {
+			result:      "0x",
+			expectedErr: true,
+		},
+		"short": {
+			result:      "0x03e8",
+			expectedErr: true,
+		},
+		"not hex": {
+			result:      "1000",
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			allowance, err := parseAllowance(test.result)
+			if test.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, allowance)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedAllowance, allowance)
+			}
+		})
+	}
+}
+
+func TestCall_Allowance(t *testing.T) {
+	currency := &RosettaTypes.Currency{
+		Symbol:   "DAI",
+		Decimals: 18,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: allowanceToken,
+		},
+	}
+
+	var tests = map[string]struct {
+		params   map[string]interface{}
+		blockTag string
+		code     string
+		result   string
+		callErr  error
+
+		expectedAllowance string
+		expectedIndex     int64
+		expectedErr       error
+	}{
+		"at index": {
+			params: map[string]interface{}{
+				"owner":   allowanceOwner,
+				"spender": allowanceSpender,
+				"token":   allowanceToken,
+				"index":   10992,
+			},
+			blockTag:          "0x2af0",
+			code:              "0x6080604052",
+			result:            "0x00000000000000000000000000000000000000000000000000000000000003e8",
+			expectedAllowance: "1000",
+			expectedIndex:     10992,
+		},
+		"at current block": {
+			params: map[string]interface{}{
+				"owner":   allowanceOwner,
+				"spender": allowanceSpender,
+				"token":   allowanceToken,
+			},
+			blockTag:          "latest",
+			code:              "0x6080604052",
+			result:            "0x0000000000000000000000000000000000000000000000000000000000000000",
+			expectedAllowance: "0",
+			expectedIndex:     10992,
+		},
+		"invalid owner": {
+			params: map[string]interface{}{
+				"owner":   "0x2f93b2f047e05cdf602820ac4b3178efc2b43d5",
+				"spender": allowanceSpender,
+				"token":   allowanceToken,
+			},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"invalid spender": {
+			params: map[string]interface{}{
+				"owner":   allowanceOwner,
+				"spender": "0x7492ce19d83b3a0bac1BEBC9706ce0df4add105f",
+				"token":   allowanceToken,
+			},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"negative index": {
+			params: map[string]interface{}{
+				"owner":   allowanceOwner,
+				"spender": allowanceSpender,
+				"token":   allowanceToken,
+				"index":   -1,
+			},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"token is not a contract": {
+			params: map[string]interface{}{
+				"owner":   allowanceOwner,
+				"spender": allowanceSpender,
+				"token":   allowanceToken,
+			},
+			blockTag:    "latest",
+			code:        "0x",
+			result:      "0x",
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"token reverts": {
+			params: map[string]interface{}{
+				"owner":   allowanceOwner,
+				"spender": allowanceSpender,
+				"token":   allowanceToken,
+			},
+			blockTag:    "latest",
+			code:        "0x6080604052",
+			callErr:     errors.New("execution reverted"),
+			expectedErr: ErrCallParametersInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: mockCurrencyFetcher,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			if test.blockTag != "" {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"eth_getBlockByNumber",
+					test.blockTag,
+					false,
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						r := args.Get(1).(*json.RawMessage)
+
+						file, err := ioutil.ReadFile("testdata/block_10992.json")
+						assert.NoError(t, err)
+
+						*r = json.RawMessage(file)
+					},
+				).Once()
+				mockJSONRPC.On(
+					"BatchCallContext",
+					ctx,
+					mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+						return len(rpcs) == 2 && rpcs[0].Method == "eth_getCode" && rpcs[1].Method == "eth_call"
+					}),
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						r := args.Get(1).([]rpc.BatchElem)
+
+						assert.Equal(t, []interface{}{allowanceToken, "0x2af0"}, r[0].Args)
+						assert.Equal(t, allowanceToken, r[1].Args[0].(map[string]string)["to"])
+						assert.Equal(t, "0x2af0", r[1].Args[1])
+
+						*(r[0].Result.(*string)) = test.code
+						*(r[1].Result.(*string)) = test.result
+						r[1].Error = test.callErr
+					},
+				).Once()
+			}
+			if test.expectedErr == nil {
+				mockCurrencyFetcher.On(
+					"FetchCurrency",
+					ctx,
+					uint64(10992),
+					allowanceToken,
+				).Return(
+					currency,
+					nil,
+				).Once()
+			}
+
+			resp, err := c.Call(
+				ctx,
+				&RosettaTypes.CallRequest{
+					Method:     AllowanceMethod,
+					Parameters: test.params,
+				},
+			)
+			if test.expectedErr != nil {
+				assert.Nil(t, resp)
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{
+					"allowance": test.expectedAllowance,
+					"currency":  currency,
+					"block_identifier": &RosettaTypes.BlockIdentifier{
+						Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+						Index: test.expectedIndex,
+					},
+				}, resp.Result)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+			mockCurrencyFetcher.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// ApprovalsKey is the transaction metadata key holding the ERC20
+	// approvals emitted by the transaction. Approvals do not move
+	// balances, so they are not operations.
+	ApprovalsKey = "approvals"
+
+	erc20ApprovalEventLogTopics = "Approval(address,address,uint256)"
+
+	// Approval logs have the same layout as Transfer logs: the owner
+	// and spender are indexed and the value is the log data.
+	numTopicsERC20Approval = 3
+	erc20ApprovalDataSize  = 32
+)
+
+var (
+	erc20ApprovalTopic = common.BytesToHash(crypto.Keccak256([]byte(erc20ApprovalEventLogTopics)))
+
+	// maxUint256 is the value of an infinite approval.
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)) // nolint:gomnd
+)
+
+// erc20Approvals decodes the Approval events of indexed tokens
+// in the logs of receipt.
+func (ec *Client) erc20Approvals(receipt *types.Receipt) []map[string]interface{} {
+	var approvals []map[string]interface{}
+	for _, receiptLog := range receipt.Logs {
+		if len(receiptLog.Topics) != numTopicsERC20Approval || receiptLog.Topics[0] != erc20ApprovalTopic {
+			continue
+		}
+		if len(receiptLog.Data) != erc20ApprovalDataSize {
+			continue
+		}
+
+		tokenAddress := receiptLog.Address.Hex()
+		if !ec.isIndexedToken(tokenAddress) {
+			continue
+		}
+
+		value := new(big.Int).SetBytes(receiptLog.Data)
+		approvals = append(approvals, map[string]interface{}{
+			"token_address": tokenAddress,
+			"owner":         common.BytesToAddress(receiptLog.Topics[1].Bytes()).Hex(),
+			"spender":       common.BytesToAddress(receiptLog.Topics[2].Bytes()).Hex(),
+			"value":         value.String(),
+			"infinite":      value.Cmp(maxUint256) == 0,
+			"log_index":     int64(receiptLog.Index),
+		})
+	}
+
+	return approvals
+}
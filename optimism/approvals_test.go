@@ -0,0 +1,94 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPopulateTransaction_Approvals(t *testing.T) {
+	file, err := ioutil.ReadFile("testdata/tx_receipt_erc20_approval.json")
+	assert.NoError(t, err)
+	receipt := new(types.Receipt)
+	assert.NoError(t, receipt.UnmarshalJSON(file))
+
+	token := common.HexToAddress("0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1")
+	from := common.HexToAddress("0x7492ce19d83b3a0BaC1BEBC9706ce0dF4ADD105F")
+	ethTx := types.NewTransaction(0, token, big.NewInt(0), 60000, big.NewInt(1), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1241186)}, []*types.Transaction{ethTx}, nil, nil)
+	tx := &loadedTransaction{
+		Transaction: ethTx,
+		From:        &from,
+		FeeAmount:   big.NewInt(60000),
+		Miner:       sequencerFeeVaultAddr,
+		Receipt:     receipt,
+	}
+
+	// Only the tokens in the allowlist are decoded
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+	c := &Client{
+		p:               params.GoerliChainConfig,
+		currencyFetcher: mockCurrencyFetcher,
+		supportedTokens: map[string]bool{
+			"0xda10009cbd5d07dd0cecc66161fc93d7c9000da1": true,
+		},
+	}
+
+	populated, err := c.populateTransaction(context.Background(), block, tx, &TraceDiagnostics{})
+	assert.NoError(t, err)
+
+	// Approvals do not create operations
+	assert.Len(t, populated.Operations, 2)
+	for _, op := range populated.Operations {
+		assert.Equal(t, FeeOpType, op.Type)
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"token_address": token.Hex(),
+			"owner":         "0x7492ce19d83b3a0BaC1BEBC9706ce0dF4ADD105F",
+			"spender":       "0x55C34cE12566cD4a0625E58C09f38d92D991E7b5",
+			"value":         maxUint256.String(),
+			"infinite":      true,
+			"log_index":     int64(0),
+		},
+		{
+			"token_address": token.Hex(),
+			"owner":         "0x7492ce19d83b3a0BaC1BEBC9706ce0dF4ADD105F",
+			"spender":       "0x55C34cE12566cD4a0625E58C09f38d92D991E7b5",
+			"value":         "1000000000000000000",
+			"infinite":      false,
+			"log_index":     int64(3),
+		},
+	}, populated.Metadata[ApprovalsKey])
+
+	// Receipts without approvals of indexed tokens have no approvals
+	c.supportedTokens = map[string]bool{}
+	populated, err = c.populateTransaction(context.Background(), block, tx, &TraceDiagnostics{})
+	assert.NoError(t, err)
+	assert.NotContains(t, populated.Metadata, ApprovalsKey)
+
+	mockCurrencyFetcher.AssertExpectations(t)
+}
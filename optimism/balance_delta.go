@@ -0,0 +1,97 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// BalanceDelta returns the signed change of the balances of account
+// in block: its balances at block minus its balances at the parent of
+// block, as returned by Balance for currencies. At genesis, the change
+// is the genesis balance. A reconciler can compare it with the sum of
+// the operations of account in block.
+//
+// Both blocks are read by hash, the parent by the parent hash in the
+// header of block, so a reorg fails the call with ErrBlockOrphaned
+// instead of mixing balances from different chains.
+func (ec *Client) BalanceDelta(
+	ctx context.Context,
+	account *RosettaTypes.AccountIdentifier,
+	block *RosettaTypes.BlockIdentifier,
+	currencies []*RosettaTypes.Currency,
+) ([]*RosettaTypes.Amount, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	pinned := &RosettaTypes.PartialBlockIdentifier{Hash: &block.Hash}
+	head, err := ec.accountBlockHeader(ctx, pinned)
+	if err != nil {
+		return nil, rpcError(err, true)
+	}
+
+	after, err := ec.balanceAtHeader(ctx, account, pinned, head, currencies)
+	if err != nil {
+		return nil, rpcError(err, true)
+	}
+	if head.Number.Int64() <= GenesisBlockIndex {
+		return balanceDeltas(after.Balances, nil)
+	}
+
+	parentHash := head.ParentHash.Hex()
+	before, err := ec.balance(
+		ctx,
+		account,
+		&RosettaTypes.PartialBlockIdentifier{Hash: &parentHash},
+		currencies,
+	)
+	if err != nil {
+		return nil, rpcError(err, true)
+	}
+
+	return balanceDeltas(after.Balances, before.Balances)
+}
+
+// balanceDeltas subtracts the balances before from the balances after,
+// which were returned for the same currencies. A nil before is zero.
+func balanceDeltas(after []*RosettaTypes.Amount, before []*RosettaTypes.Amount) ([]*RosettaTypes.Amount, error) {
+	deltas := make([]*RosettaTypes.Amount, len(after))
+	for i, amount := range after {
+		delta, ok := new(big.Int).SetString(amount.Value, 10)
+		if !ok {
+			return nil, fmt.Errorf("could not parse balance %s", amount.Value)
+		}
+
+		if before != nil {
+			value, ok := new(big.Int).SetString(before[i].Value, 10)
+			if !ok {
+				return nil, fmt.Errorf("could not parse balance %s", before[i].Value)
+			}
+			delta.Sub(delta, value)
+		}
+
+		deltas[i] = &RosettaTypes.Amount{
+			Value:    delta.String(),
+			Currency: amount.Currency,
+		}
+	}
+
+	return deltas, nil
+}
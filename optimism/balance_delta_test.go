@@ -0,0 +1,216 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockBalancesAt mocks the native balance requests at the block with
+// header, returning balance for any account.
+func mockBalancesAt(
+	t *testing.T,
+	mockJSONRPC *mocks.JSONRPC,
+	header *types.Header,
+	balance int64,
+) {
+	blockNum := hexutil.EncodeUint64(header.Number.Uint64())
+	mockJSONRPC.On(
+		"BatchCallContext",
+		mock.Anything,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return rpcs[0].Method == "eth_getBalance" && rpcs[0].Args[1] == blockNum
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+			for i := range r {
+				switch r[i].Method {
+				case "eth_getBalance":
+					*(r[i].Result.(**hexutil.Big)) = (*hexutil.Big)(big.NewInt(balance))
+				case "eth_getTransactionCount":
+					*(r[i].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+				case "eth_getCode":
+					*(r[i].Result.(**string)) = RosettaTypes.String("0x")
+				case "eth_getBlockByNumber":
+					*(r[i].Result.(**types.Header)) = header
+				default:
+					assert.Fail(t, "unexpected method "+r[i].Method)
+				}
+			}
+		},
+	).Once()
+}
+
+// mockAccountHeader mocks the header lookup of a balance request
+// made with method and arg.
+func mockAccountHeader(
+	t *testing.T,
+	mockJSONRPC *mocks.JSONRPC,
+	header *types.Header,
+	method string,
+	arg interface{},
+) {
+	raw, err := json.Marshal(header)
+	assert.NoError(t, err)
+
+	mockJSONRPC.On(
+		"CallContext",
+		mock.Anything,
+		mock.Anything,
+		method,
+		arg,
+		false,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			*args.Get(1).(*json.RawMessage) = raw
+		},
+	).Once()
+}
+
+func TestBalanceDelta(t *testing.T) {
+	parent := &types.Header{Number: big.NewInt(9), Difficulty: big.NewInt(0)}
+	block := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(10),
+		Difficulty: big.NewInt(0),
+	}
+	account := &RosettaTypes.AccountIdentifier{
+		Address: common.BigToAddress(big.NewInt(6)).Hex(),
+	}
+
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+
+	// The account received a transfer of 250 in block 10
+	blockHash := block.Hash().Hex()
+	mockAccountHeader(t, mockJSONRPC, block, "eth_getBlockByHash", &blockHash)
+	mockBalancesAt(t, mockJSONRPC, block, 1250)
+	parentHash := parent.Hash().Hex()
+	mockAccountHeader(t, mockJSONRPC, parent, "eth_getBlockByHash", &parentHash)
+	mockBalancesAt(t, mockJSONRPC, parent, 1000)
+
+	delta, err := c.BalanceDelta(
+		context.Background(),
+		account,
+		&RosettaTypes.BlockIdentifier{Hash: blockHash, Index: 10},
+		[]*RosettaTypes.Currency{Currency},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []*RosettaTypes.Amount{
+		{
+			Value:    "250",
+			Currency: Currency,
+		},
+	}, delta)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestBalanceDelta_ParentOrphaned(t *testing.T) {
+	parent := &types.Header{Number: big.NewInt(9), Difficulty: big.NewInt(0)}
+	block := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     big.NewInt(10),
+		Difficulty: big.NewInt(0),
+	}
+	reorged := &types.Header{Number: big.NewInt(9), Difficulty: big.NewInt(1)}
+	account := &RosettaTypes.AccountIdentifier{
+		Address: common.BigToAddress(big.NewInt(6)).Hex(),
+	}
+
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+
+	// The parent is replaced at height 9 after block 10 is read
+	blockHash := block.Hash().Hex()
+	mockAccountHeader(t, mockJSONRPC, block, "eth_getBlockByHash", &blockHash)
+	mockBalancesAt(t, mockJSONRPC, block, 1250)
+	parentHash := parent.Hash().Hex()
+	mockAccountHeader(t, mockJSONRPC, parent, "eth_getBlockByHash", &parentHash)
+	mockBalancesAt(t, mockJSONRPC, reorged, 1000)
+
+	delta, err := c.BalanceDelta(
+		context.Background(),
+		account,
+		&RosettaTypes.BlockIdentifier{Hash: blockHash, Index: 10},
+		[]*RosettaTypes.Currency{Currency},
+	)
+	assert.Nil(t, delta)
+	assert.ErrorIs(t, err, ErrBlockOrphaned)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestBalanceDelta_Genesis(t *testing.T) {
+	genesis := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(0)}
+	account := &RosettaTypes.AccountIdentifier{
+		Address: common.BigToAddress(big.NewInt(6)).Hex(),
+	}
+
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+
+	// The genesis balance is the change at genesis
+	genesisHash := genesis.Hash().Hex()
+	mockAccountHeader(t, mockJSONRPC, genesis, "eth_getBlockByHash", &genesisHash)
+	mockBalancesAt(t, mockJSONRPC, genesis, 1000)
+
+	delta, err := c.BalanceDelta(
+		context.Background(),
+		account,
+		&RosettaTypes.BlockIdentifier{Hash: genesisHash, Index: 0},
+		[]*RosettaTypes.Currency{Currency},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []*RosettaTypes.Amount{
+		{
+			Value:    "1000",
+			Currency: Currency,
+		},
+	}, delta)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestBalanceDelta_Closed(t *testing.T) {
+	c := &Client{closed: 1}
+
+	delta, err := c.BalanceDelta(
+		context.Background(),
+		&RosettaTypes.AccountIdentifier{Address: common.BigToAddress(big.NewInt(6)).Hex()},
+		&RosettaTypes.BlockIdentifier{Hash: common.Hash{}.Hex(), Index: 1},
+		nil,
+	)
+	assert.Nil(t, delta)
+	assert.ErrorIs(t, err, ErrClientClosed)
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// BalanceHistoryMethod is the call method that returns the native
+// balance of an account at several blocks, e.g. to chart it.
+const BalanceHistoryMethod = "balance_history"
+
+// maxBalanceHistoryBlocks is the largest number of blocks
+// BalanceHistoryMethod reads balances at at once.
+const maxBalanceHistoryBlocks = 100
+
+// BalanceHistoryInput is the input to BalanceHistoryMethod.
+type BalanceHistoryInput struct {
+	Address string  `json:"address"`
+	Indices []int64 `json:"indices"`
+}
+
+// balanceHistory reads the balance of the account of params at each
+// of its block indices in a single batch. Balances are returned in
+// wei, keyed by block index.
+func (ec *Client) balanceHistory(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input BalanceHistoryInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	address, err := ValidateAddress("address", input.Address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	if len(input.Indices) == 0 {
+		return nil, fmt.Errorf("%w: indices missing from params", ErrCallParametersInvalid)
+	}
+	if len(input.Indices) > maxBalanceHistoryBlocks {
+		return nil, fmt.Errorf(
+			"%w: %d indices requested, at most %d are supported",
+			ErrCallParametersInvalid,
+			len(input.Indices),
+			maxBalanceHistoryBlocks,
+		)
+	}
+
+	balances := make(map[int64]*hexutil.Big, len(input.Indices))
+	reqs := make([]rpc.BatchElem, 0, len(input.Indices))
+	for _, index := range input.Indices {
+		if index < 0 {
+			return nil, fmt.Errorf("%w: index %d is negative", ErrCallParametersInvalid, index)
+		}
+		if _, ok := balances[index]; ok {
+			continue
+		}
+
+		balances[index] = new(hexutil.Big)
+		reqs = append(reqs, rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{address.Hex(), toBlockNumArg(big.NewInt(index))},
+			Result: balances[index],
+		})
+	}
+
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, historicalStateError(err)
+	}
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			return nil, historicalStateError(reqs[i].Error)
+		}
+	}
+
+	result := make(map[string]interface{}, len(balances))
+	for index, balance := range balances {
+		result[strconv.FormatInt(index, 10)] = balance.ToInt().String()
+	}
+
+	return map[string]interface{}{
+		"balances": result,
+	}, nil
+}
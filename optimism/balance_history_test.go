@@ -0,0 +1,121 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCall_BalanceHistory(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+	address := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+
+	balances := map[string]string{
+		"0x12f062": "0x0",
+		"0x12f063": "0xde0b6b3a7640000",
+		"0x12f064": "0x6f05b59d3b20000",
+	}
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 3 && rpcs[0].Method == "eth_getBalance"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+			for i := range r {
+				assert.Equal(t, address, r[i].Args[0])
+				*(r[i].Result.(*hexutil.Big)) = *(*hexutil.Big)(hexutil.MustDecodeBig(balances[r[i].Args[1].(string)]))
+			}
+		},
+	).Once()
+
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+		Method: BalanceHistoryMethod,
+		Parameters: map[string]interface{}{
+			"address": address,
+			"indices": []int64{1241186, 1241187, 1241188, 1241187},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result: map[string]interface{}{
+			"balances": map[string]interface{}{
+				"1241186": "0",
+				"1241187": "1000000000000000000",
+				"1241188": "500000000000000000",
+			},
+		},
+		Idempotent: true,
+	}, resp)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestCall_BalanceHistory_Invalid(t *testing.T) {
+	tooMany := make([]int64, maxBalanceHistoryBlocks+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i)
+	}
+
+	tests := map[string]map[string]interface{}{
+		"invalid address": {
+			"address": "0x1",
+			"indices": []int64{1},
+		},
+		"no indices": {
+			"address": "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+		},
+		"negative index": {
+			"address": "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			"indices": []int64{1, -1},
+		},
+		"too many indices": {
+			"address": "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			"indices": tooMany,
+		},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+
+			resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+				Method:     BalanceHistoryMethod,
+				Parameters: params,
+			})
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,458 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+const (
+	// AliasedFromKey is the amount metadata key set on the native
+	// balance when it was requested as the OVM_ETH token.
+	AliasedFromKey = "aliased_from"
+
+	// DeduplicatedKey is the amount metadata key set on the native
+	// balance when it was requested more than once, e.g. as both
+	// the native currency and OVM_ETH.
+	DeduplicatedKey = "deduplicated"
+
+	// defaultBalancesBatchSize is the number of accounts
+	// read per batch request by Balances.
+	defaultBalancesBatchSize = 100
+)
+
+// BalancesError is returned by Balances when partial balances are
+// enabled and some accounts could not be read. Errors is aligned with
+// the accounts passed to Balances and is nil for accounts whose
+// balance was returned.
+type BalancesError struct {
+	Errors []error
+}
+
+func (e *BalancesError) Error() string {
+	var (
+		failed int
+		first  error
+	)
+	for _, err := range e.Errors {
+		if err == nil {
+			continue
+		}
+		if first == nil {
+			first = err
+		}
+		failed++
+	}
+
+	return fmt.Sprintf("unable to get balances of %d of %d accounts: %v", failed, len(e.Errors), first)
+}
+
+// Balances returns the balances of the default currencies of accounts
+// at a *RosettaTypes.PartialBlockIdentifier, in the order of accounts.
+// The block is resolved once and the accounts are read in batches, so
+// all balances are consistent.
+//
+// By default the first account that cannot be read fails the call. If
+// partial balances are enabled, its response is nil instead and its
+// error is reported in a *BalancesError returned with the responses.
+func (ec *Client) Balances(
+	ctx context.Context,
+	accounts []*RosettaTypes.AccountIdentifier,
+	block *RosettaTypes.PartialBlockIdentifier,
+) ([]*RosettaTypes.AccountBalanceResponse, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*RosettaTypes.AccountBalanceResponse, len(accounts))
+	errs := make([]error, len(accounts))
+	var failed bool
+	fail := func(i int, err error) error {
+		err = rpcError(err, true)
+		if !ec.partialBalances {
+			return fmt.Errorf("%w: unable to get balance of %s", err, accounts[i].Address)
+		}
+		errs[i] = err
+		failed = true
+
+		return nil
+	}
+
+	queries := make([]*balanceQuery, len(accounts))
+	for i, account := range accounts {
+		query, err := newBalanceQuery(account, nil, ec.nativeCurrency())
+		if err != nil {
+			if err := fail(i, err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		queries[i] = query
+	}
+
+	head, err := ec.accountBlockHeader(ctx, block)
+	if err != nil {
+		return nil, rpcError(err, true)
+	}
+	blockNum := hexutil.EncodeUint64(head.Number.Uint64())
+
+	batchSize := ec.balancesBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBalancesBatchSize
+	}
+	for _, batch := range batchRanges(len(accounts), batchSize) {
+		start, end := batch[0], batch[1]
+
+		var reqs []rpc.BatchElem
+		offsets := map[int]int{}
+		for i := start; i < end; i++ {
+			if queries[i] == nil {
+				continue
+			}
+			if err := queries[i].prepare(ctx, ec, head, blockNum); err != nil {
+				if err := fail(i, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			offsets[i] = len(reqs)
+			reqs = append(reqs, queries[i].reqs...)
+		}
+		if len(reqs) == 0 {
+			continue
+		}
+
+		// Every batch checks the block is still canonical, so
+		// balances are not mixed across a reorg.
+		accountReqs := len(reqs)
+		reqs, canonical := canonicalHeaderRequest(reqs, block, blockNum)
+		if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+			for i := range offsets {
+				if err := fail(i, historicalStateError(err)); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := checkBatchErrors(reqs[accountReqs:]); err != nil {
+			return nil, rpcError(err, true)
+		}
+		if err := checkCanonicalHeader(block, head, *canonical); err != nil {
+			return nil, err
+		}
+
+		for i := start; i < end; i++ {
+			offset, ok := offsets[i]
+			if !ok {
+				continue
+			}
+
+			query := queries[i]
+			copy(query.reqs, reqs[offset:offset+len(query.reqs)])
+			if err := query.check(ec); err != nil {
+				if err := fail(i, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			response, err := query.response(ec, head)
+			if err != nil {
+				if err := fail(i, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			responses[i] = response
+		}
+	}
+
+	if failed {
+		return responses, &BalancesError{Errors: errs}
+	}
+
+	return responses, nil
+}
+
+// balanceQuery reads the balances of an account at a block. The
+// requests it prepares may be batched with those of other accounts.
+type balanceQuery struct {
+	account           *RosettaTypes.AccountIdentifier
+	currencies        []*RosettaTypes.Currency
+	defaultCurrencies bool
+	contractAddresses []string
+	nativeMetadata    map[string]interface{}
+
+	reqs          []rpc.BatchElem
+	accountInfo   func() (*AccountInfo, error)
+	tokenBalances []string
+	tokenReverted []bool
+	tokenReqs     map[int]int
+	proof         *accountProof
+	proofReq      int
+}
+
+// newBalanceQuery validates the account and currencies of a balance
+// query before the node is queried. Without currencies, the native
+// and OP token balances are returned. native is the native currency
+// of the chain.
+func newBalanceQuery(
+	account *RosettaTypes.AccountIdentifier,
+	currencies []*RosettaTypes.Currency,
+	native *RosettaTypes.Currency,
+) (*balanceQuery, error) {
+	if _, err := ValidateAddress("account_identifier.address", account.Address); err != nil {
+		return nil, err
+	}
+
+	defaultCurrencies := len(currencies) == 0
+	if defaultCurrencies {
+		currencies = []*RosettaTypes.Currency{native, OPTokenCurrency}
+	}
+
+	// Native currency entries are left empty. OVM_ETH is the native
+	// currency, so requests for it are served from the native balance
+	// rather than balanceOf, and only one native entry is returned.
+	var (
+		requested         []*RosettaTypes.Currency
+		contractAddresses []string
+		nativeIndex       = -1
+		nativeRequests    int
+		aliased           bool
+	)
+	for _, curr := range currencies {
+		var contractAddress string
+		if !reflect.DeepEqual(curr, native) {
+			var err error
+			if contractAddress, err = tokenContractAddress(curr); err != nil {
+				return nil, err
+			}
+		}
+
+		if isOVMETH := strings.EqualFold(contractAddress, ovmEthAddr.Hex()); isOVMETH || len(contractAddress) == 0 {
+			aliased = aliased || isOVMETH
+			nativeRequests++
+			if nativeIndex >= 0 {
+				continue
+			}
+			nativeIndex = len(requested)
+			contractAddress = ""
+		}
+
+		requested = append(requested, curr)
+		contractAddresses = append(contractAddresses, contractAddress)
+	}
+
+	var nativeMetadata map[string]interface{}
+	if aliased {
+		nativeMetadata = map[string]interface{}{
+			AliasedFromKey: ovmEthAddr.Hex(),
+		}
+		if nativeRequests > 1 {
+			nativeMetadata[DeduplicatedKey] = true
+		}
+	}
+
+	return &balanceQuery{
+		account:           account,
+		currencies:        requested,
+		defaultCurrencies: defaultCurrencies,
+		contractAddresses: contractAddresses,
+		nativeMetadata:    nativeMetadata,
+		proofReq:          -1,
+	}, nil
+}
+
+// prepare builds the requests reading the balances at head.
+func (q *balanceQuery) prepare(
+	ctx context.Context,
+	ec *Client,
+	head *types.Header,
+	blockNum string,
+) error {
+	q.reqs, q.accountInfo = accountInfoRequests(q.account.Address, blockNum)
+
+	// Token balances are fetched in the same batch as the account state,
+	// pinned to the resolved block so all balances are consistent.
+	q.tokenBalances = make([]string, len(q.currencies))
+	q.tokenReverted = make([]bool, len(q.currencies))
+	q.tokenReqs = map[int]int{}
+	for i, curr := range q.currencies {
+		contractAddress := q.contractAddresses[i]
+		if len(contractAddress) == 0 {
+			continue
+		}
+
+		// Token details are cached, so this only hits the node the first
+		// time a contract is seen.
+		if !q.defaultCurrencies {
+			if _, err := ec.currencyFetcher.FetchCurrency(ctx, head.Number.Uint64(), contractAddress); err != nil {
+				if errors.Is(err, ErrNotERC20) {
+					return err
+				}
+				log.Printf("error while fetching currency details for currency: %s: %v", curr.Symbol, err)
+			}
+		}
+
+		callParams, err := balanceOfCallParams(q.account.Address, contractAddress)
+		if err != nil {
+			return err
+		}
+		q.tokenReqs[len(q.reqs)] = i
+		q.reqs = append(q.reqs, rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{callParams, blockNum},
+			Result: &q.tokenBalances[i],
+		})
+	}
+
+	// The proof is read in the same batch, so it proves the
+	// returned balance against the state root of head.
+	if includeProof(q.account) {
+		q.proofReq = len(q.reqs)
+		q.reqs = append(q.reqs, accountProofRequest(q.account.Address, blockNum, &q.proof))
+	}
+
+	return nil
+}
+
+// check returns the first error of the executed requests that
+// prevents the balances from being returned.
+func (q *balanceQuery) check(ec *Client) error {
+	for i := range q.reqs {
+		if q.reqs[i].Error == nil {
+			continue
+		}
+		if err := historicalStateError(q.reqs[i].Error); errors.Is(err, ErrHistoricalStateUnavailable) {
+			return err
+		}
+		if i == q.proofReq && isMethodNotFound(q.reqs[i].Error) {
+			q.proof = nil
+			continue
+		}
+
+		currIndex, isToken := q.tokenReqs[i]
+		if isToken && ec.lenientTokenBalances && isRevert(q.reqs[i].Error) {
+			q.tokenReverted[currIndex] = true
+			continue
+		}
+
+		return q.reqs[i].Error
+	}
+
+	return nil
+}
+
+// response converts the results of the executed requests.
+func (q *balanceQuery) response(
+	ec *Client,
+	head *types.Header,
+) (*RosettaTypes.AccountBalanceResponse, error) {
+	info, err := q.accountInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]*RosettaTypes.Amount, len(q.currencies))
+	for i, curr := range q.currencies {
+		if len(q.contractAddresses[i]) == 0 {
+			balances[i] = &RosettaTypes.Amount{
+				Value:    info.Balance.String(),
+				Currency: ec.nativeCurrency(),
+				Metadata: q.nativeMetadata,
+			}
+			continue
+		}
+
+		// Calls to addresses without code succeed with no return data
+		if ec.lenientTokenBalances && (q.tokenReverted[i] || q.tokenBalances[i] == "0x") {
+			balances[i] = &RosettaTypes.Amount{
+				Value:    "0",
+				Currency: curr,
+				Metadata: map[string]interface{}{
+					"call_reverted": true,
+				},
+			}
+			continue
+		}
+
+		tokenBalance, err := decodeHexData(q.tokenBalances[i])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"err encountered for currency %s, token address %s; %v",
+				curr.Symbol,
+				q.contractAddresses[i],
+				err,
+			)
+		}
+		balances[i] = &RosettaTypes.Amount{
+			Value:    tokenBalance.String(),
+			Currency: curr,
+		}
+	}
+
+	metadata, err := ec.balanceMetadata(q.account, info.Nonce, hexutil.Encode(info.Code))
+	if err != nil {
+		return nil, err
+	}
+	if q.proofReq >= 0 {
+		addProofMetadata(metadata, head, q.proof)
+	}
+
+	return &RosettaTypes.AccountBalanceResponse{
+		Balances: balances,
+		BlockIdentifier: &RosettaTypes.BlockIdentifier{
+			Hash:  head.Hash().Hex(),
+			Index: head.Number.Int64(),
+		},
+		Metadata: metadata,
+	}, nil
+}
+
+// batchRanges splits n items into consecutive [start, end)
+// ranges of at most size items.
+func batchRanges(n int, size int) [][2]int {
+	var ranges [][2]int
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		ranges = append(ranges, [2]int{start, end})
+	}
+
+	return ranges
+}
+
+// checkBatchErrors returns the first error of reqs.
+func checkBatchErrors(reqs []rpc.BatchElem) error {
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			return historicalStateError(reqs[i].Error)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,403 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+// balancesAccounts returns n accounts whose native balance is
+// their position and whose OP token balance is twice that.
+func balancesAccounts(n int) []*RosettaTypes.AccountIdentifier {
+	accounts := make([]*RosettaTypes.AccountIdentifier, n)
+	for i := range accounts {
+		accounts[i] = &RosettaTypes.AccountIdentifier{
+			Address: common.BigToAddress(big.NewInt(int64(i + 1))).Hex(),
+		}
+	}
+
+	return accounts
+}
+
+// fillBalances sets the results of the balance requests in r.
+// Accounts listed in failing get an error on their eth_getBalance.
+func fillBalances(t *testing.T, r []rpc.BatchElem, failing map[string]bool) {
+	for i := range r {
+		switch r[i].Method {
+		case "eth_getBalance":
+			address := r[i].Args[0].(string)
+			if failing[address] {
+				r[i].Error = errors.New("internal error")
+				continue
+			}
+			balance := new(big.Int).Sub(new(big.Int).SetBytes(common.HexToAddress(address).Bytes()), big.NewInt(1))
+			*(r[i].Result.(**hexutil.Big)) = (*hexutil.Big)(balance)
+		case "eth_getTransactionCount":
+			*(r[i].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+		case "eth_getCode":
+			*(r[i].Result.(**string)) = RosettaTypes.String("0x")
+		case "eth_call":
+			data := r[i].Args[0].(map[string]string)["data"]
+			holder := new(big.Int).SetBytes(hexutil.MustDecode("0x" + data[len(data)-40:]))
+			balance := new(big.Int).Mul(new(big.Int).Sub(holder, big.NewInt(1)), big.NewInt(2))
+			*(r[i].Result.(*string)) = hexutil.EncodeBig(balance)
+		default:
+			assert.Fail(t, "unexpected method "+r[i].Method)
+		}
+	}
+}
+
+func TestBalances(t *testing.T) {
+	var tests = map[string]struct {
+		accounts        []*RosettaTypes.AccountIdentifier
+		failing         map[string]bool
+		partialBalances bool
+
+		expectedBatches int
+		expectedFailed  []int
+		expectedErr     error
+	}{
+		"all accounts": {
+			accounts:        balancesAccounts(5),
+			expectedBatches: 3,
+		},
+		"partial failures": {
+			accounts: append(balancesAccounts(3), &RosettaTypes.AccountIdentifier{
+				Address: "0x2F93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			}),
+			failing: map[string]bool{
+				common.BigToAddress(big.NewInt(2)).Hex(): true,
+			},
+			partialBalances: true,
+			expectedBatches: 2,
+			expectedFailed:  []int{1, 3},
+		},
+		"failure": {
+			accounts: balancesAccounts(3),
+			failing: map[string]bool{
+				common.BigToAddress(big.NewInt(2)).Hex(): true,
+			},
+			expectedBatches: 1,
+			expectedErr:     errors.New("internal error"),
+		},
+		"invalid address": {
+			accounts: append(balancesAccounts(1), &RosettaTypes.AccountIdentifier{
+				Address: "0x2F93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			}),
+			expectedErr: ErrInvalidAddress,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			c := &Client{
+				c:                 mockJSONRPC,
+				g:                 mockGraphQL,
+				currencyFetcher:   cf,
+				traceSemaphore:    semaphore.NewWeighted(100),
+				balancesBatchSize: 2,
+				partialBalances:   test.partialBalances,
+			}
+
+			ctx := context.Background()
+			if errors.Is(test.expectedErr, ErrInvalidAddress) {
+				// Accounts are validated before the block is resolved
+				_, err := c.Balances(ctx, test.accounts, nil)
+				assert.True(t, errors.Is(err, ErrInvalidAddress))
+				mockJSONRPC.AssertExpectations(t)
+				return
+			}
+
+			// The block is resolved once for all accounts
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"latest",
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					// Each account reads its balance, nonce, code and OP token balance
+					return len(rpcs) > 0 && len(rpcs) <= 8 && len(rpcs)%4 == 0
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					for _, req := range args.Get(1).([]rpc.BatchElem) {
+						assert.Equal(t, "0x2af0", req.Args[len(req.Args)-1])
+					}
+					fillBalances(t, args.Get(1).([]rpc.BatchElem), test.failing)
+				},
+			).Times(test.expectedBatches)
+
+			resp, err := c.Balances(ctx, test.accounts, nil)
+			if test.expectedErr != nil {
+				assert.Nil(t, resp)
+				assert.Error(t, err)
+				assert.True(t, strings.Contains(err.Error(), test.expectedErr.Error()))
+				mockJSONRPC.AssertExpectations(t)
+				return
+			}
+
+			failed := map[int]bool{}
+			if len(test.expectedFailed) > 0 {
+				var balancesErr *BalancesError
+				assert.True(t, errors.As(err, &balancesErr))
+				assert.Len(t, balancesErr.Errors, len(test.accounts))
+				for _, i := range test.expectedFailed {
+					failed[i] = true
+					assert.Error(t, balancesErr.Errors[i])
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Responses are aligned with the accounts
+			assert.Len(t, resp, len(test.accounts))
+			for i := range test.accounts {
+				if failed[i] {
+					assert.Nil(t, resp[i])
+					continue
+				}
+				assert.Equal(t, &RosettaTypes.BlockIdentifier{
+					Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+					Index: 10992,
+				}, resp[i].BlockIdentifier)
+				assert.Equal(t, fmt.Sprint(i), resp[i].Balances[0].Value)
+				assert.Equal(t, fmt.Sprint(2*i), resp[i].Balances[1].Value)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBalance_OVMETHAlias(t *testing.T) {
+	ovmETH := &RosettaTypes.Currency{
+		Symbol:   "ETH",
+		Decimals: 18,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: "0xDeadDeAddeAddEAddeadDEaDDEAdDeaDDeAD0000",
+		},
+	}
+
+	var tests = map[string]struct {
+		currencies []*RosettaTypes.Currency
+
+		expectedMetadata map[string]interface{}
+	}{
+		"native and OVM_ETH": {
+			currencies: []*RosettaTypes.Currency{Currency, ovmETH},
+			expectedMetadata: map[string]interface{}{
+				AliasedFromKey:  ovmEthAddr.Hex(),
+				DeduplicatedKey: true,
+			},
+		},
+		"OVM_ETH": {
+			currencies: []*RosettaTypes.Currency{ovmETH},
+			expectedMetadata: map[string]interface{}{
+				AliasedFromKey: ovmEthAddr.Hex(),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: mockCurrencyFetcher,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"latest",
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+					*r = json.RawMessage(file)
+				},
+			).Once()
+
+			// OVM_ETH is served from the native balance, without balanceOf
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 3 && rpcs[0].Method == "eth_getBalance"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					fillBalances(t, args.Get(1).([]rpc.BatchElem), nil)
+				},
+			).Once()
+
+			resp, err := c.Balance(
+				ctx,
+				&RosettaTypes.AccountIdentifier{
+					Address: common.BigToAddress(big.NewInt(6)).Hex(),
+				},
+				nil,
+				test.currencies,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, []*RosettaTypes.Amount{
+				{
+					Value:    "5",
+					Currency: Currency,
+					Metadata: test.expectedMetadata,
+				},
+			}, resp.Balances)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+			mockCurrencyFetcher.AssertExpectations(t)
+		})
+	}
+}
+
+// latencyJSONRPC serves balance requests after a fixed
+// round trip delay, like a remote node.
+type latencyJSONRPC struct {
+	header  json.RawMessage
+	latency time.Duration
+}
+
+func (c *latencyJSONRPC) CallContext(
+	ctx context.Context,
+	result interface{},
+	method string,
+	args ...interface{},
+) error {
+	time.Sleep(c.latency)
+	*(result.(*json.RawMessage)) = c.header
+
+	return nil
+}
+
+func (c *latencyJSONRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	time.Sleep(c.latency)
+	for i := range b {
+		switch b[i].Method {
+		case "eth_getBalance":
+			*(b[i].Result.(**hexutil.Big)) = (*hexutil.Big)(big.NewInt(1))
+		case "eth_getTransactionCount":
+			*(b[i].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+		case "eth_getCode":
+			*(b[i].Result.(**string)) = RosettaTypes.String("0x")
+		case "eth_call":
+			*(b[i].Result.(*string)) = hexutil.EncodeBig(big.NewInt(2))
+		}
+	}
+
+	return nil
+}
+
+func (c *latencyJSONRPC) Close() {}
+
+func benchmarkBalancesClient(b *testing.B) *Client {
+	header, err := ioutil.ReadFile("testdata/block_10992.json")
+	assert.NoError(b, err)
+
+	return &Client{
+		c: &latencyJSONRPC{
+			header:  header,
+			latency: 100 * time.Microsecond,
+		},
+		traceSemaphore: semaphore.NewWeighted(100),
+	}
+}
+
+func BenchmarkBalances(b *testing.B) {
+	c := benchmarkBalancesClient(b)
+	accounts := balancesAccounts(1000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Balances(ctx, accounts, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBalances_Sequential(b *testing.B) {
+	c := benchmarkBalancesClient(b)
+	accounts := balancesAccounts(1000)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, account := range accounts {
+			if _, err := c.Balance(ctx, account, nil, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+)
+
+// BlockNoTraceMethod is the call method that returns the Rosetta
+// block assembled from transactions and receipts only. It skips
+// tracing, so it is much faster than /block but omits the operations
+// of internal calls and value transfers.
+const BlockNoTraceMethod = "block_no_trace"
+
+// BlockNoTraceInput is the input to BlockNoTraceMethod. Without an
+// index or hash, the current block is returned.
+type BlockNoTraceInput struct {
+	Index *int64  `json:"index,omitempty"`
+	Hash  *string `json:"hash,omitempty"`
+}
+
+type noTracesKey struct{}
+
+// withoutTraces marks blocks fetched with the returned context to be
+// assembled without transaction traces.
+func withoutTraces(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noTracesKey{}, true)
+}
+
+// skipTraces reports whether ctx was marked with withoutTraces.
+func skipTraces(ctx context.Context) bool {
+	skip, _ := ctx.Value(noTracesKey{}).(bool)
+	return skip
+}
+
+// blockNoTrace returns the block requested by params without
+// tracing any of its transactions.
+func (ec *Client) blockNoTrace(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input BlockNoTraceInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	block, err := ec.block(withoutTraces(ctx), &RosettaTypes.PartialBlockIdentifier{
+		Index: input.Index,
+		Hash:  input.Hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := RosettaTypes.MarshalMap(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+	}
+
+	return map[string]interface{}{
+		"block": resp,
+	}, nil
+}
+
+// topLevelCall returns the top-level call of tx as known from its
+// body and receipt, for clients with DisableTracing. The sender and
+// receipt of tx must be known. The revert reason of a failed
+// transaction is not known without a trace.
+func topLevelCall(tx *loadedTransaction) *flatCall {
+	call := &flatCall{
+		Type:  CallOpType,
+		From:  *tx.From,
+		Value: tx.Transaction.Value(),
+		Input: hexutil.Encode(tx.Transaction.Data()),
+	}
+	if to := tx.Transaction.To(); to != nil {
+		call.To = *to
+	} else {
+		call.Type = CreateOpType
+		call.To = tx.Receipt.ContractAddress
+	}
+	if tx.Receipt.Status == types.ReceiptStatusFailed {
+		call.Revert = true
+		call.ErrorMessage = "transaction failed"
+	}
+
+	return call
+}
@@ -0,0 +1,251 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestCall_BlockNoTrace(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		currencyFetcher: mockCurrencyFetcher,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		mock.Anything,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x12f063",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_deposit.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		mock.Anything,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt" &&
+				rpcs[0].Args[0] == depositTxHash
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			file, err := ioutil.ReadFile("testdata/tx_receipt_deposit.json")
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
+
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+		Method: BlockNoTraceMethod,
+		Parameters: map[string]interface{}{
+			"index": 1241187,
+		},
+	})
+	assert.NoError(t, err)
+
+	var result struct {
+		Block *RosettaTypes.Block `json:"block"`
+	}
+	assert.NoError(t, RosettaTypes.UnmarshalMap(resp.Result, &result))
+	assert.Equal(t, &RosettaTypes.BlockIdentifier{
+		Index: 1241187,
+		Hash:  "0x5ddc90fb7dca0f218764a9666e1506bbd458a0f8e1cc444aacae3a9a7d6acdeb",
+	}, result.Block.BlockIdentifier)
+	assert.Len(t, result.Block.Transactions, 1)
+
+	// Only the top-level mint is known without the trace
+	tx := result.Block.Transactions[0]
+	assert.Equal(t, depositTxHash, tx.TransactionIdentifier.Hash)
+	assert.Len(t, tx.Operations, 1)
+	assert.Equal(t, MintOpType, tx.Operations[0].Type)
+
+	for _, call := range mockJSONRPC.Calls {
+		if call.Method != "BatchCallContext" {
+			continue
+		}
+		for _, elem := range call.Arguments.Get(1).([]rpc.BatchElem) {
+			assert.NotEqual(t, "debug_traceTransaction", elem.Method)
+		}
+	}
+	mockJSONRPC.AssertExpectations(t)
+	mockCurrencyFetcher.AssertExpectations(t)
+}
+
+func TestCall_BlockNoTrace_InvalidInput(t *testing.T) {
+	c := &Client{}
+
+	resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+		Method: BlockNoTraceMethod,
+		Parameters: map[string]interface{}{
+			"index": "latest",
+		},
+	})
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+}
+
+func TestBlock_DisableTracing(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		currencyFetcher: cf,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+		disableTracing:  true,
+	}
+
+	mockBlock985(t, mockJSONRPC, false)
+	resp, err := c.Block(
+		context.Background(),
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(985),
+		},
+	)
+	assert.NoError(t, err)
+
+	// The contract creation is the top-level call, so the operations
+	// match those of the traced block.
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_985.json")
+	assert.NoError(t, err)
+	var correctResp *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+
+	jsonResp, err := jsonifyBlock(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, correctResp.Block, jsonResp)
+
+	for _, call := range mockJSONRPC.Calls {
+		if call.Method != "BatchCallContext" {
+			continue
+		}
+		for _, elem := range call.Arguments.Get(1).([]rpc.BatchElem) {
+			assert.NotEqual(t, "debug_traceTransaction", elem.Method)
+		}
+	}
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestTopLevelCall(t *testing.T) {
+	from := common.HexToAddress("0x70b17c0fe982ab4a7ac17a4c25485643151a1f2d")
+	to := common.HexToAddress("0x8ce8c13d816fe6daf12d6fd9e4952e1fc88850af")
+	created := common.HexToAddress("0x2f93b2f047e05cdf602820ac4b3178efc2b43d55")
+
+	tests := map[string]struct {
+		tx     *types.Transaction
+		status uint64
+
+		expected *flatCall
+	}{
+		"transfer": {
+			tx:     types.NewTransaction(0, to, big.NewInt(100), 21000, big.NewInt(1), nil),
+			status: types.ReceiptStatusSuccessful,
+			expected: &flatCall{
+				Type:  CallOpType,
+				From:  from,
+				To:    to,
+				Value: big.NewInt(100),
+				Input: "0x",
+			},
+		},
+		"failed transfer": {
+			tx:     types.NewTransaction(0, to, big.NewInt(100), 21000, big.NewInt(1), []byte{0x01}),
+			status: types.ReceiptStatusFailed,
+			expected: &flatCall{
+				Type:         CallOpType,
+				From:         from,
+				To:           to,
+				Value:        big.NewInt(100),
+				Input:        "0x01",
+				Revert:       true,
+				ErrorMessage: "transaction failed",
+			},
+		},
+		"contract creation": {
+			tx:     types.NewContractCreation(0, big.NewInt(0), 100000, big.NewInt(1), []byte{0x60}),
+			status: types.ReceiptStatusSuccessful,
+			expected: &flatCall{
+				Type:  CreateOpType,
+				From:  from,
+				To:    created,
+				Value: big.NewInt(0),
+				Input: "0x60",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := &loadedTransaction{
+				Transaction: test.tx,
+				From:        &from,
+				Receipt: &types.Receipt{
+					Status:          test.status,
+					ContractAddress: created,
+				},
+			}
+
+			assert.Equal(t, test.expected, topLevelCall(tx))
+		})
+	}
+}
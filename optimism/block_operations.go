@@ -0,0 +1,114 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// BlockOperationsMethod is the call method that returns the Rosetta
+// block with only the operations of the requested types, e.g. only
+// FEE and MINT operations. Operations keep the indices they have in
+// /block, so they can be matched with a full block.
+const BlockOperationsMethod = "block_operations"
+
+// BlockOperationsInput is the input to BlockOperationsMethod. Without
+// an index or hash, the current block is returned. OperationTypes must
+// be a non-empty subset of OperationTypes.
+type BlockOperationsInput struct {
+	Index          *int64   `json:"index,omitempty"`
+	Hash           *string  `json:"hash,omitempty"`
+	OperationTypes []string `json:"operation_types"`
+}
+
+// blockOperations returns the block requested by params with its
+// operations filtered to the requested types.
+func (ec *Client) blockOperations(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input BlockOperationsInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	wanted, err := operationTypeSet(input.OperationTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := ec.block(ctx, &RosettaTypes.PartialBlockIdentifier{
+		Index: input.Index,
+		Hash:  input.Hash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tx := range block.Transactions {
+		tx.Operations = filterOperations(tx.Operations, wanted)
+	}
+
+	resp, err := RosettaTypes.MarshalMap(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+	}
+
+	return map[string]interface{}{
+		"block": resp,
+	}, nil
+}
+
+// operationTypeSet validates opTypes against OperationTypes
+// and returns them as a set.
+func operationTypeSet(opTypes []string) (map[string]bool, error) {
+	if len(opTypes) == 0 {
+		return nil, fmt.Errorf("%w: operation_types missing from params", ErrCallParametersInvalid)
+	}
+
+	supported := make(map[string]bool, len(OperationTypes))
+	for _, opType := range OperationTypes {
+		supported[opType] = true
+	}
+
+	wanted := make(map[string]bool, len(opTypes))
+	for _, opType := range opTypes {
+		if !supported[opType] {
+			return nil, fmt.Errorf("%w: unsupported operation type %s", ErrCallParametersInvalid, opType)
+		}
+		wanted[opType] = true
+	}
+
+	return wanted, nil
+}
+
+// filterOperations returns the operations of ops whose type is in
+// wanted, keeping their operation identifiers.
+func filterOperations(
+	ops []*RosettaTypes.Operation,
+	wanted map[string]bool,
+) []*RosettaTypes.Operation {
+	filtered := []*RosettaTypes.Operation{}
+	for _, op := range ops {
+		if wanted[op.Type] {
+			filtered = append(filtered, op)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,198 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+// mockBlock985 mocks the requests to populate block 985,
+// which has a single contract creation. The transaction
+// trace is only mocked if traced is set.
+func mockBlock985(t *testing.T, mockJSONRPC *mocks.JSONRPC, traced bool) {
+	mockJSONRPC.On(
+		"CallContext",
+		mock.Anything,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x3d9",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			file, err := ioutil.ReadFile("testdata/block_985.json")
+			assert.NoError(t, err)
+
+			*args.Get(1).(*json.RawMessage) = json.RawMessage(file)
+		},
+	).Once()
+	if traced {
+		mockJSONRPC.On(
+			"BatchCallContext",
+			mock.Anything,
+			mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+				return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+			}),
+		).Return(
+			nil,
+		).Run(
+			func(args mock.Arguments) {
+				r := args.Get(1).([]rpc.BatchElem)
+
+				file, err := ioutil.ReadFile("testdata/tx_trace_985.json")
+				assert.NoError(t, err)
+
+				call := new(Call)
+				assert.NoError(t, call.UnmarshalJSON(file))
+				*(r[0].Result.(**Call)) = call
+			},
+		).Once()
+	}
+	mockJSONRPC.On(
+		"BatchCallContext",
+		mock.Anything,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_0x9ed8f713b2cc6439657db52dcd2fdb9cc944915428f3c6e2a7703e242b259cb9.json",
+			) // nolint
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
+}
+
+func TestCall_BlockOperations(t *testing.T) {
+	tests := map[string]struct {
+		operationTypes []string
+
+		expectedIndices []int64
+	}{
+		"fee": {
+			operationTypes:  []string{FeeOpType},
+			expectedIndices: []int64{0, 1},
+		},
+		"create": {
+			operationTypes:  []string{CreateOpType},
+			expectedIndices: []int64{2, 3},
+		},
+		"fee and mint": {
+			operationTypes:  []string{FeeOpType, MintOpType},
+			expectedIndices: []int64{0, 1},
+		},
+		"no match": {
+			operationTypes:  []string{PaymentOpType},
+			expectedIndices: []int64{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			tc, err := testTraceConfig()
+			assert.NoError(t, err)
+			c := &Client{
+				c:               mockJSONRPC,
+				currencyFetcher: cf,
+				tc:              tc,
+				p:               params.GoerliChainConfig,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			mockBlock985(t, mockJSONRPC, true)
+			resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+				Method: BlockOperationsMethod,
+				Parameters: map[string]interface{}{
+					"index":           985,
+					"operation_types": test.operationTypes,
+				},
+			})
+			assert.NoError(t, err)
+
+			var result struct {
+				Block *RosettaTypes.Block `json:"block"`
+			}
+			assert.NoError(t, RosettaTypes.UnmarshalMap(resp.Result, &result))
+			assert.Equal(t, int64(985), result.Block.BlockIdentifier.Index)
+			assert.Len(t, result.Block.Transactions, 1)
+
+			indices := []int64{}
+			for _, op := range result.Block.Transactions[0].Operations {
+				assert.Contains(t, test.operationTypes, op.Type)
+				indices = append(indices, op.OperationIdentifier.Index)
+			}
+			assert.Equal(t, test.expectedIndices, indices)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_BlockOperations_InvalidInput(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"missing types": {
+			"index": 985,
+		},
+		"unsupported type": {
+			"index":           985,
+			"operation_types": []string{FeeOpType, "DEPOSIT"},
+		},
+		"invalid index": {
+			"index":           "latest",
+			"operation_types": []string{FeeOpType},
+		},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{}
+
+			resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+				Method:     BlockOperationsMethod,
+				Parameters: params,
+			})
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// methodNotFoundCode is the JSON-RPC error code returned
+// for methods the node does not expose.
+const methodNotFoundCode = -32601
+
+// GetBlockReceipts returns the receipts of all transactions in the
+// block with blockHash, ordered by transaction index, using a single
+// eth_getBlockReceipts call.
+func (ec *Client) GetBlockReceipts(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	d, err := ec.nodeDialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var receipts []*types.Receipt
+	if err := ec.c.CallContext(ctx, d.receiptsResult(&receipts), "eth_getBlockReceipts", blockHash.Hex()); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(receipts, func(i, j int) bool {
+		return receipts[i].TransactionIndex < receipts[j].TransactionIndex
+	})
+
+	return receipts, nil
+}
+
+// getReceiptsByBlock returns the receipts of the block with blockHash
+// from eth_getBlockReceipts, or nil if the client does not prefer it
+// or the node does not expose it.
+func (ec *Client) getReceiptsByBlock(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	if atomic.LoadUint32(&ec.preferBlockReceipts) == 0 {
+		return nil, nil
+	}
+
+	receipts, err := ec.GetBlockReceipts(ctx, blockHash)
+	if err != nil {
+		if isMethodNotFound(err) {
+			log.Printf("eth_getBlockReceipts is not available, fetching receipts by transaction: %v", err)
+			atomic.StoreUint32(&ec.preferBlockReceipts, 0)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+// isMethodNotFound returns true if err is the node
+// rejecting a method it does not expose.
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode {
+		return true
+	}
+
+	return strings.Contains(err.Error(), "does not exist/is not available")
+}
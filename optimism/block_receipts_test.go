@@ -0,0 +1,168 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestBlock_BlockReceipts(t *testing.T) {
+	var tests = map[string]struct {
+		preferBlockReceipts uint32
+		blockReceiptsErr    error
+
+		expectedPreferBlockReceipts uint32
+	}{
+		"batched": {},
+		"single call": {
+			preferBlockReceipts:         1,
+			expectedPreferBlockReceipts: 1,
+		},
+		"single call not available": {
+			preferBlockReceipts: 1,
+			blockReceiptsErr:    errors.New("the method eth_getBlockReceipts does not exist/is not available"),
+		},
+	}
+
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
+	assert.NoError(t, err)
+	var correct *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			tc, err := testTraceConfig()
+			assert.NoError(t, err)
+			c := &Client{
+				c:                   mockJSONRPC,
+				g:                   mockGraphQL,
+				currencyFetcher:     cf,
+				tc:                  tc,
+				p:                   params.GoerliChainConfig,
+				traceSemaphore:      semaphore.NewWeighted(100),
+				preferBlockReceipts: test.preferBlockReceipts,
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"0x1",
+				true,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+					file, err := ioutil.ReadFile("testdata/block_1.json")
+					assert.NoError(t, err)
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					file, err := ioutil.ReadFile("testdata/tx_trace_1.json")
+					assert.NoError(t, err)
+					call := new(Call)
+					assert.NoError(t, call.UnmarshalJSON(file))
+					*(r[0].Result.(**Call)) = call
+				},
+			).Once()
+
+			receiptFile, err := ioutil.ReadFile("testdata/tx_receipt_1.json")
+			assert.NoError(t, err)
+			if test.preferBlockReceipts == 1 {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"eth_getBlockReceipts",
+					"0xbee7192e575af30420cae0c7776304ac196077ee72b048970549e4f08e875453",
+				).Return(
+					test.blockReceiptsErr,
+				).Run(
+					func(args mock.Arguments) {
+						if test.blockReceiptsErr != nil {
+							return
+						}
+
+						receipt := new(types.Receipt)
+						assert.NoError(t, receipt.UnmarshalJSON(receiptFile))
+						*(args.Get(1).(*[]*types.Receipt)) = []*types.Receipt{receipt}
+					},
+				).Once()
+			}
+			if test.expectedPreferBlockReceipts == 0 {
+				mockJSONRPC.On(
+					"BatchCallContext",
+					ctx,
+					mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+						return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+					}),
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						r := args.Get(1).([]rpc.BatchElem)
+						receipt := new(types.Receipt)
+						assert.NoError(t, receipt.UnmarshalJSON(receiptFile))
+						*(r[0].Result.(**types.Receipt)) = receipt
+					},
+				).Once()
+			}
+
+			// Both paths produce the same operations
+			resp, err := c.Block(ctx, &RosettaTypes.PartialBlockIdentifier{
+				Index: RosettaTypes.Int64(1),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, correct.Block, resp)
+			assert.Equal(t, test.expectedPreferBlockReceipts, c.preferBlockReceipts)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
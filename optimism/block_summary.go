@@ -0,0 +1,54 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// BlockSummary is the size of a block, without its operations.
+type BlockSummary struct {
+	BlockIdentifier  *RosettaTypes.BlockIdentifier `json:"block_identifier"`
+	Timestamp        int64                         `json:"timestamp"`
+	TransactionCount int                           `json:"transaction_count"`
+	OperationCount   int                           `json:"operation_count"`
+}
+
+// BlockSummary returns the number of transactions and operations in
+// the block identified by blockIdentifier. The operations are built
+// exactly as in Block, so the counts match, but only the counts are
+// returned.
+func (ec *Client) BlockSummary(
+	ctx context.Context,
+	blockIdentifier *RosettaTypes.PartialBlockIdentifier,
+) (*BlockSummary, error) {
+	block, err := ec.Block(ctx, blockIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &BlockSummary{
+		BlockIdentifier:  block.BlockIdentifier,
+		Timestamp:        block.Timestamp,
+		TransactionCount: len(block.Transactions),
+	}
+	for _, tx := range block.Transactions {
+		summary.OperationCount += len(tx.Operations)
+	}
+
+	return summary, nil
+}
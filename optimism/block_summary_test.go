@@ -0,0 +1,73 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestBlockSummary(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		currencyFetcher: mockCurrencyFetcher,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockDepositBlock(ctx, t, mockJSONRPC)
+
+	summary, err := c.BlockSummary(ctx, &RosettaTypes.PartialBlockIdentifier{
+		Index: RosettaTypes.Int64(1241187),
+	})
+	assert.NoError(t, err)
+
+	// The deposit mints ETH and moves it with a single call
+	assert.Equal(t, &BlockSummary{
+		BlockIdentifier: &RosettaTypes.BlockIdentifier{
+			Index: 1241187,
+			Hash:  "0x5ddc90fb7dca0f218764a9666e1506bbd458a0f8e1cc444aacae3a9a7d6acdeb",
+		},
+		Timestamp:        1645628960000,
+		TransactionCount: 1,
+		OperationCount:   3,
+	}, summary)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockCurrencyFetcher.AssertExpectations(t)
+}
+
+func TestBlockSummary_Closed(t *testing.T) {
+	c := &Client{closed: 1}
+
+	summary, err := c.BlockSummary(context.Background(), nil)
+	assert.Nil(t, summary)
+	assert.True(t, errors.Is(err, ErrClientClosed))
+}
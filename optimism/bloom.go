@@ -0,0 +1,114 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"expvar"
+	"fmt"
+	"log"
+
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+)
+
+const (
+	// BloomMismatchKey is the block metadata key set when the
+	// logsBloom of the block or its receipts does not match
+	// the logs returned by the node.
+	BloomMismatchKey = "bloom_mismatch"
+)
+
+// BloomCheck determines how receipts' logsBloom are verified.
+type BloomCheck string
+
+const (
+	// BloomCheckWarn flags blocks with inconsistent blooms in
+	// their metadata. This is the default.
+	BloomCheckWarn BloomCheck = "warn"
+
+	// BloomCheckFail fails to parse blocks with inconsistent blooms.
+	BloomCheckFail BloomCheck = "fail"
+
+	// BloomCheckDisabled skips recomputing blooms.
+	BloomCheckDisabled BloomCheck = "disabled"
+)
+
+// bloomMismatches counts the blocks found with inconsistent
+// blooms, published under /debug/vars.
+var bloomMismatches = expvar.NewInt(BloomMismatchKey)
+
+// checkBlooms recomputes the bloom of each receipt from its logs and
+// the bloom of the header from the receipts, returning an error
+// describing the first inconsistency found. The header bloom is not
+// checked if any receipt is missing.
+func checkBlooms(head *types.Header, receipts []*types.Receipt) error {
+	complete := true
+	for _, receipt := range receipts {
+		if receipt == nil {
+			complete = false
+			continue
+		}
+
+		if err := checkReceiptBloom(receipt); err != nil {
+			return err
+		}
+	}
+
+	if complete && types.CreateBloom(receipts) != head.Bloom {
+		return fmt.Errorf("logsBloom of block %d does not match its receipts", head.Number.Uint64())
+	}
+
+	return nil
+}
+
+// checkReceiptBloom recomputes the bloom of receipt from its logs,
+// returning an error if it does not match its logsBloom.
+func checkReceiptBloom(receipt *types.Receipt) error {
+	bloom := types.BytesToBloom(types.LogsBloom(receipt.Logs).Bytes())
+	if bloom != receipt.Bloom {
+		return fmt.Errorf("logsBloom of receipt %s does not match its logs", receipt.TxHash.Hex())
+	}
+
+	return nil
+}
+
+// WithBloomValidation makes receipts whose logsBloom does not match
+// their logs fail with ErrBloomMismatch, as a sign of a malformed or
+// tampered receipt. It sets BloomCheckFail, so it applies to blocks
+// and to the receipts returned by the eth_getTransactionReceipt call
+// method.
+func (ec *Client) WithBloomValidation() *Client {
+	ec.bloomCheck = BloomCheckFail
+	return ec
+}
+
+// receiptBloomMismatch applies the bloom check of the client to a
+// receipt returned on its own. It returns true if the mismatch must
+// be flagged, and ErrBloomMismatch if it must fail instead.
+func (ec *Client) receiptBloomMismatch(receipt *types.Receipt) (bool, error) {
+	if ec.bloomCheck == BloomCheckDisabled {
+		return false, nil
+	}
+
+	if err := checkReceiptBloom(receipt); err != nil {
+		if ec.bloomCheck == BloomCheckFail {
+			return false, fmt.Errorf("%w: %s", ErrBloomMismatch, err.Error())
+		}
+		log.Printf("receipt may have been tampered: %v", err)
+		bloomMismatches.Add(1)
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckBlooms(t *testing.T) {
+	var tests = map[string]struct {
+		receipt     string
+		clearHeader bool
+
+		err bool
+	}{
+		"consistent": {
+			receipt: "testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
+		},
+		"tampered log": {
+			receipt: "testdata/tx_receipt_tampered_log.json",
+			err:     true,
+		},
+		"tampered header": {
+			receipt:     "testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
+			clearHeader: true,
+			err:         true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, err := ioutil.ReadFile("testdata/block_1241186.json")
+			assert.NoError(t, err)
+			var head types.Header
+			assert.NoError(t, json.Unmarshal(file, &head))
+			if test.clearHeader {
+				head.Bloom = types.Bloom{}
+			}
+
+			file, err = ioutil.ReadFile(test.receipt)
+			assert.NoError(t, err)
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+
+			err = checkBlooms(&head, []*types.Receipt{receipt})
+			if test.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithBloomValidation(t *testing.T) {
+	var tests = map[string]struct {
+		receipt    string
+		validation bool
+
+		mismatch bool
+		err      bool
+	}{
+		"consistent": {
+			receipt:    "testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
+			validation: true,
+		},
+		"tampered log": {
+			receipt:    "testdata/tx_receipt_tampered_log.json",
+			validation: true,
+			err:        true,
+		},
+		"tampered log without validation": {
+			receipt:  "testdata/tx_receipt_tampered_log.json",
+			mismatch: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC, bloomCheck: BloomCheckWarn}
+			if test.validation {
+				c = c.WithBloomValidation()
+			}
+
+			ctx := context.Background()
+			hash := common.HexToHash("0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009")
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getTransactionReceipt",
+				hash,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(**types.Receipt)
+
+					file, err := ioutil.ReadFile(test.receipt)
+					assert.NoError(t, err)
+
+					*r = new(types.Receipt)
+					assert.NoError(t, (*r).UnmarshalJSON(file))
+				},
+			).Once()
+
+			resp, err := c.Call(
+				ctx,
+				&RosettaTypes.CallRequest{
+					Method: "eth_getTransactionReceipt",
+					Parameters: map[string]interface{}{
+						"tx_hash": hash.Hex(),
+					},
+				},
+			)
+			if test.err {
+				assert.ErrorIs(t, err, ErrBloomMismatch)
+				assert.Nil(t, resp)
+			} else {
+				assert.NoError(t, err)
+				_, ok := resp.Result[BloomMismatchKey]
+				assert.Equal(t, test.mismatch, ok)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
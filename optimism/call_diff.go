@@ -0,0 +1,94 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// CallDiffMethod is the call method that performs the same eth_call
+// at two blocks and reports whether its result changed between them.
+// It accepts the parameters of eth_call, without index or hash, and
+// a CallDiffInput.
+const CallDiffMethod = "eth_call_diff"
+
+// CallDiffInput holds the blocks compared by CallDiffMethod.
+type CallDiffInput struct {
+	Before *RosettaTypes.PartialBlockIdentifier `json:"before"`
+	After  *RosettaTypes.PartialBlockIdentifier `json:"after"`
+}
+
+// callDiff performs the eth_call in params at the before and after
+// blocks and returns both results.
+func (ec *Client) callDiff(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input CallDiffInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	if !callDiffBlockValid(input.Before) || !callDiffBlockValid(input.After) {
+		return nil, fmt.Errorf("%w: before and after blocks are required", ErrCallParametersInvalid)
+	}
+
+	before, err := ec.callAt(ctx, params, input.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := ec.callAt(ctx, params, input.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"before":  before,
+		"after":   after,
+		"changed": !strings.EqualFold(before, after),
+	}, nil
+}
+
+func callDiffBlockValid(block *RosettaTypes.PartialBlockIdentifier) bool {
+	return block != nil && (block.Index != nil || block.Hash != nil)
+}
+
+// callAt performs the eth_call in params at block, ignoring any block
+// already set in params.
+func (ec *Client) callAt(
+	ctx context.Context,
+	params map[string]interface{},
+	block *RosettaTypes.PartialBlockIdentifier,
+) (string, error) {
+	callParams := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		switch k {
+		case "before", "after", "index", "hash":
+		default:
+			callParams[k] = v
+		}
+	}
+	setCallBlock(callParams, block)
+
+	resp, err := ec.contractCall(ctx, callParams)
+	if err != nil {
+		return "", err
+	}
+
+	return resp["data"].(string), nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCall_CallDiff(t *testing.T) {
+	const (
+		balance    = "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000"
+		newBalance = "0x0000000000000000000000000000000000000000000000001bc16d674ec80000"
+	)
+
+	tests := map[string]struct {
+		before string
+		after  string
+
+		expectedChanged bool
+	}{
+		"changed": {
+			before:          balance,
+			after:           newBalance,
+			expectedChanged: true,
+		},
+		"unchanged": {
+			before: balance,
+			after:  balance,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+			ctx := context.Background()
+
+			callParams := map[string]string{
+				"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+				"data": "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+			}
+			results := map[string]string{
+				"0x2af0": test.before,
+				"0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae": test.after,
+			}
+			for query, result := range results {
+				result := result
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"eth_call",
+					callParams,
+					query,
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						*args.Get(1).(*string) = result
+					},
+				).Once()
+			}
+
+			resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+				Method: CallDiffMethod,
+				Parameters: map[string]interface{}{
+					"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+					"data": "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+					"before": map[string]interface{}{
+						"index": float64(10992),
+					},
+					"after": map[string]interface{}{
+						"hash": "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+					},
+				},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, &RosettaTypes.CallResponse{
+				Result: map[string]interface{}{
+					"before":  test.before,
+					"after":   test.after,
+					"changed": test.expectedChanged,
+				},
+			}, resp)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_CallDiffMissingBlock(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+
+	resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+		Method: CallDiffMethod,
+		Parameters: map[string]interface{}{
+			"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+			"data": "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+			"before": map[string]interface{}{
+				"index": float64(10992),
+			},
+		},
+	})
+	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+	assert.Nil(t, resp)
+	mockJSONRPC.AssertExpectations(t)
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+)
+
+// quantityPrecision is the precision used to parse quantities given
+// as floating point numbers, which is how JSON numbers are decoded.
+const quantityPrecision = 256
+
+// parseCallQuantity parses a non-negative integer parameter of the
+// call methods, such as a gas limit or a value in wei, and returns it
+// hex encoded. It may be given as a number, a decimal string or a
+// 0x-prefixed hex string.
+func parseCallQuantity(name string, v interface{}) (string, error) {
+	var value *big.Int
+	switch q := v.(type) {
+	case string:
+		if strings.HasPrefix(q, "0x") {
+			decoded, err := hexutil.DecodeBig(q)
+			if err != nil {
+				return "", fmt.Errorf("%w: %s %q is not a hex quantity", ErrCallParametersInvalid, name, q)
+			}
+			value = decoded
+			break
+		}
+
+		decoded, ok := new(big.Int).SetString(q, 10) // nolint:gomnd
+		if !ok {
+			return "", fmt.Errorf("%w: %s %q is not a quantity", ErrCallParametersInvalid, name, q)
+		}
+		value = decoded
+	case float64:
+		f := new(big.Float).SetPrec(quantityPrecision).SetFloat64(q)
+		if !f.IsInt() {
+			return "", fmt.Errorf("%w: %s %v is not an integer", ErrCallParametersInvalid, name, q)
+		}
+		value, _ = f.Int(nil)
+	case int:
+		value = big.NewInt(int64(q))
+	case int64:
+		value = big.NewInt(q)
+	default:
+		return "", fmt.Errorf("%w: %s has unsupported type %T", ErrCallParametersInvalid, name, v)
+	}
+
+	if value.Sign() < 0 {
+		return "", fmt.Errorf("%w: %s %s is negative", ErrCallParametersInvalid, name, value)
+	}
+
+	return hexutil.EncodeBig(value), nil
+}
+
+// addCallQuantities adds the optional gas, gas price and value of
+// input to the call object params.
+func addCallQuantities(params map[string]string, input *GetCallInput) error {
+	quantities := []struct {
+		name  string
+		key   string
+		value interface{}
+	}{
+		{name: "gas", key: "gas", value: input.Gas},
+		{name: "gas_price", key: "gasPrice", value: input.GasPrice},
+		{name: "value", key: "value", value: input.Value},
+	}
+	for _, q := range quantities {
+		if q.value == nil {
+			continue
+		}
+
+		encoded, err := parseCallQuantity(q.name, q.value)
+		if err != nil {
+			return err
+		}
+		params[q.key] = encoded
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCallQuantity(t *testing.T) {
+	tests := map[string]struct {
+		input interface{}
+
+		expected    string
+		expectedErr error
+	}{
+		"float":           {input: float64(21000), expected: "0x5208"},
+		"large float":     {input: float64(1e18), expected: "0xde0b6b3a7640000"},
+		"int":             {input: 21000, expected: "0x5208"},
+		"int64":           {input: int64(21000), expected: "0x5208"},
+		"decimal string":  {input: "21000", expected: "0x5208"},
+		"hex string":      {input: "0x5208", expected: "0x5208"},
+		"zero":            {input: "0x0", expected: "0x0"},
+		"empty string":    {input: "", expectedErr: ErrCallParametersInvalid},
+		"invalid hex":     {input: "0xzz", expectedErr: ErrCallParametersInvalid},
+		"leading zero":    {input: "0x05", expectedErr: ErrCallParametersInvalid},
+		"negative":        {input: -1, expectedErr: ErrCallParametersInvalid},
+		"negative string": {input: "-21000", expectedErr: ErrCallParametersInvalid},
+		"fraction":        {input: 1.5, expectedErr: ErrCallParametersInvalid},
+		"not a number":    {input: "gas", expectedErr: ErrCallParametersInvalid},
+		"bool":            {input: true, expectedErr: ErrCallParametersInvalid},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			q, err := parseCallQuantity("gas", test.input)
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, q)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// Capability is a feature of the node that Client relies on.
+type Capability string
+
+const (
+	// CapabilityBlockByNumber is serving blocks with eth_getBlockByNumber.
+	CapabilityBlockByNumber Capability = "eth_getBlockByNumber"
+
+	// CapabilityBatchCalls is serving JSON-RPC batch requests.
+	CapabilityBatchCalls Capability = "batch_calls"
+
+	// CapabilityTraceTransaction is tracing transactions with
+	// debug_traceTransaction, or the configured TraceProvider.
+	CapabilityTraceTransaction Capability = "debug_traceTransaction"
+
+	// CapabilityGraphQL is serving GraphQL queries. It is only
+	// checked if the client has a GraphQL endpoint.
+	CapabilityGraphQL Capability = "graphql"
+)
+
+// traceProbeBlocks is the number of blocks, back from the latest,
+// searched for a transaction to trace.
+const traceProbeBlocks = 16
+
+// CapabilityStatus is whether a Capability is available and,
+// if it is not, why. Unverified capabilities could not be checked,
+// e.g. because there was nothing to trace.
+type CapabilityStatus struct {
+	Available  bool   `json:"available"`
+	Unverified bool   `json:"unverified,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// CapabilityReport is the status of each Capability checked.
+type CapabilityReport map[Capability]*CapabilityStatus
+
+// Available returns true if capability was checked and is available.
+func (r CapabilityReport) Available(capability Capability) bool {
+	status, ok := r[capability]
+	return ok && status.Available
+}
+
+func (r CapabilityReport) set(capability Capability, err error) {
+	if err != nil {
+		r[capability] = &CapabilityStatus{Reason: err.Error()}
+		return
+	}
+
+	r[capability] = &CapabilityStatus{Available: true}
+}
+
+func (r CapabilityReport) unverified(capability Capability, reason string) {
+	r[capability] = &CapabilityStatus{Unverified: true, Reason: reason}
+}
+
+type probeBlock struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Transactions []common.Hash  `json:"transactions"`
+}
+
+// VerifyCapabilities checks that the node serves the methods Client
+// relies on, so a node (or provider) that does not can be caught at
+// startup rather than at the first block that needs it. Tracing is
+// verified by tracing the first transaction of the latest block that
+// has one. Each capability is checked independently.
+func (ec *Client) VerifyCapabilities(ctx context.Context) (CapabilityReport, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	report := CapabilityReport{}
+
+	txHash, err := ec.probeTransaction(ctx)
+	report.set(CapabilityBlockByNumber, err)
+
+	report.set(CapabilityBatchCalls, ec.probeBatchCalls(ctx))
+
+	switch {
+	case err != nil:
+		report.unverified(CapabilityTraceTransaction, "no block to find a transaction to trace in")
+	case txHash == nil:
+		report.unverified(
+			CapabilityTraceTransaction,
+			fmt.Sprintf("no transaction to trace in the last %d blocks", traceProbeBlocks),
+		)
+	default:
+		_, err := ec.tracer().TraceTransaction(ctx, *txHash)
+		report.set(CapabilityTraceTransaction, err)
+	}
+
+	if ec.g != nil {
+		_, err := ec.g.Query(ctx, "{ block { number } }", nil)
+		report.set(CapabilityGraphQL, err)
+	}
+
+	return report, nil
+}
+
+// probeTransaction returns the hash of the first transaction of the
+// latest of the last traceProbeBlocks blocks with one, or nil if
+// they have none.
+func (ec *Client) probeTransaction(ctx context.Context) (*common.Hash, error) {
+	var block *probeBlock
+	if err := ec.c.CallContext(ctx, &block, "eth_getBlockByNumber", toBlockNumArg(nil), false); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("latest block not found")
+	}
+
+	for i := 0; ; i++ {
+		if len(block.Transactions) > 0 {
+			return &block.Transactions[0], nil
+		}
+		if i == traceProbeBlocks-1 || block.Number == 0 {
+			return nil, nil
+		}
+
+		number := hexutil.EncodeUint64(uint64(block.Number) - 1)
+		if err := ec.c.CallContext(ctx, &block, "eth_getBlockByNumber", number, false); err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %s not found", number)
+		}
+	}
+}
+
+// probeBatchCalls makes a batch request of two calls, both of
+// which must succeed.
+func (ec *Client) probeBatchCalls(ctx context.Context) error {
+	var (
+		blockNumber hexutil.Uint64
+		chainID     hexutil.Big
+	)
+	reqs := []rpc.BatchElem{
+		{Method: "eth_blockNumber", Result: &blockNumber},
+		{Method: "eth_chainId", Result: &chainID},
+	}
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return err
+	}
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			return fmt.Errorf("%s: %w", reqs[i].Method, reqs[i].Error)
+		}
+	}
+
+	return nil
+}
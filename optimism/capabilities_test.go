@@ -0,0 +1,218 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const probeTxHash = "0xb358c6958b1cab722752939cbb92e3fec6b6023de360305910ce80c56c3dad9d"
+
+func mockProbeBlock(ctx context.Context, mockJSONRPC *mocks.JSONRPC, number string, block string, err error) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		number,
+		false,
+	).Return(
+		err,
+	).Run(
+		func(args mock.Arguments) {
+			if err == nil {
+				_ = json.Unmarshal([]byte(block), args.Get(1))
+			}
+		},
+	).Once()
+}
+
+func TestVerifyCapabilities(t *testing.T) {
+	blockErr := errors.New("connection refused")
+	traceErr := &jsonError{
+		code:    methodNotFoundCode,
+		message: "the method debug_traceTransaction does not exist/is not available",
+	}
+	batchErr := errors.New("batch requests are not supported")
+	graphQLErr := errors.New("404 page not found")
+
+	var tests = map[string]struct {
+		blockErr   error
+		batchErr   error
+		traceErr   error
+		graphQLErr error
+
+		expected CapabilityReport
+	}{
+		"all available": {
+			expected: CapabilityReport{
+				CapabilityBlockByNumber:    {Available: true},
+				CapabilityBatchCalls:       {Available: true},
+				CapabilityTraceTransaction: {Available: true},
+				CapabilityGraphQL:          {Available: true},
+			},
+		},
+		"blocks unavailable": {
+			blockErr: blockErr,
+			expected: CapabilityReport{
+				CapabilityBlockByNumber: {Reason: blockErr.Error()},
+				CapabilityBatchCalls:    {Available: true},
+				CapabilityTraceTransaction: {
+					Unverified: true,
+					Reason:     "no block to find a transaction to trace in",
+				},
+				CapabilityGraphQL: {Available: true},
+			},
+		},
+		"batch calls unavailable": {
+			batchErr: batchErr,
+			expected: CapabilityReport{
+				CapabilityBlockByNumber:    {Available: true},
+				CapabilityBatchCalls:       {Reason: batchErr.Error()},
+				CapabilityTraceTransaction: {Available: true},
+				CapabilityGraphQL:          {Available: true},
+			},
+		},
+		"tracing unavailable": {
+			traceErr: traceErr,
+			expected: CapabilityReport{
+				CapabilityBlockByNumber:    {Available: true},
+				CapabilityBatchCalls:       {Available: true},
+				CapabilityTraceTransaction: {Reason: traceErr.Error()},
+				CapabilityGraphQL:          {Available: true},
+			},
+		},
+		"graphql unavailable": {
+			graphQLErr: graphQLErr,
+			expected: CapabilityReport{
+				CapabilityBlockByNumber:    {Available: true},
+				CapabilityBatchCalls:       {Available: true},
+				CapabilityTraceTransaction: {Available: true},
+				CapabilityGraphQL:          {Reason: graphQLErr.Error()},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			tc, err := testTraceConfig()
+			assert.NoError(t, err)
+			c := &Client{c: mockJSONRPC, g: mockGraphQL, tc: tc}
+			ctx := context.Background()
+
+			mockProbeBlock(ctx, mockJSONRPC, "latest", `{"number":"0x2af0","transactions":["`+probeTxHash+`"]}`, test.blockErr)
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(reqs []rpc.BatchElem) bool {
+					return len(reqs) == 2 && reqs[0].Method == "eth_blockNumber" && reqs[1].Method == "eth_chainId"
+				}),
+			).Return(
+				test.batchErr,
+			).Run(
+				func(args mock.Arguments) {
+					reqs := args.Get(1).([]rpc.BatchElem)
+					assert.NoError(t, json.Unmarshal([]byte(`"0x2af0"`), reqs[0].Result))
+					assert.NoError(t, json.Unmarshal([]byte(`"0xa"`), reqs[1].Result))
+				},
+			).Once()
+			if test.blockErr == nil {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"debug_traceTransaction",
+					probeTxHash,
+					tc,
+				).Return(
+					test.traceErr,
+				).Run(
+					func(args mock.Arguments) {
+						if test.traceErr == nil {
+							assert.NoError(t, json.Unmarshal([]byte(`{"type":"CALL","value":"0x0"}`), args.Get(1)))
+						}
+					},
+				).Once()
+			}
+			mockGraphQL.On(
+				"Query",
+				ctx,
+				"{ block { number } }",
+				mock.Anything,
+			).Return(
+				`{"data":{"block":{"number":10992}}}`,
+				test.graphQLErr,
+			).Once()
+
+			report, err := c.VerifyCapabilities(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, report)
+			assert.Equal(t, test.expected[CapabilityTraceTransaction].Available, report.Available(CapabilityTraceTransaction))
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestVerifyCapabilities_EmptyBlocks(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{
+		c: mockJSONRPC,
+		traceProvider: &fakeTraceProvider{
+			traces: map[common.Hash]*Call{common.HexToHash(probeTxHash): {Type: CallOpType}},
+		},
+	}
+	ctx := context.Background()
+
+	mockProbeBlock(ctx, mockJSONRPC, "latest", `{"number":"0x2","transactions":[]}`, nil)
+	mockProbeBlock(ctx, mockJSONRPC, "0x1", `{"number":"0x1","transactions":["`+probeTxHash+`"]}`, nil)
+	mockJSONRPC.On("BatchCallContext", ctx, mock.Anything).Return(nil).Once()
+
+	report, err := c.VerifyCapabilities(ctx)
+	assert.NoError(t, err)
+	assert.True(t, report.Available(CapabilityTraceTransaction))
+	assert.NotContains(t, report, CapabilityGraphQL)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestVerifyCapabilities_NothingToTrace(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	mockProbeBlock(ctx, mockJSONRPC, "latest", `{"number":"0x0","transactions":[]}`, nil)
+	mockJSONRPC.On("BatchCallContext", ctx, mock.Anything).Return(nil).Once()
+
+	report, err := c.VerifyCapabilities(ctx)
+	assert.NoError(t, err)
+	assert.False(t, report.Available(CapabilityTraceTransaction))
+	assert.True(t, report[CapabilityTraceTransaction].Unverified)
+
+	mockJSONRPC.AssertExpectations(t)
+}
@@ -0,0 +1,194 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+)
+
+// knownChainConfigs are the chain configs selected for the chain
+// ids reported by known nodes.
+var knownChainConfigs = map[uint64]*params.ChainConfig{
+	// Optimism mainnet and Goerli keep the fork schedules the
+	// networks were configured with before detection.
+	10:  withChainID(params.MainnetChainConfig, 10),
+	420: withChainID(params.TestnetChainConfig, 420),
+
+	// Optimism Kovan and local devnets enable every fork at genesis.
+	69:    withChainID(params.AllEthashProtocolChanges, 69),
+	17:    withChainID(params.AllEthashProtocolChanges, 17),
+	901:   withChainID(params.AllEthashProtocolChanges, 901),
+	31337: withChainID(params.AllEthashProtocolChanges, 31337),
+}
+
+// withChainID returns a copy of config with chainID.
+func withChainID(config *params.ChainConfig, chainID int64) *params.ChainConfig {
+	c := *config
+	c.ChainID = big.NewInt(chainID)
+	return &c
+}
+
+// ChainConfigForID returns the chain config of the known chain
+// with chainID.
+func ChainConfigForID(chainID uint64) (*params.ChainConfig, bool) {
+	config, ok := knownChainConfigs[chainID]
+	return config, ok
+}
+
+// detectChainID returns the chain id reported by the node with
+// eth_chainId, or with net_version for nodes that don't serve it.
+func detectChainID(ctx context.Context, c JSONRPC) (uint64, error) {
+	var chainID hexutil.Uint64
+	err := c.CallContext(ctx, &chainID, "eth_chainId")
+	if err == nil {
+		return uint64(chainID), nil
+	}
+
+	var version string
+	if versionErr := c.CallContext(ctx, &version, "net_version"); versionErr != nil {
+		return 0, fmt.Errorf("%w: unable to get chain id", err)
+	}
+	networkID, parseErr := strconv.ParseUint(version, 10, 64)
+	if parseErr != nil {
+		return 0, fmt.Errorf("%w: invalid net_version %s", parseErr, version)
+	}
+
+	return networkID, nil
+}
+
+// resolveChainConfig selects the chain config for the chain id of the
+// node. configured is the chain config of the network the operator
+// configured, and custom is set when it was loaded from
+// WithChainConfigJSON, in which case it is kept. It is an
+// ErrChainIDMismatch for the node to be on another chain. If the node
+// can't be reached, configured is used.
+func resolveChainConfig(
+	ctx context.Context,
+	c JSONRPC,
+	configured *params.ChainConfig,
+	custom bool,
+) (*params.ChainConfig, error) {
+	chainID, err := detectChainID(ctx, c)
+	if err != nil {
+		if configured == nil {
+			return nil, err
+		}
+		log.Printf("%s: using the configured chain config", err.Error())
+		return configured, nil
+	}
+
+	if configured != nil && configured.ChainID != nil && configured.ChainID.Uint64() != chainID {
+		return nil, fmt.Errorf(
+			"%w: node is on chain %d, but the network is configured for chain %d",
+			ErrChainIDMismatch,
+			chainID,
+			configured.ChainID.Uint64(),
+		)
+	}
+
+	if custom {
+		return configured, nil
+	}
+	if config, ok := ChainConfigForID(chainID); ok {
+		log.Printf("detected chain id %d (%s)", chainID, NetworkName(chainID))
+		return config, nil
+	}
+	if configured != nil {
+		return configured, nil
+	}
+
+	return nil, fmt.Errorf("chain id %d is unknown, its chain config must be provided", chainID)
+}
+
+// genesisChainConfig is the subset of a genesis file
+// that holds the chain configuration.
+type genesisChainConfig struct {
+	Config *params.ChainConfig `json:"config"`
+}
+
+// ParseChainConfigJSON parses a chain config from either a full genesis
+// file or a bare chain config object, and validates that the fork blocks
+// required to pick a signer and compute fees are present.
+func ParseChainConfigJSON(data []byte) (*params.ChainConfig, error) {
+	var genesis genesisChainConfig
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return nil, fmt.Errorf("%w: unable to unmarshal chain config", err)
+	}
+
+	config := genesis.Config
+	if config == nil {
+		config = new(params.ChainConfig)
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("%w: unable to unmarshal chain config", err)
+		}
+	}
+
+	if config.ChainID == nil {
+		return nil, fmt.Errorf("chain config is missing chainId")
+	}
+
+	required := []struct {
+		name  string
+		block *big.Int
+	}{
+		{"homesteadBlock", config.HomesteadBlock},
+		{"eip150Block", config.EIP150Block},
+		{"eip155Block", config.EIP155Block},
+		{"eip158Block", config.EIP158Block},
+		{"byzantiumBlock", config.ByzantiumBlock},
+		{"constantinopleBlock", config.ConstantinopleBlock},
+		{"petersburgBlock", config.PetersburgBlock},
+		{"istanbulBlock", config.IstanbulBlock},
+	}
+	for _, fork := range required {
+		if fork.block == nil {
+			return nil, fmt.Errorf("chain config is missing %s", fork.name)
+		}
+	}
+
+	if err := config.CheckConfigForkOrder(); err != nil {
+		return nil, fmt.Errorf("%w: invalid chain config", err)
+	}
+
+	return config, nil
+}
+
+// WithChainConfigJSON replaces the chain config of the client with the
+// genesis-style chain config in data, for custom deployments with their
+// own fork schedule. It drives signer selection and fee computation
+// instead of the config of the chain id reported by the node. It is an
+// ErrChainIDMismatch for the node to be on another chain.
+func (ec *Client) WithChainConfigJSON(data []byte) (*Client, error) {
+	config, err := ParseChainConfigJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to load chain config", err)
+	}
+
+	config, err = resolveChainConfig(context.Background(), ec.c, config, true)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to select chain config", err)
+	}
+
+	ec.p = config
+	return ec, nil
+}
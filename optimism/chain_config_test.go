@@ -0,0 +1,279 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseChainConfigJSON(t *testing.T) {
+	genesis, err := ioutil.ReadFile("testdata/chain_config_custom.json")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		data    []byte
+		chainID *big.Int
+		err     string
+	}{
+		"genesis file": {
+			data:    genesis,
+			chainID: big.NewInt(901),
+		},
+		"bare config": {
+			data: []byte(`{"chainId": 902, "homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0,
+				"eip158Block": 0, "byzantiumBlock": 0, "constantinopleBlock": 0, "petersburgBlock": 0,
+				"istanbulBlock": 0}`),
+			chainID: big.NewInt(902),
+		},
+		"missing chain id": {
+			data: []byte(`{"config": {"homesteadBlock": 0}}`),
+			err:  "chain config is missing chainId",
+		},
+		"missing fork block": {
+			data: []byte(`{"config": {"chainId": 901, "homesteadBlock": 0, "eip150Block": 0, "eip155Block": 0,
+				"eip158Block": 0, "byzantiumBlock": 0, "constantinopleBlock": 0, "petersburgBlock": 0}}`),
+			err: "chain config is missing istanbulBlock",
+		},
+		"invalid json": {
+			data: []byte(`{"config": `),
+			err:  "unable to unmarshal chain config",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, err := ParseChainConfigJSON(test.data)
+			if test.err != "" {
+				assert.Nil(t, config)
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.chainID, config.ChainID)
+		})
+	}
+}
+
+func TestWithChainConfigJSON(t *testing.T) {
+	genesis, err := ioutil.ReadFile("testdata/chain_config_custom.json")
+	assert.NoError(t, err)
+
+	c, err := NewClient("http://localhost:8545", params.MainnetChainConfig, ClientOptions{
+		EnableGethTracer: true,
+	})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	_, err = c.WithChainConfigJSON(genesis)
+	assert.NoError(t, err)
+
+	assert.Equal(t, big.NewInt(901), c.p.ChainID)
+	assert.NotNil(t, c.p.Clique)
+}
+
+func mockChainID(ctx context.Context, mockJSONRPC *mocks.JSONRPC, chainID uint64, err error) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_chainId",
+	).Return(
+		err,
+	).Run(
+		func(args mock.Arguments) {
+			*args.Get(1).(*hexutil.Uint64) = hexutil.Uint64(chainID)
+		},
+	).Once()
+}
+
+func TestResolveChainConfig_KnownChains(t *testing.T) {
+	tests := map[string]struct {
+		chainID uint64
+
+		expectedEIP155 *big.Int
+	}{
+		"optimism mainnet": {
+			chainID:        10,
+			expectedEIP155: params.MainnetChainConfig.EIP155Block,
+		},
+		"optimism goerli": {
+			chainID:        420,
+			expectedEIP155: params.TestnetChainConfig.EIP155Block,
+		},
+		"optimism kovan": {
+			chainID:        69,
+			expectedEIP155: big.NewInt(0),
+		},
+		"devnet": {
+			chainID:        17,
+			expectedEIP155: big.NewInt(0),
+		},
+		"bedrock devnet": {
+			chainID:        901,
+			expectedEIP155: big.NewInt(0),
+		},
+		"hardhat": {
+			chainID:        31337,
+			expectedEIP155: big.NewInt(0),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			ctx := context.Background()
+			mockChainID(ctx, mockJSONRPC, test.chainID, nil)
+
+			config, err := resolveChainConfig(ctx, mockJSONRPC, nil, false)
+			assert.NoError(t, err)
+			assert.Equal(t, new(big.Int).SetUint64(test.chainID), config.ChainID)
+			assert.Equal(t, test.expectedEIP155, config.EIP155Block)
+
+			// The configured chain config of the same chain is replaced
+			mockChainID(ctx, mockJSONRPC, test.chainID, nil)
+			configured := withChainID(params.TestChainConfig, int64(test.chainID))
+			detected, err := resolveChainConfig(ctx, mockJSONRPC, configured, false)
+			assert.NoError(t, err)
+			assert.Equal(t, config, detected)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveChainConfig(t *testing.T) {
+	custom := withChainID(params.TestChainConfig, 12345)
+
+	tests := map[string]struct {
+		mock       func(context.Context, *mocks.JSONRPC)
+		configured *params.ChainConfig
+		custom     bool
+
+		expected    *params.ChainConfig
+		expectedErr error
+	}{
+		"mismatch": {
+			mock: func(ctx context.Context, m *mocks.JSONRPC) {
+				mockChainID(ctx, m, 420, nil)
+			},
+			configured:  knownChainConfigs[10],
+			expectedErr: ErrChainIDMismatch,
+		},
+		"custom chain config mismatch": {
+			mock: func(ctx context.Context, m *mocks.JSONRPC) {
+				mockChainID(ctx, m, 10, nil)
+			},
+			configured:  custom,
+			custom:      true,
+			expectedErr: ErrChainIDMismatch,
+		},
+		"custom chain config": {
+			mock: func(ctx context.Context, m *mocks.JSONRPC) {
+				mockChainID(ctx, m, 12345, nil)
+			},
+			configured: custom,
+			custom:     true,
+			expected:   custom,
+		},
+		"unknown chain": {
+			mock: func(ctx context.Context, m *mocks.JSONRPC) {
+				mockChainID(ctx, m, 12345, nil)
+			},
+			configured: custom,
+			expected:   custom,
+		},
+		"net_version fallback": {
+			mock: func(ctx context.Context, m *mocks.JSONRPC) {
+				mockChainID(ctx, m, 0, &jsonError{code: -32601, message: "the method eth_chainId does not exist/is not available"})
+				m.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"net_version",
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						*args.Get(1).(*string) = "420"
+					},
+				).Once()
+			},
+			expected: knownChainConfigs[420],
+		},
+		"node unreachable": {
+			mock: func(ctx context.Context, m *mocks.JSONRPC) {
+				mockChainID(ctx, m, 0, errors.New("connection refused"))
+				m.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"net_version",
+				).Return(
+					errors.New("connection refused"),
+				).Once()
+			},
+			configured: knownChainConfigs[10],
+			expected:   knownChainConfigs[10],
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			ctx := context.Background()
+			test.mock(ctx, mockJSONRPC)
+
+			config, err := resolveChainConfig(ctx, mockJSONRPC, test.configured, test.custom)
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+				assert.Nil(t, config)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, config)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewClient_ChainIDMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1a4"}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, knownChainConfigs[10], ClientOptions{
+		EnableGethTracer: true,
+	})
+	assert.True(t, errors.Is(err, ErrChainIDMismatch))
+	assert.Contains(t, err.Error(), "node is on chain 420, but the network is configured for chain 10")
+	assert.Nil(t, c)
+}
@@ -0,0 +1,172 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+)
+
+// opStackNetworkNames maps the chain ids of known OP Stack
+// chains to a human-readable network name.
+var opStackNetworkNames = map[uint64]string{
+	10:        "op-mainnet",
+	420:       "op-goerli",
+	11155420:  "op-sepolia",
+	8453:      "base-mainnet",
+	84531:     "base-goerli",
+	84532:     "base-sepolia",
+	7777777:   "zora-mainnet",
+	999999999: "zora-sepolia",
+	34443:     "mode-mainnet",
+}
+
+// NetworkName returns the name of the OP Stack chain with chainID,
+// or the decimal chain id if the chain is unknown.
+func NetworkName(chainID uint64) string {
+	if name, ok := opStackNetworkNames[chainID]; ok {
+		return name
+	}
+
+	return strconv.FormatUint(chainID, 10)
+}
+
+// chainIDGuard remembers the first chain id reported by the node
+// so a node on another chain, e.g. behind a misconfigured load
+// balancer, is noticed. It is shared by the copies of a Client.
+type chainIDGuard struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	chainID *uint64
+	checked time.Time
+}
+
+// WithChainIDCheck makes Status and Block fetch eth_chainId at most
+// once per interval and fail with ErrChainIDChanged if it differs from
+// the first chain id observed. eth_chainId call results are always
+// compared. An interval of 0 disables the check.
+func (ec *Client) WithChainIDCheck(interval time.Duration) *Client {
+	if interval <= 0 {
+		return ec
+	}
+
+	log.Printf("checking the node chain id every %s", interval)
+	ec.chainIDGuard = &chainIDGuard{interval: interval}
+	return ec
+}
+
+// observe records chainID as the chain id of the node, returning
+// ErrChainIDChanged if it differs from the first one observed. A
+// mismatch is not cached, so the node is checked on every request
+// until it reports the first chain id again. The caller must hold
+// g.mu.
+func (g *chainIDGuard) observe(chainID uint64) error {
+	if g.chainID == nil {
+		g.chainID = &chainID
+	}
+
+	if *g.chainID != chainID {
+		return fmt.Errorf(
+			"%w: node reported chain id %d, expected %d",
+			ErrChainIDChanged,
+			chainID,
+			*g.chainID,
+		)
+	}
+
+	g.checked = time.Now()
+	return nil
+}
+
+// checkChainID compares the chain id of the node with the first one
+// observed if the last check is older than the configured interval.
+func (ec *Client) checkChainID(ctx context.Context) error {
+	g := ec.chainIDGuard
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.chainID != nil && time.Since(g.checked) < g.interval {
+		return nil
+	}
+
+	var chainID hexutil.Uint64
+	if err := ec.c.CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+		return rpcError(err, false)
+	}
+
+	return g.observe(uint64(chainID))
+}
+
+// noCallParameters returns ErrCallParametersInvalid if a call method
+// without parameters was given any.
+func noCallParameters(method string, params map[string]interface{}) error {
+	if len(params) > 0 {
+		return fmt.Errorf("%w: %s takes no parameters", ErrCallParametersInvalid, method)
+	}
+
+	return nil
+}
+
+// chainID returns the chain id reported by the node, as hex and
+// decimal, and the name of the network it belongs to.
+func (ec *Client) chainID(ctx context.Context) (map[string]interface{}, error) {
+	var chainID hexutil.Uint64
+	if err := ec.c.CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+		return nil, err
+	}
+
+	if g := ec.chainIDGuard; g != nil {
+		g.mu.Lock()
+		err := g.observe(uint64(chainID))
+		g.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"chain_id":         chainID.String(),
+		"chain_id_decimal": strconv.FormatUint(uint64(chainID), 10),
+		"network_name":     NetworkName(uint64(chainID)),
+	}, nil
+}
+
+// networkID returns the network id reported by the node with
+// net_version, as decimal and hex.
+func (ec *Client) networkID(ctx context.Context) (map[string]interface{}, error) {
+	var version string
+	if err := ec.c.CallContext(ctx, &version, "net_version"); err != nil {
+		return nil, err
+	}
+	networkID, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid net_version %s", err, version)
+	}
+
+	return map[string]interface{}{
+		"network_id":     strconv.FormatUint(networkID, 10),
+		"network_id_hex": hexutil.EncodeUint64(networkID),
+	}, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// switchingNode mocks eth_chainId on a node whose chain id can
+// change, e.g. behind a load balancer, counting the lookups.
+type switchingNode struct {
+	chainID uint64
+
+	calls int
+}
+
+func (n *switchingNode) mock(ctx context.Context, mockJSONRPC *mocks.JSONRPC) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_chainId",
+	).Return(
+		func(_ context.Context, result interface{}, _ string, _ ...interface{}) error {
+			n.calls++
+			*result.(*hexutil.Uint64) = hexutil.Uint64(n.chainID)
+			return nil
+		},
+	)
+}
+
+func TestNetworkName(t *testing.T) {
+	assert.Equal(t, "op-mainnet", NetworkName(10))
+	assert.Equal(t, "12345", NetworkName(12345))
+}
+
+func TestCheckChainID(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithChainIDCheck(time.Minute)
+	ctx := context.Background()
+	node := &switchingNode{chainID: 10}
+	node.mock(ctx, mockJSONRPC)
+
+	// The first chain id observed is remembered
+	assert.NoError(t, c.checkChainID(ctx))
+	assert.Equal(t, 1, node.calls)
+
+	// The node is not asked again within the interval
+	node.chainID = 420
+	assert.NoError(t, c.checkChainID(ctx))
+	assert.Equal(t, 1, node.calls)
+
+	// Once the interval has passed, the change is reported
+	c.chainIDGuard.checked = time.Now().Add(-time.Hour)
+	err := c.checkChainID(ctx)
+	assert.True(t, errors.Is(err, ErrChainIDChanged))
+	assert.Contains(t, err.Error(), "node reported chain id 420, expected 10")
+	assert.Equal(t, 2, node.calls)
+
+	// Blocks are not served from the other chain, which is
+	// checked on every request until the node switches back
+	block, err := c.Block(ctx, &RosettaTypes.PartialBlockIdentifier{Index: RosettaTypes.Int64(1)})
+	assert.True(t, errors.Is(err, ErrChainIDChanged))
+	assert.Nil(t, block)
+
+	// Nor is its chain id
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{Method: "eth_chainId"})
+	assert.True(t, errors.Is(err, ErrChainIDChanged))
+	assert.Nil(t, resp)
+
+	assert.Equal(t, 4, node.calls)
+
+	// The node returning to the first chain clears the error
+	node.chainID = 10
+	resp, err = c.Call(ctx, &RosettaTypes.CallRequest{Method: "eth_chainId"})
+	assert.NoError(t, err)
+	assert.Equal(t, "0xa", resp.Result["chain_id"])
+	assert.NoError(t, c.checkChainID(ctx))
+	assert.Equal(t, 5, node.calls)
+}
+
+func TestCheckChainID_Disabled(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithChainIDCheck(0)
+	ctx := context.Background()
+
+	assert.Nil(t, c.chainIDGuard)
+	assert.NoError(t, c.checkChainID(ctx))
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestCheckChainID_NodeError(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithChainIDCheck(time.Minute)
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_chainId",
+	).Return(
+		&jsonError{code: -32005, message: "rate limit exceeded"},
+	).Once()
+
+	err := c.checkChainID(ctx)
+	assert.True(t, errors.Is(err, ErrRateLimited))
+	assert.Nil(t, c.chainIDGuard.chainID)
+	mockJSONRPC.AssertExpectations(t)
+}
@@ -0,0 +1,218 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// DefaultCircuitBreakerCooldown is how long a circuit breaker stays
+// open when WithCircuitBreaker is given no cooldown.
+const DefaultCircuitBreakerCooldown = 30 * time.Second
+
+// WithCircuitBreaker stops calling a JSON-RPC method of the node after
+// threshold consecutive failures of it, e.g. when every
+// debug_traceTransaction times out. Calls of the method then fail
+// with ErrNodeUnavailable without reaching the node for cooldown,
+// after which a single call is let through: its success closes the
+// breaker and its failure opens it again. A failure is the node not
+// answering or timing out, not an error response to a request it
+// rejected. A non-positive threshold disables the breaker.
+func (ec *Client) WithCircuitBreaker(threshold int, cooldown time.Duration) *Client {
+	if threshold <= 0 {
+		return ec
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultCircuitBreakerCooldown
+	}
+
+	breaker := &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		methods:   map[string]*breakerState{},
+	}
+	ec.wrapJSONRPC(func(c JSONRPC) JSONRPC {
+		return &breakerJSONRPC{JSONRPC: c, breaker: breaker}
+	})
+
+	return ec
+}
+
+// breakerState is the state of the circuit breaker of a method. The
+// breaker is open while failures reaches the threshold, until
+// openUntil, and half-open after it, while probing is set.
+type breakerState struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// circuitBreaker tracks the consecutive failures of each method.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu      sync.Mutex
+	methods map[string]*breakerState
+}
+
+// acquire returns ErrNodeUnavailable if the breaker of any of methods
+// is open, or half-open with a call already testing the node.
+// Otherwise, the calls are let through, the first call of a
+// half-open method becoming its probe.
+func (b *circuitBreaker) acquire(methods []string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	for _, method := range methods {
+		s, ok := b.methods[method]
+		if !ok || s.failures < b.threshold {
+			continue
+		}
+		if now.Before(s.openUntil) {
+			return fmt.Errorf(
+				"%w: circuit breaker of %s open for %s after %d failures",
+				ErrNodeUnavailable,
+				method,
+				s.openUntil.Sub(now).Round(time.Second),
+				s.failures,
+			)
+		}
+		if s.probing {
+			return fmt.Errorf("%w: circuit breaker of %s is testing the node", ErrNodeUnavailable, method)
+		}
+	}
+
+	for _, method := range methods {
+		if s, ok := b.methods[method]; ok && s.failures >= b.threshold {
+			s.probing = true
+		}
+	}
+
+	return nil
+}
+
+// record records the outcome of a call of method, opening its breaker
+// once failures reach the threshold, or again if its probe failed.
+// Calls canceled by the caller say nothing about the node, so they
+// only end the probe.
+func (b *circuitBreaker) record(method string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.methods[method]
+	if !ok {
+		s = &breakerState{}
+		b.methods[method] = s
+	}
+	s.probing = false
+
+	switch {
+	case errors.Is(err, context.Canceled):
+	case breakerFailure(err):
+		s.failures++
+		if s.failures >= b.threshold {
+			log.Printf("opening circuit breaker of %s for %s after %d failures", method, b.cooldown, s.failures)
+			s.openUntil = time.Now().Add(b.cooldown)
+		}
+	default:
+		if s.failures >= b.threshold {
+			log.Printf("closing circuit breaker of %s", method)
+		}
+		s.failures = 0
+	}
+}
+
+// breakerFailure returns true if err shows the node failing to serve
+// a request. Error responses are the node working as intended, e.g.
+// rejecting invalid parameters or reverting a call, unless they
+// report it timing out or being unavailable.
+func breakerFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return true
+	}
+
+	return errors.Is(rpcErrorKind(err, false), ErrNodeUnavailable) ||
+		strings.Contains(strings.ToLower(err.Error()), "timeout")
+}
+
+// breakerJSONRPC is a JSONRPC that only calls the node while the
+// circuit breakers of the methods called are closed.
+type breakerJSONRPC struct {
+	JSONRPC
+	breaker *circuitBreaker
+}
+
+func (c *breakerJSONRPC) CallContext(
+	ctx context.Context,
+	result interface{},
+	method string,
+	args ...interface{},
+) error {
+	if err := c.breaker.acquire([]string{method}); err != nil {
+		return err
+	}
+
+	err := c.JSONRPC.CallContext(ctx, result, method, args...)
+	c.breaker.record(method, err)
+	return err
+}
+
+// BatchCallContext fails the whole batch if the breaker of any of its
+// methods is open. A method fails if the batch fails, or if any of
+// its elements do.
+func (c *breakerJSONRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	var methods []string
+	errs := map[string]error{}
+	for i := range b {
+		if _, ok := errs[b[i].Method]; !ok {
+			methods = append(methods, b[i].Method)
+			errs[b[i].Method] = nil
+		}
+	}
+
+	if err := c.breaker.acquire(methods); err != nil {
+		return err
+	}
+
+	err := c.JSONRPC.BatchCallContext(ctx, b)
+	for i := range b {
+		switch {
+		case err != nil:
+			errs[b[i].Method] = err
+		case errs[b[i].Method] == nil && breakerFailure(b[i].Error):
+			errs[b[i].Method] = b[i].Error
+		}
+	}
+	for _, method := range methods {
+		c.breaker.record(method, errs[method])
+	}
+
+	return err
+}
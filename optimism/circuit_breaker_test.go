@@ -0,0 +1,181 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithCircuitBreaker(3, time.Minute)
+	breaker := c.c.(*breakerJSONRPC).breaker
+	ctx := context.Background()
+
+	nodeErr := errors.New("502 Bad Gateway")
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"debug_traceTransaction",
+	).Return(
+		nodeErr,
+	).Times(3)
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_blockNumber",
+	).Return(
+		nil,
+	).Once()
+
+	// Repeated failures open the breaker
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, nodeErr, c.c.CallContext(ctx, nil, "debug_traceTransaction"))
+	}
+
+	// Subsequent calls fail fast without reaching the node
+	for i := 0; i < 2; i++ {
+		err := c.c.CallContext(ctx, nil, "debug_traceTransaction")
+		assert.True(t, errors.Is(err, ErrNodeUnavailable))
+	}
+	mockJSONRPC.AssertNumberOfCalls(t, "CallContext", 3)
+
+	// Other methods are still called
+	assert.NoError(t, c.c.CallContext(ctx, nil, "eth_blockNumber"))
+
+	// After the cooldown, a failed probe opens the breaker again
+	breaker.methods["debug_traceTransaction"].openUntil = time.Now()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"debug_traceTransaction",
+	).Return(
+		context.DeadlineExceeded,
+	).Once()
+	assert.True(t, errors.Is(c.c.CallContext(ctx, nil, "debug_traceTransaction"), context.DeadlineExceeded))
+	assert.True(t, errors.Is(c.c.CallContext(ctx, nil, "debug_traceTransaction"), ErrNodeUnavailable))
+
+	// A successful probe closes it
+	breaker.methods["debug_traceTransaction"].openUntil = time.Now()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"debug_traceTransaction",
+	).Return(
+		nil,
+	).Twice()
+	assert.NoError(t, c.c.CallContext(ctx, nil, "debug_traceTransaction"))
+	assert.NoError(t, c.c.CallContext(ctx, nil, "debug_traceTransaction"))
+	assert.Equal(t, 0, breaker.methods["debug_traceTransaction"].failures)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestWithCircuitBreaker_ErrorResponses(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithCircuitBreaker(2, time.Minute)
+	ctx := context.Background()
+
+	// Reverts are the node working, so they never open the breaker
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_call",
+	).Return(
+		&jsonError{code: -32000, message: "execution reverted"},
+	).Times(4)
+	for i := 0; i < 4; i++ {
+		err := c.c.CallContext(ctx, nil, "eth_call")
+		assert.False(t, errors.Is(err, ErrNodeUnavailable))
+	}
+
+	// Nor do calls canceled by the caller
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_blockNumber",
+	).Return(
+		context.Canceled,
+	).Times(4)
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, context.Canceled, c.c.CallContext(ctx, nil, "eth_blockNumber"))
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestWithCircuitBreaker_Batch(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithCircuitBreaker(2, time.Minute)
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.Anything,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			reqs := args.Get(1).([]rpc.BatchElem)
+			reqs[1].Error = &jsonError{code: -32000, message: "execution timeout"}
+		},
+	).Twice()
+
+	batch := func() []rpc.BatchElem {
+		return []rpc.BatchElem{
+			{Method: "debug_traceTransaction"},
+			{Method: "debug_traceTransaction"},
+		}
+	}
+
+	// A timed out element fails the method of the batch
+	assert.NoError(t, c.c.BatchCallContext(ctx, batch()))
+	assert.NoError(t, c.c.BatchCallContext(ctx, batch()))
+
+	err := c.c.BatchCallContext(ctx, batch())
+	assert.True(t, errors.Is(err, ErrNodeUnavailable))
+
+	// Batches including it fail as a whole
+	err = c.c.BatchCallContext(ctx, []rpc.BatchElem{
+		{Method: "eth_getTransactionReceipt"},
+		{Method: "debug_traceTransaction"},
+	})
+	assert.True(t, errors.Is(err, ErrNodeUnavailable))
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestWithCircuitBreaker_Disabled(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithCircuitBreaker(0, time.Minute)
+
+	assert.Equal(t, mockJSONRPC, c.c)
+}
@@ -17,12 +17,13 @@ package optimism
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"net/http"
-	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/coinbase/rosetta-ethereum/optimism/utilities/artifacts"
@@ -35,11 +36,18 @@ import (
 	"github.com/ethereum-optimism/optimism/l2geth/rlp"
 	"github.com/ethereum-optimism/optimism/l2geth/rpc"
 
+	gethCommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/eth/tracers"
 	"golang.org/x/sync/semaphore"
 )
 
+const (
+	legacyTxType     = 0x0
+	accessListTxType = 0x1
+	dynamicFeeTxType = 0x2
+	depositTxType    = 0x7e
+)
+
 const (
 	defaultHTTPTimeout    = 240 * time.Second
 	defaultTraceCacheSize = 20
@@ -57,6 +65,10 @@ const (
 
 	erc20TransferEventLogTopics = "Transfer(address,address,uint256)"
 
+	// includeCodeKey is the account identifier metadata key used to
+	// request the full account code in a balance response.
+	includeCodeKey = "include_code"
+
 	// While parsing ERC20 ops, we will ignore any event logs that we think are an ERC20 tansfer
 	// that do not contain 3 topics and who's 'data' field is not a single 32 byte hex string representing the amount of the transfer
 	numTopicsERC20Transfer = 3
@@ -106,16 +118,87 @@ var (
 //
 // Client borrows HEAVILY from https://github.com/ethereum/go-ethereum/tree/master/ethclient.
 type Client struct {
-	p          *params.ChainConfig
-	tc         *tracers.TraceConfig
-	traceCache TraceCache
+	p             *params.ChainConfig
+	tc            *traceConfig
+	traceCache    TraceCache
+	traceProvider TraceProvider
 
 	c JSONRPC
 	g GraphQL
 
+	// gc is the GraphQL client g was created with, kept apart
+	// from g, which may be wrapped, so Close can release it.
+	gc *GraphQLClient
+
 	currencyFetcher CurrencyFetcher
 	traceSemaphore  *semaphore.Weighted
 	supportedTokens map[string]bool
+	indexAllTokens  bool
+
+	legacyBalanceMetadata bool
+	legacyTxMetadata      bool
+	lenientTokenBalances  bool
+	cliqueSealer          bool
+	feeRecipientOverrides []FeeRecipientOverride
+	feeVaultHeight        uint64
+	bloomCheck            BloomCheck
+	missingReceipts       map[string]bool
+	omitMissingReceiptFee bool
+	abiRegistry           ABIRegistry
+	maxTraceDepth         int
+	disableTracing        bool
+	skipDegradedTraces    bool
+	checkTxChainID        bool
+
+	debugResponses bool
+
+	balancesBatchSize int
+	partialBalances   bool
+
+	// decisions is only set on the copy of the client
+	// made by DebugBlock.
+	decisions *DecisionLog
+
+	// preferBlockReceipts is unset once the node rejects
+	// eth_getBlockReceipts, so it is only attempted once.
+	preferBlockReceipts uint32
+
+	// skipAdminCalls is set when admin_peers must not be called,
+	// either by configuration or because the node rejected it.
+	skipAdminCalls uint32
+
+	// dialect resolves the RPC dialect of the node. Clients
+	// without one speak LegacyDialect.
+	dialect *dialectResolver
+
+	// genesis caches the identifier of the genesis block.
+	genesis *genesisCache
+
+	// clientVersion caches the client version of the node.
+	clientVersion *clientVersionCache
+
+	// oldestBlock is set by WithOldestBlockProbe.
+	oldestBlock *oldestBlockProbe
+
+	// chainIDGuard is set by WithChainIDCheck.
+	chainIDGuard *chainIDGuard
+
+	// tipSource is set by WithTipSource.
+	tipSource *tipSource
+
+	// currency is set by WithCurrencyByChainID. Clients
+	// without one use Currency.
+	currency *RosettaTypes.Currency
+
+	// skipRollupInfo is set once the node rejects rollup_getInfo,
+	// which only Optimism nodes serve.
+	skipRollupInfo uint32
+
+	// httpHeaders are added to each request made to the node.
+	// See WithHTTPHeaders.
+	httpHeaders *httpHeaders
+
+	closed uint32
 }
 
 type ClientOptions struct {
@@ -124,23 +207,180 @@ type ClientOptions struct {
 	EnableTraceCache    bool
 	EnableGethTracer    bool
 	SupportedTokens     map[string]bool
+
+	// EnableNativeTracer traces transactions with the callTracer built
+	// into geth instead of call_tracer.js. It cannot be combined with
+	// EnableGethTracer.
+	EnableNativeTracer bool
+
+	// NativeTracerConfig is passed to the native callTracer as its
+	// tracerConfig.
+	NativeTracerConfig CallTracerConfig
+
+	// TraceProvider overrides the default debug_traceTransaction
+	// trace provider when set.
+	TraceProvider TraceProvider
+
+	// LegacyBalanceMetadata returns the full account code in Balance
+	// metadata instead of the code hash and contract flag.
+	LegacyBalanceMetadata bool
+
+	// LegacyTxMetadata hex-encodes the gas and fee values of
+	// transaction metadata instead of returning decimal strings.
+	LegacyTxMetadata bool
+
+	// DisableGraphQL skips creating the GraphQL client for endpoints
+	// that only expose JSON-RPC. All queries, including Balance, are
+	// then served over JSON-RPC.
+	DisableGraphQL bool
+
+	// CurrencyCacheSize is the number of ERC20 contracts whose
+	// symbol and decimals are cached. Defaults to 100.
+	CurrencyCacheSize int
+
+	// EnableCliqueSealer adds the address that sealed each block, as
+	// recovered from the clique signature in extraData, to Block
+	// metadata. Only use this on chains with clique-style sealing.
+	EnableCliqueSealer bool
+
+	// LenientTokenBalances returns a zero balance, flagged with
+	// call_reverted in the amount metadata, for token balanceOf calls
+	// that revert or return no data (e.g. the address is not a
+	// contract) instead of failing the whole Balance request.
+	LenientTokenBalances bool
+
+	// FeeRecipientOverrides replace the address credited with fees
+	// for the blocks in their height range. The first match wins.
+	FeeRecipientOverrides []FeeRecipientOverride
+
+	// FeeVaultHeight is the first block whose fees are credited to the
+	// sequencer fee vault. Fees of earlier blocks are credited to the
+	// header coinbase. Defaults to 0, as the vault predates all blocks
+	// of the supported networks.
+	FeeVaultHeight uint64
+
+	// BloomCheck determines whether the logsBloom of each block and
+	// its receipts are recomputed from the logs returned by the node,
+	// and what happens when they disagree. Defaults to BloomCheckWarn.
+	BloomCheck BloomCheck
+
+	// MissingReceiptOverrides lists transactions whose receipts the
+	// node cannot return. They are converted without their receipt,
+	// with a fee of gasLimit*gasPrice, instead of failing the block.
+	// Receipts of all other transactions are still required.
+	MissingReceiptOverrides []MissingReceiptOverride
+
+	// OmitMissingReceiptFee skips fee operations for transactions
+	// in MissingReceiptOverrides instead of charging the upper bound.
+	OmitMissingReceiptFee bool
+
+	// ABIRegistry is used to decode logs returned by the eth_getLogs
+	// call method. Defaults to the standard ERC20 events.
+	ABIRegistry ABIRegistry
+
+	// IndexAllTokens emits operations for every ERC20 Transfer log
+	// instead of only those in SupportedTokens. Token details are
+	// fetched (and cached) for each new contract, which increases
+	// RPC load.
+	IndexAllTokens bool
+
+	// PreferBlockReceipts fetches the receipts of a block with a single
+	// eth_getBlockReceipts call instead of one eth_getTransactionReceipt
+	// per transaction. If the node does not expose the method, receipts
+	// are fetched per transaction from then on.
+	PreferBlockReceipts bool
+
+	// BalancesBatchSize is the number of accounts read per batch
+	// request by Balances. Defaults to 100.
+	BalancesBatchSize int
+
+	// PartialBalances makes Balances report the accounts it cannot
+	// read in a *BalancesError instead of failing the whole call.
+	PartialBalances bool
+
+	// SkipAdminCalls stops Status from listing peers with admin_peers,
+	// for nodes that don't expose the admin API.
+	SkipAdminCalls bool
+
+	// Dialect is the RPC dialect of the node. Defaults to
+	// AutoDialect, which detects it on first use.
+	Dialect Dialect
+
+	// MaxTraceDepth drops the operations of internal calls nested
+	// deeper than it, which keeps the operations of transactions with
+	// deeply nested calls in check. Calls that move value are kept at
+	// any depth, so balances reconcile, but lose the calls between
+	// them and the limit. Calls are not limited if it is 0.
+	MaxTraceDepth int
+
+	// DisableTracing stops Block from tracing transactions. Operations
+	// are built from the block body and receipts only: fees, mints,
+	// token transfers and the top-level call or contract creation of
+	// each transaction. Internal calls, including internal transfers
+	// of the native currency, are absent.
+	DisableTracing bool
+
+	// SkipDegradedTraces converts transactions whose trace timed out
+	// or was truncated without it, instead of failing the block. Only
+	// the operations of their top-level call are emitted, and the
+	// block is flagged with trace diagnostics.
+	SkipDegradedTraces bool
+
+	// CheckTransactionChainID makes SendTransaction compare the chain
+	// id a transaction is signed for with the net_version of the node,
+	// and reject it with ErrChainIDMismatch before broadcasting it if
+	// they differ.
+	CheckTransactionChainID bool
 }
 
 // NewClient creates a Client that from the provided url and params.
+// The chain config is selected from the chain id reported by the node,
+// which must match the chain id of params when set. params is used as
+// is if the node can't be reached yet.
 func NewClient(url string, params *params.ChainConfig, opts ClientOptions) (*Client, error) {
 	if opts.HTTPTimeout == 0 {
 		opts.HTTPTimeout = defaultHTTPTimeout
 	}
+
+	headers := &httpHeaders{}
 	c, err := rpc.DialHTTPWithClient(url, &http.Client{
-		Timeout: opts.HTTPTimeout,
+		Timeout:   opts.HTTPTimeout,
+		Transport: &headerTransport{base: http.DefaultTransport, headers: headers},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to dial node", err)
 	}
 
+	client, err := newClient(c, url, params, headers, opts)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// newClient creates a Client around the dialed node c. The caller
+// closes c if it fails.
+func newClient(
+	c *rpc.Client,
+	url string,
+	params *params.ChainConfig,
+	headers *httpHeaders,
+	opts ClientOptions,
+) (*Client, error) {
+	chainCtx, cancel := context.WithTimeout(context.Background(), opts.HTTPTimeout)
+	params, err := resolveChainConfig(chainCtx, c, params, false)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to select chain config", err)
+	}
+
 	tspec := tracerSpec{
-		TracerPath:    defaultTracerPath,
-		UseGethTracer: opts.EnableGethTracer,
+		TracerPath:         defaultTracerPath,
+		UseGethTracer:      opts.EnableGethTracer,
+		UseNativeTracer:    opts.EnableNativeTracer,
+		NativeTracerConfig: opts.NativeTracerConfig,
 	}
 	log.Printf("tracer spec: %#v", tspec)
 	tc, err := loadTraceConfig(tspec, opts.HTTPTimeout)
@@ -148,12 +388,48 @@ func NewClient(url string, params *params.ChainConfig, opts ClientOptions) (*Cli
 		return nil, fmt.Errorf("%w: unable to load trace config", err)
 	}
 
-	g, err := newGraphQLClient(url, opts.HTTPTimeout)
+	dialectName, err := ParseDialect(string(opts.Dialect))
 	if err != nil {
-		return nil, fmt.Errorf("%w: unable to create GraphQL client", err)
+		return nil, err
+	}
+	dialect := newDialectResolver(dialectName)
+	log.Printf("node dialect is %s", dialectName)
+
+	var (
+		g  GraphQL
+		gc *GraphQLClient
+	)
+	switch {
+	case opts.DisableGraphQL:
+		log.Println("GraphQL disabled, using JSON-RPC only")
+	case dialect.dialect != nil && !dialect.dialect.graphQL():
+		log.Printf("GraphQL not served in %s dialect, using JSON-RPC only", dialectName)
+	default:
+		gc, err = newGraphQLClient(url, opts.HTTPTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to create GraphQL client", err)
+		}
+		gc.client.Transport = &headerTransport{base: gc.client.Transport, headers: headers}
+		g = gc
+	}
+
+	feeRecipientOverrides, err := CheckFeeRecipientOverrides(opts.FeeRecipientOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	switch opts.BloomCheck {
+	case "":
+		opts.BloomCheck = BloomCheckWarn
+	case BloomCheckWarn, BloomCheckFail, BloomCheckDisabled:
+	default:
+		return nil, fmt.Errorf("%s is not a valid bloom check", opts.BloomCheck)
 	}
 
-	currencyFetcher, err := newERC20CurrencyFetcher(c)
+	if opts.CurrencyCacheSize == 0 {
+		opts.CurrencyCacheSize = defaultCacheSize
+	}
+	currencyFetcher, err := newERC20CurrencyFetcherWithCacheSize(c, opts.CurrencyCacheSize)
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to create CurrencyFetcher", err)
 	}
@@ -171,21 +447,97 @@ func NewClient(url string, params *params.ChainConfig, opts ClientOptions) (*Cli
 		}
 	}
 
+	var preferBlockReceipts uint32
+	if opts.PreferBlockReceipts {
+		preferBlockReceipts = 1
+	}
+
+	var skipAdminCalls uint32
+	if opts.SkipAdminCalls {
+		skipAdminCalls = 1
+	}
+
 	return &Client{
-		p:               params,
-		tc:              tc,
-		c:               c,
-		g:               g,
-		currencyFetcher: currencyFetcher,
-		traceSemaphore:  semaphore.NewWeighted(opts.MaxTraceConcurrency),
-		traceCache:      traceCache,
-		supportedTokens: opts.SupportedTokens,
+		p:                     params,
+		tc:                    tc,
+		c:                     c,
+		g:                     g,
+		gc:                    gc,
+		currencyFetcher:       currencyFetcher,
+		traceSemaphore:        semaphore.NewWeighted(opts.MaxTraceConcurrency),
+		traceCache:            traceCache,
+		traceProvider:         opts.TraceProvider,
+		supportedTokens:       opts.SupportedTokens,
+		legacyBalanceMetadata: opts.LegacyBalanceMetadata,
+		legacyTxMetadata:      opts.LegacyTxMetadata,
+		lenientTokenBalances:  opts.LenientTokenBalances,
+		indexAllTokens:        opts.IndexAllTokens,
+		cliqueSealer:          opts.EnableCliqueSealer,
+		feeRecipientOverrides: feeRecipientOverrides,
+		feeVaultHeight:        opts.FeeVaultHeight,
+		bloomCheck:            opts.BloomCheck,
+		missingReceipts:       newMissingReceipts(opts.MissingReceiptOverrides),
+		omitMissingReceiptFee: opts.OmitMissingReceiptFee,
+		maxTraceDepth:         opts.MaxTraceDepth,
+		disableTracing:        opts.DisableTracing,
+		skipDegradedTraces:    opts.SkipDegradedTraces,
+		checkTxChainID:        opts.CheckTransactionChainID,
+		abiRegistry:           opts.ABIRegistry,
+		preferBlockReceipts:   preferBlockReceipts,
+		balancesBatchSize:     opts.BalancesBatchSize,
+		partialBalances:       opts.PartialBalances,
+		httpHeaders:           headers,
+		skipAdminCalls:        skipAdminCalls,
+		dialect:               dialect,
+		genesis:               &genesisCache{},
+		clientVersion:         &clientVersionCache{},
 	}, nil
 }
 
-// Close shuts down the RPC client connection.
-func (ec *Client) Close() {
+// ChainConfig returns the chain config the client was created with.
+func (ec *Client) ChainConfig() *params.ChainConfig {
+	return ec.p
+}
+
+// Close shuts down the RPC client connection and releases any idle
+// GraphQL connections. Close is idempotent; calls made after Close
+// return ErrClientClosed.
+func (ec *Client) Close() error {
+	if !atomic.CompareAndSwapUint32(&ec.closed, 0, 1) {
+		return nil
+	}
+
 	ec.c.Close()
+	if ec.gc != nil {
+		ec.gc.Close()
+	}
+	if ec.tipSource != nil {
+		ec.tipSource.c.Close()
+	}
+
+	return nil
+}
+
+// checkClosed returns ErrClientClosed if Close has been called.
+func (ec *Client) checkClosed() error {
+	if atomic.LoadUint32(&ec.closed) == 1 {
+		return ErrClientClosed
+	}
+
+	return nil
+}
+
+// wrapJSONRPC replaces the JSON-RPC client with wrap of it. The
+// currency fetcher and trace cache query the node directly, so they
+// are pointed at the wrapped client too.
+func (ec *Client) wrapJSONRPC(wrap func(JSONRPC) JSONRPC) {
+	ec.c = wrap(ec.c)
+	if fetcher, ok := ec.currencyFetcher.(*ERC20CurrencyFetcher); ok {
+		fetcher.c = ec.c
+	}
+	if cache, ok := ec.traceCache.(*traceCache); ok {
+		cache.client = ec.c
+	}
 }
 
 // Status returns geth status information
@@ -197,27 +549,52 @@ func (ec *Client) Status(ctx context.Context) (
 	[]*RosettaTypes.Peer,
 	error,
 ) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, -1, nil, nil, err
+	}
+
+	if err := ec.checkChainID(ctx); err != nil {
+		return nil, -1, nil, nil, err
+	}
+
 	// TODO: figure out if header corresponds to replica or sequencer
 	header, err := ec.blockHeader(ctx, nil)
 	if err != nil {
 		return nil, -1, nil, nil, err
 	}
 
-	// TODO: Redo sync status with comparison to sequencer here
-	// TODO: use rollup_getInfo instead
-	// https://community.optimism.io/docs/developers/l2/json-rpc.html#rollup-getinfo
-	// progress, err := ec.syncProgress(ctx)
-	// if err != nil {
-	// 	return nil, -1, nil, nil, err
-	// }
+	// While the node is syncing, the latest header is its own
+	// head, not the head of the chain, so progress is reported
+	// from eth_syncing instead.
+	progress, err := ec.syncProgress(ctx)
+	if err != nil {
+		return nil, -1, nil, nil, err
+	}
 
 	var syncStatus *RosettaTypes.SyncStatus
-	currentIndex := int64(header.Number.Uint64())
-	targetIndex := int64(header.Number.Uint64()) // TODO: use rollup_getInfo value
+	if progress != nil {
+		syncStatus = &RosettaTypes.SyncStatus{
+			CurrentIndex: RosettaTypes.Int64(int64(progress.CurrentBlock)),
+			TargetIndex:  RosettaTypes.Int64(int64(progress.HighestBlock)),
+			Synced:       RosettaTypes.Bool(false),
+		}
+	} else {
+		syncStatus = &RosettaTypes.SyncStatus{
+			CurrentIndex: RosettaTypes.Int64(header.Number.Int64()),
+			TargetIndex:  RosettaTypes.Int64(header.Number.Int64()),
+			Synced:       RosettaTypes.Bool(true),
+		}
+	}
+
+	// The latest header of a verifier lags the chain tip, so the
+	// target is taken from the tip source when one is configured.
+	if ec.tipSource != nil {
+		ec.tipSource.adjust(ctx, syncStatus, progress != nil)
+	}
 
-	syncStatus = &RosettaTypes.SyncStatus{
-		CurrentIndex: &currentIndex,
-		TargetIndex:  &targetIndex,
+	peers, err := ec.peers(ctx)
+	if err != nil {
+		return nil, -1, nil, nil, err
 	}
 
 	return &RosettaTypes.BlockIdentifier{
@@ -226,21 +603,45 @@ func (ec *Client) Status(ctx context.Context) (
 		},
 		convertTime(header.Time),
 		syncStatus,
-		nil, // Replicas currently do not have peers
+		peers,
 		nil
 }
 
 // PendingNonceAt returns the account nonce of the given account in the pending state.
 // This is the nonce that should be used for the next transaction.
 func (ec *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
-	var result hexutil.Uint64
-	err := ec.c.CallContext(ctx, &result, "eth_getTransactionCount", account, "pending")
-	return uint64(result), err
+	if err := ec.checkClosed(); err != nil {
+		return 0, err
+	}
+
+	// The account state is read through the same requests as AccountInfo
+	// and Balance, so the nonce used in construction can't drift from
+	// the one they return.
+	reqs, accountInfo := accountInfoRequests(account.Hex(), "pending")
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return 0, err
+	}
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			return 0, reqs[i].Error
+		}
+	}
+
+	info, err := accountInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Nonce, nil
 }
 
 // SuggestGasPrice retrieves the currently suggested gas price to allow a timely
 // execution of a transaction.
 func (ec *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
 	var hex hexutil.Big
 	if err := ec.c.CallContext(ctx, &hex, "eth_gasPrice"); err != nil {
 		return nil, err
@@ -253,6 +654,14 @@ func (ec *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 // If the transaction was a contract creation use the TransactionReceipt method to get the
 // contract address after the transaction has been mined.
 func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := ec.checkClosed(); err != nil {
+		return err
+	}
+
+	if err := ec.checkTransactionChainID(ctx, tx); err != nil {
+		return err
+	}
+
 	data, err := rlp.EncodeToBytes(tx)
 	if err != nil {
 		return err
@@ -260,24 +669,216 @@ func (ec *Client) SendTransaction(ctx context.Context, tx *types.Transaction) er
 	return ec.c.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
 }
 
+// SendTransactionWithHash is SendTransaction, but also returns the hash
+// the node assigned to tx. It fails with ErrTransactionHashMismatch if
+// that hash is not the hash of tx, e.g. because a proxy in front of the
+// node rewrote the transaction, which has been broadcast regardless.
+// The hash reported by the node is returned with the error, so the
+// broadcast transaction can still be tracked.
+func (ec *Client) SendTransactionWithHash(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
+	if err := ec.checkClosed(); err != nil {
+		return common.Hash{}, err
+	}
+
+	if err := ec.checkTransactionChainID(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	var hash common.Hash
+	if err := ec.c.CallContext(ctx, &hash, "eth_sendRawTransaction", hexutil.Encode(data)); err != nil {
+		return common.Hash{}, err
+	}
+	if hash != tx.Hash() {
+		return hash, fmt.Errorf(
+			"%w: node returned %s for transaction %s",
+			ErrTransactionHashMismatch,
+			hash.Hex(),
+			tx.Hash().Hex(),
+		)
+	}
+
+	return hash, nil
+}
+
+// ParseRawTransaction decodes a signed, RLP-encoded transaction in the
+// same format accepted by SendTransaction and returns the operations it
+// would produce, without broadcasting it. The fee is computed from the
+// gas limit, as the gas actually used is not known until execution.
+func (ec *Client) ParseRawTransaction(rawHex string) (*RosettaTypes.Transaction, error) {
+	data, err := hexutil.Decode(rawHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRawTransaction, err.Error())
+	}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(data, tx); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRawTransaction, err.Error())
+	}
+
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewEIP155Signer(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot recover sender: %s", ErrInvalidRawTransaction, err.Error())
+	}
+
+	loadedTx := &loadedTransaction{
+		Transaction: tx,
+		From:        &from,
+		FeeAmount:   new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas())),
+		Miner:       ec.feeRecipient(nil, common.Address{}),
+	}
+	ops := feeOps(loadedTx)
+
+	// The top-level call is all we can know about without executing
+	// the transaction.
+	call := &flatCall{
+		Type:  CallOpType,
+		From:  from,
+		Value: tx.Value(),
+		Input: hexutil.Encode(tx.Data()),
+	}
+	if tx.To() != nil {
+		call.To = *tx.To()
+	} else {
+		call.Type = CreateOpType
+		call.To = common.Address(crypto.CreateAddress(gethCommon.Address(from), tx.Nonce()))
+	}
+	block := types.NewBlock(&types.Header{}, []*types.Transaction{tx}, nil, nil)
+	ops = append(ops, traceOps(block, []*flatCall{call}, len(ops))...)
+
+	return &RosettaTypes.Transaction{
+		TransactionIdentifier: &RosettaTypes.TransactionIdentifier{
+			Hash: tx.Hash().Hex(),
+		},
+		Operations: ops,
+		Metadata: map[string]interface{}{
+			"gas_limit": hexutil.EncodeUint64(tx.Gas()),
+			"gas_price": hexutil.EncodeBig(tx.GasPrice()),
+			"nonce":     hexutil.EncodeUint64(tx.Nonce()),
+		},
+	}, nil
+}
+
+// Negative block numbers select a block by tag, following the
+// rpc.BlockNumber conventions of go-ethereum.
+const (
+	PendingBlockIndex   = int64(-1)
+	LatestBlockIndex    = int64(-2)
+	FinalizedBlockIndex = int64(-3)
+	SafeBlockIndex      = int64(-4)
+)
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
 	}
-	pending := big.NewInt(-1)
-	if number.Cmp(pending) == 0 {
-		return "pending"
+	if number.IsInt64() {
+		switch number.Int64() {
+		case PendingBlockIndex:
+			return "pending"
+		case LatestBlockIndex:
+			return "latest"
+		case FinalizedBlockIndex:
+			return FinalizedBlockTag
+		case SafeBlockIndex:
+			return SafeBlockTag
+		}
 	}
 	return hexutil.EncodeBig(number)
 }
 
+// callBlockQuery returns the block argument of eth_call and
+// eth_estimateGas for input. The index takes precedence over the
+// hash, and either may be omitted to query the latest block.
+func callBlockQuery(input *GetCallInput) (string, error) {
+	switch {
+	case input.BlockIndex < SafeBlockIndex:
+		return "", fmt.Errorf("%w: invalid block index %d", ErrCallParametersInvalid, input.BlockIndex)
+	case input.BlockIndex != 0:
+		return toBlockNumArg(big.NewInt(input.BlockIndex)), nil
+	case len(input.BlockHash) > 0:
+		return input.BlockHash, nil
+	default:
+		return toBlockNumArg(nil), nil
+	}
+}
+
+const (
+	// SafeBlockTag may be passed as the hash of a
+	// *RosettaTypes.PartialBlockIdentifier without an index to
+	// request the latest block derived from L1 data.
+	SafeBlockTag = "safe"
+
+	// FinalizedBlockTag may be passed as the hash of a
+	// *RosettaTypes.PartialBlockIdentifier without an index to
+	// request the latest block derived from finalized L1 data.
+	FinalizedBlockTag = "finalized"
+)
+
+// blockTag returns the block tag requested by blockIdentifier, if any.
+func blockTag(blockIdentifier *RosettaTypes.PartialBlockIdentifier) (string, bool) {
+	if blockIdentifier == nil || blockIdentifier.Hash == nil || blockIdentifier.Index != nil {
+		return "", false
+	}
+
+	switch tag := *blockIdentifier.Hash; tag {
+	case SafeBlockTag, FinalizedBlockTag:
+		return tag, true
+	default:
+		return "", false
+	}
+}
+
+// requestedByHash returns true if blockIdentifier refers to
+// a block by its hash rather than by index or tag.
+func requestedByHash(blockIdentifier *RosettaTypes.PartialBlockIdentifier) bool {
+	if _, ok := blockTag(blockIdentifier); ok {
+		return false
+	}
+
+	return blockIdentifier != nil && blockIdentifier.Hash != nil
+}
+
 // Block returns a populated block at the *RosettaTypes.PartialBlockIdentifier.
 // If neither the hash or index is populated in the *RosettaTypes.PartialBlockIdentifier,
-// the current block is returned.
+// the current block is returned. A hash of SafeBlockTag or FinalizedBlockTag without
+// an index returns the block with that tag.
 func (ec *Client) Block(
 	ctx context.Context,
 	blockIdentifier *RosettaTypes.PartialBlockIdentifier,
 ) (*RosettaTypes.Block, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if err := ec.checkChainID(ctx); err != nil {
+		return nil, err
+	}
+
+	block, err := ec.block(ctx, blockIdentifier)
+	if err != nil {
+		return nil, rpcError(err, true)
+	}
+
+	return block, nil
+}
+
+func (ec *Client) block(
+	ctx context.Context,
+	blockIdentifier *RosettaTypes.PartialBlockIdentifier,
+) (*RosettaTypes.Block, error) {
+	if tag, ok := blockTag(blockIdentifier); ok {
+		return ec.getParsedBlock(ctx, "eth_getBlockByNumber", tag, true)
+	}
+
 	if blockIdentifier != nil {
 		if blockIdentifier.Hash != nil {
 			return ec.getParsedBlock(ctx, "eth_getBlockByHash", *blockIdentifier.Hash, true)
@@ -354,23 +955,35 @@ func (ec *Client) getBlock(
 	// concurrent traces that are computed to 16 to avoid overwhelming geth).
 	var traces []*Call
 	var addTraces bool
-	if head.Number.Int64() != GenesisBlockIndex { // not possible to get traces at genesis
+	partial := &PartialTraceError{}
+	if head.Number.Int64() != GenesisBlockIndex && !skipTraces(ctx) && !ec.disableTracing { // not possible to get traces at genesis
 		addTraces = true
-		traces, err = ec.getTransactionTraces(ctx, body.Transactions)
-		if err != nil {
+		traces, err = ec.getTransactionTraces(ctx, body.Hash, body.Transactions)
+		if err != nil && !(ec.skipDegradedTraces && errors.As(err, &partial)) {
 			return nil, nil, fmt.Errorf("%w: could not get traces for all txs in block %x", err, body.Hash[:])
 		}
 	}
+	traceErrors := map[common.Hash]error{}
+	for _, txHash := range partial.TimedOut {
+		traceErrors[txHash] = errTraceTimedOut
+	}
+	for _, txHash := range partial.Truncated {
+		traceErrors[txHash] = errTraceTruncated
+	}
 
 	// Convert all txs to loaded txs
 	txs := make([]*types.Transaction, len(body.Transactions))
 	loadedTxs := make([]*loadedTransaction, len(body.Transactions))
+	feeRecipient := ec.feeRecipient(head.Number, head.Coinbase)
 	for i, tx := range body.Transactions {
 		txs[i] = tx.tx
 		receipt := receipts[i]
 
 		var feeAmount *big.Int
-		if feeAmountInDupTx := originalFeeAmountInDupTx[string(body.Hash.Hex())]; feeAmountInDupTx == "" {
+		if receipt == nil {
+			// Without a receipt, the fee can only be bounded by the gas limit
+			feeAmount = new(big.Int).Mul(new(big.Int).SetUint64(txs[i].Gas()), txs[i].GasPrice())
+		} else if feeAmountInDupTx := originalFeeAmountInDupTx[string(body.Hash.Hex())]; feeAmountInDupTx == "" {
 			gasUsedBig := new(big.Int).SetUint64(receipt.GasUsed)
 			l2feeAmount := gasUsedBig.Mul(gasUsedBig, txs[i].GasPrice())
 			feeAmount = l2feeAmount.Add(l2feeAmount, receipts[i].L1Fee)
@@ -383,13 +996,13 @@ func (ec *Client) getBlock(
 
 		loadedTxs[i] = tx.LoadedTransaction()
 		loadedTxs[i].Transaction = txs[i]
+		if loadedTxs[i].From == nil {
+			loadedTxs[i].From = ec.recoverSender(head.Number, txs[i])
+		}
 		loadedTxs[i].FeeAmount = feeAmount
-		// Miner is fixed on Optimism and block rewards are sent internally to the OVM_SEQUENCER_FEE_VAULT contract.
-		// However, the block.coinbase is set to 0x0, rather than the vault contract.
-		// It would be nice for l2geth to populate the appropriate coinbase so we're robust against changes to the vault addresss.
-		loadedTxs[i].Miner = sequencerFeeVaultAddr
+		loadedTxs[i].Miner = feeRecipient
 		loadedTxs[i].Receipt = receipt
-		loadedTxs[i].Status = receipt.Status == 1
+		loadedTxs[i].Status = receipt == nil || receipt.Status == 1
 
 		// Continue if calls does not exist (occurs at genesis)
 		if !addTraces {
@@ -397,6 +1010,7 @@ func (ec *Client) getBlock(
 		}
 
 		loadedTxs[i].Trace = traces[i]
+		loadedTxs[i].TraceError = traceErrors[tx.hash()]
 	}
 
 	return types.NewBlockWithHeader(&head).WithBody(
@@ -407,51 +1021,36 @@ func (ec *Client) getBlock(
 
 func (ec *Client) getTransactionTraces(
 	ctx context.Context,
+	blockHash common.Hash,
 	txs []rpcTransaction,
 ) ([]*Call, error) {
-	if err := ec.traceSemaphore.Acquire(ctx, semaphoreTraceWeight); err != nil {
+	// Empty blocks have nothing to trace, so they don't
+	// wait for trace capacity.
+	if len(txs) == 0 {
+		return []*Call{}, nil
+	}
+
+	if err := ec.acquireTrace(ctx); err != nil {
 		return nil, err
 	}
 	defer ec.traceSemaphore.Release(semaphoreTraceWeight)
 
-	traces := make([]*Call, len(txs))
-	if len(txs) == 0 {
-		return traces, nil
+	txHashes := make([]common.Hash, len(txs))
+	for i := range txs {
+		txHashes[i] = txs[i].hash()
 	}
 
-	if ec.traceCache != nil {
-		for i := range txs {
-			result, err := ec.traceCache.FetchTransaction(ctx, txs[i].tx.Hash())
-			if err != nil {
-				return nil, err
-			}
-			traces[i] = result
-		}
-		return traces, nil
-	}
+	return ec.tracer().TraceBlock(ctx, blockHash, txHashes)
+}
 
-	reqs := make([]rpc.BatchElem, len(txs))
-	// TODO(inphi): Run this sequentially to avoid DoS'ing l2geth
-	for i := range reqs {
-		reqs[i] = rpc.BatchElem{
-			Method: "debug_traceTransaction",
-			Args:   []interface{}{txs[i].tx.Hash().Hex(), ec.tc},
-			Result: &traces[i],
-		}
-	}
-	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
-		return nil, err
-	}
-	for i := range reqs {
-		if reqs[i].Error != nil {
-			return nil, reqs[i].Error
-		}
-		if traces[i] == nil {
-			return nil, fmt.Errorf("got empty trace for %x", txs[i].tx.Hash().Hex())
-		}
+// tracer returns the configured TraceProvider, defaulting to
+// debug_traceTransaction against the node.
+func (ec *Client) tracer() TraceProvider {
+	if ec.traceProvider != nil {
+		return ec.traceProvider
 	}
 
-	return traces, nil
+	return &debugTraceProvider{c: ec.c, tc: ec.tc, cache: ec.traceCache}
 }
 
 func (ec *Client) getBlockReceipts(
@@ -464,23 +1063,52 @@ func (ec *Client) getBlockReceipts(
 		return receipts, nil
 	}
 
-	reqs := make([]rpc.BatchElem, len(txs))
-	for i := range reqs {
-		reqs[i] = rpc.BatchElem{
-			Method: "eth_getTransactionReceipt",
-			Args:   []interface{}{txs[i].tx.Hash().Hex()},
-			Result: &receipts[i],
-		}
-	}
-	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+	errs := make([]error, len(txs))
+	blockReceipts, err := ec.getReceiptsByBlock(ctx, blockHash)
+	if err != nil {
 		return nil, err
 	}
-	for i := range reqs {
-		if reqs[i].Error != nil {
-			return nil, reqs[i].Error
+	if blockReceipts != nil {
+		receiptsByHash := make(map[common.Hash]*types.Receipt, len(blockReceipts))
+		for _, receipt := range blockReceipts {
+			receiptsByHash[receipt.TxHash] = receipt
+		}
+		for i := range txs {
+			receipts[i] = receiptsByHash[txs[i].hash()]
+		}
+	} else {
+		d, err := ec.nodeDialect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		reqs := make([]rpc.BatchElem, len(txs))
+		for i := range reqs {
+			reqs[i] = rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{txs[i].hash().Hex()},
+				Result: d.receiptResult(&receipts[i]),
+			}
+		}
+		if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+			return nil, err
+		}
+		for i := range reqs {
+			errs[i] = reqs[i].Error
+		}
+	}
+
+	for i := range txs {
+		if (errs[i] != nil || receipts[i] == nil) && ec.receiptMissing(blockHash, txs[i].hash()) {
+			log.Printf("receipt of %s is listed as missing, converting without it", txs[i].hash().Hex())
+			receipts[i] = nil
+			continue
+		}
+		if errs[i] != nil {
+			return nil, errs[i]
 		}
 		if receipts[i] == nil {
-			return nil, fmt.Errorf("got empty receipt for %x", txs[i].tx.Hash().Hex())
+			return nil, fmt.Errorf("got empty receipt for %x", txs[i].hash().Hex())
 		}
 		if receipts[i].BlockHash.Hex() != blockHash.Hex() && !blockContainsDuplicateTransaction(blockHash) {
 			return nil, fmt.Errorf(
@@ -514,8 +1142,10 @@ func (ec *Client) erc20TokenOps(
 	keccak := crypto.Keccak256([]byte(erc20TransferEventLogTopics))
 	encodedTransferMethod := hexutil.Encode(keccak)
 
-	// To handle cases such as out-of-gas errors, where no logs are emitted
-	if status == FailureStatus && len(receipt.Logs) == 0 {
+	// To handle cases such as out-of-gas errors, where no logs are emitted.
+	// The transfer is read from the trace, so it is lost if the trace was
+	// skipped.
+	if status == FailureStatus && len(receipt.Logs) == 0 && tx.Trace != nil {
 		input := strings.ToLower(tx.Trace.Input)
 
 		// special case for failed ERC20 token transfers
@@ -565,7 +1195,7 @@ func (ec *Client) erc20TokenOps(
 			continue
 		}
 
-		if _, ok := ec.supportedTokens[strings.ToLower(contractAddress)]; !ok {
+		if !ec.isIndexedToken(contractAddress) {
 			continue
 		}
 
@@ -600,6 +1230,17 @@ func (ec *Client) erc20TokenOps(
 	return ops, nil
 }
 
+// isIndexedToken returns true if ERC20 transfers emitted by the
+// contract should be surfaced as operations.
+func (ec *Client) isIndexedToken(contractAddress string) bool {
+	if ec.indexAllTokens {
+		return true
+	}
+
+	_, ok := ec.supportedTokens[strings.ToLower(contractAddress)]
+	return ok
+}
+
 func appendERC20Operations(ops []*RosettaTypes.Operation,
 	fromAddress string,
 	toAddress string,
@@ -728,7 +1369,11 @@ func (t *Call) flatten() *flatCall {
 	}
 }
 
-// UnmarshalJSON is a custom unmarshaler for Call.
+// UnmarshalJSON is a custom unmarshaler for Call. It decodes the
+// frames of both call_tracer.js and the native callTracer, which omits
+// the value of calls that can't transfer any (such as STATICCALL) and
+// adds fields (such as logs and revertReason) that are not needed to
+// build operations.
 func (t *Call) UnmarshalJSON(input []byte) error {
 	type CustomTrace struct {
 		Type         string         `json:"type"`
@@ -755,7 +1400,7 @@ func (t *Call) UnmarshalJSON(input []byte) error {
 		t.Value = new(big.Int)
 	}
 	if dec.GasUsed != nil {
-		t.GasUsed = (*big.Int)(dec.Value)
+		t.GasUsed = (*big.Int)(dec.GasUsed)
 	} else {
 		t.GasUsed = new(big.Int)
 	}
@@ -774,6 +1419,11 @@ func (t *Call) UnmarshalJSON(input []byte) error {
 func flattenTraces(data *Call, flattened []*flatCall) []*flatCall {
 	results := append(flattened, data.flatten())
 	for _, child := range data.Calls {
+		// A null frame has no call to convert
+		if child == nil {
+			continue
+		}
+
 		// Ensure all children of a reverted call
 		// are also reverted!
 		if data.Revert {
@@ -804,14 +1454,31 @@ func containsTopic(log *types.Log, topic string) bool {
 
 // traceOps returns all *RosettaTypes.Operation for a given
 // array of flattened traces.
-func traceOps(block *types.Block, calls []*flatCall, startIndex int) []*RosettaTypes.Operation { // nolint: gocognit
+func traceOps(block *types.Block, calls []*flatCall, startIndex int) []*RosettaTypes.Operation {
+	return traceFrameOps(block, calls, startIndex, nil)
+}
+
+// traceFrameOps is traceOps that, if frameStarts is not nil, also sets
+// frameStarts[i] to the offset in the returned operations of the first
+// operation of calls[i], and frameStarts[len(calls)] to the offset of
+// the first operation zeroing a destroyed account.
+func traceFrameOps( // nolint: gocognit
+	block *types.Block,
+	calls []*flatCall,
+	startIndex int,
+	frameStarts []int,
+) []*RosettaTypes.Operation {
 	var ops []*RosettaTypes.Operation
 	if len(calls) == 0 {
 		return ops
 	}
 
 	destroyedAccounts := map[string]*big.Int{}
-	for _, trace := range calls {
+	for i, trace := range calls {
+		if frameStarts != nil {
+			frameStarts[i] = len(ops)
+		}
+
 		// Rejected transactions do not produce traces (ex: attempts to deploy contracts that aren't in the Optimism whitelist)
 		if trace.Type == "" {
 			continue
@@ -854,6 +1521,9 @@ func traceOps(block *types.Block, calls []*flatCall, startIndex int) []*RosettaT
 				var err error
 				burnMintAddr, burnMintAmt, err = decodeAddressUint256(trace.Input[fnSelectorLen:])
 				if err != nil {
+					// Calldata that does not decode cannot have
+					// burned or minted anything.
+					burnCall, mintCall = false, false
 					burnMintAddr, burnMintAmt = common.Address{}, nil
 				}
 			}
@@ -1031,6 +1701,10 @@ func traceOps(block *types.Block, calls []*flatCall, startIndex int) []*RosettaT
 		}
 	}
 
+	if frameStarts != nil {
+		frameStarts[len(calls)] = len(ops)
+	}
+
 	// Zero-out all destroyed accounts that are removed
 	// during transaction finalization.
 	for acct, val := range destroyedAccounts {
@@ -1082,9 +1756,15 @@ func decodeAddressUint256(hex string) (common.Address, *big.Int, error) {
 }
 
 type txExtraInfo struct {
-	BlockNumber *string         `json:"blockNumber,omitempty"`
-	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
-	From        *common.Address `json:"from,omitempty"`
+	Hash                 *common.Hash    `json:"hash,omitempty"`
+	BlockNumber          *string         `json:"blockNumber,omitempty"`
+	BlockHash            *common.Hash    `json:"blockHash,omitempty"`
+	From                 *common.Address `json:"from,omitempty"`
+	Type                 *string         `json:"type,omitempty"`
+	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Mint                 *hexutil.Big    `json:"mint,omitempty"`
 }
 
 type rpcTransaction struct {
@@ -1099,18 +1779,88 @@ func (tx *rpcTransaction) UnmarshalJSON(msg []byte) error {
 	return json.Unmarshal(msg, &tx.txExtraInfo)
 }
 
-func (tx *rpcTransaction) LoadedTransaction() *loadedTransaction {
-	ethTx := &loadedTransaction{
-		Transaction: tx.tx,
-		From:        tx.txExtraInfo.From,
-		BlockNumber: tx.txExtraInfo.BlockNumber,
-		BlockHash:   tx.txExtraInfo.BlockHash,
+// hash returns the hash of the transaction reported by the node.
+// Typed transactions are decoded as legacy transactions, whose hash
+// differs, so the hash of tx is only used if the node reports none.
+func (tx *rpcTransaction) hash() common.Hash {
+	if tx.txExtraInfo.Hash != nil {
+		return *tx.txExtraInfo.Hash
 	}
-	return ethTx
+
+	return tx.tx.Hash()
 }
 
-type loadedTransaction struct {
-	Transaction *types.Transaction
+// transactionTypeName returns the normalized name of the raw "type"
+// field of a transaction. Unrecognized values are reported as
+// unknown(<hex>) instead of failing the block.
+func transactionTypeName(rawType *string) string {
+	if rawType == nil {
+		return LegacyTxTypeName
+	}
+
+	txType, err := hexutil.DecodeUint64(*rawType)
+	if err != nil {
+		return fmt.Sprintf("unknown(%s)", *rawType)
+	}
+
+	switch txType {
+	case legacyTxType:
+		return LegacyTxTypeName
+	case accessListTxType:
+		return AccessListTxTypeName
+	case dynamicFeeTxType:
+		return DynamicFeeTxTypeName
+	case depositTxType:
+		return DepositTxTypeName
+	default:
+		return fmt.Sprintf("unknown(%s)", hexutil.EncodeUint64(txType))
+	}
+}
+
+func (tx *rpcTransaction) LoadedTransaction() *loadedTransaction {
+	hash := tx.hash()
+	ethTx := &loadedTransaction{
+		Transaction: tx.tx,
+		Hash:        &hash,
+		From:        tx.txExtraInfo.From,
+		BlockNumber: tx.txExtraInfo.BlockNumber,
+		BlockHash:   tx.txExtraInfo.BlockHash,
+	}
+
+	// Legacy transactions do not carry a type field. The raw value is
+	// kept so that types unknown to us are reported rather than rejected.
+	ethTx.TypeName = transactionTypeName(tx.txExtraInfo.Type)
+	if tx.txExtraInfo.Type != nil {
+		if txType, err := hexutil.DecodeUint64(*tx.txExtraInfo.Type); err == nil {
+			ethTx.Type = txType
+		}
+	}
+
+	// Prefer the chain id reported by the node, falling back to the one
+	// encoded in the signature of replay-protected legacy transactions.
+	// Transactions enqueued from L1 are unsigned (v = 0) and have none.
+	if tx.txExtraInfo.ChainID != nil {
+		ethTx.ChainID = tx.txExtraInfo.ChainID.ToInt()
+	} else if v, _, _ := tx.tx.RawSignatureValues(); v.Sign() != 0 && tx.tx.Protected() {
+		ethTx.ChainID = tx.tx.ChainId()
+	}
+
+	if tx.txExtraInfo.MaxFeePerGas != nil {
+		ethTx.MaxFeePerGas = tx.txExtraInfo.MaxFeePerGas.ToInt()
+	}
+	if tx.txExtraInfo.MaxPriorityFeePerGas != nil {
+		ethTx.MaxPriorityFeePerGas = tx.txExtraInfo.MaxPriorityFeePerGas.ToInt()
+	}
+	if tx.txExtraInfo.Mint != nil {
+		ethTx.Mint = tx.txExtraInfo.Mint.ToInt()
+	}
+
+	return ethTx
+}
+
+type loadedTransaction struct {
+	Transaction *types.Transaction
+	Hash        *common.Hash
 	From        *common.Address
 	BlockNumber *string
 	BlockHash   *common.Hash
@@ -1118,9 +1868,33 @@ type loadedTransaction struct {
 	Miner       string
 	Status      bool
 
+	Type                 uint64
+	TypeName             string
+	ChainID              *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+
+	// Mint is the ETH minted to the sender of a
+	// deposit transaction before it is executed.
+	Mint *big.Int
+
 	Trace    *Call
 	RawTrace json.RawMessage
 	Receipt  *types.Receipt
+
+	// TraceError is set when the trace was dropped because
+	// the tracer hit its limits.
+	TraceError error
+}
+
+// hash returns the hash of the transaction reported by the node,
+// or the hash of Transaction if it is unknown.
+func (tx *loadedTransaction) hash() common.Hash {
+	if tx.Hash != nil {
+		return *tx.Hash
+	}
+
+	return tx.Transaction.Hash()
 }
 
 func feeOps(tx *loadedTransaction) []*RosettaTypes.Operation {
@@ -1181,8 +1955,13 @@ func (ec *Client) transactionReceipt(
 	ctx context.Context,
 	txHash common.Hash,
 ) (*types.Receipt, error) {
+	d, err := ec.nodeDialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var r *types.Receipt
-	err := ec.c.CallContext(ctx, &r, "eth_getTransactionReceipt", txHash)
+	err = ec.c.CallContext(ctx, d.receiptResult(&r), "eth_getTransactionReceipt", txHash)
 	if err == nil {
 		if r == nil {
 			return nil, ethereum.NotFound
@@ -1215,6 +1994,35 @@ func (ec *Client) blockByNumber(
 	return r, err
 }
 
+// blockWithReceipts returns the block at index along with the receipts
+// of all its transactions. Receipts are fetched by the hash of the
+// returned block, so both are consistent even if the chain reorgs.
+func (ec *Client) blockWithReceipts(
+	ctx context.Context,
+	index *int64,
+	showTxDetails bool,
+) (map[string]interface{}, error) {
+	block, err := ec.blockByNumber(ctx, index, showTxDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	hash, ok := block["hash"].(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: block hash missing", ErrCallOutputMarshal)
+	}
+
+	var receipts []map[string]interface{}
+	if err := ec.c.CallContext(ctx, &receipts, "eth_getBlockReceipts", hash); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"block":    block,
+		"receipts": receipts,
+	}, nil
+}
+
 // contractCall returns the data specified by the given contract method
 func (ec *Client) contractCall(
 	ctx context.Context,
@@ -1226,20 +2034,14 @@ func (ec *Client) contractCall(
 		return nil, err
 	}
 
-	// default query
-	blockQuery := "latest"
-
-	// if block number or hash, override blockQuery
-	if input.BlockIndex > int64(0) {
-		blockQuery = toBlockNumArg(big.NewInt(input.BlockIndex))
-	} else if len(input.BlockHash) > 0 {
-		blockQuery = input.BlockHash
+	blockQuery, err := callBlockQuery(input)
+	if err != nil {
+		return nil, err
 	}
 
 	// ensure valid contract address
-	_, ok := ChecksumAddress(input.To)
-	if !ok {
-		return nil, ErrCallParametersInvalid
+	if _, err := ValidateAddress("to", input.To); err != nil {
+		return nil, err
 	}
 
 	// parameters for eth_call
@@ -1247,6 +2049,15 @@ func (ec *Client) contractCall(
 		"to":   input.To,
 		"data": input.Data,
 	}
+	if len(input.From) > 0 {
+		if _, err := ValidateAddress("from", input.From); err != nil {
+			return nil, err
+		}
+		callParams["from"] = input.From
+	}
+	if err := addCallQuantities(callParams, input); err != nil {
+		return nil, err
+	}
 
 	var resp string
 	if err := ec.c.CallContext(ctx, &resp, "eth_call", callParams, blockQuery); err != nil {
@@ -1270,15 +2081,17 @@ func (ec *Client) estimateGas(
 	}
 
 	// ensure valid contract address
-	_, ok := ChecksumAddress(input.To)
-	if !ok {
-		return nil, ErrCallParametersInvalid
+	if _, err := ValidateAddress("to", input.To); err != nil {
+		return nil, err
 	}
 
 	// ensure valid from address
-	_, ok = ChecksumAddress(input.From)
-	if !ok {
-		return nil, ErrCallParametersInvalid
+	if _, err := ValidateAddress("from", input.From); err != nil {
+		return nil, err
+	}
+
+	if input.MarginPercent != nil && *input.MarginPercent < 0 {
+		return nil, fmt.Errorf("%w: margin_percent %d is negative", ErrCallParametersInvalid, *input.MarginPercent)
 	}
 
 	// parameters for eth_estimateGas
@@ -1287,19 +2100,114 @@ func (ec *Client) estimateGas(
 		"to":   input.To,
 		"data": input.Data,
 	}
+	if err := addCallQuantities(estimateGasParams, input); err != nil {
+		return nil, err
+	}
+	args := []interface{}{estimateGasParams}
+
+	// State overrides are passed after the block, which is then required
+	if input.StateOverrides != nil {
+		overrides, err := parseStateOverrides(input.StateOverrides)
+		if err != nil {
+			return nil, err
+		}
+
+		blockQuery, err := callBlockQuery(input)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, blockQuery, overrides)
+	}
 
 	var resp string
-	if err := ec.c.CallContext(ctx, &resp, "eth_estimateGas", estimateGasParams); err != nil {
+	if err := ec.c.CallContext(ctx, &resp, "eth_estimateGas", args...); err != nil {
 		return nil, err
 	}
 
+	if input.MarginPercent == nil {
+		return map[string]interface{}{
+			"data": resp,
+		}, nil
+	}
+
+	adjusted, err := addGasMargin(resp, *input.MarginPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	// data stays the raw estimate, so callers that don't
+	// know about margins are not affected.
 	return map[string]interface{}{
-		"data": resp,
+		"data":           resp,
+		"raw_gas":        resp,
+		"adjusted_gas":   adjusted,
+		"margin_percent": *input.MarginPercent,
 	}, nil
 }
 
-//  EstimateGas retrieves the currently gas limit
+// addGasMargin returns the hex gas estimate increased by
+// marginPercent percent, rounded up.
+func addGasMargin(estimate string, marginPercent int64) (string, error) {
+	gas, err := hexutil.DecodeBig(estimate)
+	if err != nil {
+		return "", fmt.Errorf("%w: unable to decode gas estimate %s", err, estimate)
+	}
+
+	hundred := big.NewInt(100) // nolint:gomnd
+	adjusted := new(big.Int).Mul(gas, new(big.Int).Add(hundred, big.NewInt(marginPercent)))
+	adjusted.Add(adjusted, new(big.Int).Sub(hundred, big.NewInt(1)))
+	adjusted.Div(adjusted, hundred)
+
+	return hexutil.EncodeBig(adjusted), nil
+}
+
+// structLogTrace runs debug_traceTransaction with the default struct
+// logger and returns the opcode-level execution trace.
+func (ec *Client) structLogTrace(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input TraceTransactionInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	if len(input.TxHash) == 0 {
+		return nil, fmt.Errorf("%w: tx_hash missing from params", ErrCallParametersInvalid)
+	}
+
+	// Omitting the tracer selects the node's built-in struct logger
+	logConfig := map[string]interface{}{
+		"disableStack":   input.DisableStack,
+		"disableMemory":  input.DisableMemory,
+		"disableStorage": input.DisableStorage,
+	}
+
+	var result StructLogResult
+	if err := ec.c.CallContext(
+		ctx,
+		&result,
+		"debug_traceTransaction",
+		common.HexToHash(input.TxHash),
+		logConfig,
+	); err != nil {
+		return nil, err
+	}
+
+	resp, err := RosettaTypes.MarshalMap(result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+	}
+
+	return resp, nil
+}
+
+// EstimateGas retrieves the currently gas limit
 func (ec *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	if err := ec.checkClosed(); err != nil {
+		return 0, err
+	}
+
 	arg := map[string]interface{}{
 		"from": msg.From,
 		"to":   msg.To,
@@ -1325,6 +2233,40 @@ func (ec *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64
 	return uint64(hex), nil
 }
 
+// parseStateOverrides decodes the "state_overrides" call parameter,
+// ensuring every override targets a valid address and sets at most
+// one of state and stateDiff.
+func parseStateOverrides(raw map[string]interface{}) (map[string]*AccountOverride, error) {
+	// The hex encoded fields can only be decoded from JSON
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	var overrides map[string]*AccountOverride
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	for addr, override := range overrides {
+		if _, ok := ChecksumAddress(addr); !ok {
+			return nil, fmt.Errorf("%w: invalid state override address %s", ErrCallParametersInvalid, addr)
+		}
+		if override == nil {
+			return nil, fmt.Errorf("%w: empty state override for %s", ErrCallParametersInvalid, addr)
+		}
+		if override.State != nil && override.StateDiff != nil {
+			return nil, fmt.Errorf(
+				"%w: state override for %s has both state and stateDiff",
+				ErrCallParametersInvalid,
+				addr,
+			)
+		}
+	}
+
+	return overrides, nil
+}
+
 func validateCallInput(params map[string]interface{}) (*GetCallInput, error) {
 	var input GetCallInput
 	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
@@ -1368,16 +2310,50 @@ func (ec *Client) getParsedBlock(
 		}
 	}
 
-	txs, err := ec.populateTransactions(ctx, blockIdentifier, block, loadedTransactions)
+	diagnostics := &TraceDiagnostics{}
+	txs, err := ec.populateTransactions(ctx, blockIdentifier, block, loadedTransactions, diagnostics)
 	if err != nil {
 		return nil, err
 	}
+	for _, tx := range txs {
+		setOperationIDs(block.Hash(), tx)
+	}
+
+	metadata := map[string]interface{}{}
+	if !diagnostics.empty() {
+		log.Printf("block %d has degraded traces: %+v", blockIdentifier.Index, *diagnostics)
+		diagnostics.publish()
+		metadata[TraceDiagnosticsKey] = diagnostics.metadata()
+	}
+	if ec.bloomCheck != BloomCheckDisabled {
+		receipts := make([]*types.Receipt, len(loadedTransactions))
+		for i, tx := range loadedTransactions {
+			receipts[i] = tx.Receipt
+		}
+		if err := checkBlooms(block.Header(), receipts); err != nil {
+			if ec.bloomCheck == BloomCheckFail {
+				return nil, fmt.Errorf("%w: %s", ErrBloomMismatch, err.Error())
+			}
+			log.Printf("block %d may have tampered receipts: %v", blockIdentifier.Index, err)
+			bloomMismatches.Add(1)
+			metadata[BloomMismatchKey] = true
+		}
+	}
+	metadata[FeeRecipientKey] = MustChecksum(ec.feeRecipient(block.Number(), block.Coinbase()))
+	if ec.cliqueSealer && blockIdentifier.Index != GenesisBlockIndex {
+		sealer, err := cliqueSealer(block.Header())
+		if err != nil {
+			return nil, fmt.Errorf("%w: could not get sealer of block %d", err, blockIdentifier.Index)
+		}
+		metadata[CliqueSealerKey] = sealer.Hex()
+	}
 
 	return &RosettaTypes.Block{
 		BlockIdentifier:       blockIdentifier,
 		ParentBlockIdentifier: parentBlockIdentifier,
 		Timestamp:             convertTime(block.Time()),
 		Transactions:          txs,
+		Metadata:              metadata,
 	}, nil
 }
 
@@ -1390,6 +2366,7 @@ func (ec *Client) populateTransactions(
 	blockIdentifier *RosettaTypes.BlockIdentifier,
 	block *types.Block,
 	loadedTransactions []*loadedTransaction,
+	diagnostics *TraceDiagnostics,
 ) ([]*RosettaTypes.Transaction, error) {
 	transactions := make(
 		[]*RosettaTypes.Transaction,
@@ -1410,9 +2387,9 @@ func (ec *Client) populateTransactions(
 			}
 		}
 
-		transaction, err := ec.populateTransaction(ctx, block, tx)
+		transaction, err := ec.populateTransaction(ctx, block, tx, diagnostics)
 		if err != nil {
-			return nil, fmt.Errorf("%w: cannot parse %s", err, tx.Transaction.Hash().Hex())
+			return nil, fmt.Errorf("%w: cannot parse %s", err, tx.hash().Hex())
 		}
 
 		transactions[i] = transaction
@@ -1421,39 +2398,159 @@ func (ec *Client) populateTransactions(
 	return transactions, nil
 }
 
+// gasMetadata encodes a gas amount or fee per gas of transaction
+// metadata as a decimal string, or as hex if legacy transaction
+// metadata is enabled. Identifiers, such as the type, nonce and
+// chain id, are always hex.
+func (ec *Client) gasMetadata(value *big.Int) string {
+	if ec.legacyTxMetadata {
+		return hexutil.EncodeBig(value)
+	}
+
+	return value.String()
+}
+
 func (ec *Client) populateTransaction(
 	ctx context.Context,
 	block *types.Block,
 	tx *loadedTransaction,
+	diagnostics *TraceDiagnostics,
 ) (*RosettaTypes.Transaction, error) {
 	ops := []*RosettaTypes.Operation{}
 
-	// Compute fee operations
-	feeOps := feeOps(tx)
-	patchFeeOps(ec.p.ChainID, block, tx.Transaction, feeOps)
-	ops = append(ops, feeOps...)
+	var decisions *TransactionDecisions
+	if ec.decisions != nil {
+		decisions = ec.decisions.addTransaction(tx)
+	}
 
-	erc20TokenOps, err := ec.erc20TokenOps(ctx, block, tx, len(ops))
-	if err != nil {
-		return nil, err
+	// Compute fee operations. The fee is debited from the sender,
+	// so none are emitted if it is unknown. Deposits pay for their
+	// gas on L1, so they credit the minted ETH instead.
+	switch {
+	case tx.From == nil:
+		if decisions != nil {
+			decisions.fee(tx, nil, "omitted: sender unrecoverable")
+		}
+	case tx.Type == depositTxType:
+		mintOps := mintOps(tx, len(ops))
+		ops = append(ops, mintOps...)
+		if decisions != nil {
+			decisions.fee(tx, nil, "omitted: deposit transaction")
+			decisions.mint(tx, mintOps)
+		}
+	case tx.Receipt != nil || !ec.omitMissingReceiptFee:
+		feeOps := feeOps(tx)
+		patchFeeOps(ec.p.ChainID, block, tx.Transaction, feeOps)
+		ops = append(ops, feeOps...)
+		if decisions != nil {
+			decisions.fee(tx, feeOps, "")
+		}
+	case decisions != nil:
+		decisions.fee(tx, nil, "omitted: receipt missing")
 	}
-	ops = append(ops, erc20TokenOps...)
 
-	traces := flattenTraces(tx.Trace, []*flatCall{})
+	// Token transfers are only known from receipt logs
+	if tx.Receipt != nil {
+		erc20TokenOps, err := ec.erc20TokenOps(ctx, block, tx, len(ops))
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, erc20TokenOps...)
+		if decisions != nil {
+			decisions.erc20(tx, erc20TokenOps)
+		}
+	}
 
-	traceOps := traceOps(block, traces, len(ops))
-	ops = append(ops, traceOps...)
+	switch {
+	case tx.TraceError != nil:
+		if errors.Is(tx.TraceError, errTraceTimedOut) {
+			diagnostics.TimedOutTraces++
+		} else {
+			diagnostics.TruncatedTraces++
+		}
+		diagnostics.SkippedTransactions++
+		if decisions != nil {
+			decisions.skipTrace(tx.TraceError)
+		}
+
+		// The top-level call is still known from the transaction and
+		// its receipt, so its transfer is kept like with DisableTracing.
+		if tx.From != nil && tx.Receipt != nil {
+			ops = appendFrameOps(block, []*flatCall{topLevelCall(tx)}, ops, decisions)
+		}
+	case tx.Trace != nil:
+		var traces []*flatCall
+		for _, trace := range flattenTraces(limitTraceDepth(tx.Trace, ec.maxTraceDepth), []*flatCall{}) {
+			// Rejected transactions have an empty root frame, which
+			// traceOps skips on its own.
+			if trace.Type != "" && !traceFrameType(trace.Type) {
+				diagnostics.UnknownFrameTypes++
+				if decisions != nil {
+					decisions.frame(trace, nil, "skipped: unknown frame type")
+				}
+				continue
+			}
+			traces = append(traces, trace)
+		}
 
-	// Marshal receipt and trace data
-	// TODO: replace with marshalJSONMap (used in `services`)
-	receiptBytes, err := tx.Receipt.MarshalJSON()
-	if err != nil {
-		return nil, fmt.Errorf("%w: cannot marshal receipt json", err)
+		ops = appendFrameOps(block, traces, ops, decisions)
+	case ec.disableTracing && tx.From != nil && tx.Receipt != nil:
+		ops = appendFrameOps(block, []*flatCall{topLevelCall(tx)}, ops, decisions)
+	}
+	ec.setNativeCurrency(ops)
+	traced := tx.Trace != nil && tx.TraceError == nil
+
+	populatedTransaction := &RosettaTypes.Transaction{
+		TransactionIdentifier: &RosettaTypes.TransactionIdentifier{
+			Hash: tx.hash().Hex(),
+		},
+		Operations: ops,
+		Metadata: map[string]interface{}{
+			"gas_limit": ec.gasMetadata(new(big.Int).SetUint64(tx.Transaction.Gas())),
+			"gas_price": ec.gasMetadata(tx.Transaction.GasPrice()),
+			"type":      hexutil.EncodeUint64(tx.Type),
+			"type_name": tx.TypeName,
+			"input":     hexutil.Encode(tx.Transaction.Data()),
+			"nonce":     hexutil.EncodeUint64(tx.Transaction.Nonce()),
+		},
+	}
+	if to := tx.Transaction.To(); to != nil {
+		populatedTransaction.Metadata["to"] = to.Hex()
+	} else if tx.Receipt != nil {
+		populatedTransaction.Metadata["contract_address"] = tx.Receipt.ContractAddress.Hex()
 	}
 
-	var receiptMap map[string]interface{}
-	if err := json.Unmarshal(receiptBytes, &receiptMap); err != nil {
-		return nil, fmt.Errorf("%w: cannot unmarshal receipt bytes into map", err)
+	if tx.From == nil {
+		populatedTransaction.Metadata[SenderUnrecoverableKey] = true
+	}
+	if tx.Receipt == nil {
+		populatedTransaction.Metadata[ReceiptMissingKey] = true
+	} else {
+		// Marshal receipt and trace data
+		// TODO: replace with marshalJSONMap (used in `services`)
+		receiptBytes, err := tx.Receipt.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("%w: cannot marshal receipt json", err)
+		}
+
+		var receiptMap map[string]interface{}
+		if err := json.Unmarshal(receiptBytes, &receiptMap); err != nil {
+			return nil, fmt.Errorf("%w: cannot unmarshal receipt bytes into map", err)
+		}
+
+		populatedTransaction.Metadata["receipt"] = receiptMap
+		populatedTransaction.Metadata["gas_used"] = ec.gasMetadata(new(big.Int).SetUint64(tx.Receipt.GasUsed))
+
+		if approvals := ec.erc20Approvals(tx.Receipt); len(approvals) > 0 {
+			populatedTransaction.Metadata[ApprovalsKey] = approvals
+		}
+
+		// Value transfers are only emitted from traces. Without one,
+		// the value of a failed transaction, which never moved, is
+		// recorded instead.
+		if !traced && tx.Receipt.Status == types.ReceiptStatusFailed && tx.Transaction.Value().Sign() > 0 {
+			populatedTransaction.Metadata[IntendedValueKey] = tx.Transaction.Value().String()
+		}
 	}
 
 	// TODO: Currently not saving raw trace
@@ -1461,23 +2558,56 @@ func (ec *Client) populateTransaction(
 	// if err := json.Unmarshal(tx.RawTrace, &traceMap); err != nil {
 	// 	return nil, fmt.Errorf("%w: cannot unmarshal raw trace", err)
 	// }
+	// populatedTransaction.Metadata["trace"] = traceMap // TODO: use non-raw trace
 
-	populatedTransaction := &RosettaTypes.Transaction{
-		TransactionIdentifier: &RosettaTypes.TransactionIdentifier{
-			Hash: tx.Transaction.Hash().Hex(),
-		},
-		Operations: ops,
-		Metadata: map[string]interface{}{
-			"gas_limit": hexutil.EncodeUint64(tx.Transaction.Gas()),
-			"gas_price": hexutil.EncodeBig(tx.Transaction.GasPrice()),
-			"receipt":   receiptMap,
-			// "trace":     traceMap, // TODO: use non-raw trace
-		},
+	// Mined dynamic fee transactions report the price actually paid
+	// as their gas price.
+	if tx.Type == dynamicFeeTxType {
+		populatedTransaction.Metadata["effective_gas_price"] = ec.gasMetadata(tx.Transaction.GasPrice())
+	}
+	if tx.ChainID != nil {
+		populatedTransaction.Metadata["chain_id"] = hexutil.EncodeBig(tx.ChainID)
+	}
+	if tx.MaxFeePerGas != nil {
+		populatedTransaction.Metadata["max_fee_per_gas"] = ec.gasMetadata(tx.MaxFeePerGas)
+	}
+	if tx.MaxPriorityFeePerGas != nil {
+		populatedTransaction.Metadata["max_priority_fee_per_gas"] = ec.gasMetadata(tx.MaxPriorityFeePerGas)
+	}
+
+	// The L1 attributes deposit records the L1 origin of the block.
+	// Its operations are unaffected if it can't be decoded.
+	attributes, ok, err := l1Attributes(tx)
+	switch {
+	case err != nil:
+		log.Printf("%s: cannot decode L1 attributes of %s", err.Error(), tx.hash().Hex())
+	case ok:
+		populatedTransaction.Metadata[L1AttributesKey] = attributes
 	}
 
 	return populatedTransaction, nil
 }
 
+// appendFrameOps appends the operations of traces to ops,
+// recording them in decisions when it is set.
+func appendFrameOps(
+	block *types.Block,
+	traces []*flatCall,
+	ops []*RosettaTypes.Operation,
+	decisions *TransactionDecisions,
+) []*RosettaTypes.Operation {
+	var frameStarts []int
+	if decisions != nil {
+		frameStarts = make([]int, len(traces)+1)
+	}
+	traceOps := traceFrameOps(block, traces, len(ops), frameStarts)
+	if decisions != nil {
+		decisions.frames(traces, traceOps, frameStarts)
+	}
+
+	return append(ops, traceOps...)
+}
+
 type rpcProgress struct {
 	StartingBlock hexutil.Uint64
 	CurrentBlock  hexutil.Uint64
@@ -1486,7 +2616,6 @@ type rpcProgress struct {
 	KnownStates   hexutil.Uint64
 }
 
-// TODO: make this a sequencer height check instead
 // syncProgress retrieves the current progress of the sync algorithm. If there's
 // no sync currently running, it returns nil.
 func (ec *Client) syncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
@@ -1532,6 +2661,39 @@ type graphqlBalance struct {
 	} `json:"data"`
 }
 
+// prunedStateMessages are the error messages returned by geth, over
+// both JSON-RPC and GraphQL, when the state of a block has been pruned.
+var prunedStateMessages = []string{
+	"missing trie node",
+	"required historical state unavailable",
+}
+
+// historicalStateError wraps err with ErrStatePruned if it was caused
+// by querying pruned state. Other errors are returned as is.
+func historicalStateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if isStatePruned(err) {
+		return fmt.Errorf("%w: %s", ErrStatePruned, err.Error())
+	}
+
+	return err
+}
+
+// isStatePruned returns true if err was caused by
+// querying pruned state.
+func isStatePruned(err error) bool {
+	for _, msg := range prunedStateMessages {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // decodeHexData accepts a fully formed hex string (including the 0x prefix) and returns a big.Int
 func decodeHexData(data string) (*big.Int, error) {
 	decoded, ok := new(big.Int).SetString(data[2:], 16)
@@ -1550,136 +2712,153 @@ func (ec *Client) Balance(
 	block *RosettaTypes.PartialBlockIdentifier,
 	currencies []*RosettaTypes.Currency,
 ) (*RosettaTypes.AccountBalanceResponse, error) {
-	var raw json.RawMessage
-	if block != nil {
-		if block.Hash != nil {
-			if err := ec.c.CallContext(ctx, &raw, "eth_getBlockByHash", block.Hash, false); err != nil {
-				return nil, err
-			}
-		}
-		if block.Hash == nil && block.Index != nil {
-			if err := ec.c.CallContext(
-				ctx,
-				&raw,
-				"eth_getBlockByNumber",
-				hexutil.EncodeUint64(uint64(*block.Index)),
-				false,
-			); err != nil {
-				return nil, err
-			}
-		}
-	} else {
-		if err := ec.c.CallContext(ctx, &raw, "eth_getBlockByNumber", toBlockNumArg(nil), false); err != nil {
-			return nil, err
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if ec.debugResponses {
+		debug, recorder := ec.debugClient()
+		balance, err := debug.balance(ctx, account, block, currencies)
+		if err != nil {
+			return nil, rpcError(err, true)
 		}
+		balance.Metadata[DebugKey] = recorder.metadata()
+
+		return balance, nil
 	}
-	if len(raw) == 0 {
-		return nil, ethereum.NotFound
+
+	balance, err := ec.balance(ctx, account, block, currencies)
+	if err != nil {
+		return nil, rpcError(err, true)
 	}
 
-	var head *types.Header
-	if err := json.Unmarshal(raw, &head); err != nil {
+	return balance, nil
+}
+
+func (ec *Client) balance(
+	ctx context.Context,
+	account *RosettaTypes.AccountIdentifier,
+	block *RosettaTypes.PartialBlockIdentifier,
+	currencies []*RosettaTypes.Currency,
+) (*RosettaTypes.AccountBalanceResponse, error) {
+	// Validate the account and currencies before querying the node
+	if _, err := newBalanceQuery(account, currencies, ec.nativeCurrency()); err != nil {
 		return nil, err
 	}
 
-	var (
-		balance hexutil.Big
-		nonce   hexutil.Uint64
-		code    string
-	)
+	head, err := ec.accountBlockHeader(ctx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	return ec.balanceAtHeader(ctx, account, block, head, currencies)
+}
+
+// balanceAtHeader is balance at the block with head, which was
+// already resolved from block.
+func (ec *Client) balanceAtHeader(
+	ctx context.Context,
+	account *RosettaTypes.AccountIdentifier,
+	block *RosettaTypes.PartialBlockIdentifier,
+	head *types.Header,
+	currencies []*RosettaTypes.Currency,
+) (*RosettaTypes.AccountBalanceResponse, error) {
+	query, err := newBalanceQuery(account, currencies, ec.nativeCurrency())
+	if err != nil {
+		return nil, err
+	}
 
 	blockNum := hexutil.EncodeUint64(head.Number.Uint64())
-	reqs := []rpc.BatchElem{
-		{Method: "eth_getBalance", Args: []interface{}{account.Address, blockNum}, Result: &balance},
-		{Method: "eth_getTransactionCount", Args: []interface{}{account.Address, blockNum}, Result: &nonce},
-		{Method: "eth_getCode", Args: []interface{}{account.Address, blockNum}, Result: &code},
+	if err := query.prepare(ctx, ec, head, blockNum); err != nil {
+		return nil, err
 	}
+
+	reqs, canonical := canonicalHeaderRequest(query.reqs, block, blockNum)
 	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, historicalStateError(err)
+	}
+	copy(query.reqs, reqs)
+	if err := query.check(ec); err != nil {
 		return nil, err
 	}
-	for i := range reqs {
-		if reqs[i].Error != nil {
-			return nil, reqs[i].Error
-		}
+	if err := checkBatchErrors(reqs[len(query.reqs):]); err != nil {
+		return nil, err
 	}
-
-	nativeBalance := &RosettaTypes.Amount{
-		Value:    balance.ToInt().String(),
-		Currency: Currency,
+	if err := checkCanonicalHeader(block, head, *canonical); err != nil {
+		return nil, err
 	}
 
-	var balances []*RosettaTypes.Amount
-	for _, curr := range currencies {
-		if reflect.DeepEqual(curr, Currency) {
-			balances = append(balances, nativeBalance)
-			continue
-		}
+	return query.response(ec, head)
+}
 
-		contractAddress := fmt.Sprintf("%s", curr.Metadata[ContractAddressKey])
-		_, ok := ChecksumAddress(contractAddress)
-		if !ok {
-			return nil, fmt.Errorf("invalid contract address %s", contractAddress)
-		}
+// balanceMetadata returns the account metadata attached to a balance
+// response. By default only the code hash and a contract flag are
+// returned; the full code is included when "include_code" is set in
+// the account identifier metadata or legacy metadata is enabled.
+func (ec *Client) balanceMetadata(
+	account *RosettaTypes.AccountIdentifier,
+	nonce uint64,
+	code string,
+) (map[string]interface{}, error) {
+	if ec.legacyBalanceMetadata {
+		return map[string]interface{}{
+			"nonce": int64(nonce),
+			"code":  code,
+		}, nil
+	}
 
-		balance, err := ec.getBalance(ctx, account.Address, blockNum, contractAddress)
-		if err != nil {
-			return nil, fmt.Errorf("err encountered for currency %s, token address %s; %v", curr.Symbol, contractAddress, err)
-		}
-		balances = append(balances, &RosettaTypes.Amount{
-			Value:    balance,
-			Currency: curr,
-		})
+	codeBytes, err := hexutil.Decode(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode code", err)
 	}
 
-	if len(currencies) == 0 {
-		opTokenBalance, err := ec.getBalance(ctx, account.Address, blockNum, opTokenContractAddress.String())
-		if err != nil {
-			return nil, fmt.Errorf("err getting OP token balance; %v", err)
-		}
-		balances = append(balances, nativeBalance, &RosettaTypes.Amount{
-			Value:    opTokenBalance,
-			Currency: OPTokenCurrency,
-		})
+	metadata := map[string]interface{}{
+		"nonce":       int64(nonce),
+		"is_contract": len(codeBytes) > 0,
+		"code_hash":   crypto.Keccak256Hash(codeBytes).Hex(),
+	}
+	if includeCode, _ := account.Metadata[includeCodeKey].(bool); includeCode {
+		metadata["code"] = code
 	}
 
-	return &RosettaTypes.AccountBalanceResponse{
-		Balances: balances,
-		BlockIdentifier: &RosettaTypes.BlockIdentifier{
-			Hash:  head.Hash().Hex(),
-			Index: head.Number.Int64(),
-		},
-		Metadata: map[string]interface{}{
-			"nonce": int64(nonce),
-			"code":  code,
-		},
-	}, nil
+	return metadata, nil
 }
 
-func (ec *Client) getBalance(ctx context.Context, accountAddress string, blockNum string, contractAddress string) (string, error) {
-	erc20Data, err := artifacts.ERC20ABI.Pack("balanceOf", common.HexToAddress(accountAddress))
-	if err != nil {
-		return "", err
+// tokenContractAddress returns the contract address of a token
+// currency, or ErrInvalidTokenContractAddress if it is missing or
+// malformed.
+func tokenContractAddress(currency *RosettaTypes.Currency) (string, error) {
+	if currency == OPTokenCurrency {
+		return opTokenContractAddress.String(), nil
 	}
-	encodedERC20Data := hexutil.Encode(erc20Data)
 
-	callParams := map[string]string{
-		"to":   contractAddress,
-		"data": encodedERC20Data,
+	contractAddress, ok := currency.Metadata[ContractAddressKey].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: missing for currency %s", ErrInvalidTokenContractAddress, currency.Symbol)
 	}
-	var resp string
-	if err := ec.c.CallContext(ctx, &resp, "eth_call", callParams, blockNum); err != nil {
-		return "", err
+	if _, ok := ChecksumAddress(contractAddress); !ok {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTokenContractAddress, contractAddress)
 	}
-	balance, err := decodeHexData(resp)
+
+	return contractAddress, nil
+}
+
+// balanceOfCallParams returns the eth_call parameters to query the
+// token balance of accountAddress.
+func balanceOfCallParams(accountAddress string, contractAddress string) (map[string]string, error) {
+	erc20Data, err := artifacts.ERC20ABI.Pack("balanceOf", common.HexToAddress(accountAddress))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return balance.String(), nil
+	return map[string]string{
+		"to":   contractAddress,
+		"data": hexutil.Encode(erc20Data),
+	}, nil
 }
 
 // GetBlockByNumberInput is the input to the call
-// method "eth_getBlockByNumber".
+// methods "eth_getBlockByNumber" and "block_with_receipts".
 type GetBlockByNumberInput struct {
 	Index         *int64 `json:"index,omitempty"`
 	ShowTxDetails bool   `json:"show_transaction_details"`
@@ -1687,27 +2866,86 @@ type GetBlockByNumberInput struct {
 
 // GetTransactionReceiptInput is the input to the call
 // method "eth_getTransactionReceipt".
+//
+// When TxHashes is set, the receipts of all of them are fetched in
+// batches and returned in order, with null for missing receipts.
+//
+// When IncludeRevertReason is set, the receipt of a failed transaction
+// is re-executed with debug_traceTransaction and includes the decoded
+// "revert_reason" (or the raw "revert_data" if it is not an
+// Error(string) revert).
 type GetTransactionReceiptInput struct {
-	TxHash string `json:"tx_hash"`
+	TxHash              string   `json:"tx_hash"`
+	TxHashes            []string `json:"tx_hashes,omitempty"`
+	IncludeRevertReason bool     `json:"include_revert_reason,omitempty"`
+}
+
+// TraceTransactionInput is the input to the call
+// method "debug_traceTransaction".
+type TraceTransactionInput struct {
+	TxHash         string `json:"tx_hash"`
+	DisableStack   bool   `json:"disable_stack"`
+	DisableMemory  bool   `json:"disable_memory"`
+	DisableStorage bool   `json:"disable_storage"`
 }
 
 // GetCallInput is the input to the call
 // method "eth_call", "eth_estimateGas".
 type GetCallInput struct {
+	// BlockIndex may be one of the negative block indexes, such
+	// as FinalizedBlockIndex, to query a block by tag.
 	BlockIndex int64  `json:"index,omitempty"`
 	BlockHash  string `json:"hash,omitempty"`
 	From       string `json:"from"`
 	To         string `json:"to"`
-	Gas        int64  `json:"gas"`
-	GasPrice   int64  `json:"gas_price"`
-	Value      int64  `json:"value"`
 	Data       string `json:"data"`
+
+	// Gas, GasPrice and Value are optional and forwarded to the
+	// node when set. Each may be a number, a decimal string or
+	// a hex string.
+	Gas      interface{} `json:"gas,omitempty"`
+	GasPrice interface{} `json:"gas_price,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+
+	// StateOverrides replaces account state while estimating gas. It
+	// maps addresses to AccountOverride objects.
+	StateOverrides map[string]interface{} `json:"state_overrides,omitempty"`
+
+	// MarginPercent is the safety margin added to the estimate
+	// returned by "eth_estimateGas".
+	MarginPercent *int64 `json:"margin_percent,omitempty"`
+}
+
+// AccountOverride is the state of an account to
+// override in "eth_estimateGas".
+type AccountOverride struct {
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes              `json:"code,omitempty"`
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	State     map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
 }
 
 // Call handles calls to the /call endpoint.
 func (ec *Client) Call(
 	ctx context.Context,
 	request *RosettaTypes.CallRequest,
+) (*RosettaTypes.CallResponse, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	resp, err := ec.call(ctx, request)
+	if err != nil {
+		return nil, callError(err)
+	}
+
+	return resp, nil
+}
+
+func (ec *Client) call(
+	ctx context.Context,
+	request *RosettaTypes.CallRequest,
 ) (*RosettaTypes.CallResponse, error) {
 	switch request.Method { // nolint:gocritic
 	case "eth_getBlockByNumber":
@@ -1721,6 +2959,20 @@ func (ec *Client) Call(
 			return nil, err
 		}
 
+		return &RosettaTypes.CallResponse{
+			Result: res,
+		}, nil
+	case "block_with_receipts":
+		var input GetBlockByNumberInput
+		if err := RosettaTypes.UnmarshalMap(request.Parameters, &input); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+		}
+
+		res, err := ec.blockWithReceipts(ctx, input.Index, input.ShowTxDetails)
+		if err != nil {
+			return nil, err
+		}
+
 		return &RosettaTypes.CallResponse{
 			Result: res,
 		}, nil
@@ -1730,6 +2982,17 @@ func (ec *Client) Call(
 			return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
 		}
 
+		if len(input.TxHashes) > 0 {
+			result, err := ec.receiptsCallResult(ctx, input.TxHashes, input.IncludeRevertReason)
+			if err != nil {
+				return nil, err
+			}
+
+			return &RosettaTypes.CallResponse{
+				Result: result,
+			}, nil
+		}
+
 		if len(input.TxHash) == 0 {
 			return nil, fmt.Errorf("%w:tx_hash missing from params", ErrCallParametersInvalid)
 		}
@@ -1751,6 +3014,20 @@ func (ec *Client) Call(
 			return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
 		}
 
+		mismatch, err := ec.receiptBloomMismatch(receipt)
+		if err != nil {
+			return nil, err
+		}
+		if mismatch {
+			receiptMap[BloomMismatchKey] = true
+		}
+
+		if input.IncludeRevertReason && receipt.Status == types.ReceiptStatusFailed {
+			if err := ec.addRevertReason(ctx, receipt.TxHash, receiptMap); err != nil {
+				return nil, err
+			}
+		}
+
 		// We must encode data over the wire so we can unmarshal correctly
 		return &RosettaTypes.CallResponse{
 			Result: receiptMap,
@@ -1761,6 +3038,25 @@ func (ec *Client) Call(
 			return nil, err
 		}
 
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case "eth_getBalance", "eth_getCode":
+		resp, pinned, err := ec.accountCall(ctx, request.Method, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result:     resp,
+			Idempotent: pinned,
+		}, nil
+	case CallDiffMethod:
+		resp, err := ec.callDiff(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
 		return &RosettaTypes.CallResponse{
 			Result: resp,
 		}, nil
@@ -1773,6 +3069,146 @@ func (ec *Client) Call(
 		return &RosettaTypes.CallResponse{
 			Result: resp,
 		}, nil
+	case "eth_getLogs":
+		resp, err := ec.getLogs(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case "debug_traceTransaction":
+		resp, err := ec.structLogTrace(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case FinalizedOffsetMethod:
+		resp, err := ec.finalizedOffsetBlock(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case AllowanceMethod:
+		resp, err := ec.allowance(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case BlockNoTraceMethod:
+		resp, err := ec.blockNoTrace(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case BalanceHistoryMethod:
+		resp, err := ec.balanceHistory(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result:     resp,
+			Idempotent: true,
+		}, nil
+	case BlockOperationsMethod:
+		resp, err := ec.blockOperations(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case RollupInfoMethod:
+		resp, err := ec.rollupInfoCallResult(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case DecodeTransactionMethod:
+		resp, err := DecodeTransaction(request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result:     resp,
+			Idempotent: true,
+		}, nil
+	case GetStorageAtMethod:
+		resp, pinned, err := ec.storageAt(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result:     resp,
+			Idempotent: pinned,
+		}, nil
+	case GetProofMethod:
+		resp, err := ec.getProof(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result: resp,
+		}, nil
+	case SendBundleMethod:
+		resp, err := ec.sendBundle(ctx, request.Parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result:     resp,
+			Idempotent: true,
+		}, nil
+	case "eth_chainId":
+		if err := noCallParameters(request.Method, request.Parameters); err != nil {
+			return nil, err
+		}
+
+		resp, err := ec.chainID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result:     resp,
+			Idempotent: true,
+		}, nil
+	case "net_version":
+		if err := noCallParameters(request.Method, request.Parameters); err != nil {
+			return nil, err
+		}
+
+		resp, err := ec.networkID(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RosettaTypes.CallResponse{
+			Result:     resp,
+			Idempotent: true,
+		}, nil
 	}
 
 	return nil, fmt.Errorf("%w: %s", ErrCallMethodInvalid, request.Method)
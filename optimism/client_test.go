@@ -34,8 +34,9 @@ import (
 	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
 	"github.com/ethereum-optimism/optimism/l2geth/core/types"
 	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rlp"
 	"github.com/ethereum-optimism/optimism/l2geth/rpc"
-	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/sync/semaphore"
@@ -110,6 +111,40 @@ func TestStatus_NotSyncing(t *testing.T) {
 		},
 	).Once()
 
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_syncing",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/syncing_false.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"admin_peers",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			file, err := ioutil.ReadFile("testdata/admin_peers.json")
+			assert.NoError(t, err)
+
+			assert.NoError(t, json.Unmarshal(file, args.Get(1)))
+		},
+	).Once()
+
 	block, timestamp, syncStatus, peers, err := c.Status(ctx)
 	assert.Equal(t, &RosettaTypes.BlockIdentifier{
 		Hash:  "0x48269a339ce1489cff6bab70eff432289c4f490b81dbd00ff1f81c68de06b842",
@@ -119,8 +154,28 @@ func TestStatus_NotSyncing(t *testing.T) {
 	assert.Equal(t, &RosettaTypes.SyncStatus{
 		CurrentIndex: RosettaTypes.Int64(8916656),
 		TargetIndex:  RosettaTypes.Int64(8916656),
+		Synced:       RosettaTypes.Bool(true),
 	}, syncStatus)
-	assert.Nil(t, peers)
+	assert.Len(t, peers, 2)
+	assert.Equal(t, &RosettaTypes.Peer{
+		PeerID: "5d1ad4b6d3a1f43ab23f3d12b4a1f4ba6b9d92d9f3f8a0ac5ba5e9d7e32c8f2e",
+		Metadata: map[string]interface{}{
+			"name":           "Geth/v0.5.11-stable-a8763df7/linux-amd64/go1.15.13",
+			"enode":          "enode://a1b7d7dcb2b7e8e2b4bb7e5a3c1e74bf4d5e0a9c1f4a4ad6b0b9b4e7d7c2f3a4b5c6d7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8@34.229.12.8:30303",
+			"caps":           []string{"eth/63", "eth/64", "eth/65"},
+			"remote_address": "34.229.12.8:30303",
+			"inbound":        false,
+			"protocols": map[string]interface{}{
+				"eth": map[string]interface{}{
+					"version":    float64(65),
+					"difficulty": float64(1),
+					"head":       "0x48269a339ce1489cff6bab70eff432289c4f490b81dbd00ff1f81c68de06b842",
+				},
+			},
+		},
+	}, peers[0])
+	assert.Equal(t, "9b4c2ea1df5e2b7fc7bd5a8fa4f29d0c3e7de41b8fbc0e5a9d31ab7e08f6c3d1", peers[1].PeerID)
+	assert.Equal(t, true, peers[1].Metadata["inbound"])
 	assert.NoError(t, err)
 
 	mockJSONRPC.AssertExpectations(t)
@@ -162,6 +217,34 @@ func TestStatus_Syncing(t *testing.T) {
 		},
 	).Once()
 
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_syncing",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/syncing_info.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+
+	// Hosted nodes don't expose the admin API
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"admin_peers",
+	).Return(
+		&jsonError{code: -32601, message: "The method admin_peers does not exist/is not available"},
+	).Once()
+
 	block, timestamp, syncStatus, peers, err := c.Status(ctx)
 	assert.Equal(t, &RosettaTypes.BlockIdentifier{
 		Hash:  "0x48269a339ce1489cff6bab70eff432289c4f490b81dbd00ff1f81c68de06b842",
@@ -169,8 +252,9 @@ func TestStatus_Syncing(t *testing.T) {
 	}, block)
 	assert.Equal(t, int64(1603225195000), timestamp)
 	assert.Equal(t, &RosettaTypes.SyncStatus{
-		CurrentIndex: RosettaTypes.Int64(8916656),
-		TargetIndex:  RosettaTypes.Int64(8916656),
+		CurrentIndex: RosettaTypes.Int64(25),
+		TargetIndex:  RosettaTypes.Int64(8916760),
+		Synced:       RosettaTypes.Bool(false),
 	}, syncStatus)
 	assert.Nil(t, peers)
 	assert.NoError(t, err)
@@ -179,6 +263,71 @@ func TestStatus_Syncing(t *testing.T) {
 	mockGraphQL.AssertExpectations(t)
 }
 
+func TestClose(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+
+	c := &Client{
+		c:              mockJSONRPC,
+		g:              mockGraphQL,
+		traceSemaphore: semaphore.NewWeighted(100),
+	}
+
+	mockJSONRPC.On("Close").Return().Once()
+
+	assert.NoError(t, c.Close())
+	assert.NoError(t, c.Close())
+
+	ctx := context.Background()
+	block, err := c.Block(ctx, nil)
+	assert.Nil(t, block)
+	assert.True(t, errors.Is(err, ErrClientClosed))
+
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{Method: "eth_getBlockByNumber"})
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrClientClosed))
+
+	_, err = c.PendingNonceAt(ctx, common.HexToAddress("0xfFC614eE978630D7fB0C06758DeB580c152154d3"))
+	assert.True(t, errors.Is(err, ErrClientClosed))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestWrapJSONRPC(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+	cache := &traceCache{client: mockJSONRPC}
+
+	c := &Client{
+		c:               mockJSONRPC,
+		currencyFetcher: cf,
+		traceCache:      cache,
+	}
+	c.wrapJSONRPC(func(inner JSONRPC) JSONRPC {
+		return &rateLimitedJSONRPC{JSONRPC: inner}
+	})
+
+	// Components that query the node directly use the wrapped client
+	wrapped, ok := c.c.(*rateLimitedJSONRPC)
+	assert.True(t, ok)
+	assert.Equal(t, mockJSONRPC, wrapped.JSONRPC)
+	assert.Equal(t, c.c, cf.(*ERC20CurrencyFetcher).c)
+	assert.Equal(t, c.c, cache.client)
+}
+
+func TestNewClient_DisableGraphQL(t *testing.T) {
+	c, err := NewClient("http://localhost:8545", params.MainnetChainConfig, ClientOptions{
+		EnableGethTracer: true,
+		DisableGraphQL:   true,
+	})
+	assert.NoError(t, err)
+
+	assert.Nil(t, c.g)
+	assert.NoError(t, c.Close())
+}
+
 func TestBalance(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
@@ -220,7 +369,7 @@ func TestBalance(t *testing.T) {
 		"BatchCallContext",
 		ctx,
 		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 3 && rpcs[0].Method == "eth_getBalance" && rpcs[1].Method == "eth_getTransactionCount" && rpcs[2].Method == "eth_getCode"
+			return len(rpcs) == 4 && rpcs[0].Method == "eth_getBalance" && rpcs[1].Method == "eth_getTransactionCount" && rpcs[2].Method == "eth_getCode" && rpcs[3].Method == "eth_call"
 		}),
 	).Return(
 		nil,
@@ -228,45 +377,33 @@ func TestBalance(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).([]rpc.BatchElem)
 
-			assert.Len(t, r, 3)
-			for i := range r {
+			assert.Len(t, r, 4)
+			for i := range r[:3] {
 				assert.Len(t, r[i].Args, 2)
 				assert.Equal(t, r[i].Args[0], account)
 				assert.Equal(t, r[i].Args[1], blockNum)
 			}
 
 			balance := hexutil.MustDecodeBig("0x2324c0d180077fe7000")
-			*(r[0].Result.(*hexutil.Big)) = (hexutil.Big)(*balance)
-			*(r[1].Result.(*hexutil.Uint64)) = hexutil.Uint64(0)
-			*(r[2].Result.(*string)) = "0x"
-		},
-	).Once()
+			*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(balance)
+			*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+			*(r[2].Result.(**string)) = RosettaTypes.String("0x")
 
-	callData, err := artifacts.ERC20ABI.Pack("balanceOf", common.HexToAddress(account))
-	assert.NoError(t, err)
-	mockJSONRPC.On(
-		"CallContext",
-		ctx,
-		mock.Anything,
-		"eth_call",
-		map[string]string{
-			"data": fmt.Sprintf("0x%s", common.Bytes2Hex(callData)),
-			"to":   opTokenContractAddress.String(),
-		},
-		blockNum,
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).(*string)
-			var expected map[string]interface{}
-			file, err := ioutil.ReadFile("testdata/call_balance_token_10992.json")
+			callData, err := artifacts.ERC20ABI.Pack("balanceOf", common.HexToAddress(account))
 			assert.NoError(t, err)
+			assert.Equal(t, []interface{}{
+				map[string]string{
+					"data": fmt.Sprintf("0x%s", common.Bytes2Hex(callData)),
+					"to":   opTokenContractAddress.String(),
+				},
+				blockNum,
+			}, r[3].Args)
 
-			err = json.Unmarshal(file, &expected)
+			var expected map[string]interface{}
+			file, err := ioutil.ReadFile("testdata/call_balance_token_10992.json")
 			assert.NoError(t, err)
-
-			*r = expected["data"].(string)
+			assert.NoError(t, json.Unmarshal(file, &expected))
+			*(r[3].Result.(*string)) = expected["data"].(string)
 		},
 	).Once()
 
@@ -294,8 +431,9 @@ func TestBalance(t *testing.T) {
 			},
 		},
 		Metadata: map[string]interface{}{
-			"code":  "0x",
-			"nonce": int64(0),
+			"nonce":       int64(0),
+			"is_contract": false,
+			"code_hash":   "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
 		},
 	}, resp)
 	assert.NoError(t, err)
@@ -303,7 +441,7 @@ func TestBalance(t *testing.T) {
 	mockJSONRPC.AssertExpectations(t)
 }
 
-func TestBalance_Historical_Hash(t *testing.T) {
+func TestBalance_NullAccount(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -317,29 +455,27 @@ func TestBalance_Historical_Hash(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	account := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
-	blockNum := fmt.Sprintf("0x%s", strconv.FormatInt(10992, 16))
-
 	mockJSONRPC.On(
 		"CallContext",
 		ctx,
 		mock.Anything,
-		"eth_getBlockByHash",
-		mock.Anything,
+		"eth_getBlockByNumber",
+		"latest",
 		false,
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
-			blockHash := *args.Get(3).(*string)
-			assert.Equal(t, "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae", blockHash)
-
 			r := args.Get(1).(*json.RawMessage)
+
 			file, err := ioutil.ReadFile("testdata/block_10992.json")
 			assert.NoError(t, err)
+
 			*r = json.RawMessage(file)
 		},
 	).Once()
+
+	// Leave every result unset as if the node returned null
 	mockJSONRPC.On(
 		"BatchCallContext",
 		ctx,
@@ -348,65 +484,17 @@ func TestBalance_Historical_Hash(t *testing.T) {
 		}),
 	).Return(
 		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).([]rpc.BatchElem)
-
-			assert.Len(t, r, 3)
-			for i := range r {
-				assert.Len(t, r[i].Args, 2)
-				assert.Equal(t, r[i].Args[0], account)
-				assert.Equal(t, r[i].Args[1], blockNum)
-			}
-
-			balance := hexutil.MustDecodeBig("0x2324c0d180077fe7000")
-			*(r[0].Result.(*hexutil.Big)) = (hexutil.Big)(*balance)
-			*(r[1].Result.(*hexutil.Uint64)) = hexutil.Uint64(0)
-			*(r[2].Result.(*string)) = "0x"
-		},
-	).Once()
-
-	callData, err := artifacts.ERC20ABI.Pack("balanceOf", common.HexToAddress(account))
-	assert.NoError(t, err)
-	mockJSONRPC.On(
-		"CallContext",
-		ctx,
-		mock.Anything,
-		"eth_call",
-		map[string]string{
-			"data": fmt.Sprintf("0x%s", common.Bytes2Hex(callData)),
-			"to":   opTokenContractAddress.String(),
-		},
-		blockNum,
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).(*string)
-			var expected map[string]interface{}
-			file, err := ioutil.ReadFile("testdata/call_balance_token_10992.json")
-			assert.NoError(t, err)
-
-			err = json.Unmarshal(file, &expected)
-			assert.NoError(t, err)
-
-			*r = expected["data"].(string)
-		},
 	).Once()
 
 	resp, err := c.Balance(
 		ctx,
 		&RosettaTypes.AccountIdentifier{
-			Address: account,
-		},
-		&RosettaTypes.PartialBlockIdentifier{
-			Hash: RosettaTypes.String(
-				"0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
-			),
-			Index: RosettaTypes.Int64(8165),
+			Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
 		},
 		nil,
+		[]*RosettaTypes.Currency{Currency},
 	)
+	assert.NoError(t, err)
 	assert.Equal(t, &RosettaTypes.AccountBalanceResponse{
 		BlockIdentifier: &RosettaTypes.BlockIdentifier{
 			Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
@@ -414,26 +502,22 @@ func TestBalance_Historical_Hash(t *testing.T) {
 		},
 		Balances: []*RosettaTypes.Amount{
 			{
-				Value:    "10372550232136640000000",
+				Value:    "0",
 				Currency: Currency,
 			},
-			{
-				Value:    "1000000000000000000000",
-				Currency: OPTokenCurrency,
-			},
 		},
 		Metadata: map[string]interface{}{
-			"code":  "0x",
-			"nonce": int64(0),
+			"nonce":       int64(0),
+			"is_contract": false,
+			"code_hash":   "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
 		},
 	}, resp)
-	assert.NoError(t, err)
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestBalance_Historical_Index(t *testing.T) {
+func TestBalance_Genesis(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -446,131 +530,293 @@ func TestBalance_Historical_Index(t *testing.T) {
 		traceSemaphore:  semaphore.NewWeighted(100),
 	}
 
+	// The genesis header is resolved over JSON-RPC, never
+	// with a GraphQL block(number: 0) query.
 	ctx := context.Background()
-	account := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
-	blockNum := fmt.Sprintf("0x%s", strconv.FormatInt(10992, 16))
-
 	mockJSONRPC.On(
 		"CallContext",
 		ctx,
 		mock.Anything,
 		"eth_getBlockByNumber",
-		blockNum,
+		"0x0",
 		false,
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
-			file, err := ioutil.ReadFile("testdata/block_10992.json")
+
+			file, err := ioutil.ReadFile("testdata/genesis_header.json")
 			assert.NoError(t, err)
+
 			*r = json.RawMessage(file)
 		},
 	).Once()
+
 	mockJSONRPC.On(
 		"BatchCallContext",
 		ctx,
 		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 3 && rpcs[0].Method == "eth_getBalance" && rpcs[1].Method == "eth_getTransactionCount" && rpcs[2].Method == "eth_getCode"
-		}),
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).([]rpc.BatchElem)
-
-			assert.Len(t, r, 3)
-			for i := range r {
-				assert.Len(t, r[i].Args, 2)
-				assert.Equal(t, r[i].Args[0], account)
-				assert.Equal(t, r[i].Args[1], blockNum)
+			if len(rpcs) != 3 {
+				return false
+			}
+			for _, req := range rpcs {
+				if req.Args[1] != "0x0" {
+					return false
+				}
 			}
 
-			balance := hexutil.MustDecodeBig("0x2324c0d180077fe7000")
-			*(r[0].Result.(*hexutil.Big)) = (hexutil.Big)(*balance)
-			*(r[1].Result.(*hexutil.Uint64)) = hexutil.Uint64(0)
-			*(r[2].Result.(*string)) = "0x"
-		},
-	).Once()
-
-	callData, err := artifacts.ERC20ABI.Pack("balanceOf", common.HexToAddress(account))
-	assert.NoError(t, err)
-	mockJSONRPC.On(
-		"CallContext",
-		ctx,
-		mock.Anything,
-		"eth_call",
-		map[string]string{
-			"data": fmt.Sprintf("0x%s", common.Bytes2Hex(callData)),
-			"to":   opTokenContractAddress.String(),
-		},
-		blockNum,
+			return rpcs[0].Method == "eth_getBalance" && rpcs[1].Method == "eth_getTransactionCount" && rpcs[2].Method == "eth_getCode"
+		}),
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
-			r := args.Get(1).(*string)
-			var expected map[string]interface{}
-			file, err := ioutil.ReadFile("testdata/call_balance_token_10992.json")
-			assert.NoError(t, err)
-
-			err = json.Unmarshal(file, &expected)
-			assert.NoError(t, err)
+			r := args.Get(1).([]rpc.BatchElem)
 
-			*r = expected["data"].(string)
+			balance := hexutil.MustDecodeBig("0x56bc75e2d63100000")
+			*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(balance)
+			*(r[1].Result.(**hexutil.Uint64)) = hexUint64(0)
+			*(r[2].Result.(**string)) = RosettaTypes.String("0x")
 		},
 	).Once()
 
 	resp, err := c.Balance(
 		ctx,
 		&RosettaTypes.AccountIdentifier{
-			Address: account,
+			Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
 		},
 		&RosettaTypes.PartialBlockIdentifier{
-			Index: RosettaTypes.Int64(10992),
+			Index: RosettaTypes.Int64(GenesisBlockIndex),
 		},
-		nil,
+		[]*RosettaTypes.Currency{Currency},
 	)
+	assert.NoError(t, err)
 	assert.Equal(t, &RosettaTypes.AccountBalanceResponse{
 		BlockIdentifier: &RosettaTypes.BlockIdentifier{
-			Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
-			Index: 10992,
+			Hash:  "0x1b07e068011044b5ccc09cfdc57d828d721288990a6adafc23f5aaaa93862c07",
+			Index: GenesisBlockIndex,
 		},
 		Balances: []*RosettaTypes.Amount{
 			{
-				Value:    "10372550232136640000000",
+				Value:    "100000000000000000000",
 				Currency: Currency,
 			},
-			{
-				Value:    "1000000000000000000000",
-				Currency: OPTokenCurrency,
-			},
 		},
 		Metadata: map[string]interface{}{
-			"code":  "0x",
-			"nonce": int64(0),
+			"nonce":       int64(0),
+			"is_contract": false,
+			"code_hash":   "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
 		},
 	}, resp)
-	assert.NoError(t, err)
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestBalance_InvalidAddress(t *testing.T) {
-	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
-
-	c := &Client{
-		c:               mockJSONRPC,
-		g:               mockGraphQL,
-		currencyFetcher: cf,
-		traceSemaphore:  semaphore.NewWeighted(100),
-	}
-
-	ctx := context.Background()
+func TestBalanceMetadata(t *testing.T) {
+	contractCode := "0x6080604052348015600f57600080fd5b50"
+	contractCodeHash := crypto.Keccak256Hash(hexutil.MustDecode(contractCode)).Hex()
+
+	tests := map[string]struct {
+		account *RosettaTypes.AccountIdentifier
+		code    string
+		legacy  bool
+
+		expected map[string]interface{}
+	}{
+		"eoa": {
+			account: &RosettaTypes.AccountIdentifier{Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"},
+			code:    "0x",
+			expected: map[string]interface{}{
+				"nonce":       int64(1),
+				"is_contract": false,
+				"code_hash":   "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+			},
+		},
+		"contract": {
+			account: &RosettaTypes.AccountIdentifier{Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"},
+			code:    contractCode,
+			expected: map[string]interface{}{
+				"nonce":       int64(1),
+				"is_contract": true,
+				"code_hash":   contractCodeHash,
+			},
+		},
+		"contract with include_code": {
+			account: &RosettaTypes.AccountIdentifier{
+				Address:  "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+				Metadata: map[string]interface{}{"include_code": true},
+			},
+			code: contractCode,
+			expected: map[string]interface{}{
+				"nonce":       int64(1),
+				"is_contract": true,
+				"code_hash":   contractCodeHash,
+				"code":        contractCode,
+			},
+		},
+		"legacy": {
+			account: &RosettaTypes.AccountIdentifier{Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"},
+			code:    contractCode,
+			legacy:  true,
+			expected: map[string]interface{}{
+				"nonce": int64(1),
+				"code":  contractCode,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{legacyBalanceMetadata: test.legacy}
+
+			metadata, err := c.balanceMetadata(test.account, 1, test.code)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, metadata)
+		})
+	}
+}
+
+func TestBalance_Proof(t *testing.T) {
+	var tests = map[string]struct {
+		proofErr error
+
+		expectedMetadata map[string]interface{}
+	}{
+		"proof": {
+			expectedMetadata: map[string]interface{}{
+				"nonce":       int64(0),
+				"is_contract": false,
+				"code_hash":   "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+				"account_proof": []string{
+					"0xf90211a0e8d3b8f9c7e2ad5d0f2c0aeb7f1b9b77e4f66b9f9a3c0de1f2a9b3b4c5d6e7f8a0b3c8c95e0c8b4e8f1f7e6d5c4b3a29180706050403020100ffeeddccbbaa9988a0c1d2e3f4a5b6c7d8e9f00112233445566778899aabbccddeeff00112233445566a0d7e6f5a4b3c2d1e0f9e8d7c6b5a49382716a5b4c3d2e1f00112233445566778880",
+					"0xf8718080a0f1e2d3c4b5a69788796a5b4c3d2e1f0f1e2d3c4b5a69788796a5b4c3d2e1f080808080a0a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f908080808080808080",
+					"0xf8709e3b2f047e05cdf602820ac4b3178efc2b43d55a1b2c3d4e5f6071829304b84ff84d80890232c4c0d180077fe7000a056e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421a0c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+				},
+				"storage_hash": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+				"state_root":   "0x7ee9ad0f0e749dd73f900a4998c90fb1b074a4146d9d3cb0919acc1a91f87c26",
+			},
+		},
+		"unsupported": {
+			proofErr: errors.New("the method eth_getProof does not exist/is not available"),
+			expectedMetadata: map[string]interface{}{
+				"nonce":             int64(0),
+				"is_contract":       false,
+				"code_hash":         "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+				"proof_unavailable": true,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: cf,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"latest",
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+
+					*r = json.RawMessage(file)
+				},
+			).Once()
+
+			account := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 4 && rpcs[3].Method == "eth_getProof"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					assert.Equal(t, []interface{}{account, []string{}, "0x2af0"}, r[3].Args)
+
+					balance := hexutil.MustDecodeBig("0x2324c0d180077fe7000")
+					*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(balance)
+					*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+					*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+
+					if test.proofErr != nil {
+						r[3].Error = test.proofErr
+						return
+					}
+
+					file, err := ioutil.ReadFile("testdata/get_proof_10992.json")
+					assert.NoError(t, err)
+					assert.NoError(t, json.Unmarshal(file, r[3].Result))
+				},
+			).Once()
+
+			resp, err := c.Balance(
+				ctx,
+				&RosettaTypes.AccountIdentifier{
+					Address:  account,
+					Metadata: map[string]interface{}{"include_proof": true},
+				},
+				nil,
+				[]*RosettaTypes.Currency{Currency},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, "10372550232136640000000", resp.Balances[0].Value)
+			assert.Equal(t, test.expectedMetadata, resp.Metadata)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBalance_TokenMetadataCached(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	account := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+	blockNum := fmt.Sprintf("0x%s", strconv.FormatInt(10992, 16))
+	token := opTokenContractAddress.String()
+	currency := &RosettaTypes.Currency{
+		Symbol:   TokenSymbol,
+		Decimals: TokenDecimals,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: token,
+		},
+	}
 
 	mockJSONRPC.On(
 		"CallContext",
@@ -584,188 +830,442 @@ func TestBalance_InvalidAddress(t *testing.T) {
 	).Run(
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
-
 			file, err := ioutil.ReadFile("testdata/block_10992.json")
 			assert.NoError(t, err)
-
 			*r = json.RawMessage(file)
 		},
-	).Once()
+	).Twice()
 	mockJSONRPC.On(
 		"BatchCallContext",
 		ctx,
 		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 3 && rpcs[0].Method == "eth_getBalance" && rpcs[1].Method == "eth_getTransactionCount" && rpcs[2].Method == "eth_getCode"
+			return len(rpcs) == 4 && rpcs[0].Method == "eth_getBalance" && rpcs[3].Method == "eth_call"
 		}),
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
 			r := args.Get(1).([]rpc.BatchElem)
-			assert.Len(t, r, 3)
-			r[0].Error = fmt.Errorf("invalid argument 0")
+			*(r[0].Result.(**hexutil.Big)) = new(hexutil.Big)
+			*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+			*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+			assert.Equal(t, blockNum, r[3].Args[1])
+
+			var expected map[string]interface{}
+			file, err := ioutil.ReadFile("testdata/call_balance_token_10992.json")
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(file, &expected))
+			*(r[3].Result.(*string)) = expected["data"].(string)
 		},
-	).Once()
+	).Twice()
 
-	resp, err := c.Balance(
+	// Token details are only fetched for the first request
+	decimals, err := artifacts.ERC20ABI.Methods["decimals"].Outputs.Pack(big.NewInt(TokenDecimals))
+	assert.NoError(t, err)
+	symbol, err := artifacts.ERC20ABI.Methods["symbol"].Outputs.Pack(TokenSymbol)
+	assert.NoError(t, err)
+	mockJSONRPC.On(
+		"BatchCallContext",
 		ctx,
-		&RosettaTypes.AccountIdentifier{
-			Address: "0x4cfc400fed52f9681b42454c2db4b18ab98f8de",
-		},
-		nil,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 2 && rpcs[0].Method == "eth_call" && rpcs[1].Method == "eth_call"
+		}),
+	).Return(
 		nil,
-	)
-	assert.Nil(t, resp)
-	assert.Error(t, err)
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+			*(r[0].Result.(*string)) = hexutil.Encode(decimals)
+			*(r[1].Result.(*string)) = hexutil.Encode(symbol)
+		},
+	).Once()
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.Balance(
+			ctx,
+			&RosettaTypes.AccountIdentifier{
+				Address: account,
+			},
+			nil,
+			[]*RosettaTypes.Currency{currency},
+		)
+		assert.NoError(t, err)
+		assert.Len(t, resp.Balances, 1)
+		assert.Equal(t, currency, resp.Balances[0].Currency)
+	}
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestBalance_InvalidHash(t *testing.T) {
+func TestBalance_NotERC20(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
 
 	c := &Client{
 		c:               mockJSONRPC,
 		g:               mockGraphQL,
-		currencyFetcher: cf,
+		currencyFetcher: mockCurrencyFetcher,
 		traceSemaphore:  semaphore.NewWeighted(100),
 	}
 
 	ctx := context.Background()
-	invalidHash := "0x7d2a2713026a0e66f131878de2bb2df2fff6c24562c1df61ec0265e5fedf2626"
-
 	mockJSONRPC.On(
 		"CallContext",
 		ctx,
 		mock.Anything,
-		"eth_getBlockByHash",
-		mock.Anything,
+		"eth_getBlockByNumber",
+		"latest",
 		false,
 	).Return(
-		fmt.Errorf("invalid argument"),
+		nil,
 	).Run(
 		func(args mock.Arguments) {
-			blockHash := *args.Get(3).(*string)
-			assert.Equal(t, invalidHash, blockHash)
+			r := args.Get(1).(*json.RawMessage)
+			file, err := ioutil.ReadFile("testdata/block_10992.json")
+			assert.NoError(t, err)
+			*r = json.RawMessage(file)
 		},
 	).Once()
+	token := "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	mockCurrencyFetcher.On(
+		"FetchCurrency",
+		ctx,
+		uint64(10992),
+		token,
+	).Return(
+		nil,
+		ErrNotERC20,
+	).Once()
 
 	resp, err := c.Balance(
 		ctx,
 		&RosettaTypes.AccountIdentifier{
 			Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
 		},
-		&RosettaTypes.PartialBlockIdentifier{
-			Hash: RosettaTypes.String(
-				invalidHash,
-			),
-		},
 		nil,
+		[]*RosettaTypes.Currency{
+			{
+				Symbol:   "AAA",
+				Decimals: 18,
+				Metadata: map[string]interface{}{
+					ContractAddressKey: token,
+				},
+			},
+		},
 	)
 	assert.Nil(t, resp)
-	assert.Error(t, err)
+	assert.True(t, errors.Is(err, ErrNotERC20))
 
 	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
+	mockCurrencyFetcher.AssertExpectations(t)
 }
 
-func TestCall_GetBlockByNumber(t *testing.T) {
+func TestBalance_Currencies(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
 
 	c := &Client{
 		c:               mockJSONRPC,
 		g:               mockGraphQL,
-		currencyFetcher: cf,
+		currencyFetcher: mockCurrencyFetcher,
 		traceSemaphore:  semaphore.NewWeighted(100),
 	}
 
 	ctx := context.Background()
+	account := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+	blockNum := fmt.Sprintf("0x%s", strconv.FormatInt(10992, 16))
+	tokenA := &RosettaTypes.Currency{
+		Symbol:   "AAA",
+		Decimals: 6,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		},
+	}
+	tokenB := &RosettaTypes.Currency{
+		Symbol:   "BBB",
+		Decimals: 18,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		},
+	}
 
 	mockJSONRPC.On(
 		"CallContext",
 		ctx,
 		mock.Anything,
 		"eth_getBlockByNumber",
-		"0x2af0",
+		"latest",
 		false,
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
-			r := args.Get(1).(*map[string]interface{})
-
+			r := args.Get(1).(*json.RawMessage)
 			file, err := ioutil.ReadFile("testdata/block_10992.json")
 			assert.NoError(t, err)
-
-			err = json.Unmarshal(file, r)
-			assert.NoError(t, err)
+			*r = json.RawMessage(file)
 		},
 	).Once()
+	mockCurrencyFetcher.On(
+		"FetchCurrency",
+		ctx,
+		uint64(10992),
+		mock.Anything,
+	).Return(
+		nil,
+		nil,
+	).Twice()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_10992.json")
-	assert.NoError(t, err)
-	var correct map[string]interface{}
-	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+	// Native and token balances are fetched in a single batch
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 5 && rpcs[3].Method == "eth_call" && rpcs[4].Method == "eth_call"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+			assert.Equal(t, "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", r[3].Args[0].(map[string]string)["to"])
+			assert.Equal(t, "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", r[4].Args[0].(map[string]string)["to"])
+			for i := range r {
+				assert.Equal(t, blockNum, r[i].Args[1])
+			}
 
-	resp, err := c.Call(
+			*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(big.NewInt(100))
+			*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+			*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+			*(r[3].Result.(*string)) = hexutil.EncodeBig(big.NewInt(5000000))
+			*(r[4].Result.(*string)) = hexutil.EncodeBig(big.NewInt(0))
+		},
+	).Once()
+
+	resp, err := c.Balance(
 		ctx,
-		&RosettaTypes.CallRequest{
-			Method: "eth_getBlockByNumber",
-			Parameters: map[string]interface{}{
-				"index":                    RosettaTypes.Int64(10992),
-				"show_transaction_details": false,
-			},
+		&RosettaTypes.AccountIdentifier{
+			Address: account,
 		},
+		nil,
+		[]*RosettaTypes.Currency{tokenA, Currency, tokenB},
 	)
-	assert.Equal(t, &RosettaTypes.CallResponse{
-		Result:     correct,
-		Idempotent: false,
-	}, resp)
 	assert.NoError(t, err)
+	assert.Equal(t, []*RosettaTypes.Amount{
+		{
+			Value:    "5000000",
+			Currency: tokenA,
+		},
+		{
+			Value:    "100",
+			Currency: Currency,
+		},
+		{
+			Value:    "0",
+			Currency: tokenB,
+		},
+	}, resp.Balances)
 
 	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
+	mockCurrencyFetcher.AssertExpectations(t)
 }
 
-func TestCall_GetBlockByNumber_InvalidArgs(t *testing.T) {
-	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
+func TestBalance_LenientTokenBalances(t *testing.T) {
+	tokenA := &RosettaTypes.Currency{
+		Symbol:   "AAA",
+		Decimals: 6,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+		},
+	}
+	tokenB := &RosettaTypes.Currency{
+		Symbol:   "BBB",
+		Decimals: 18,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+		},
+	}
+	tokenC := &RosettaTypes.Currency{
+		Symbol:   "CCC",
+		Decimals: 18,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: "0xcccccccccccccccccccccccccccccccccccccccc",
+		},
+	}
 
-	c := &Client{
-		c:               mockJSONRPC,
-		g:               mockGraphQL,
-		currencyFetcher: cf,
-		traceSemaphore:  semaphore.NewWeighted(100),
+	tests := map[string]struct {
+		lenient bool
+
+		expectedBalances []*RosettaTypes.Amount
+		expectedErr      string
+	}{
+		"lenient": {
+			lenient: true,
+			expectedBalances: []*RosettaTypes.Amount{
+				{
+					Value:    "5000000",
+					Currency: tokenA,
+				},
+				{
+					Value:    "0",
+					Currency: tokenB,
+					Metadata: map[string]interface{}{
+						"call_reverted": true,
+					},
+				},
+				{
+					Value:    "0",
+					Currency: tokenC,
+					Metadata: map[string]interface{}{
+						"call_reverted": true,
+					},
+				},
+			},
+		},
+		"strict": {
+			expectedErr: "execution reverted",
+		},
 	}
 
-	ctx := context.Background()
-	resp, err := c.Call(
-		ctx,
-		&RosettaTypes.CallRequest{
-			Method: "eth_getBlockByNumber",
-			Parameters: map[string]interface{}{
-				"index":                    "a string",
-				"show_transaction_details": false,
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+			c := &Client{
+				c:                    mockJSONRPC,
+				g:                    mockGraphQL,
+				currencyFetcher:      mockCurrencyFetcher,
+				traceSemaphore:       semaphore.NewWeighted(100),
+				lenientTokenBalances: test.lenient,
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"latest",
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockCurrencyFetcher.On(
+				"FetchCurrency",
+				ctx,
+				uint64(10992),
+				mock.Anything,
+			).Return(
+				nil,
+				nil,
+			).Times(3)
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 6
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(big.NewInt(100))
+					*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+					*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+					*(r[3].Result.(*string)) = hexutil.EncodeBig(big.NewInt(5000000))
+					r[4].Error = errors.New("execution reverted")
+					*(r[5].Result.(*string)) = "0x"
+				},
+			).Once()
+
+			resp, err := c.Balance(
+				ctx,
+				&RosettaTypes.AccountIdentifier{
+					Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+				},
+				nil,
+				[]*RosettaTypes.Currency{tokenA, tokenB, tokenC},
+			)
+			if len(test.expectedErr) > 0 {
+				assert.Nil(t, resp)
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedBalances, resp.Balances)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+			mockCurrencyFetcher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHistoricalStateError(t *testing.T) {
+	var tests = map[string]struct {
+		file     string
+		messages func([]byte) []string
+	}{
+		"json-rpc": {
+			file: "testdata/err_missing_trie_node.json",
+			messages: func(file []byte) []string {
+				var resp struct {
+					Error struct {
+						Message string `json:"message"`
+					} `json:"error"`
+				}
+				assert.NoError(t, json.Unmarshal(file, &resp))
+				return []string{resp.Error.Message}
 			},
 		},
-	)
-	assert.Nil(t, resp)
-	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+		"graphql": {
+			file: "testdata/err_graphql_historical_state.json",
+			messages: func(file []byte) []string {
+				var resp graphqlBalance
+				assert.NoError(t, json.Unmarshal(file, &resp))
+
+				messages := []string{}
+				for _, e := range resp.Errors {
+					messages = append(messages, e.Message)
+				}
+				return messages
+			},
+		},
+	}
 
-	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, err := ioutil.ReadFile(test.file)
+			assert.NoError(t, err)
+
+			messages := test.messages(file)
+			assert.NotEmpty(t, messages)
+			for _, msg := range messages {
+				err := historicalStateError(errors.New(msg))
+				assert.True(t, errors.Is(err, ErrHistoricalStateUnavailable))
+				assert.Contains(t, err.Error(), msg)
+			}
+		})
+	}
+
+	other := errors.New("execution reverted")
+	assert.Equal(t, other, historicalStateError(other))
+	assert.NoError(t, historicalStateError(nil))
 }
 
-func TestCall_GetTransactionReceipt(t *testing.T) {
+func TestBalance_HistoricalStateUnavailable(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -779,62 +1279,62 @@ func TestCall_GetTransactionReceipt(t *testing.T) {
 	}
 
 	ctx := context.Background()
-
 	mockJSONRPC.On(
 		"CallContext",
 		ctx,
 		mock.Anything,
-		"eth_getTransactionReceipt",
-		common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a"),
+		"eth_getBlockByNumber",
+		"latest",
+		false,
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
-			r := args.Get(1).(**types.Receipt)
-
-			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_1.json",
-			)
+			r := args.Get(1).(*json.RawMessage)
+			file, err := ioutil.ReadFile("testdata/block_10992.json")
 			assert.NoError(t, err)
-
-			*r = new(types.Receipt)
-
-			assert.NoError(t, (*r).UnmarshalJSON(file))
+			*r = json.RawMessage(file)
 		},
 	).Once()
-	resp, err := c.Call(
+	mockJSONRPC.On(
+		"BatchCallContext",
 		ctx,
-		&RosettaTypes.CallRequest{
-			Method: "eth_getTransactionReceipt",
-			Parameters: map[string]interface{}{
-				"tx_hash": "0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
-			},
-		},
-	)
-	assert.NoError(t, err)
+		mock.Anything,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			file, err := ioutil.ReadFile("testdata/err_missing_trie_node.json")
+			assert.NoError(t, err)
 
-	file, err := ioutil.ReadFile("testdata/tx_receipt_1.json")
-	assert.NoError(t, err)
-	var receiptMap map[string]interface{}
-	assert.NoError(t, json.Unmarshal(file, &receiptMap))
+			var resp struct {
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			assert.NoError(t, json.Unmarshal(file, &resp))
 
-	// set null fields
-	receiptMap["root"] = "0x"
-	receiptMap["contractAddress"] = "0x0000000000000000000000000000000000000000"
-	delete(receiptMap, "from")
-	delete(receiptMap, "to")
+			r := args.Get(1).([]rpc.BatchElem)
+			r[0].Error = errors.New(resp.Error.Message)
+		},
+	).Once()
 
-	assert.Equal(t, &RosettaTypes.CallResponse{
-		Result:     receiptMap,
-		Idempotent: false,
-	}, resp)
-	assert.NoError(t, err)
+	resp, err := c.Balance(
+		ctx,
+		&RosettaTypes.AccountIdentifier{
+			Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+		},
+		nil,
+		[]*RosettaTypes.Currency{Currency},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrHistoricalStateUnavailable))
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestCall_GetTransactionReceipt_InvalidArgs(t *testing.T) {
+func TestBalance_InvalidContractAddress(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -848,20 +1348,30 @@ func TestCall_GetTransactionReceipt_InvalidArgs(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	resp, err := c.Call(
+	resp, err := c.Balance(
 		ctx,
-		&RosettaTypes.CallRequest{
-			Method: "eth_getTransactionReceipt",
+		&RosettaTypes.AccountIdentifier{
+			Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+		},
+		nil,
+		[]*RosettaTypes.Currency{
+			{
+				Symbol:   "AAA",
+				Decimals: 18,
+				Metadata: map[string]interface{}{
+					ContractAddressKey: "0xdeadbeef",
+				},
+			},
 		},
 	)
 	assert.Nil(t, resp)
-	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+	assert.True(t, errors.Is(err, ErrInvalidTokenContractAddress))
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestCall_Call(t *testing.T) {
+func TestBalance_Historical_Hash(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -875,53 +1385,109 @@ func TestCall_Call(t *testing.T) {
 	}
 
 	ctx := context.Background()
+	account := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+	blockNum := fmt.Sprintf("0x%s", strconv.FormatInt(10992, 16))
 
 	mockJSONRPC.On(
 		"CallContext",
 		ctx,
 		mock.Anything,
-		"eth_call",
-		map[string]string{
-			"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
-			"data": "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+		"eth_getBlockByHash",
+		mock.Anything,
+		false,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			blockHash := *args.Get(3).(*string)
+			assert.Equal(t, "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae", blockHash)
+
+			r := args.Get(1).(*json.RawMessage)
+			file, err := ioutil.ReadFile("testdata/block_10992.json")
+			assert.NoError(t, err)
+			*r = json.RawMessage(file)
 		},
-		toBlockNumArg(big.NewInt(11408349)),
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 5 && rpcs[0].Method == "eth_getBalance" && rpcs[1].Method == "eth_getTransactionCount" && rpcs[2].Method == "eth_getCode" && rpcs[3].Method == "eth_call" && rpcs[4].Method == "eth_getBlockByNumber"
+		}),
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
-			r := args.Get(1).(*string)
+			r := args.Get(1).([]rpc.BatchElem)
 
-			var expected map[string]interface{}
-			file, err := ioutil.ReadFile("testdata/call_balance_11408349.json")
+			assert.Len(t, r, 5)
+			for i := range r[:3] {
+				assert.Len(t, r[i].Args, 2)
+				assert.Equal(t, r[i].Args[0], account)
+				assert.Equal(t, r[i].Args[1], blockNum)
+			}
+
+			balance := hexutil.MustDecodeBig("0x2324c0d180077fe7000")
+			*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(balance)
+			*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+			*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+
+			callData, err := artifacts.ERC20ABI.Pack("balanceOf", common.HexToAddress(account))
 			assert.NoError(t, err)
+			assert.Equal(t, []interface{}{
+				map[string]string{
+					"data": fmt.Sprintf("0x%s", common.Bytes2Hex(callData)),
+					"to":   opTokenContractAddress.String(),
+				},
+				blockNum,
+			}, r[3].Args)
 
-			err = json.Unmarshal(file, &expected)
+			var expected map[string]interface{}
+			file, err := ioutil.ReadFile("testdata/call_balance_token_10992.json")
 			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(file, &expected))
+			*(r[3].Result.(*string)) = expected["data"].(string)
 
-			*r = expected["data"].(string)
+			assert.Equal(t, []interface{}{blockNum, false}, r[4].Args)
+			file, err = ioutil.ReadFile("testdata/block_10992.json")
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(file, r[4].Result))
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/call_balance_11408349.json")
-	assert.NoError(t, err)
-	var correct map[string]interface{}
-	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
-
-	resp, err := c.Call(
+	resp, err := c.Balance(
 		ctx,
-		&RosettaTypes.CallRequest{
-			Method: "eth_call",
-			Parameters: map[string]interface{}{
-				"index": 11408349,
-				"to":    "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
-				"data":  "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
-			},
+		&RosettaTypes.AccountIdentifier{
+			Address: account,
+		},
+		&RosettaTypes.PartialBlockIdentifier{
+			Hash: RosettaTypes.String(
+				"0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+			),
+			Index: RosettaTypes.Int64(8165),
 		},
+		nil,
 	)
-	assert.Equal(t, &RosettaTypes.CallResponse{
-		Result:     correct,
-		Idempotent: false,
+	assert.Equal(t, &RosettaTypes.AccountBalanceResponse{
+		BlockIdentifier: &RosettaTypes.BlockIdentifier{
+			Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+			Index: 10992,
+		},
+		Balances: []*RosettaTypes.Amount{
+			{
+				Value:    "10372550232136640000000",
+				Currency: Currency,
+			},
+			{
+				Value:    "1000000000000000000000",
+				Currency: OPTokenCurrency,
+			},
+		},
+		Metadata: map[string]interface{}{
+			"nonce":       int64(0),
+			"is_contract": false,
+			"code_hash":   "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		},
 	}, resp)
 	assert.NoError(t, err)
 
@@ -929,40 +1495,92 @@ func TestCall_Call(t *testing.T) {
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestCall_Call_InvalidArgs(t *testing.T) {
-	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
-
-	c := &Client{
-		c:               mockJSONRPC,
-		g:               mockGraphQL,
-		currencyFetcher: cf,
-		traceSemaphore:  semaphore.NewWeighted(100),
+func TestBalance_Historical_Hash_Orphaned(t *testing.T) {
+	var tests = map[string]struct {
+		canonicalBlock string
+	}{
+		"replaced": {
+			canonicalBlock: "testdata/block_10991.json",
+		},
+		"missing": {},
 	}
 
-	ctx := context.Background()
-	resp, err := c.Call(
-		ctx,
-		&RosettaTypes.CallRequest{
-			Method: "eth_call",
-			Parameters: map[string]interface{}{
-				"index": 11408349,
-				"Hash":  "0x73fc065bc04f16c98247f8ec1e990f581ec58723bcd8059de85f93ab18706448",
-				"to":    "not valid  ",
-				"data":  "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
-			},
-		},
-	)
-	assert.Nil(t, resp)
-	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
 
-	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
-}
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: cf,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
 
-func TestCall_EstimateGas(t *testing.T) {
+			ctx := context.Background()
+			hash := "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae"
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByHash",
+				&hash,
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 4 && rpcs[3].Method == "eth_getBlockByNumber"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(big.NewInt(100))
+					*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+					*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+
+					// The hash now resolves to another block at the same height
+					if len(test.canonicalBlock) > 0 {
+						file, err := ioutil.ReadFile(test.canonicalBlock)
+						assert.NoError(t, err)
+						assert.NoError(t, json.Unmarshal(file, r[3].Result))
+					}
+				},
+			).Once()
+
+			resp, err := c.Balance(
+				ctx,
+				&RosettaTypes.AccountIdentifier{
+					Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+				},
+				&RosettaTypes.PartialBlockIdentifier{
+					Hash: &hash,
+				},
+				[]*RosettaTypes.Currency{Currency},
+			)
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrBlockOrphaned))
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+func TestBalance_Historical_Index(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -976,66 +1594,281 @@ func TestCall_EstimateGas(t *testing.T) {
 	}
 
 	ctx := context.Background()
+	account := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+	blockNum := fmt.Sprintf("0x%s", strconv.FormatInt(10992, 16))
 
 	mockJSONRPC.On(
 		"CallContext",
 		ctx,
 		mock.Anything,
-		"eth_estimateGas",
-		map[string]string{
-			"from": "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
-			"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
-			"data": "0xa9059cbb000000000000000000000000ae7e48ee0f758cd706b76cf7e2175d982800879a" +
-				"00000000000000000000000000000000000000000000000000521c5f98b8ea00",
+		"eth_getBlockByNumber",
+		blockNum,
+		false,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+			file, err := ioutil.ReadFile("testdata/block_10992.json")
+			assert.NoError(t, err)
+			*r = json.RawMessage(file)
 		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 4 && rpcs[0].Method == "eth_getBalance" && rpcs[1].Method == "eth_getTransactionCount" && rpcs[2].Method == "eth_getCode" && rpcs[3].Method == "eth_call"
+		}),
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
-			r := args.Get(1).(*string)
+			r := args.Get(1).([]rpc.BatchElem)
 
-			var expected map[string]interface{}
-			file, err := ioutil.ReadFile("testdata/estimate_gas_0xaD6D458402F60fD3Bd25163575031ACDce07538D.json")
-			assert.NoError(t, err)
+			assert.Len(t, r, 4)
+			for i := range r[:3] {
+				assert.Len(t, r[i].Args, 2)
+				assert.Equal(t, r[i].Args[0], account)
+				assert.Equal(t, r[i].Args[1], blockNum)
+			}
 
-			err = json.Unmarshal(file, &expected)
+			balance := hexutil.MustDecodeBig("0x2324c0d180077fe7000")
+			*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(balance)
+			*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+			*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+
+			callData, err := artifacts.ERC20ABI.Pack("balanceOf", common.HexToAddress(account))
 			assert.NoError(t, err)
+			assert.Equal(t, []interface{}{
+				map[string]string{
+					"data": fmt.Sprintf("0x%s", common.Bytes2Hex(callData)),
+					"to":   opTokenContractAddress.String(),
+				},
+				blockNum,
+			}, r[3].Args)
 
-			*r = expected["data"].(string)
+			var expected map[string]interface{}
+			file, err := ioutil.ReadFile("testdata/call_balance_token_10992.json")
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(file, &expected))
+			*(r[3].Result.(*string)) = expected["data"].(string)
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/estimate_gas_0xaD6D458402F60fD3Bd25163575031ACDce07538D.json")
-	assert.NoError(t, err)
-	var correct map[string]interface{}
-	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
-
-	resp, err := c.Call(
+	resp, err := c.Balance(
 		ctx,
-		&RosettaTypes.CallRequest{
-			Method: "eth_estimateGas",
-			Parameters: map[string]interface{}{
-				"from": "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
-				"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
-				"data": "0xa9059cbb000000000000000000000000ae7e48ee0f758cd706b76cf7e2175d982800879a" +
-					"00000000000000000000000000000000000000000000000000521c5f98b8ea00",
-			},
+		&RosettaTypes.AccountIdentifier{
+			Address: account,
+		},
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(10992),
 		},
+		nil,
 	)
-	assert.Equal(t, &RosettaTypes.CallResponse{
-		Result:     correct,
-		Idempotent: false,
-	}, resp)
-	assert.NoError(t, err)
-
-	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
-}
-
-func TestCall_EstimateGas_InvalidArgs(t *testing.T) {
-	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.Equal(t, &RosettaTypes.AccountBalanceResponse{
+		BlockIdentifier: &RosettaTypes.BlockIdentifier{
+			Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+			Index: 10992,
+		},
+		Balances: []*RosettaTypes.Amount{
+			{
+				Value:    "10372550232136640000000",
+				Currency: Currency,
+			},
+			{
+				Value:    "1000000000000000000000",
+				Currency: OPTokenCurrency,
+			},
+		},
+		Metadata: map[string]interface{}{
+			"nonce":       int64(0),
+			"is_contract": false,
+			"code_hash":   "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		},
+	}, resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestBalance_Tags(t *testing.T) {
+	for _, tag := range []string{SafeBlockTag, FinalizedBlockTag} {
+		t.Run(tag, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: cf,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				tag,
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+
+					*r = json.RawMessage(file)
+				},
+			).Once()
+
+			// The tagged block is resolved by number, so no canonical
+			// header is requested alongside the account state.
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 3 && rpcs[0].Method == "eth_getBalance"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					for i := range r {
+						assert.Equal(t, "0x2af0", r[i].Args[1])
+					}
+
+					*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(big.NewInt(100))
+					*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+					*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+				},
+			).Once()
+
+			resp, err := c.Balance(
+				ctx,
+				&RosettaTypes.AccountIdentifier{
+					Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+				},
+				&RosettaTypes.PartialBlockIdentifier{
+					Hash: RosettaTypes.String(tag),
+				},
+				[]*RosettaTypes.Currency{Currency},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, &RosettaTypes.BlockIdentifier{
+				Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+				Index: 10992,
+			}, resp.BlockIdentifier)
+			assert.Equal(t, "100", resp.Balances[0].Value)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBalance_InvalidAddress(t *testing.T) {
+	var tests = map[string]string{
+		"too short":        "0x4cfc400fed52f9681b42454c2db4b18ab98f8de",
+		"invalid checksum": "0x2F93B2f047E05cdf602820Ac4B3178efc2b43D55",
+	}
+
+	for name, address := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: cf,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			// The address is rejected before the node is queried
+			resp, err := c.Balance(
+				context.Background(),
+				&RosettaTypes.AccountIdentifier{
+					Address: address,
+				},
+				nil,
+				nil,
+			)
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrInvalidAddress))
+			assert.Contains(t, err.Error(), "account_identifier.address")
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBalance_InvalidHash(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	invalidHash := "0x7d2a2713026a0e66f131878de2bb2df2fff6c24562c1df61ec0265e5fedf2626"
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByHash",
+		mock.Anything,
+		false,
+	).Return(
+		fmt.Errorf("invalid argument"),
+	).Run(
+		func(args mock.Arguments) {
+			blockHash := *args.Get(3).(*string)
+			assert.Equal(t, invalidHash, blockHash)
+		},
+	).Once()
+
+	resp, err := c.Balance(
+		ctx,
+		&RosettaTypes.AccountIdentifier{
+			Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+		},
+		&RosettaTypes.PartialBlockIdentifier{
+			Hash: RosettaTypes.String(
+				invalidHash,
+			),
+		},
+		nil,
+	)
+	assert.Nil(t, resp)
+	assert.Error(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_GetBlockByNumber(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
 	assert.NoError(t, err)
 
 	c := &Client{
@@ -1046,13 +1879,152 @@ func TestCall_EstimateGas_InvalidArgs(t *testing.T) {
 	}
 
 	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x2af0",
+		false,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*map[string]interface{})
+
+			file, err := ioutil.ReadFile("testdata/block_10992.json")
+			assert.NoError(t, err)
+
+			err = json.Unmarshal(file, r)
+			assert.NoError(t, err)
+		},
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/block_10992.json")
+	assert.NoError(t, err)
+	var correct map[string]interface{}
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+
 	resp, err := c.Call(
 		ctx,
 		&RosettaTypes.CallRequest{
-			Method: "eth_estimateGas",
+			Method: "eth_getBlockByNumber",
 			Parameters: map[string]interface{}{
-				"From": "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
-				"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
+				"index":                    RosettaTypes.Int64(10992),
+				"show_transaction_details": false,
+			},
+		},
+	)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result:     correct,
+		Idempotent: false,
+	}, resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_BlockWithReceipts(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	blockFile, err := ioutil.ReadFile("testdata/block_1241186.json")
+	assert.NoError(t, err)
+	receiptFile, err := ioutil.ReadFile(
+		"testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
+	)
+	assert.NoError(t, err)
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x12f062",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*map[string]interface{})
+			assert.NoError(t, json.Unmarshal(blockFile, r))
+		},
+	).Once()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockReceipts",
+		"0x0d35e7b4046195842623ec858648497936c906523ea0d477083d0457b7b8a6b2",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*[]map[string]interface{})
+			*r = make([]map[string]interface{}, 1)
+			assert.NoError(t, json.Unmarshal(receiptFile, &(*r)[0]))
+		},
+	).Once()
+
+	var correctBlock, correctReceipt map[string]interface{}
+	assert.NoError(t, json.Unmarshal(blockFile, &correctBlock))
+	assert.NoError(t, json.Unmarshal(receiptFile, &correctReceipt))
+
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "block_with_receipts",
+			Parameters: map[string]interface{}{
+				"index":                    RosettaTypes.Int64(1241186),
+				"show_transaction_details": true,
+			},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result: map[string]interface{}{
+			"block":    correctBlock,
+			"receipts": []map[string]interface{}{correctReceipt},
+		},
+	}, resp)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_GetBlockByNumber_InvalidArgs(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_getBlockByNumber",
+			Parameters: map[string]interface{}{
+				"index":                    "a string",
+				"show_transaction_details": false,
 			},
 		},
 	)
@@ -1063,48 +2035,1848 @@ func TestCall_EstimateGas_InvalidArgs(t *testing.T) {
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestCall_InvalidMethod(t *testing.T) {
-	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
+func TestCall_GetTransactionReceipt(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getTransactionReceipt",
+		common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a"),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(**types.Receipt)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_1.json",
+			)
+			assert.NoError(t, err)
+
+			*r = new(types.Receipt)
+
+			assert.NoError(t, (*r).UnmarshalJSON(file))
+		},
+	).Once()
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_getTransactionReceipt",
+			Parameters: map[string]interface{}{
+				"tx_hash": "0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	file, err := ioutil.ReadFile("testdata/tx_receipt_1.json")
+	assert.NoError(t, err)
+	var receiptMap map[string]interface{}
+	assert.NoError(t, json.Unmarshal(file, &receiptMap))
+
+	// set null fields
+	receiptMap["root"] = "0x"
+	receiptMap["contractAddress"] = "0x0000000000000000000000000000000000000000"
+	delete(receiptMap, "from")
+	delete(receiptMap, "to")
+
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result:     receiptMap,
+		Idempotent: false,
+	}, resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_GetTransactionReceipt_InvalidArgs(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_getTransactionReceipt",
+		},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_Call(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_call",
+		map[string]string{
+			"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+			"data": "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+		},
+		toBlockNumArg(big.NewInt(11408349)),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*string)
+
+			var expected map[string]interface{}
+			file, err := ioutil.ReadFile("testdata/call_balance_11408349.json")
+			assert.NoError(t, err)
+
+			err = json.Unmarshal(file, &expected)
+			assert.NoError(t, err)
+
+			*r = expected["data"].(string)
+		},
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/call_balance_11408349.json")
+	assert.NoError(t, err)
+	var correct map[string]interface{}
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_call",
+			Parameters: map[string]interface{}{
+				"index": 11408349,
+				"to":    "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+				"data":  "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+			},
+		},
+	)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result:     correct,
+		Idempotent: false,
+	}, resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestToBlockNumArg(t *testing.T) {
+	tests := map[string]struct {
+		number   *big.Int
+		expected string
+	}{
+		"nil":       {number: nil, expected: "latest"},
+		"pending":   {number: big.NewInt(PendingBlockIndex), expected: "pending"},
+		"latest":    {number: big.NewInt(LatestBlockIndex), expected: "latest"},
+		"finalized": {number: big.NewInt(FinalizedBlockIndex), expected: "finalized"},
+		"safe":      {number: big.NewInt(SafeBlockIndex), expected: "safe"},
+		"genesis":   {number: big.NewInt(0), expected: "0x0"},
+		"positive":  {number: big.NewInt(11408349), expected: "0xae13dd"},
+		"large": {
+			number:   new(big.Int).Lsh(big.NewInt(1), 64),
+			expected: "0x10000000000000000",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, toBlockNumArg(test.number))
+		})
+	}
+}
+
+func TestCall_Call_BlockTags(t *testing.T) {
+	tests := map[string]struct {
+		params map[string]interface{}
+
+		expectedQuery string
+		expectedErr   error
+	}{
+		"no block": {
+			params:        map[string]interface{}{},
+			expectedQuery: "latest",
+		},
+		"pending": {
+			params:        map[string]interface{}{"index": PendingBlockIndex},
+			expectedQuery: "pending",
+		},
+		"latest": {
+			params:        map[string]interface{}{"index": LatestBlockIndex},
+			expectedQuery: "latest",
+		},
+		"finalized": {
+			params:        map[string]interface{}{"index": FinalizedBlockIndex},
+			expectedQuery: "finalized",
+		},
+		"safe": {
+			params:        map[string]interface{}{"index": SafeBlockIndex},
+			expectedQuery: "safe",
+		},
+		"index over hash": {
+			params: map[string]interface{}{
+				"index": 11408349,
+				"hash":  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+			},
+			expectedQuery: "0xae13dd",
+		},
+		"hash": {
+			params: map[string]interface{}{
+				"hash": "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+			},
+			expectedQuery: "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+		},
+		"unknown tag": {
+			params:      map[string]interface{}{"index": -5},
+			expectedErr: ErrCallParametersInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+			ctx := context.Background()
+
+			params := map[string]interface{}{
+				"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+				"data": "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+			}
+			for k, v := range test.params {
+				params[k] = v
+			}
+
+			if test.expectedErr == nil {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"eth_call",
+					mock.Anything,
+					test.expectedQuery,
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						*args.Get(1).(*string) = "0x"
+					},
+				).Once()
+			}
+
+			_, err := c.Call(ctx, &RosettaTypes.CallRequest{
+				Method:     "eth_call",
+				Parameters: params,
+			})
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_Call_GasAndValue(t *testing.T) {
+	tests := map[string]struct {
+		params map[string]interface{}
+
+		expectedCall map[string]string
+		expectedErr  error
+	}{
+		"value": {
+			params: map[string]interface{}{"value": "0xde0b6b3a7640000"},
+			expectedCall: map[string]string{
+				"value": "0xde0b6b3a7640000",
+			},
+		},
+		"decimal value": {
+			params: map[string]interface{}{"value": 1000000000000000000},
+			expectedCall: map[string]string{
+				"value": "0xde0b6b3a7640000",
+			},
+		},
+		"gas and gas price": {
+			params: map[string]interface{}{
+				"gas":       "100000",
+				"gas_price": "0x3b9aca00",
+			},
+			expectedCall: map[string]string{
+				"gas":      "0x186a0",
+				"gasPrice": "0x3b9aca00",
+			},
+		},
+		"from": {
+			params: map[string]interface{}{
+				"from":  "0xe3a5B4d7f79d64088C8d4ef153A7DDe2B2d47309",
+				"value": 1,
+			},
+			expectedCall: map[string]string{
+				"from":  "0xe3a5B4d7f79d64088C8d4ef153A7DDe2B2d47309",
+				"value": "0x1",
+			},
+		},
+		"invalid from": {
+			params:      map[string]interface{}{"from": "0x123"},
+			expectedErr: ErrInvalidAddress,
+		},
+		"invalid hex value": {
+			params:      map[string]interface{}{"value": "0xzz"},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"negative value": {
+			params:      map[string]interface{}{"value": -1},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"fractional gas": {
+			params:      map[string]interface{}{"gas": 21000.5},
+			expectedErr: ErrCallParametersInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+			ctx := context.Background()
+
+			params := map[string]interface{}{
+				"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+				"data": "0xd0e30db0",
+			}
+			for k, v := range test.params {
+				params[k] = v
+			}
+
+			if test.expectedErr == nil {
+				expectedCall := map[string]string{
+					"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+					"data": "0xd0e30db0",
+				}
+				for k, v := range test.expectedCall {
+					expectedCall[k] = v
+				}
+
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"eth_call",
+					expectedCall,
+					"latest",
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						*args.Get(1).(*string) = "0x"
+					},
+				).Once()
+			}
+
+			_, err := c.Call(ctx, &RosettaTypes.CallRequest{
+				Method:     "eth_call",
+				Parameters: params,
+			})
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_Call_InvalidArgs(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_call",
+			Parameters: map[string]interface{}{
+				"index": 11408349,
+				"Hash":  "0x73fc065bc04f16c98247f8ec1e990f581ec58723bcd8059de85f93ab18706448",
+				"to":    "not valid  ",
+				"data":  "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+			},
+		},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrInvalidAddress))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_EstimateGas(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_estimateGas",
+		map[string]string{
+			"from": "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
+			"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
+			"data": "0xa9059cbb000000000000000000000000ae7e48ee0f758cd706b76cf7e2175d982800879a" +
+				"00000000000000000000000000000000000000000000000000521c5f98b8ea00",
+		},
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*string)
+
+			var expected map[string]interface{}
+			file, err := ioutil.ReadFile("testdata/estimate_gas_0xaD6D458402F60fD3Bd25163575031ACDce07538D.json")
+			assert.NoError(t, err)
+
+			err = json.Unmarshal(file, &expected)
+			assert.NoError(t, err)
+
+			*r = expected["data"].(string)
+		},
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/estimate_gas_0xaD6D458402F60fD3Bd25163575031ACDce07538D.json")
+	assert.NoError(t, err)
+	var correct map[string]interface{}
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_estimateGas",
+			Parameters: map[string]interface{}{
+				"from": "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
+				"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
+				"data": "0xa9059cbb000000000000000000000000ae7e48ee0f758cd706b76cf7e2175d982800879a" +
+					"00000000000000000000000000000000000000000000000000521c5f98b8ea00",
+			},
+		},
+	)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result:     correct,
+		Idempotent: false,
+	}, resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_EstimateGas_Margin(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_estimateGas",
+		map[string]string{
+			"from": "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
+			"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
+			"data": "0x",
+		},
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*string)
+			*r = "0xca30" // 51760
+		},
+	).Once()
+
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_estimateGas",
+			Parameters: map[string]interface{}{
+				"from":           "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
+				"to":             "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
+				"data":           "0x",
+				"margin_percent": 20,
+			},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result: map[string]interface{}{
+			"data":           "0xca30",
+			"raw_gas":        "0xca30",
+			"adjusted_gas":   "0xf2a0", // 62112
+			"margin_percent": int64(20),
+		},
+		Idempotent: false,
+	}, resp)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_EstimateGas_NegativeMargin(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	resp, err := c.Call(
+		context.Background(),
+		&RosettaTypes.CallRequest{
+			Method: "eth_estimateGas",
+			Parameters: map[string]interface{}{
+				"from":           "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
+				"to":             "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
+				"data":           "0x",
+				"margin_percent": -5,
+			},
+		},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestAddGasMargin(t *testing.T) {
+	tests := map[string]struct {
+		estimate string
+		margin   int64
+		expected string
+	}{
+		"no margin":      {estimate: "0xca30", margin: 0, expected: "0xca30"},
+		"twenty percent": {estimate: "0xca30", margin: 20, expected: "0xf2a0"},
+		"rounds up":      {estimate: "0x5209", margin: 33, expected: "0x6d1c"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			adjusted, err := addGasMargin(test.estimate, test.margin)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, adjusted)
+		})
+	}
+
+	_, err := addGasMargin("not hex", 10)
+	assert.Error(t, err)
+}
+
+func TestCall_EstimateGas_StateOverrides(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	from := "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3"
+	to := "0xaD6D458402F60fD3Bd25163575031ACDce07538D"
+	data := "0xa9059cbb000000000000000000000000ae7e48ee0f758cd706b76cf7e2175d982800879a" +
+		"00000000000000000000000000000000000000000000000000521c5f98b8ea00"
+	balance := (*hexutil.Big)(big.NewInt(1000000000000000000))
+	slot := common.HexToHash("0x1")
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_estimateGas",
+		map[string]string{
+			"from": from,
+			"to":   to,
+			"data": data,
+		},
+		"0x2af0",
+		map[string]*AccountOverride{
+			from: {
+				Balance: balance,
+			},
+			to: {
+				StateDiff: map[common.Hash]common.Hash{
+					slot: common.HexToHash("0x64"),
+				},
+			},
+		},
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*string)
+			*r = "0x8fe1"
+		},
+	).Once()
+
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_estimateGas",
+			Parameters: map[string]interface{}{
+				"index": 10992,
+				"from":  from,
+				"to":    to,
+				"data":  data,
+				"state_overrides": map[string]interface{}{
+					from: map[string]interface{}{
+						"balance": "0xde0b6b3a7640000",
+					},
+					to: map[string]interface{}{
+						"stateDiff": map[string]interface{}{
+							slot.Hex(): "0x0000000000000000000000000000000000000000000000000000000000000064",
+						},
+					},
+				},
+			},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result: map[string]interface{}{
+			"data": "0x8fe1",
+		},
+	}, resp)
+
+	// Malformed overrides are rejected before reaching the node
+	for _, overrides := range []interface{}{
+		map[string]interface{}{
+			"0xdeadbeef": map[string]interface{}{"balance": "0x1"},
+		},
+		map[string]interface{}{
+			from: map[string]interface{}{"balance": "1000"},
+		},
+		map[string]interface{}{
+			from: map[string]interface{}{
+				"state":     map[string]interface{}{slot.Hex(): slot.Hex()},
+				"stateDiff": map[string]interface{}{slot.Hex(): slot.Hex()},
+			},
+		},
+		[]string{"not", "a", "map"},
+	} {
+		resp, err := c.Call(
+			ctx,
+			&RosettaTypes.CallRequest{
+				Method: "eth_estimateGas",
+				Parameters: map[string]interface{}{
+					"from":            from,
+					"to":              to,
+					"data":            data,
+					"state_overrides": overrides,
+				},
+			},
+		)
+		assert.Nil(t, resp)
+		assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_EstimateGas_InvalidArgs(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_estimateGas",
+			Parameters: map[string]interface{}{
+				"From": "0xE550f300E477C60CE7e7172d12e5a27e9379D2e3",
+				"to":   "0xaD6D458402F60fD3Bd25163575031ACDce07538D",
+			},
+		},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_TraceTransaction(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	txHash := "0xb358c6958b1cab722752939cbb92e3fec6b6023de360305910ce80c56c3dad9d"
+
+	file, err := ioutil.ReadFile("testdata/struct_log_trace.json")
+	assert.NoError(t, err)
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"debug_traceTransaction",
+		common.HexToHash(txHash),
+		map[string]interface{}{
+			"disableStack":   false,
+			"disableMemory":  true,
+			"disableStorage": false,
+		},
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*StructLogResult)
+			assert.NoError(t, json.Unmarshal(file, r))
+		},
+	).Once()
+
+	var expected StructLogResult
+	assert.NoError(t, json.Unmarshal(file, &expected))
+	correct, err := RosettaTypes.MarshalMap(expected)
+	assert.NoError(t, err)
+
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "debug_traceTransaction",
+			Parameters: map[string]interface{}{
+				"tx_hash":        txHash,
+				"disable_memory": true,
+			},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result:     correct,
+		Idempotent: false,
+	}, resp)
+	assert.Len(t, resp.Result["structLogs"], 4)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_TraceTransaction_InvalidArgs(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "debug_traceTransaction",
+			Parameters: map[string]interface{}{
+				"disable_stack": true,
+			},
+		},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_GetLogs(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	token := "0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1"
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getLogs",
+		map[string]interface{}{
+			"fromBlock": "0x12f062",
+			"toBlock":   "0x12f062",
+			"address":   []string{token},
+		},
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*[]*types.Log)
+
+			file, err := ioutil.ReadFile("testdata/get_logs_transfer.json")
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(file, r))
+		},
+	).Twice()
+
+	params := map[string]interface{}{
+		"from_block": 1241186,
+		"to_block":   1241186,
+		"addresses":  []string{token},
+	}
+
+	// Logs are returned raw unless decoding is requested
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+		Method:     "eth_getLogs",
+		Parameters: params,
+	})
+	assert.NoError(t, err)
+	logs := resp.Result["logs"].([]interface{})
+	assert.Len(t, logs, 2)
+	assert.NotContains(t, logs[0], "event")
+
+	params["decode"] = true
+	resp, err = c.Call(ctx, &RosettaTypes.CallRequest{
+		Method:     "eth_getLogs",
+		Parameters: params,
+	})
+	assert.NoError(t, err)
+	logs = resp.Result["logs"].([]interface{})
+	assert.Len(t, logs, 2)
+
+	transfer := logs[0].(map[string]interface{})
+	assert.Equal(t, "Transfer", transfer["event"])
+	assert.Equal(t, map[string]interface{}{
+		"from":  "0x7492ce19d83b3a0BaC1BEBC9706ce0dF4ADD105F",
+		"to":    "0x55C34cE12566cD4a0625E58C09f38d92D991E7b5",
+		"value": "1000000000000000000",
+	}, transfer["args"])
+	assert.Equal(t, "0x5a3f2a1d8e3b0e8f6c4a2d1b9e7c5a3f1d9b7e5c3a1f9d7b5e3c1a9f7d5b3e1c", transfer["transactionHash"])
+
+	// Approval requires two indexed topics, so this malformed log is left raw
+	assert.NotContains(t, logs[1], "event")
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_GetLogs_InvalidArgs(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_getLogs",
+			Parameters: map[string]interface{}{
+				"block_hash": "0x0d35e7b4046195842623ec858648497936c906523ea0d477083d0457b7b8a6b2",
+				"from_block": 1241186,
+			},
+		},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_ChainID(t *testing.T) {
+	var tests = map[string]struct {
+		chainID hexutil.Uint64
+
+		expectedResult map[string]interface{}
+	}{
+		"base mainnet": {
+			chainID: 8453,
+			expectedResult: map[string]interface{}{
+				"chain_id":         "0x2105",
+				"chain_id_decimal": "8453",
+				"network_name":     "base-mainnet",
+			},
+		},
+		"unknown chain": {
+			chainID: 4660,
+			expectedResult: map[string]interface{}{
+				"chain_id":         "0x1234",
+				"chain_id_decimal": "4660",
+				"network_name":     "4660",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+
+			c := &Client{
+				c:              mockJSONRPC,
+				g:              mockGraphQL,
+				traceSemaphore: semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_chainId",
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*hexutil.Uint64)
+					*r = test.chainID
+				},
+			).Once()
+
+			resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+				Method: "eth_chainId",
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, &RosettaTypes.CallResponse{
+				Result:     test.expectedResult,
+				Idempotent: true,
+			}, resp)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_NetVersion(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"net_version",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*string)
+			*r = "8453"
+		},
+	).Once()
+
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+		Method: "net_version",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result: map[string]interface{}{
+			"network_id":     "8453",
+			"network_id_hex": "0x2105",
+		},
+		Idempotent: true,
+	}, resp)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestCall_ChainID_UnexpectedParameters(t *testing.T) {
+	for _, method := range []string{"eth_chainId", "net_version"} {
+		t.Run(method, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+
+			resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+				Method: method,
+				Parameters: map[string]interface{}{
+					"index": 1,
+				},
+			})
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_InvalidMethod(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "blah",
+		},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrCallMethodInvalid))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func testTraceConfig() (*traceConfig, error) {
+	return loadTraceConfig(tracerSpec{TracerPath: "call_tracer.js"}, 120*time.Second)
+}
+
+func TestBlock_Current(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"latest",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_1.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Len(t, r[0].Args, 2)
+			assert.Equal(
+				t,
+				common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a").Hex(),
+				r[0].Args[0],
+			)
+			assert.Equal(t, tc, r[0].Args[1])
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_trace_1.json",
+			)
+			assert.NoError(t, err)
+
+			call := new(Call)
+			assert.NoError(t, call.UnmarshalJSON(file))
+			*(r[0].Result.(**Call)) = call
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Equal(
+				t,
+				"0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
+				r[0].Args[0],
+			)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_1.json",
+			)
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
+	assert.NoError(t, err)
+	var correct *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+
+	resp, err := c.Block(
+		ctx,
+		nil,
+	)
+	assert.Equal(t, correct.Block, resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestBlock_Tags(t *testing.T) {
+	for _, tag := range []string{SafeBlockTag, FinalizedBlockTag} {
+		t.Run(tag, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+
+			tc, err := testTraceConfig()
+			assert.NoError(t, err)
+			c := &Client{
+				c:              mockJSONRPC,
+				g:              mockGraphQL,
+				tc:             tc,
+				p:              params.GoerliChainConfig,
+				traceSemaphore: semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				tag,
+				true,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+
+					*r = json.RawMessage(file)
+				},
+			).Once()
+
+			resp, err := c.Block(ctx, &RosettaTypes.PartialBlockIdentifier{
+				Hash: RosettaTypes.String(tag),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, &RosettaTypes.BlockIdentifier{
+				Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+				Index: 10992,
+			}, resp.BlockIdentifier)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBlock_Hash(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByHash",
+		"0xbee7192e575af30420cae0c7776304ac196077ee72b048970549e4f08e875453",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_1.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Len(t, r[0].Args, 2)
+			assert.Equal(
+				t,
+				common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a").Hex(),
+				r[0].Args[0],
+			)
+			assert.Equal(t, tc, r[0].Args[1])
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_trace_1.json",
+			)
+			assert.NoError(t, err)
+
+			call := new(Call)
+			assert.NoError(t, call.UnmarshalJSON(file))
+			*(r[0].Result.(**Call)) = call
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Equal(
+				t,
+				"0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
+				r[0].Args[0],
+			)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_1.json",
+			)
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
+	assert.NoError(t, err)
+	var correct *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+
+	resp, err := c.Block(
+		ctx,
+		&RosettaTypes.PartialBlockIdentifier{
+			Hash: RosettaTypes.String(
+				"0xbee7192e575af30420cae0c7776304ac196077ee72b048970549e4f08e875453",
+			),
+		},
+	)
+	assert.Equal(t, correct.Block, resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func jsonifyBlock(b *RosettaTypes.Block) (*RosettaTypes.Block, error) {
+	bytes, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var bo RosettaTypes.Block
+	if err := json.Unmarshal(bytes, &bo); err != nil {
+		return nil, err
+	}
+
+	return &bo, nil
+}
+
+func TestBlock_Index(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		tc:              tc,
+		p:               params.MainnetChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x1",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_1.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Len(t, r[0].Args, 2)
+			assert.Equal(
+				t,
+				common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a").Hex(),
+				r[0].Args[0],
+			)
+			assert.Equal(t, tc, r[0].Args[1])
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_trace_1.json",
+			)
+			assert.NoError(t, err)
+
+			call := new(Call)
+			assert.NoError(t, call.UnmarshalJSON(file))
+			*(r[0].Result.(**Call)) = call
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Equal(
+				t,
+				"0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
+				r[0].Args[0],
+			)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_1.json",
+			)
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
+	assert.NoError(t, err)
+	var correctResp *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+
+	resp, err := c.Block(
+		ctx,
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(1),
+		},
+	)
+	assert.Equal(t, correctResp.Block, resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+// Block with duplicate transaction bug
+func TestBlock_Empty(t *testing.T) {
+	tests := map[string]struct {
+		file string
+	}{
+		"empty transactions": {
+			file: "testdata/block_10992.json",
+		},
+		"null transactions": {
+			file: "testdata/block_empty.json",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			provider := &fakeTraceProvider{}
+
+			// No receipts or traces are requested, so the empty block
+			// doesn't wait for trace capacity either.
+			c := &Client{
+				c:              mockJSONRPC,
+				g:              mockGraphQL,
+				p:              params.MainnetChainConfig,
+				traceProvider:  provider,
+				traceSemaphore: semaphore.NewWeighted(0),
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"0x2af0",
+				true,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+
+					file, err := ioutil.ReadFile(test.file)
+					assert.NoError(t, err)
+
+					*r = json.RawMessage(file)
+				},
+			).Once()
+
+			block, err := c.Block(
+				ctx,
+				&RosettaTypes.PartialBlockIdentifier{
+					Index: RosettaTypes.Int64(10992),
+				},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, &RosettaTypes.BlockIdentifier{
+				Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+				Index: 10992,
+			}, block.BlockIdentifier)
+			assert.NotNil(t, block.Transactions)
+			assert.Empty(t, block.Transactions)
+			assert.Empty(t, provider.blocks)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBlock_985(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x3d9",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_985.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Len(t, r[0].Args, 2)
+			assert.Equal(
+				t,
+				common.HexToHash("0x9ed8f713b2cc6439657db52dcd2fdb9cc944915428f3c6e2a7703e242b259cb9").Hex(),
+				r[0].Args[0],
+			)
+			assert.Equal(t, tc, r[0].Args[1])
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_trace_985.json",
+			)
+			assert.NoError(t, err)
+
+			call := new(Call)
+			assert.NoError(t, call.UnmarshalJSON(file))
+			*(r[0].Result.(**Call)) = call
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Equal(
+				t,
+				"0x9ed8f713b2cc6439657db52dcd2fdb9cc944915428f3c6e2a7703e242b259cb9",
+				r[0].Args[0],
+			)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_0x9ed8f713b2cc6439657db52dcd2fdb9cc944915428f3c6e2a7703e242b259cb9.json",
+			) // nolint
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_985.json")
+	assert.NoError(t, err)
+	var correctResp *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+
+	resp, err := c.Block(
+		ctx,
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(985),
+		},
+	)
+	assert.NoError(t, err)
+
+	// Ensure types match
+	jsonResp, err := jsonifyBlock(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, correctResp.Block, jsonResp)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+
+}
+
+// Block with tx send to non-whitelisted contract
+func TestBlock_87673(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x15679",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_87673.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Len(t, r[0].Args, 2)
+			assert.Equal(
+				t,
+				common.HexToHash("0xcf6e46a1f41e1678fba10590f9d092690c5e8fd2e85a3614715fb21caa74655d").Hex(),
+				r[0].Args[0],
+			)
+			assert.Equal(t, tc, r[0].Args[1])
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_trace_87673.json",
+			)
+			assert.NoError(t, err)
+
+			call := new(Call)
+			assert.NoError(t, call.UnmarshalJSON(file))
+			*(r[0].Result.(**Call)) = call
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Equal(
+				t,
+				"0xcf6e46a1f41e1678fba10590f9d092690c5e8fd2e85a3614715fb21caa74655d",
+				r[0].Args[0],
+			)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_0xcf6e46a1f41e1678fba10590f9d092690c5e8fd2e85a3614715fb21caa74655d.json",
+			) // nolint
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
 
-	c := &Client{
-		c:               mockJSONRPC,
-		g:               mockGraphQL,
-		currencyFetcher: cf,
-		traceSemaphore:  semaphore.NewWeighted(100),
-	}
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_87673.json")
+	assert.NoError(t, err)
+	var correctResp *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
 
-	ctx := context.Background()
-	resp, err := c.Call(
+	resp, err := c.Block(
 		ctx,
-		&RosettaTypes.CallRequest{
-			Method: "blah",
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(87673),
 		},
 	)
-	assert.Nil(t, resp)
-	assert.True(t, errors.Is(err, ErrCallMethodInvalid))
+	assert.NoError(t, err)
+
+	// Ensure types match
+	jsonResp, err := jsonifyBlock(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, correctResp.Block, jsonResp)
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func testTraceConfig() (*tracers.TraceConfig, error) {
-	loadedFile, err := ioutil.ReadFile("call_tracer.js")
-	if err != nil {
-		return nil, fmt.Errorf("%w: could not load tracer file", err)
-	}
-
-	loadedTracer := string(loadedFile)
-	tracerTimeout := "120s"
-	return &tracers.TraceConfig{
-		Timeout: &tracerTimeout,
-		Tracer:  &loadedTracer,
-	}, nil
-}
-
-func TestBlock_Current(t *testing.T) {
+// Block with L2 deposit
+func TestBlock_22698(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -1127,7 +3899,7 @@ func TestBlock_Current(t *testing.T) {
 		ctx,
 		mock.Anything,
 		"eth_getBlockByNumber",
-		"latest",
+		"0x58aa",
 		true,
 	).Return(
 		nil,
@@ -1135,7 +3907,7 @@ func TestBlock_Current(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
 
-			file, err := ioutil.ReadFile("testdata/block_1.json")
+			file, err := ioutil.ReadFile("testdata/block_22698.json")
 			assert.NoError(t, err)
 
 			*r = json.RawMessage(file)
@@ -1157,13 +3929,13 @@ func TestBlock_Current(t *testing.T) {
 			assert.Len(t, r[0].Args, 2)
 			assert.Equal(
 				t,
-				common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a").Hex(),
+				common.HexToHash("0xe58efba2da474da0cd5d32d4a9781629fb832391bc9d8897879790843225b1a9").Hex(),
 				r[0].Args[0],
 			)
 			assert.Equal(t, tc, r[0].Args[1])
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_1.json",
+				"testdata/tx_trace_22698.json",
 			)
 			assert.NoError(t, err)
 
@@ -1187,13 +3959,13 @@ func TestBlock_Current(t *testing.T) {
 			assert.Len(t, r, 1)
 			assert.Equal(
 				t,
-				"0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
+				"0xe58efba2da474da0cd5d32d4a9781629fb832391bc9d8897879790843225b1a9",
 				r[0].Args[0],
 			)
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_1.json",
-			)
+				"testdata/tx_receipt_0xe58efba2da474da0cd5d32d4a9781629fb832391bc9d8897879790843225b1a9.json",
+			) // nolint
 			assert.NoError(t, err)
 
 			receipt := new(types.Receipt)
@@ -1202,23 +3974,30 @@ func TestBlock_Current(t *testing.T) {
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_22698.json")
 	assert.NoError(t, err)
-	var correct *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+	var correctResp *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
 
 	resp, err := c.Block(
 		ctx,
-		nil,
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(22698),
+		},
 	)
-	assert.Equal(t, correct.Block, resp)
 	assert.NoError(t, err)
 
+	// Ensure types match
+	jsonResp, err := jsonifyBlock(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, correctResp.Block, jsonResp)
+
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestBlock_Hash(t *testing.T) {
+// Block with L2 OPTETH withdraw
+func TestBlock_985465(t *testing.T) { // updated
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -1240,8 +4019,8 @@ func TestBlock_Hash(t *testing.T) {
 		"CallContext",
 		ctx,
 		mock.Anything,
-		"eth_getBlockByHash",
-		"0xbee7192e575af30420cae0c7776304ac196077ee72b048970549e4f08e875453",
+		"eth_getBlockByNumber",
+		"0xf0979", // updated
 		true,
 	).Return(
 		nil,
@@ -1249,7 +4028,7 @@ func TestBlock_Hash(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
 
-			file, err := ioutil.ReadFile("testdata/block_1.json")
+			file, err := ioutil.ReadFile("testdata/block_985465.json")
 			assert.NoError(t, err)
 
 			*r = json.RawMessage(file)
@@ -1271,13 +4050,13 @@ func TestBlock_Hash(t *testing.T) {
 			assert.Len(t, r[0].Args, 2)
 			assert.Equal(
 				t,
-				common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a").Hex(),
+				common.HexToHash("0x4ee3a15e4ff6c8e8c6ff64c6a2e74ebce90eccb2e479d7488f5bb070727a3e5c").Hex(),
 				r[0].Args[0],
 			)
 			assert.Equal(t, tc, r[0].Args[1])
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_1.json",
+				"testdata/tx_trace_985465.json",
 			)
 			assert.NoError(t, err)
 
@@ -1301,13 +4080,13 @@ func TestBlock_Hash(t *testing.T) {
 			assert.Len(t, r, 1)
 			assert.Equal(
 				t,
-				"0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
+				"0x4ee3a15e4ff6c8e8c6ff64c6a2e74ebce90eccb2e479d7488f5bb070727a3e5c",
 				r[0].Args[0],
 			)
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_1.json",
-			)
+				"testdata/tx_receipt_0x4ee3a15e4ff6c8e8c6ff64c6a2e74ebce90eccb2e479d7488f5bb070727a3e5c.json",
+			) // nolint
 			assert.NoError(t, err)
 
 			receipt := new(types.Receipt)
@@ -1316,172 +4095,544 @@ func TestBlock_Hash(t *testing.T) {
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_985465.json")
 	assert.NoError(t, err)
-	var correct *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
+	var correctResp *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
 
 	resp, err := c.Block(
 		ctx,
 		&RosettaTypes.PartialBlockIdentifier{
-			Hash: RosettaTypes.String(
-				"0xbee7192e575af30420cae0c7776304ac196077ee72b048970549e4f08e875453",
-			),
+			Index: RosettaTypes.Int64(985465),
 		},
 	)
-	assert.Equal(t, correct.Block, resp)
 	assert.NoError(t, err)
 
+	// Ensure types match
+	jsonResp, err := jsonifyBlock(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, correctResp.Block, jsonResp)
+
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func jsonifyBlock(b *RosettaTypes.Block) (*RosettaTypes.Block, error) {
-	bytes, err := json.Marshal(b)
-	if err != nil {
-		return nil, err
+func TestPendingNonceAt(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
 	}
 
-	var bo RosettaTypes.Block
-	if err := json.Unmarshal(bytes, &bo); err != nil {
-		return nil, err
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 3 && rpcs[1].Method == "eth_getTransactionCount"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+			for i := range r {
+				assert.Equal(t, []interface{}{"0xfFC614eE978630D7fB0C06758DeB580c152154d3", "pending"}, r[i].Args)
+			}
+
+			nonce := hexutil.Uint64(10)
+			*(r[1].Result.(**hexutil.Uint64)) = &nonce
+		},
+	).Once()
+	resp, err := c.PendingNonceAt(
+		ctx,
+		common.HexToAddress("0xfFC614eE978630D7fB0C06758DeB580c152154d3"),
+	)
+	assert.Equal(t, uint64(10), resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestSuggestGasPrice(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
 	}
 
-	return &bo, nil
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_gasPrice",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*hexutil.Big)
+
+			*r = *(*hexutil.Big)(big.NewInt(100000))
+		},
+	).Once()
+	resp, err := c.SuggestGasPrice(
+		ctx,
+	)
+	assert.Equal(t, big.NewInt(100000), resp)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
 }
 
-func TestBlock_Index(t *testing.T) {
+func TestSendTransaction(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
 	assert.NoError(t, err)
 
-	tc, err := testTraceConfig()
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_sendRawTransaction",
+		"0xf86a80843b9aca00825208941ff502f9fe838cd772874cb67d0d96b93fd1d6d78725d4b6199a415d8029a01d110bf9fd468f7d00b3ce530832e99818835f45e9b08c66f8d9722264bb36c7a02711f47ec99f9ac585840daef41b7118b52ec72f02fcb30d874d36b10b668b59", // nolint
+	).Return(
+		nil,
+	).Once()
+
+	rawTx, err := ioutil.ReadFile("testdata/submitted_tx.json")
+	assert.NoError(t, err)
+
+	tx := new(types.Transaction)
+	assert.NoError(t, tx.UnmarshalJSON(rawTx))
+
+	assert.NoError(t, c.SendTransaction(
+		ctx,
+		tx,
+	))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestSendTransactionWithHash(t *testing.T) {
+	rawTx, err := ioutil.ReadFile("testdata/submitted_tx.json")
+	assert.NoError(t, err)
+	tx := new(types.Transaction)
+	assert.NoError(t, tx.UnmarshalJSON(rawTx))
+
+	tests := map[string]struct {
+		nodeHash common.Hash
+
+		expectedHash common.Hash
+		expectedErr  error
+	}{
+		"matching hash": {
+			nodeHash:     tx.Hash(),
+			expectedHash: tx.Hash(),
+		},
+		"mismatched hash": {
+			nodeHash:     common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a"),
+			expectedHash: common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a"),
+			expectedErr:  ErrTransactionHashMismatch,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_sendRawTransaction",
+				"0xf86a80843b9aca00825208941ff502f9fe838cd772874cb67d0d96b93fd1d6d78725d4b6199a415d8029a01d110bf9fd468f7d00b3ce530832e99818835f45e9b08c66f8d9722264bb36c7a02711f47ec99f9ac585840daef41b7118b52ec72f02fcb30d874d36b10b668b59", // nolint
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					*args.Get(1).(*common.Hash) = test.nodeHash
+				},
+			).Once()
+
+			hash, err := c.SendTransactionWithHash(ctx, tx)
+			assert.Equal(t, test.expectedHash, hash)
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestParseRawTransaction(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+
+	c := &Client{
+		c:              mockJSONRPC,
+		g:              mockGraphQL,
+		traceSemaphore: semaphore.NewWeighted(100),
+	}
+
+	rawTx, err := ioutil.ReadFile("testdata/submitted_tx.json")
+	assert.NoError(t, err)
+
+	tx := new(types.Transaction)
+	assert.NoError(t, tx.UnmarshalJSON(rawTx))
+	data, err := rlp.EncodeToBytes(tx)
+	assert.NoError(t, err)
+
+	parsed, err := c.ParseRawTransaction(hexutil.Encode(data))
 	assert.NoError(t, err)
-	c := &Client{
-		c:               mockJSONRPC,
-		g:               mockGraphQL,
-		currencyFetcher: cf,
-		tc:              tc,
-		p:               params.MainnetChainConfig,
-		traceSemaphore:  semaphore.NewWeighted(100),
-	}
+	assert.Equal(t, "0xf5aaf8c5c14fc1e7ad200e3d3b5ce64fc6a211c204194a9585ac38417921ad27", parsed.TransactionIdentifier.Hash)
+	assert.Equal(t, map[string]interface{}{
+		"gas_limit": "0x5208",
+		"gas_price": "0x3b9aca00",
+		"nonce":     "0x0",
+	}, parsed.Metadata)
+
+	// 21000 gas at 1 gwei
+	fee := "21000000000000"
+	value := "10648452716970333"
+	assert.Len(t, parsed.Operations, 4)
+	assert.Equal(t, FeeOpType, parsed.Operations[0].Type)
+	assert.Equal(t, "-"+fee, parsed.Operations[0].Amount.Value)
+	assert.Equal(t, FeeOpType, parsed.Operations[1].Type)
+	assert.Equal(t, fee, parsed.Operations[1].Amount.Value)
+	assert.Equal(t, MustChecksum(sequencerFeeVaultAddr), parsed.Operations[1].Account.Address)
+	assert.Equal(t, parsed.Operations[0].Account, parsed.Operations[2].Account)
+	assert.Equal(t, CallOpType, parsed.Operations[2].Type)
+	assert.Equal(t, "-"+value, parsed.Operations[2].Amount.Value)
+	assert.Equal(t, int64(2), parsed.Operations[2].OperationIdentifier.Index)
+	assert.Equal(t, CallOpType, parsed.Operations[3].Type)
+	assert.Equal(t, "0x1fF502f9fE838cd772874cb67D0d96B93FD1d6D7", parsed.Operations[3].Account.Address)
+	assert.Equal(t, value, parsed.Operations[3].Amount.Value)
+
+	_, err = c.ParseRawTransaction("0xdeadbeef")
+	assert.True(t, errors.Is(err, ErrInvalidRawTransaction))
 
-	ctx := context.Background()
-	mockJSONRPC.On(
-		"CallContext",
-		ctx,
-		mock.Anything,
-		"eth_getBlockByNumber",
-		"0x1",
-		true,
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).(*json.RawMessage)
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
 
-			file, err := ioutil.ReadFile("testdata/block_1.json")
-			assert.NoError(t, err)
+func TestBlock_BloomMismatch(t *testing.T) {
+	var tests = map[string]struct {
+		bloomCheck BloomCheck
 
-			*r = json.RawMessage(file)
+		expectedErr error
+	}{
+		"warn": {
+			bloomCheck: BloomCheckWarn,
 		},
-	).Once()
-	mockJSONRPC.On(
-		"BatchCallContext",
-		ctx,
-		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
-		}),
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).([]rpc.BatchElem)
+		"fail": {
+			bloomCheck:  BloomCheckFail,
+			expectedErr: ErrBloomMismatch,
+		},
+	}
 
-			assert.Len(t, r, 1)
-			assert.Len(t, r[0].Args, 2)
-			assert.Equal(
-				t,
-				common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a").Hex(),
-				r[0].Args[0],
-			)
-			assert.Equal(t, tc, r[0].Args[1])
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
 
-			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_1.json",
-			)
+			tc, err := testTraceConfig()
 			assert.NoError(t, err)
+			c := &Client{
+				c:              mockJSONRPC,
+				g:              mockGraphQL,
+				tc:             tc,
+				p:              params.GoerliChainConfig,
+				traceSemaphore: semaphore.NewWeighted(100),
+				bloomCheck:     test.bloomCheck,
+			}
 
-			call := new(Call)
-			assert.NoError(t, call.UnmarshalJSON(file))
-			*(r[0].Result.(**Call)) = call
-		},
-	).Once()
-	mockJSONRPC.On(
-		"BatchCallContext",
-		ctx,
-		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
-		}),
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).([]rpc.BatchElem)
-
-			assert.Len(t, r, 1)
-			assert.Equal(
-				t,
-				"0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
-				r[0].Args[0],
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"0x12f062",
+				true,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+
+					file, err := ioutil.ReadFile("testdata/block_1241186.json")
+					assert.NoError(t, err)
+
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+
+					file, err := ioutil.ReadFile("testdata/tx_trace_1241186.json")
+					assert.NoError(t, err)
+
+					call := new(Call)
+					assert.NoError(t, call.UnmarshalJSON(file))
+					*(r[0].Result.(**Call)) = call
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+
+					file, err := ioutil.ReadFile("testdata/tx_receipt_tampered_log.json")
+					assert.NoError(t, err)
+
+					receipt := new(types.Receipt)
+					assert.NoError(t, receipt.UnmarshalJSON(file))
+					*(r[0].Result.(**types.Receipt)) = receipt
+				},
+			).Once()
+
+			mismatches := bloomMismatches.Value()
+			resp, err := c.Block(
+				ctx,
+				&RosettaTypes.PartialBlockIdentifier{
+					Index: RosettaTypes.Int64(1241186),
+				},
 			)
+			if test.expectedErr != nil {
+				assert.Nil(t, resp)
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, true, resp.Metadata[BloomMismatchKey])
+				assert.Equal(t, mismatches+1, bloomMismatches.Value())
+			}
 
-			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_1.json",
-			)
-			assert.NoError(t, err)
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
 
-			receipt := new(types.Receipt)
-			assert.NoError(t, receipt.UnmarshalJSON(file))
-			*(r[0].Result.(**types.Receipt)) = receipt
+func TestBlock_MissingReceiptOverride(t *testing.T) {
+	blockHash := "0x0d35e7b4046195842623ec858648497936c906523ea0d477083d0457b7b8a6b2"
+	txHash := "0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009"
+	overrides := []MissingReceiptOverride{
+		{
+			BlockHash: blockHash,
+			TxHash:    txHash,
 		},
-	).Once()
+	}
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
-	assert.NoError(t, err)
-	var correctResp *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+	var tests = map[string]struct {
+		overrides []MissingReceiptOverride
+		omitFee   bool
 
-	resp, err := c.Block(
-		ctx,
-		&RosettaTypes.PartialBlockIdentifier{
-			Index: RosettaTypes.Int64(1),
+		expectedFee string
+		expectedErr bool
+	}{
+		"strict": {
+			expectedErr: true,
 		},
-	)
-	assert.Equal(t, correctResp.Block, resp)
-	assert.NoError(t, err)
+		"other transaction listed": {
+			overrides: []MissingReceiptOverride{
+				{
+					BlockHash: blockHash,
+					TxHash:    "0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
+				},
+			},
+			expectedErr: true,
+		},
+		"fee upper bound": {
+			overrides: overrides,
+			// 0x2534b gas limit at 0x2710 gas price
+			expectedFee: "1523950000",
+		},
+		"fee omitted": {
+			overrides: overrides,
+			omitFee:   true,
+		},
+	}
 
-	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+
+			tc, err := testTraceConfig()
+			assert.NoError(t, err)
+			c := &Client{
+				c:                     mockJSONRPC,
+				g:                     mockGraphQL,
+				tc:                    tc,
+				p:                     params.GoerliChainConfig,
+				traceSemaphore:        semaphore.NewWeighted(100),
+				missingReceipts:       newMissingReceipts(test.overrides),
+				omitMissingReceiptFee: test.omitFee,
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"0x12f062",
+				true,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+
+					file, err := ioutil.ReadFile("testdata/block_1241186.json")
+					assert.NoError(t, err)
+
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					r[0].Error = errors.New("not found")
+				},
+			).Once()
+			if !test.expectedErr {
+				mockJSONRPC.On(
+					"BatchCallContext",
+					ctx,
+					mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+						return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+					}),
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						r := args.Get(1).([]rpc.BatchElem)
+
+						file, err := ioutil.ReadFile("testdata/tx_trace_1241186.json")
+						assert.NoError(t, err)
+
+						call := new(Call)
+						assert.NoError(t, call.UnmarshalJSON(file))
+						*(r[0].Result.(**Call)) = call
+					},
+				).Once()
+			}
+
+			resp, err := c.Block(
+				ctx,
+				&RosettaTypes.PartialBlockIdentifier{
+					Index: RosettaTypes.Int64(1241186),
+				},
+			)
+			if test.expectedErr {
+				assert.Nil(t, resp)
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, resp.Transactions, 1)
+
+				tx := resp.Transactions[0]
+				assert.Equal(t, txHash, tx.TransactionIdentifier.Hash)
+				assert.Equal(t, true, tx.Metadata[ReceiptMissingKey])
+				assert.NotContains(t, tx.Metadata, "receipt")
+
+				var fees []string
+				for _, op := range tx.Operations {
+					if op.Type == FeeOpType {
+						fees = append(fees, op.Amount.Value)
+					}
+				}
+				if len(test.expectedFee) > 0 {
+					assert.Equal(t, []string{"-" + test.expectedFee, test.expectedFee}, fees)
+				} else {
+					assert.Empty(t, fees)
+				}
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
 }
 
-// Block with duplicate transaction bug
-func TestBlock_985(t *testing.T) {
+func TestBlock_ERC20Mint(t *testing.T) {
+	// HACK: block JSON-RPC testdata used in this test were gleaned from a non-predeploy OP token contract on Kovan.
+	// The actual OP token predeploy contract (0x42..42) hasn't minted new tokens. So for now we override the contract
+	// address so we can test this functionality
+	token := "0xf8b089026cad7ddd8cb8d79036a1ff1d4233d64a"
+	supportedTokens := map[string]bool{
+		token: true,
+	}
+
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
 
 	tc, err := testTraceConfig()
 	assert.NoError(t, err)
 	c := &Client{
 		c:               mockJSONRPC,
 		g:               mockGraphQL,
-		currencyFetcher: cf,
+		currencyFetcher: mockCurrencyFetcher,
 		tc:              tc,
 		p:               params.GoerliChainConfig,
 		traceSemaphore:  semaphore.NewWeighted(100),
+		supportedTokens: supportedTokens,
 	}
 
 	ctx := context.Background()
@@ -1490,7 +4641,7 @@ func TestBlock_985(t *testing.T) {
 		ctx,
 		mock.Anything,
 		"eth_getBlockByNumber",
-		"0x3d9",
+		"0x12f062",
 		true,
 	).Return(
 		nil,
@@ -1498,7 +4649,7 @@ func TestBlock_985(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
 
-			file, err := ioutil.ReadFile("testdata/block_985.json")
+			file, err := ioutil.ReadFile("testdata/block_1241186.json")
 			assert.NoError(t, err)
 
 			*r = json.RawMessage(file)
@@ -1520,13 +4671,13 @@ func TestBlock_985(t *testing.T) {
 			assert.Len(t, r[0].Args, 2)
 			assert.Equal(
 				t,
-				common.HexToHash("0x9ed8f713b2cc6439657db52dcd2fdb9cc944915428f3c6e2a7703e242b259cb9").Hex(),
+				common.HexToHash("0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009").Hex(),
 				r[0].Args[0],
 			)
 			assert.Equal(t, tc, r[0].Args[1])
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_985.json",
+				"testdata/tx_trace_1241186.json",
 			)
 			assert.NoError(t, err)
 
@@ -1550,12 +4701,12 @@ func TestBlock_985(t *testing.T) {
 			assert.Len(t, r, 1)
 			assert.Equal(
 				t,
-				"0x9ed8f713b2cc6439657db52dcd2fdb9cc944915428f3c6e2a7703e242b259cb9",
+				"0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009",
 				r[0].Args[0],
 			)
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_0x9ed8f713b2cc6439657db52dcd2fdb9cc944915428f3c6e2a7703e242b259cb9.json",
+				"testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
 			) // nolint
 			assert.NoError(t, err)
 
@@ -1564,8 +4715,20 @@ func TestBlock_985(t *testing.T) {
 			*(r[0].Result.(**types.Receipt)) = receipt
 		},
 	).Once()
+	mockCurrencyFetcher.On(
+		"FetchCurrency",
+		ctx,
+		uint64(1241186),
+		mock.Anything,
+	).Return(
+		&RosettaTypes.Currency{
+			Symbol:   TokenSymbol,
+			Decimals: TokenDecimals,
+			Metadata: map[string]interface{}{"token_address": token}},
+		nil,
+	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_985.json")
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1241186.json")
 	assert.NoError(t, err)
 	var correctResp *RosettaTypes.BlockResponse
 	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
@@ -1573,7 +4736,7 @@ func TestBlock_985(t *testing.T) {
 	resp, err := c.Block(
 		ctx,
 		&RosettaTypes.PartialBlockIdentifier{
-			Index: RosettaTypes.Int64(985),
+			Index: RosettaTypes.Int64(1241186),
 		},
 	)
 	assert.NoError(t, err)
@@ -1585,132 +4748,116 @@ func TestBlock_985(t *testing.T) {
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
-
 }
 
-// Block with tx send to non-whitelisted contract
-func TestBlock_87673(t *testing.T) {
+func TestERC20TokenOps_IndexAllTokens(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
 	assert.NoError(t, err)
 
-	tc, err := testTraceConfig()
-	assert.NoError(t, err)
 	c := &Client{
 		c:               mockJSONRPC,
-		g:               mockGraphQL,
 		currencyFetcher: cf,
-		tc:              tc,
-		p:               params.GoerliChainConfig,
 		traceSemaphore:  semaphore.NewWeighted(100),
+		supportedTokens: map[string]bool{},
 	}
 
 	ctx := context.Background()
-	mockJSONRPC.On(
-		"CallContext",
-		ctx,
-		mock.Anything,
-		"eth_getBlockByNumber",
-		"0x15679",
-		true,
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).(*json.RawMessage)
-
-			file, err := ioutil.ReadFile("testdata/block_87673.json")
-			assert.NoError(t, err)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1241186)})
 
-			*r = json.RawMessage(file)
-		},
-	).Once()
-	mockJSONRPC.On(
-		"BatchCallContext",
-		ctx,
-		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
-		}),
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).([]rpc.BatchElem)
+	file, err := ioutil.ReadFile("testdata/tx_receipt_erc20_transfer.json")
+	assert.NoError(t, err)
+	receipt := new(types.Receipt)
+	assert.NoError(t, receipt.UnmarshalJSON(file))
+	tx := &loadedTransaction{Receipt: receipt}
 
-			assert.Len(t, r, 1)
-			assert.Len(t, r[0].Args, 2)
-			assert.Equal(
-				t,
-				common.HexToHash("0xcf6e46a1f41e1678fba10590f9d092690c5e8fd2e85a3614715fb21caa74655d").Hex(),
-				r[0].Args[0],
-			)
-			assert.Equal(t, tc, r[0].Args[1])
+	// Tokens outside of the supported list are ignored by default
+	ops, err := c.erc20TokenOps(ctx, block, tx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, ops, 0)
 
-			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_87673.json",
-			)
-			assert.NoError(t, err)
+	decimals, err := artifacts.ERC20ABI.Methods["decimals"].Outputs.Pack(big.NewInt(18))
+	assert.NoError(t, err)
+	symbol, err := artifacts.ERC20ABI.Methods["symbol"].Outputs.Pack("DAI")
+	assert.NoError(t, err)
 
-			call := new(Call)
-			assert.NoError(t, call.UnmarshalJSON(file))
-			*(r[0].Result.(**Call)) = call
-		},
-	).Once()
+	token := "0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1"
 	mockJSONRPC.On(
 		"BatchCallContext",
 		ctx,
 		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+			return len(rpcs) == 2 && rpcs[0].Method == "eth_call" && rpcs[1].Method == "eth_call"
 		}),
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
 			r := args.Get(1).([]rpc.BatchElem)
+			for i := range r {
+				assert.Equal(t, token, r[i].Args[0].(map[string]string)["to"])
+				assert.Equal(t, "0x12f062", r[i].Args[1])
+			}
 
-			assert.Len(t, r, 1)
-			assert.Equal(
-				t,
-				"0xcf6e46a1f41e1678fba10590f9d092690c5e8fd2e85a3614715fb21caa74655d",
-				r[0].Args[0],
-			)
-
-			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_0xcf6e46a1f41e1678fba10590f9d092690c5e8fd2e85a3614715fb21caa74655d.json",
-			) // nolint
-			assert.NoError(t, err)
-
-			receipt := new(types.Receipt)
-			assert.NoError(t, receipt.UnmarshalJSON(file))
-			*(r[0].Result.(**types.Receipt)) = receipt
+			*(r[0].Result.(*string)) = hexutil.Encode(decimals)
+			*(r[1].Result.(*string)) = hexutil.Encode(symbol)
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_87673.json")
-	assert.NoError(t, err)
-	var correctResp *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
-
-	resp, err := c.Block(
-		ctx,
-		&RosettaTypes.PartialBlockIdentifier{
-			Index: RosettaTypes.Int64(87673),
+	currency := &RosettaTypes.Currency{
+		Symbol:   "DAI",
+		Decimals: 18,
+		Metadata: map[string]interface{}{
+			ContractAddressKey: token,
+		},
+	}
+	expected := []*RosettaTypes.Operation{
+		{
+			OperationIdentifier: &RosettaTypes.OperationIdentifier{
+				Index: 2,
+			},
+			Type:   PaymentOpType,
+			Status: RosettaTypes.String(SuccessStatus),
+			Account: &RosettaTypes.AccountIdentifier{
+				Address: "0x7492ce19d83b3a0BaC1BEBC9706ce0dF4ADD105F",
+			},
+			Amount: &RosettaTypes.Amount{
+				Value:    "-1000000000000000000",
+				Currency: currency,
+			},
+		},
+		{
+			OperationIdentifier: &RosettaTypes.OperationIdentifier{
+				Index: 3,
+			},
+			RelatedOperations: []*RosettaTypes.OperationIdentifier{
+				{
+					Index: 2,
+				},
+			},
+			Type:   PaymentOpType,
+			Status: RosettaTypes.String(SuccessStatus),
+			Account: &RosettaTypes.AccountIdentifier{
+				Address: "0x55C34cE12566cD4a0625E58C09f38d92D991E7b5",
+			},
+			Amount: &RosettaTypes.Amount{
+				Value:    "1000000000000000000",
+				Currency: currency,
+			},
 		},
-	)
-	assert.NoError(t, err)
+	}
 
-	// Ensure types match
-	jsonResp, err := jsonifyBlock(resp)
-	assert.NoError(t, err)
-	assert.Equal(t, correctResp.Block, jsonResp)
+	// Currency details are fetched once and then served from the cache
+	c.indexAllTokens = true
+	for i := 0; i < 2; i++ {
+		ops, err = c.erc20TokenOps(ctx, block, tx, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ops)
+	}
 
 	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
 }
 
-// Block with L2 deposit
-func TestBlock_22698(t *testing.T) {
+func TestBlock_1502839_OPCriticalBug(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -1733,7 +4880,7 @@ func TestBlock_22698(t *testing.T) {
 		ctx,
 		mock.Anything,
 		"eth_getBlockByNumber",
-		"0x58aa",
+		"latest",
 		true,
 	).Return(
 		nil,
@@ -1741,7 +4888,7 @@ func TestBlock_22698(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
 
-			file, err := ioutil.ReadFile("testdata/block_22698.json")
+			file, err := ioutil.ReadFile("testdata/block_1502839.json")
 			assert.NoError(t, err)
 
 			*r = json.RawMessage(file)
@@ -1763,13 +4910,13 @@ func TestBlock_22698(t *testing.T) {
 			assert.Len(t, r[0].Args, 2)
 			assert.Equal(
 				t,
-				common.HexToHash("0xe58efba2da474da0cd5d32d4a9781629fb832391bc9d8897879790843225b1a9").Hex(),
+				common.HexToHash("0x3ff079ba4ea0745401e9661d623550d24c9412ea9ad578bfbb0d441dadcce9bc").Hex(),
 				r[0].Args[0],
 			)
 			assert.Equal(t, tc, r[0].Args[1])
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_22698.json",
+				"testdata/tx_trace_1502839.json",
 			)
 			assert.NoError(t, err)
 
@@ -1793,13 +4940,13 @@ func TestBlock_22698(t *testing.T) {
 			assert.Len(t, r, 1)
 			assert.Equal(
 				t,
-				"0xe58efba2da474da0cd5d32d4a9781629fb832391bc9d8897879790843225b1a9",
+				"0x3ff079ba4ea0745401e9661d623550d24c9412ea9ad578bfbb0d441dadcce9bc",
 				r[0].Args[0],
 			)
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_0xe58efba2da474da0cd5d32d4a9781629fb832391bc9d8897879790843225b1a9.json",
-			) // nolint
+				"testdata/tx_receipt_0x3ff079ba4ea0745401e9661d623550d24c9412ea9ad578bfbb0d441dadcce9bc.json",
+			)
 			assert.NoError(t, err)
 
 			receipt := new(types.Receipt)
@@ -1808,30 +4955,23 @@ func TestBlock_22698(t *testing.T) {
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_22698.json")
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1502839.json")
 	assert.NoError(t, err)
-	var correctResp *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+	var correct *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
 
 	resp, err := c.Block(
 		ctx,
-		&RosettaTypes.PartialBlockIdentifier{
-			Index: RosettaTypes.Int64(22698),
-		},
+		nil,
 	)
+	assert.Equal(t, correct.Block, resp)
 	assert.NoError(t, err)
 
-	// Ensure types match
-	jsonResp, err := jsonifyBlock(resp)
-	assert.NoError(t, err)
-	assert.Equal(t, correctResp.Block, jsonResp)
-
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-// Block with L2 OPTETH withdraw
-func TestBlock_985465(t *testing.T) { // updated
+func TestBlockCurrent_TraceCache(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -1839,11 +4979,17 @@ func TestBlock_985465(t *testing.T) { // updated
 
 	tc, err := testTraceConfig()
 	assert.NoError(t, err)
+
+	tspec := tracerSpec{TracerPath: "call_tracer.js"}
+	traceCache, err := NewTraceCache(mockJSONRPC, tspec, time.Second*120, 10)
+	assert.NoError(t, err)
+
 	c := &Client{
 		c:               mockJSONRPC,
 		g:               mockGraphQL,
 		currencyFetcher: cf,
 		tc:              tc,
+		traceCache:      traceCache,
 		p:               params.GoerliChainConfig,
 		traceSemaphore:  semaphore.NewWeighted(100),
 	}
@@ -1854,7 +5000,7 @@ func TestBlock_985465(t *testing.T) { // updated
 		ctx,
 		mock.Anything,
 		"eth_getBlockByNumber",
-		"0xf0979", // updated
+		"latest",
 		true,
 	).Return(
 		nil,
@@ -1862,41 +5008,32 @@ func TestBlock_985465(t *testing.T) { // updated
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
 
-			file, err := ioutil.ReadFile("testdata/block_985465.json")
+			file, err := ioutil.ReadFile("testdata/block_1.json")
 			assert.NoError(t, err)
 
 			*r = json.RawMessage(file)
 		},
 	).Once()
 	mockJSONRPC.On(
-		"BatchCallContext",
-		ctx,
-		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
-		}),
+		"CallContext",
+		mock.Anything,
+		mock.Anything,
+		"debug_traceTransaction",
+		common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a").Hex(),
+		tc,
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
-			r := args.Get(1).([]rpc.BatchElem)
-
-			assert.Len(t, r, 1)
-			assert.Len(t, r[0].Args, 2)
-			assert.Equal(
-				t,
-				common.HexToHash("0x4ee3a15e4ff6c8e8c6ff64c6a2e74ebce90eccb2e479d7488f5bb070727a3e5c").Hex(),
-				r[0].Args[0],
-			)
-			assert.Equal(t, tc, r[0].Args[1])
-
 			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_985465.json",
+				"testdata/tx_trace_1.json",
 			)
 			assert.NoError(t, err)
 
 			call := new(Call)
 			assert.NoError(t, call.UnmarshalJSON(file))
-			*(r[0].Result.(**Call)) = call
+			r := args.Get(1).(*Call)
+			*r = *call
 		},
 	).Once()
 	mockJSONRPC.On(
@@ -1914,13 +5051,13 @@ func TestBlock_985465(t *testing.T) { // updated
 			assert.Len(t, r, 1)
 			assert.Equal(
 				t,
-				"0x4ee3a15e4ff6c8e8c6ff64c6a2e74ebce90eccb2e479d7488f5bb070727a3e5c",
+				"0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
 				r[0].Args[0],
 			)
 
 			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_0x4ee3a15e4ff6c8e8c6ff64c6a2e74ebce90eccb2e479d7488f5bb070727a3e5c.json",
-			) // nolint
+				"testdata/tx_receipt_1.json",
+			)
 			assert.NoError(t, err)
 
 			receipt := new(types.Receipt)
@@ -1929,169 +5066,75 @@ func TestBlock_985465(t *testing.T) { // updated
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_985465.json")
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
 	assert.NoError(t, err)
-	var correctResp *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+	var correct *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
 
 	resp, err := c.Block(
 		ctx,
-		&RosettaTypes.PartialBlockIdentifier{
-			Index: RosettaTypes.Int64(985465),
-		},
+		nil,
 	)
+	assert.Equal(t, correct.Block, resp)
 	assert.NoError(t, err)
 
-	// Ensure types match
-	jsonResp, err := jsonifyBlock(resp)
-	assert.NoError(t, err)
-	assert.Equal(t, correctResp.Block, jsonResp)
-
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestPendingNonceAt(t *testing.T) {
-	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
+type fakeTraceProvider struct {
+	traces map[common.Hash]*Call
+	blocks []common.Hash
+}
 
-	c := &Client{
-		c:               mockJSONRPC,
-		g:               mockGraphQL,
-		currencyFetcher: cf,
-		traceSemaphore:  semaphore.NewWeighted(100),
+func (f *fakeTraceProvider) TraceBlock(
+	ctx context.Context,
+	blockHash common.Hash,
+	txHashes []common.Hash,
+) ([]*Call, error) {
+	f.blocks = append(f.blocks, blockHash)
+	traces := make([]*Call, len(txHashes))
+	for i, txHash := range txHashes {
+		trace, err := f.TraceTransaction(ctx, txHash)
+		if err != nil {
+			return nil, err
+		}
+		traces[i] = trace
 	}
-
-	ctx := context.Background()
-	mockJSONRPC.On(
-		"CallContext",
-		ctx,
-		mock.Anything,
-		"eth_getTransactionCount",
-		common.HexToAddress("0xfFC614eE978630D7fB0C06758DeB580c152154d3"),
-		"pending",
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).(*hexutil.Uint64)
-
-			*r = hexutil.Uint64(10)
-		},
-	).Once()
-	resp, err := c.PendingNonceAt(
-		ctx,
-		common.HexToAddress("0xfFC614eE978630D7fB0C06758DeB580c152154d3"),
-	)
-	assert.Equal(t, uint64(10), resp)
-	assert.NoError(t, err)
-
-	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
+	return traces, nil
 }
 
-func TestSuggestGasPrice(t *testing.T) {
-	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
-	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
-	assert.NoError(t, err)
-
-	c := &Client{
-		c:               mockJSONRPC,
-		g:               mockGraphQL,
-		currencyFetcher: cf,
-		traceSemaphore:  semaphore.NewWeighted(100),
+func (f *fakeTraceProvider) TraceTransaction(ctx context.Context, txHash common.Hash) (*Call, error) {
+	trace, ok := f.traces[txHash]
+	if !ok {
+		return nil, fmt.Errorf("no trace for %s", txHash.Hex())
 	}
-
-	ctx := context.Background()
-	mockJSONRPC.On(
-		"CallContext",
-		ctx,
-		mock.Anything,
-		"eth_gasPrice",
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).(*hexutil.Big)
-
-			*r = *(*hexutil.Big)(big.NewInt(100000))
-		},
-	).Once()
-	resp, err := c.SuggestGasPrice(
-		ctx,
-	)
-	assert.Equal(t, big.NewInt(100000), resp)
-	assert.NoError(t, err)
-
-	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
+	return trace, nil
 }
 
-func TestSendTransaction(t *testing.T) {
+func TestBlockCurrent_TraceProvider(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
 	assert.NoError(t, err)
 
-	c := &Client{
-		c:               mockJSONRPC,
-		g:               mockGraphQL,
-		currencyFetcher: cf,
-		traceSemaphore:  semaphore.NewWeighted(100),
-	}
-
-	ctx := context.Background()
-	mockJSONRPC.On(
-		"CallContext",
-		ctx,
-		mock.Anything,
-		"eth_sendRawTransaction",
-		"0xf86a80843b9aca00825208941ff502f9fe838cd772874cb67d0d96b93fd1d6d78725d4b6199a415d8029a01d110bf9fd468f7d00b3ce530832e99818835f45e9b08c66f8d9722264bb36c7a02711f47ec99f9ac585840daef41b7118b52ec72f02fcb30d874d36b10b668b59", // nolint
-	).Return(
-		nil,
-	).Once()
-
-	rawTx, err := ioutil.ReadFile("testdata/submitted_tx.json")
+	file, err := ioutil.ReadFile("testdata/tx_trace_1.json")
 	assert.NoError(t, err)
+	call := new(Call)
+	assert.NoError(t, call.UnmarshalJSON(file))
 
-	tx := new(types.Transaction)
-	assert.NoError(t, tx.UnmarshalJSON(rawTx))
-
-	assert.NoError(t, c.SendTransaction(
-		ctx,
-		tx,
-	))
-
-	mockJSONRPC.AssertExpectations(t)
-	mockGraphQL.AssertExpectations(t)
-}
-
-func TestBlock_ERC20Mint(t *testing.T) {
-	// HACK: block JSON-RPC testdata used in this test were gleaned from a non-predeploy OP token contract on Kovan.
-	// The actual OP token predeploy contract (0x42..42) hasn't minted new tokens. So for now we override the contract
-	// address so we can test this functionality
-	token := "0xf8b089026cad7ddd8cb8d79036a1ff1d4233d64a"
-	supportedTokens := map[string]bool{
-		token: true,
+	txHash := common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a")
+	provider := &fakeTraceProvider{
+		traces: map[common.Hash]*Call{txHash: call},
 	}
 
-	mockJSONRPC := &mocks.JSONRPC{}
-	mockGraphQL := &mocks.GraphQL{}
-	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
-
-	tc, err := testTraceConfig()
-	assert.NoError(t, err)
 	c := &Client{
 		c:               mockJSONRPC,
 		g:               mockGraphQL,
-		currencyFetcher: mockCurrencyFetcher,
-		tc:              tc,
+		currencyFetcher: cf,
+		traceProvider:   provider,
 		p:               params.GoerliChainConfig,
 		traceSemaphore:  semaphore.NewWeighted(100),
-		supportedTokens: supportedTokens,
 	}
 
 	ctx := context.Background()
@@ -2100,49 +5143,18 @@ func TestBlock_ERC20Mint(t *testing.T) {
 		ctx,
 		mock.Anything,
 		"eth_getBlockByNumber",
-		"0x12f062",
+		"latest",
 		true,
 	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).(*json.RawMessage)
-
-			file, err := ioutil.ReadFile("testdata/block_1241186.json")
-			assert.NoError(t, err)
-
-			*r = json.RawMessage(file)
-		},
-	).Once()
-	mockJSONRPC.On(
-		"BatchCallContext",
-		ctx,
-		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
-		}),
-	).Return(
-		nil,
-	).Run(
-		func(args mock.Arguments) {
-			r := args.Get(1).([]rpc.BatchElem)
-
-			assert.Len(t, r, 1)
-			assert.Len(t, r[0].Args, 2)
-			assert.Equal(
-				t,
-				common.HexToHash("0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009").Hex(),
-				r[0].Args[0],
-			)
-			assert.Equal(t, tc, r[0].Args[1])
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
 
-			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_1241186.json",
-			)
+			file, err := ioutil.ReadFile("testdata/block_1.json")
 			assert.NoError(t, err)
 
-			call := new(Call)
-			assert.NoError(t, call.UnmarshalJSON(file))
-			*(r[0].Result.(**Call)) = call
+			*r = json.RawMessage(file)
 		},
 	).Once()
 	mockJSONRPC.On(
@@ -2157,16 +5169,9 @@ func TestBlock_ERC20Mint(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).([]rpc.BatchElem)
 
-			assert.Len(t, r, 1)
-			assert.Equal(
-				t,
-				"0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009",
-				r[0].Args[0],
-			)
-
 			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
-			) // nolint
+				"testdata/tx_receipt_1.json",
+			)
 			assert.NoError(t, err)
 
 			receipt := new(types.Receipt)
@@ -2174,42 +5179,25 @@ func TestBlock_ERC20Mint(t *testing.T) {
 			*(r[0].Result.(**types.Receipt)) = receipt
 		},
 	).Once()
-	mockCurrencyFetcher.On(
-		"FetchCurrency",
-		ctx,
-		uint64(1241186),
-		mock.Anything,
-	).Return(
-		&RosettaTypes.Currency{
-			Symbol:   TokenSymbol,
-			Decimals: TokenDecimals,
-			Metadata: map[string]interface{}{"token_address": token}},
-		nil,
-	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_1241186.json")
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
 	assert.NoError(t, err)
-	var correctResp *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+	var correct *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
 
 	resp, err := c.Block(
 		ctx,
-		&RosettaTypes.PartialBlockIdentifier{
-			Index: RosettaTypes.Int64(1241186),
-		},
+		nil,
 	)
+	assert.Equal(t, correct.Block, resp)
 	assert.NoError(t, err)
-
-	// Ensure types match
-	jsonResp, err := jsonifyBlock(resp)
-	assert.NoError(t, err)
-	assert.Equal(t, correctResp.Block, jsonResp)
+	assert.Equal(t, []common.Hash{common.HexToHash(correct.Block.BlockIdentifier.Hash)}, provider.blocks)
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestBlock_1502839_OPCriticalBug(t *testing.T) {
+func TestBlock_TraceDiagnostics(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -2224,6 +5212,8 @@ func TestBlock_1502839_OPCriticalBug(t *testing.T) {
 		tc:              tc,
 		p:               params.GoerliChainConfig,
 		traceSemaphore:  semaphore.NewWeighted(100),
+
+		skipDegradedTraces: true,
 	}
 
 	ctx := context.Background()
@@ -2240,7 +5230,7 @@ func TestBlock_1502839_OPCriticalBug(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
 
-			file, err := ioutil.ReadFile("testdata/block_1502839.json")
+			file, err := ioutil.ReadFile("testdata/block_trace_diagnostics.json")
 			assert.NoError(t, err)
 
 			*r = json.RawMessage(file)
@@ -2250,7 +5240,7 @@ func TestBlock_1502839_OPCriticalBug(t *testing.T) {
 		"BatchCallContext",
 		ctx,
 		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+			return len(rpcs) == 2 && rpcs[0].Method == "debug_traceTransaction"
 		}),
 	).Return(
 		nil,
@@ -2258,30 +5248,17 @@ func TestBlock_1502839_OPCriticalBug(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).([]rpc.BatchElem)
 
-			assert.Len(t, r, 1)
-			assert.Len(t, r[0].Args, 2)
-			assert.Equal(
-				t,
-				common.HexToHash("0x3ff079ba4ea0745401e9661d623550d24c9412ea9ad578bfbb0d441dadcce9bc").Hex(),
-				r[0].Args[0],
-			)
-			assert.Equal(t, tc, r[0].Args[1])
-
-			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_1502839.json",
-			)
-			assert.NoError(t, err)
-
-			call := new(Call)
-			assert.NoError(t, call.UnmarshalJSON(file))
-			*(r[0].Result.(**Call)) = call
+			// The first trace hits the tracer timeout and the second is
+			// cut short before it can be decoded.
+			r[0].Error = errors.New("execution timeout")
+			r[1].Error = json.Unmarshal([]byte(`{"type":"CALL","calls":[`), r[1].Result)
 		},
 	).Once()
 	mockJSONRPC.On(
 		"BatchCallContext",
 		ctx,
 		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+			return len(rpcs) == 2 && rpcs[0].Method == "eth_getTransactionReceipt"
 		}),
 	).Return(
 		nil,
@@ -2289,41 +5266,54 @@ func TestBlock_1502839_OPCriticalBug(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).([]rpc.BatchElem)
 
-			assert.Len(t, r, 1)
-			assert.Equal(
-				t,
-				"0x3ff079ba4ea0745401e9661d623550d24c9412ea9ad578bfbb0d441dadcce9bc",
-				r[0].Args[0],
-			)
-
-			file, err := ioutil.ReadFile(
-				"testdata/tx_receipt_0x3ff079ba4ea0745401e9661d623550d24c9412ea9ad578bfbb0d441dadcce9bc.json",
-			)
-			assert.NoError(t, err)
-
-			receipt := new(types.Receipt)
-			assert.NoError(t, receipt.UnmarshalJSON(file))
-			*(r[0].Result.(**types.Receipt)) = receipt
+			for i, path := range []string{
+				"testdata/tx_receipt_1.json",
+				"testdata/tx_receipt_trace_diagnostics.json",
+			} {
+				file, err := ioutil.ReadFile(path)
+				assert.NoError(t, err)
+
+				receipt := new(types.Receipt)
+				assert.NoError(t, receipt.UnmarshalJSON(file))
+				*(r[i].Result.(**types.Receipt)) = receipt
+			}
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_1502839.json")
-	assert.NoError(t, err)
-	var correct *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
-
 	resp, err := c.Block(
 		ctx,
 		nil,
 	)
-	assert.Equal(t, correct.Block, resp)
 	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		TraceDiagnosticsKey: map[string]interface{}{
+			"timed_out_traces":     int64(1),
+			"truncated_traces":     int64(1),
+			"unknown_frame_types":  int64(0),
+			"skipped_transactions": int64(2),
+		},
+		FeeRecipientKey: "0x4200000000000000000000000000000000000011",
+	}, resp.Metadata)
+
+	// Untraced transactions keep the operations of their top-level
+	// call, which is a zero-value call in the first transaction.
+	assert.Len(t, resp.Transactions, 2)
+	for i, expected := range [][]string{
+		{FeeOpType, FeeOpType},
+		{FeeOpType, FeeOpType, CreateOpType, CreateOpType},
+	} {
+		var opTypes []string
+		for _, op := range resp.Transactions[i].Operations {
+			opTypes = append(opTypes, op.Type)
+		}
+		assert.Equal(t, expected, opTypes)
+	}
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
 
-func TestBlockCurrent_TraceCache(t *testing.T) {
+func TestBlock_DegradedTracesFail(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
 	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
@@ -2331,17 +5321,11 @@ func TestBlockCurrent_TraceCache(t *testing.T) {
 
 	tc, err := testTraceConfig()
 	assert.NoError(t, err)
-
-	tspec := tracerSpec{TracerPath: "call_tracer.js"}
-	traceCache, err := NewTraceCache(mockJSONRPC, tspec, time.Second*120, 10)
-	assert.NoError(t, err)
-
 	c := &Client{
 		c:               mockJSONRPC,
 		g:               mockGraphQL,
 		currencyFetcher: cf,
 		tc:              tc,
-		traceCache:      traceCache,
 		p:               params.GoerliChainConfig,
 		traceSemaphore:  semaphore.NewWeighted(100),
 	}
@@ -2360,39 +5344,35 @@ func TestBlockCurrent_TraceCache(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).(*json.RawMessage)
 
-			file, err := ioutil.ReadFile("testdata/block_1.json")
+			file, err := ioutil.ReadFile("testdata/block_trace_diagnostics.json")
 			assert.NoError(t, err)
 
 			*r = json.RawMessage(file)
 		},
 	).Once()
 	mockJSONRPC.On(
-		"CallContext",
-		mock.Anything,
-		mock.Anything,
-		"debug_traceTransaction",
-		common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a").Hex(),
-		tc,
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 2 && rpcs[0].Method == "debug_traceTransaction"
+		}),
 	).Return(
 		nil,
 	).Run(
 		func(args mock.Arguments) {
-			file, err := ioutil.ReadFile(
-				"testdata/tx_trace_1.json",
-			)
-			assert.NoError(t, err)
+			r := args.Get(1).([]rpc.BatchElem)
 
-			call := new(Call)
-			assert.NoError(t, call.UnmarshalJSON(file))
-			r := args.Get(1).(*Call)
-			*r = *call
+			// The first trace hits the tracer timeout and the second is
+			// cut short before it can be decoded.
+			r[0].Error = errors.New("execution timeout")
+			r[1].Error = json.Unmarshal([]byte(`{"type":"CALL","calls":[`), r[1].Result)
 		},
 	).Once()
 	mockJSONRPC.On(
 		"BatchCallContext",
 		ctx,
 		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
-			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+			return len(rpcs) == 2 && rpcs[0].Method == "eth_getTransactionReceipt"
 		}),
 	).Return(
 		nil,
@@ -2400,35 +5380,29 @@ func TestBlockCurrent_TraceCache(t *testing.T) {
 		func(args mock.Arguments) {
 			r := args.Get(1).([]rpc.BatchElem)
 
-			assert.Len(t, r, 1)
-			assert.Equal(
-				t,
-				"0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a",
-				r[0].Args[0],
-			)
-
-			file, err := ioutil.ReadFile(
+			for i, path := range []string{
 				"testdata/tx_receipt_1.json",
-			)
-			assert.NoError(t, err)
-
-			receipt := new(types.Receipt)
-			assert.NoError(t, receipt.UnmarshalJSON(file))
-			*(r[0].Result.(**types.Receipt)) = receipt
+				"testdata/tx_receipt_trace_diagnostics.json",
+			} {
+				file, err := ioutil.ReadFile(path)
+				assert.NoError(t, err)
+
+				receipt := new(types.Receipt)
+				assert.NoError(t, receipt.UnmarshalJSON(file))
+				*(r[i].Result.(**types.Receipt)) = receipt
+			}
 		},
 	).Once()
 
-	correctRaw, err := ioutil.ReadFile("testdata/block_response_1.json")
-	assert.NoError(t, err)
-	var correct *RosettaTypes.BlockResponse
-	assert.NoError(t, json.Unmarshal(correctRaw, &correct))
-
 	resp, err := c.Block(
 		ctx,
 		nil,
 	)
-	assert.Equal(t, correct.Block, resp)
-	assert.NoError(t, err)
+	assert.Nil(t, resp)
+	var partial *PartialTraceError
+	assert.True(t, errors.As(err, &partial))
+	assert.Len(t, partial.TimedOut, 1)
+	assert.Len(t, partial.Truncated, 1)
 
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
@@ -2679,6 +5653,64 @@ func TestBlock_GoerliNoFeeEnforcement(t *testing.T) {
 }
 
 // Asserts "buggy" OVM behavior when destroying an account with itself as the recipient
+func TestPopulateTransaction_CallMetadata(t *testing.T) {
+	to := common.HexToAddress("0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1")
+	created := common.HexToAddress("0x72E7845220483451e0B16E053F13dFDC3887BD40")
+	data := hexutil.MustDecode("0xa9059cbb000000000000000000000000ae7e48ee0f758cd706b76cf7e2175d982800f9d5")
+
+	var tests = map[string]struct {
+		tx *types.Transaction
+
+		expectedMetadata map[string]interface{}
+		missingKey       string
+	}{
+		"contract call": {
+			tx: types.NewTransaction(7, to, big.NewInt(0), 21000, big.NewInt(1), data),
+			expectedMetadata: map[string]interface{}{
+				"input": hexutil.Encode(data),
+				"nonce": "0x7",
+				"to":    to.Hex(),
+			},
+			missingKey: "contract_address",
+		},
+		"contract creation": {
+			tx: types.NewContractCreation(8, big.NewInt(0), 21000, big.NewInt(1), []byte{0x60, 0x60}),
+			expectedMetadata: map[string]interface{}{
+				"input":            "0x6060",
+				"nonce":            "0x8",
+				"contract_address": created.Hex(),
+			},
+			missingKey: "to",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{p: params.GoerliChainConfig}
+			from := common.HexToAddress("0x7492ce19d83b3a0BaC1BEBC9706ce0dF4ADD105F")
+			block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{test.tx}, nil, nil)
+			tx := &loadedTransaction{
+				Transaction: test.tx,
+				From:        &from,
+				FeeAmount:   big.NewInt(21000),
+				Miner:       "0x4200000000000000000000000000000000000011",
+				Receipt: &types.Receipt{
+					ContractAddress: created,
+					Status:          types.ReceiptStatusSuccessful,
+					GasUsed:         21000,
+				},
+			}
+
+			populated, err := c.populateTransaction(context.Background(), block, tx, &TraceDiagnostics{})
+			assert.NoError(t, err)
+			for key, value := range test.expectedMetadata {
+				assert.Equal(t, value, populated.Metadata[key])
+			}
+			assert.NotContains(t, populated.Metadata, test.missingKey)
+		})
+	}
+}
+
 func TestBlock_OVMSelfDestruct(t *testing.T) {
 	mockJSONRPC := &mocks.JSONRPC{}
 	mockGraphQL := &mocks.GraphQL{}
@@ -2794,3 +5826,478 @@ func TestBlock_OVMSelfDestruct(t *testing.T) {
 	mockJSONRPC.AssertExpectations(t)
 	mockGraphQL.AssertExpectations(t)
 }
+
+func TestRPCTransaction_LoadedTransaction(t *testing.T) {
+	raw := []byte(`{
+		"blockHash": "0x5572ca94f6ef220f754ee486190a15c43aadcdfb2371ed3be1cd2d20f6edd96f",
+		"blockNumber": "0x3d9",
+		"from": "0x9d7a9b7d6e5b5e5a3d7e9c0f1c2e3b4a5d6e7f80",
+		"gas": "0x5208",
+		"gasPrice": "0xf4240",
+		"maxFeePerGas": "0x1e8480",
+		"maxPriorityFeePerGas": "0x3e8",
+		"hash": "0x0d9b2a8e4c1b9d3f6a5e7c8b9a0f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7",
+		"input": "0x",
+		"nonce": "0x1",
+		"to": "0x4200000000000000000000000000000000000006",
+		"value": "0x0",
+		"type": "0x2",
+		"chainId": "0x2105",
+		"v": "0x1",
+		"r": "0x1",
+		"s": "0x1"
+	}`)
+
+	var tx rpcTransaction
+	assert.NoError(t, json.Unmarshal(raw, &tx))
+
+	loaded := tx.LoadedTransaction()
+	assert.Equal(t, uint64(2), loaded.Type)
+	assert.Equal(t, big.NewInt(8453), loaded.ChainID)
+	assert.Equal(t, big.NewInt(2000000), loaded.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(1000), loaded.MaxPriorityFeePerGas)
+}
+
+func TestPopulateTransaction_GasMetadata(t *testing.T) {
+	var tests = map[string]struct {
+		txType               uint64
+		typeName             string
+		maxFeePerGas         *big.Int
+		maxPriorityFeePerGas *big.Int
+		legacyTxMetadata     bool
+
+		expected map[string]interface{}
+	}{
+		"legacy": {
+			txType:   legacyTxType,
+			typeName: LegacyTxTypeName,
+			expected: map[string]interface{}{
+				"gas_limit": "21000",
+				"gas_used":  "152395",
+				"gas_price": "1000000000",
+			},
+		},
+		"dynamic fee": {
+			txType:               dynamicFeeTxType,
+			typeName:             DynamicFeeTxTypeName,
+			maxFeePerGas:         big.NewInt(2000000000),
+			maxPriorityFeePerGas: big.NewInt(1000000),
+			expected: map[string]interface{}{
+				"gas_limit":                "21000",
+				"gas_used":                 "152395",
+				"gas_price":                "1000000000",
+				"effective_gas_price":      "1000000000",
+				"max_fee_per_gas":          "2000000000",
+				"max_priority_fee_per_gas": "1000000",
+			},
+		},
+		"dynamic fee, legacy metadata": {
+			txType:               dynamicFeeTxType,
+			typeName:             DynamicFeeTxTypeName,
+			maxFeePerGas:         big.NewInt(2000000000),
+			maxPriorityFeePerGas: big.NewInt(1000000),
+			legacyTxMetadata:     true,
+			expected: map[string]interface{}{
+				"gas_limit":                "0x5208",
+				"gas_used":                 "0x2534b",
+				"gas_price":                "0x3b9aca00",
+				"effective_gas_price":      "0x3b9aca00",
+				"max_fee_per_gas":          "0x77359400",
+				"max_priority_fee_per_gas": "0xf4240",
+			},
+		},
+	}
+	gasKeys := []string{
+		"gas_limit",
+		"gas_used",
+		"gas_price",
+		"effective_gas_price",
+		"max_fee_per_gas",
+		"max_priority_fee_per_gas",
+	}
+
+	rawTx, err := ioutil.ReadFile("testdata/submitted_tx.json")
+	assert.NoError(t, err)
+	rawReceipt, err := ioutil.ReadFile(
+		"testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
+	)
+	assert.NoError(t, err)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{
+				p:                &params.ChainConfig{ChainID: big.NewInt(10)},
+				traceSemaphore:   semaphore.NewWeighted(100),
+				legacyTxMetadata: test.legacyTxMetadata,
+			}
+
+			tx := new(types.Transaction)
+			assert.NoError(t, tx.UnmarshalJSON(rawTx))
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(rawReceipt))
+
+			from := common.HexToAddress("0x7492ce19d83b3a0bac1bebc9706ce0df4add105f")
+			block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+			populated, err := c.populateTransaction(
+				context.Background(),
+				block,
+				&loadedTransaction{
+					Transaction:          tx,
+					From:                 &from,
+					FeeAmount:            big.NewInt(100),
+					Miner:                sequencerFeeVaultAddr,
+					Status:               true,
+					Type:                 test.txType,
+					TypeName:             test.typeName,
+					MaxFeePerGas:         test.maxFeePerGas,
+					MaxPriorityFeePerGas: test.maxPriorityFeePerGas,
+					Receipt:              receipt,
+				},
+				&TraceDiagnostics{},
+			)
+			assert.NoError(t, err)
+
+			// Gas and fee values share one encoding
+			for _, key := range gasKeys {
+				assert.Equal(t, test.expected[key], populated.Metadata[key], key)
+			}
+			assert.Equal(t, "0x0", populated.Metadata["nonce"])
+		})
+	}
+}
+
+func TestPopulateTransaction_FailedWithoutTrace(t *testing.T) {
+	from := common.HexToAddress("0x7492ce19d83b3a0bac1bebc9706ce0df4add105f")
+	to := common.HexToAddress("0x4200000000000000000000000000000000000007")
+	value := big.NewInt(1000000000000000000)
+	failedTrace := &Call{
+		Type:         "CALL",
+		From:         from,
+		To:           to,
+		Value:        value,
+		GasUsed:      big.NewInt(21000),
+		Revert:       true,
+		ErrorMessage: "out of gas",
+	}
+
+	var tests = map[string]struct {
+		status     uint64
+		trace      *Call
+		traceError error
+
+		expectedOpTypes []string
+		intendedValue   interface{}
+	}{
+		"failed, trace timed out": {
+			status:          types.ReceiptStatusFailed,
+			traceError:      errTraceTimedOut,
+			expectedOpTypes: []string{FeeOpType, FeeOpType, CallOpType, CallOpType},
+			intendedValue:   value.String(),
+		},
+		"failed, trace truncated": {
+			status:          types.ReceiptStatusFailed,
+			trace:           failedTrace,
+			traceError:      errTraceTruncated,
+			expectedOpTypes: []string{FeeOpType, FeeOpType, CallOpType, CallOpType},
+			intendedValue:   value.String(),
+		},
+		"failed, traced": {
+			status:          types.ReceiptStatusFailed,
+			trace:           failedTrace,
+			expectedOpTypes: []string{FeeOpType, FeeOpType, CallOpType, CallOpType},
+		},
+		"successful, trace timed out": {
+			status:          types.ReceiptStatusSuccessful,
+			traceError:      errTraceTimedOut,
+			expectedOpTypes: []string{FeeOpType, FeeOpType, CallOpType, CallOpType},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{
+				p:              &params.ChainConfig{ChainID: big.NewInt(10)},
+				traceSemaphore: semaphore.NewWeighted(100),
+			}
+
+			tx := types.NewTransaction(0, to, value, 21000, big.NewInt(1000000000), nil)
+			block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+			populated, err := c.populateTransaction(
+				context.Background(),
+				block,
+				&loadedTransaction{
+					Transaction: tx,
+					From:        &from,
+					FeeAmount:   big.NewInt(21000000000000),
+					Miner:       sequencerFeeVaultAddr,
+					Status:      test.status == types.ReceiptStatusSuccessful,
+					Receipt:     &types.Receipt{Status: test.status, GasUsed: 21000},
+					Trace:       test.trace,
+					TraceError:  test.traceError,
+				},
+				&TraceDiagnostics{},
+			)
+			assert.NoError(t, err)
+
+			opTypes := make([]string, len(populated.Operations))
+			for i, op := range populated.Operations {
+				opTypes[i] = op.Type
+			}
+			assert.Equal(t, test.expectedOpTypes, opTypes)
+			assert.Equal(t, test.intendedValue, populated.Metadata[IntendedValueKey])
+		})
+	}
+}
+
+func TestTraceOps_NestedMulticall(t *testing.T) {
+	file, err := ioutil.ReadFile("testdata/tx_trace_multicall.json")
+	assert.NoError(t, err)
+
+	var call *Call
+	assert.NoError(t, json.Unmarshal(file, &call))
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+	ops := traceOps(block, flattenTraces(call, []*flatCall{}), 2)
+
+	// Each sub-transfer is a debit of the calling contract followed by
+	// a credit of the callee related to it, however deep the call.
+	expected := []struct {
+		account string
+		value   string
+		status  string
+	}{
+		{"0x1111111111111111111111111111111111111111", "-4", SuccessStatus},
+		{"0xcA11bde05977b3631167028862bE2a173976CA11", "4", SuccessStatus},
+		{"0xcA11bde05977b3631167028862bE2a173976CA11", "-1", SuccessStatus},
+		{"0x2222222222222222222222222222222222222222", "1", SuccessStatus},
+		{"0xcA11bde05977b3631167028862bE2a173976CA11", "-2", SuccessStatus},
+		{"0x3333333333333333333333333333333333333333", "2", SuccessStatus},
+		{"0x3333333333333333333333333333333333333333", "-2", SuccessStatus},
+		{"0x4444444444444444444444444444444444444444", "2", SuccessStatus},
+		{"0xcA11bde05977b3631167028862bE2a173976CA11", "-1", FailureStatus},
+		{"0x5555555555555555555555555555555555555555", "1", FailureStatus},
+		{"0x5555555555555555555555555555555555555555", "-1", FailureStatus},
+		{"0x6666666666666666666666666666666666666666", "1", FailureStatus},
+	}
+	assert.Len(t, ops, len(expected))
+	for i, op := range ops {
+		assert.Equal(t, int64(i+2), op.OperationIdentifier.Index)
+		assert.Equal(t, CallOpType, op.Type)
+		assert.Equal(t, expected[i].account, op.Account.Address)
+		assert.Equal(t, expected[i].value, op.Amount.Value)
+		assert.Equal(t, expected[i].status, *op.Status)
+
+		if i%2 == 0 {
+			assert.Nil(t, op.RelatedOperations)
+		} else {
+			assert.Equal(t, []*RosettaTypes.OperationIdentifier{
+				{Index: ops[i-1].OperationIdentifier.Index},
+			}, op.RelatedOperations)
+		}
+		if expected[i].status == FailureStatus {
+			assert.Equal(t, "execution reverted", op.Metadata["error"])
+		}
+	}
+}
+
+func TestTraceOps_NativeCallTracer(t *testing.T) {
+	loadCall := func(path string) *Call {
+		file, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+
+		var call *Call
+		assert.NoError(t, json.Unmarshal(file, &call))
+		return call
+	}
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+
+	// The native trace also has a STATICCALL to a precompile without
+	// a value, logs and a revert reason, none of which add operations.
+	jsCall := loadCall("testdata/tx_trace_multicall.json")
+	nativeCall := loadCall("testdata/tx_trace_multicall_native.json")
+	assert.Equal(t, big.NewInt(0), nativeCall.Calls[1].Calls[1].Value)
+	assert.Equal(t, big.NewInt(1000), nativeCall.Calls[1].Calls[1].GasUsed)
+
+	jsOps := traceOps(block, flattenTraces(jsCall, []*flatCall{}), 2)
+	nativeOps := traceOps(block, flattenTraces(nativeCall, []*flatCall{}), 2)
+	assert.NotEmpty(t, nativeOps)
+	assert.Equal(t, jsOps, nativeOps)
+}
+
+func TestRPCTransaction_TypeName(t *testing.T) {
+	var tests = map[string]struct {
+		rawType  string
+		typeName string
+		txType   uint64
+	}{
+		"legacy (no type field)": {
+			typeName: LegacyTxTypeName,
+		},
+		"legacy": {
+			rawType:  `"0x0"`,
+			typeName: LegacyTxTypeName,
+		},
+		"access list": {
+			rawType:  `"0x1"`,
+			typeName: AccessListTxTypeName,
+			txType:   1,
+		},
+		"dynamic fee": {
+			rawType:  `"0x2"`,
+			typeName: DynamicFeeTxTypeName,
+			txType:   2,
+		},
+		"optimism deposit": {
+			rawType:  `"0x7e"`,
+			typeName: DepositTxTypeName,
+			txType:   0x7e,
+		},
+		"unknown": {
+			rawType:  `"0x64"`,
+			typeName: "unknown(0x64)",
+			txType:   0x64,
+		},
+		"malformed": {
+			rawType:  `"0xzz"`,
+			typeName: "unknown(0xzz)",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			typeField := ""
+			if len(test.rawType) > 0 {
+				typeField = fmt.Sprintf(`"type": %s,`, test.rawType)
+			}
+			raw := []byte(fmt.Sprintf(`{
+				%s
+				"gas": "0x5208",
+				"gasPrice": "0xf4240",
+				"hash": "0x0d9b2a8e4c1b9d3f6a5e7c8b9a0f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7",
+				"input": "0x",
+				"nonce": "0x1",
+				"to": "0x4200000000000000000000000000000000000006",
+				"value": "0x0",
+				"v": "0x0",
+				"r": "0x0",
+				"s": "0x0"
+			}`, typeField))
+
+			var tx rpcTransaction
+			assert.NoError(t, json.Unmarshal(raw, &tx))
+
+			loaded := tx.LoadedTransaction()
+			assert.Equal(t, test.typeName, loaded.TypeName)
+			assert.Equal(t, test.txType, loaded.Type)
+		})
+	}
+}
+
+func TestFlattenTraces_NullFrame(t *testing.T) {
+	var call *Call
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"type": "CALL",
+		"from": "0x1111111111111111111111111111111111111111",
+		"to": "0x2222222222222222222222222222222222222222",
+		"value": "0x1",
+		"calls": [null]
+	}`), &call))
+
+	// The null frame is dropped rather than dereferenced
+	calls := flattenTraces(call, []*flatCall{})
+	assert.Len(t, calls, 1)
+	assert.Equal(t, CallOpType, calls[0].Type)
+}
+
+func TestTraceOps_UndecodableOVMETHCall(t *testing.T) {
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+
+	for _, selector := range []string{burnSelector, mintSelector} {
+		calls := []*flatCall{
+			{
+				Type:    CallOpType,
+				From:    common.HexToAddress("0x1111111111111111111111111111111111111111"),
+				To:      ovmEthAddr,
+				Value:   big.NewInt(0),
+				GasUsed: big.NewInt(0),
+				Input:   selector + "00",
+			},
+		}
+
+		// Truncated calldata is neither a burn nor a mint
+		assert.Len(t, traceOps(block, calls, 0), 0)
+	}
+}
+
+func TestERC20TokenOps_FailedWithoutTrace(t *testing.T) {
+	c := &Client{
+		traceSemaphore:  semaphore.NewWeighted(100),
+		supportedTokens: map[string]bool{},
+	}
+
+	// The trace of a failed transaction may have been skipped,
+	// e.g. because the tracer timed out.
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	tx := &loadedTransaction{
+		Receipt: &types.Receipt{
+			Status: types.ReceiptStatusFailed,
+			Logs:   []*types.Log{},
+		},
+		TraceError: errTraceTimedOut,
+	}
+
+	ops, err := c.erc20TokenOps(context.Background(), block, tx, 0)
+	assert.NoError(t, err)
+	assert.Len(t, ops, 0)
+}
+
+func TestTraceOps_CaughtRevert(t *testing.T) {
+	file, err := ioutil.ReadFile("testdata/tx_trace_caught_revert.json")
+	assert.NoError(t, err)
+
+	var call *Call
+	assert.NoError(t, json.Unmarshal(file, &call))
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+	ops := traceOps(block, flattenTraces(call, []*flatCall{}), 0)
+
+	// The caught subcall and everything under it failed, so its
+	// operations do not move any balance. The outer call and the
+	// sibling called after the revert still succeed.
+	caller := "0x7e57C0dE7E57c0DE7e57C0De7e57C0de7E57c0DE"
+	expected := []struct {
+		account string
+		value   string
+		status  string
+	}{
+		{"0x1111111111111111111111111111111111111111", "-5", SuccessStatus},
+		{caller, "5", SuccessStatus},
+		{caller, "-2", FailureStatus},
+		{"0x2222222222222222222222222222222222222222", "2", FailureStatus},
+		{"0x2222222222222222222222222222222222222222", "-1", FailureStatus},
+		{"0x3333333333333333333333333333333333333333", "1", FailureStatus},
+		{caller, "-3", SuccessStatus},
+		{"0x4444444444444444444444444444444444444444", "3", SuccessStatus},
+		{"0x4444444444444444444444444444444444444444", "-1", SuccessStatus},
+		{"0x5555555555555555555555555555555555555555", "1", SuccessStatus},
+	}
+	assert.Len(t, ops, len(expected))
+	for i, op := range ops {
+		assert.Equal(t, int64(i), op.OperationIdentifier.Index)
+		assert.Equal(t, CallOpType, op.Type)
+		assert.Equal(t, expected[i].account, op.Account.Address)
+		assert.Equal(t, expected[i].value, op.Amount.Value)
+		assert.Equal(t, expected[i].status, *op.Status)
+
+		if expected[i].status == FailureStatus {
+			assert.Equal(t, "execution reverted", op.Metadata["error"])
+		} else {
+			assert.NotContains(t, op.Metadata, "error")
+		}
+	}
+}
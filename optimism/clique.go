@@ -0,0 +1,49 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/consensus/clique"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/crypto"
+)
+
+const (
+	// CliqueSealerKey is the block metadata key populated
+	// with the clique sealer address.
+	CliqueSealerKey = "sealer"
+)
+
+// cliqueSealer recovers the address that sealed a clique header from
+// the signature stored at the end of its extraData.
+func cliqueSealer(header *types.Header) (common.Address, error) {
+	if len(header.Extra) < crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf(
+			"extra data of %d bytes is too short to contain a seal",
+			len(header.Extra),
+		)
+	}
+	signature := header.Extra[len(header.Extra)-crypto.SignatureLength:]
+
+	pubkey, err := crypto.SigToPub(clique.SealHash(header).Bytes(), signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: unable to recover sealer", err)
+	}
+
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCliqueSealer(t *testing.T) {
+	var tests = map[string]struct {
+		file   string
+		extra  []byte
+		sealer common.Address
+		err    bool
+	}{
+		"block 1": {
+			file:   "testdata/block_1.json",
+			sealer: common.HexToAddress("0x00000398232E2064F896018496b4b44b3D62751F"),
+		},
+		"block 985": {
+			file:   "testdata/block_985.json",
+			sealer: common.HexToAddress("0x00000398232E2064F896018496b4b44b3D62751F"),
+		},
+		"missing seal": {
+			file:  "testdata/block_1.json",
+			extra: make([]byte, 32),
+			err:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, err := ioutil.ReadFile(test.file)
+			assert.NoError(t, err)
+
+			var header types.Header
+			assert.NoError(t, json.Unmarshal(file, &header))
+			if test.extra != nil {
+				header.Extra = test.extra
+			}
+
+			sealer, err := cliqueSealer(&header)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.sealer, sealer)
+		})
+	}
+}
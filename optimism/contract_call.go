@@ -0,0 +1,92 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallContract ABI-encodes a call of method on the contract at to,
+// performs it with eth_call at block and returns the decoded outputs.
+// abiJSON must describe method, but may be a fragment of the full
+// contract ABI. If block is nil, the call is made at the latest block.
+func (ec *Client) CallContract(
+	ctx context.Context,
+	abiJSON string,
+	to string,
+	method string,
+	args []interface{},
+	block *RosettaTypes.PartialBlockIdentifier,
+) ([]interface{}, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid abi: %s", ErrCallParametersInvalid, err.Error())
+	}
+	data, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to encode %s: %s", ErrCallParametersInvalid, method, err.Error())
+	}
+
+	params := map[string]interface{}{
+		"to":   to,
+		"data": hexutil.Encode(data),
+	}
+	setCallBlock(params, block)
+
+	resp, err := ec.contractCall(ctx, params)
+	if err != nil {
+		return nil, rpcError(err, false)
+	}
+
+	result, err := hexutil.Decode(resp["data"].(string))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+	}
+	outputs, err := parsedABI.Unpack(method, result)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode %s: %s", ErrCallOutputMarshal, method, err.Error())
+	}
+
+	return outputs, nil
+}
+
+// setCallBlock sets the index or hash of block in the eth_call params.
+// If block is nil, the call is made at the latest block.
+func setCallBlock(params map[string]interface{}, block *RosettaTypes.PartialBlockIdentifier) {
+	if block == nil {
+		return
+	}
+
+	switch {
+	case block.Index != nil && *block.Index == GenesisBlockIndex:
+		// A zero index means the latest block to eth_call, so
+		// genesis is requested by number instead.
+		params["hash"] = hexutil.EncodeUint64(0)
+	case block.Index != nil:
+		params["index"] = *block.Index
+	case block.Hash != nil:
+		params["hash"] = *block.Hash
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const balanceOfABI = `[{
+	"constant": true,
+	"inputs": [{"name": "owner", "type": "address"}],
+	"name": "balanceOf",
+	"outputs": [{"name": "", "type": "uint256"}],
+	"stateMutability": "view",
+	"type": "function"
+}]`
+
+func TestCallContract(t *testing.T) {
+	tests := map[string]struct {
+		block *RosettaTypes.PartialBlockIdentifier
+
+		expectedQuery string
+	}{
+		"latest": {
+			expectedQuery: "latest",
+		},
+		"index": {
+			block:         &RosettaTypes.PartialBlockIdentifier{Index: RosettaTypes.Int64(10992)},
+			expectedQuery: "0x2af0",
+		},
+		"genesis": {
+			block:         &RosettaTypes.PartialBlockIdentifier{Index: RosettaTypes.Int64(0)},
+			expectedQuery: "0x0",
+		},
+		"hash": {
+			block: &RosettaTypes.PartialBlockIdentifier{
+				Hash: RosettaTypes.String("0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae"),
+			},
+			expectedQuery: "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+			ctx := context.Background()
+
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_call",
+				map[string]string{
+					"to":   "0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+					"data": "0x70a08231000000000000000000000000b5e5d0f8c0cba267cd3d7035d6adc8eba7df7cdd",
+				},
+				test.expectedQuery,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					*args.Get(1).(*string) = "0x0000000000000000000000000000000000000000000000000de0b6b3a7640000"
+				},
+			).Once()
+
+			outputs, err := c.CallContract(
+				ctx,
+				balanceOfABI,
+				"0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+				"balanceOf",
+				[]interface{}{common.HexToAddress("0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd")},
+				test.block,
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, []interface{}{big.NewInt(1000000000000000000)}, outputs)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCallContract_InvalidArgs(t *testing.T) {
+	tests := map[string]struct {
+		abiJSON string
+		method  string
+		args    []interface{}
+	}{
+		"invalid abi": {
+			abiJSON: `[{"type": "function"`,
+			method:  "balanceOf",
+		},
+		"unknown method": {
+			abiJSON: balanceOfABI,
+			method:  "totalSupply",
+		},
+		"wrong argument type": {
+			abiJSON: balanceOfABI,
+			method:  "balanceOf",
+			args:    []interface{}{"0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+
+			outputs, err := c.CallContract(
+				context.Background(),
+				test.abiJSON,
+				"0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+				test.method,
+				test.args,
+				nil,
+			)
+			assert.Nil(t, outputs)
+			assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCallContract_EmptyResult(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	// Calls to addresses without code succeed with no data
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_call",
+		mock.Anything,
+		"latest",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			*args.Get(1).(*string) = "0x"
+		},
+	).Once()
+
+	outputs, err := c.CallContract(
+		ctx,
+		balanceOfABI,
+		"0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd",
+		"balanceOf",
+		[]interface{}{common.HexToAddress("0xB5E5D0F8C0cbA267CD3D7035d6AdC8eBA7Df7Cdd")},
+		nil,
+	)
+	assert.Nil(t, outputs)
+	assert.True(t, errors.Is(err, ErrCallOutputMarshal))
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestCallContract_Closed(t *testing.T) {
+	c := &Client{closed: 1}
+
+	outputs, err := c.CallContract(context.Background(), balanceOfABI, "", "balanceOf", nil, nil)
+	assert.Nil(t, outputs)
+	assert.True(t, errors.Is(err, ErrClientClosed))
+}
@@ -16,8 +16,11 @@ package optimism
 
 import (
 	"context"
+	"fmt"
 	"math/big"
+	"strings"
 
+	"github.com/ethereum-optimism/optimism/l2geth/common"
 	"github.com/ethereum-optimism/optimism/l2geth/rpc"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 
@@ -50,6 +53,17 @@ type ERC20CurrencyFetcher struct {
 	c JSONRPC
 }
 
+// notERC20 is cached in place of a currency for contracts that revert on
+// balanceOf() as well as decimals() or symbol(), so they aren't queried
+// again on every block.
+type notERC20 struct{}
+
+// isRevert returns true if the eth_call error means the contract
+// does not implement the called method.
+func isRevert(err error) bool {
+	return strings.Contains(err.Error(), "execution reverted")
+}
+
 // parseStringReturn parses data for ABI functions that return a single string
 func parseStringReturn(parsedABI abi.ABI, methodName string, data []byte) (string, error) {
 	stringRes, err := parsedABI.Unpack(methodName, data)
@@ -82,14 +96,19 @@ func parseIntReturn(parsedABI abi.ABI, methodName string, data []byte) (*big.Int
 // again fall back on the default symbol value.
 //
 // Note: any returned data payload with the prefix `0x4e487b71` are the first four bytes of keccak256(Panic(uint256))
-// If we encounter a failure while fetching currency details, we return a default value.
+// As decimals() and symbol() are optional in ERC20, a revert of either also falls back on the default value, unless
+// balanceOf() reverts too, in which case ErrNotERC20 is returned and cached. Currencies with a reverted call are not
+// cached, as the revert may depend on the block (e.g. a proxy not yet initialized). Other failures are returned.
 func (ecf ERC20CurrencyFetcher) FetchCurrency(
 	ctx context.Context,
 	blockNum uint64,
 	contractAddress string,
 ) (*RosettaTypes.Currency, error) {
-	if cachedCurrency, ok := ecf.currencyCache.Get(contractAddress); ok {
-		return cachedCurrency.(*RosettaTypes.Currency), nil
+	if cached, ok := ecf.currencyCache.Get(contractAddress); ok {
+		if _, ok := cached.(notERC20); ok {
+			return nil, fmt.Errorf("%w: %s", ErrNotERC20, contractAddress)
+		}
+		return cached.(*RosettaTypes.Currency), nil
 	}
 
 	decimalsData, err := artifacts.ERC20ABI.Pack("decimals")
@@ -114,9 +133,24 @@ func (ecf ERC20CurrencyFetcher) FetchCurrency(
 	if err := ecf.c.BatchCallContext(ctx, reqs); err != nil {
 		return nil, err
 	}
+	var reverted error
 	for i := range reqs {
 		if reqs[i].Error != nil {
-			return nil, reqs[i].Error
+			if !isRevert(reqs[i].Error) {
+				return nil, reqs[i].Error
+			}
+			// Reverted calls fall back on the default value
+			*reqs[i].Result.(*string) = "0x"
+			reverted = reqs[i].Error
+		}
+	}
+	if reverted != nil {
+		if err := ecf.checkBalanceOf(ctx, contractAddress, blockNumHex); err != nil {
+			if !isRevert(err) {
+				return nil, err
+			}
+			ecf.currencyCache.Add(contractAddress, notERC20{})
+			return nil, fmt.Errorf("%w: %s: %v", ErrNotERC20, contractAddress, reverted)
 		}
 	}
 
@@ -167,13 +201,37 @@ func (ecf ERC20CurrencyFetcher) FetchCurrency(
 		},
 	}
 
-	ecf.currencyCache.Add(contractAddress, currency)
+	if reverted == nil {
+		ecf.currencyCache.Add(contractAddress, currency)
+	}
 
 	return currency, nil
 }
 
+// checkBalanceOf calls balanceOf() on contractAddress, which every
+// ERC20 implements, and returns its error.
+func (ecf ERC20CurrencyFetcher) checkBalanceOf(
+	ctx context.Context,
+	contractAddress string,
+	blockNumHex string,
+) error {
+	callParams, err := balanceOfCallParams(common.Address{}.Hex(), contractAddress)
+	if err != nil {
+		return err
+	}
+
+	var result string
+	return ecf.c.CallContext(ctx, &result, "eth_call", callParams, blockNumHex)
+}
+
 func newERC20CurrencyFetcher(c JSONRPC) (CurrencyFetcher, error) {
-	cache, err := lru.New(defaultCacheSize)
+	return newERC20CurrencyFetcherWithCacheSize(c, defaultCacheSize)
+}
+
+// newERC20CurrencyFetcherWithCacheSize creates an ERC20CurrencyFetcher
+// that caches the details of up to cacheSize contracts.
+func newERC20CurrencyFetcherWithCacheSize(c JSONRPC, cacheSize int) (CurrencyFetcher, error) {
+	cache, err := lru.New(cacheSize)
 	if err != nil {
 		return nil, err
 	}
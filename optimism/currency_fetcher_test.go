@@ -16,6 +16,8 @@ package optimism
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
@@ -200,3 +202,122 @@ func TestFetchCurrency(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchCurrency_NotERC20(t *testing.T) {
+	ctx := context.Background()
+	mockJSONRPC := &mocks.JSONRPC{}
+
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 2 && rpcs[0].Method == "eth_call" && rpcs[1].Method == "eth_call"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+			r[0].Error = errors.New("execution reverted")
+			r[1].Error = errors.New("execution reverted")
+		},
+	).Once()
+	mockBalanceOf(mockJSONRPC, ctx, errors.New("execution reverted"))
+
+	// Contracts that revert are cached so they are only queried once
+	for i := 0; i < 2; i++ {
+		fetchedCurrency, err := cf.FetchCurrency(ctx, 1, unknownContractAddress)
+		assert.Nil(t, fetchedCurrency)
+		assert.True(t, errors.Is(err, ErrNotERC20))
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestFetchCurrency_CacheSize(t *testing.T) {
+	ctx := context.Background()
+	mockJSONRPC := &mocks.JSONRPC{}
+
+	cf, err := newERC20CurrencyFetcherWithCacheSize(mockJSONRPC, 1)
+	assert.NoError(t, err)
+
+	mockCalls(t, mockJSONRPC, unknownContractAddress, 1, "0x", "0x")
+	mockCalls(t, mockJSONRPC, blankSymbolContractAddress, 1, "0x", "0x")
+	mockCalls(t, mockJSONRPC, unknownContractAddress, 1, "0x", "0x")
+
+	// The second contract evicts the first, which must be fetched again
+	for _, contractAddress := range []string{
+		unknownContractAddress,
+		blankSymbolContractAddress,
+		unknownContractAddress,
+	} {
+		_, err := cf.FetchCurrency(ctx, 1, contractAddress)
+		assert.NoError(t, err)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestFetchCurrency_MetadataReverted(t *testing.T) {
+	ctx := context.Background()
+	mockJSONRPC := &mocks.JSONRPC{}
+
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	// symbol() is optional, so a contract that only reverts on it is
+	// still an ERC20. It is not cached, as it may not revert at a
+	// later block.
+	for i := 0; i < 2; i++ {
+		mockJSONRPC.On(
+			"BatchCallContext",
+			ctx,
+			mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+				return len(rpcs) == 2 && rpcs[0].Method == "eth_call" && rpcs[1].Method == "eth_call"
+			}),
+		).Return(
+			nil,
+		).Run(
+			func(args mock.Arguments) {
+				r := args.Get(1).([]rpc.BatchElem)
+				*(r[0].Result.(*string)) = "0x0000000000000000000000000000000000000000000000000000000000000012"
+				r[1].Error = errors.New("execution reverted")
+			},
+		).Once()
+		mockBalanceOf(mockJSONRPC, ctx, nil)
+
+		fetchedCurrency, err := cf.FetchCurrency(ctx, 1, unknownContractAddress)
+		assert.NoError(t, err)
+		assert.Equal(t, &RosettaTypes.Currency{
+			Symbol:   defaultERC20Symbol,
+			Decimals: 18,
+			Metadata: map[string]interface{}{
+				ContractAddressKey: unknownContractAddress,
+			},
+		}, fetchedCurrency)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func mockBalanceOf(mockJSONRPC *mocks.JSONRPC, ctx context.Context, err error) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_call",
+		mock.MatchedBy(func(callParams map[string]string) bool {
+			return strings.HasPrefix(callParams["data"], "0x70a08231")
+		}),
+		"0x1",
+	).Return(
+		err,
+	).Run(
+		func(args mock.Arguments) {
+			*(args.Get(1).(*string)) = "0x0000000000000000000000000000000000000000000000000000000000000000"
+		},
+	).Once()
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// DebugKey is the response metadata key holding the queries
+// made to the node to produce the response.
+const DebugKey = "debug"
+
+// WithDebugResponses attaches the JSON-RPC methods and params, and
+// the GraphQL queries, used to produce Balance responses to their
+// metadata under DebugKey. It is meant for debugging discrepancies
+// between providers and should not be enabled in production.
+func (ec *Client) WithDebugResponses() *Client {
+	ec.debugResponses = true
+	return ec
+}
+
+// debugRecorder records the queries made to the node.
+type debugRecorder struct {
+	mu      sync.Mutex
+	queries []interface{}
+}
+
+func (r *debugRecorder) record(query map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queries = append(r.queries, query)
+}
+
+// metadata returns the recorded queries in the order they were made.
+func (r *debugRecorder) metadata() map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return map[string]interface{}{
+		"queries": r.queries,
+	}
+}
+
+// recordingJSONRPC is a JSONRPC that records each request.
+type recordingJSONRPC struct {
+	JSONRPC
+	recorder *debugRecorder
+}
+
+func (c *recordingJSONRPC) CallContext(
+	ctx context.Context,
+	result interface{},
+	method string,
+	args ...interface{},
+) error {
+	c.recorder.record(map[string]interface{}{
+		"method": method,
+		"params": args,
+	})
+
+	return c.JSONRPC.CallContext(ctx, result, method, args...)
+}
+
+func (c *recordingJSONRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	for _, elem := range b {
+		c.recorder.record(map[string]interface{}{
+			"method": elem.Method,
+			"params": elem.Args,
+		})
+	}
+
+	return c.JSONRPC.BatchCallContext(ctx, b)
+}
+
+// recordingGraphQL is a GraphQL that records each query.
+type recordingGraphQL struct {
+	GraphQL
+	recorder *debugRecorder
+}
+
+func (g *recordingGraphQL) Query(
+	ctx context.Context,
+	input string,
+	variables map[string]interface{},
+) (string, error) {
+	g.recorder.record(map[string]interface{}{
+		"query":     input,
+		"variables": variables,
+	})
+
+	return g.GraphQL.Query(ctx, input, variables)
+}
+
+// debugClient returns a copy of ec whose queries to the node
+// are recorded by the returned *debugRecorder.
+func (ec *Client) debugClient() (*Client, *debugRecorder) {
+	recorder := &debugRecorder{}
+	debug := *ec
+	debug.c = &recordingJSONRPC{JSONRPC: ec.c, recorder: recorder}
+	if ec.g != nil {
+		debug.g = &recordingGraphQL{GraphQL: ec.g, recorder: recorder}
+	}
+
+	return &debug, recorder
+}
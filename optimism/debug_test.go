@@ -0,0 +1,161 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestBalance_DebugResponses(t *testing.T) {
+	var tests = map[string]struct {
+		debug bool
+
+		expectedDebug interface{}
+	}{
+		"disabled": {},
+		"enabled": {
+			debug: true,
+			expectedDebug: map[string]interface{}{
+				"queries": []interface{}{
+					map[string]interface{}{
+						"method": "eth_getBlockByNumber",
+						"params": []interface{}{"latest", false},
+					},
+					map[string]interface{}{
+						"method": "eth_getBalance",
+						"params": []interface{}{"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55", "0x2af0"},
+					},
+					map[string]interface{}{
+						"method": "eth_getTransactionCount",
+						"params": []interface{}{"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55", "0x2af0"},
+					},
+					map[string]interface{}{
+						"method": "eth_getCode",
+						"params": []interface{}{"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55", "0x2af0"},
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+
+			c := &Client{
+				c:              mockJSONRPC,
+				g:              mockGraphQL,
+				traceSemaphore: semaphore.NewWeighted(100),
+			}
+			if test.debug {
+				c = c.WithDebugResponses()
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"latest",
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.Anything,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(big.NewInt(100))
+					*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+					*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+				},
+			).Once()
+
+			resp, err := c.Balance(
+				ctx,
+				&RosettaTypes.AccountIdentifier{
+					Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+				},
+				nil,
+				[]*RosettaTypes.Currency{Currency},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedDebug, resp.Metadata[DebugKey])
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDebugClient_GraphQL(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+
+	c := &Client{
+		c:              mockJSONRPC,
+		g:              mockGraphQL,
+		traceSemaphore: semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	query := `query($hash: Bytes32) { block(hash: $hash) { number } }`
+	variables := map[string]interface{}{
+		"hash": "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+	}
+	mockGraphQL.On("Query", ctx, query, variables).Return(`{"data":{}}`, nil).Once()
+
+	debug, recorder := c.debugClient()
+	_, err := debug.g.Query(ctx, query, variables)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"queries": []interface{}{
+			map[string]interface{}{
+				"query":     query,
+				"variables": variables,
+			},
+		},
+	}, recorder.metadata())
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
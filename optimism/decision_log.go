@@ -0,0 +1,182 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// Decision log steps
+const (
+	FeeStep               = "fee"
+	ERC20Step             = "erc20"
+	TraceFrameStep        = "trace_frame"
+	DestroyedAccountsStep = "destroyed_accounts"
+	TraceStep             = "trace"
+	MintStep              = "mint"
+)
+
+// DecisionLog describes how a block was converted: the inputs each
+// step of the conversion consumed and the operations it emitted. It
+// marshals to JSON with a stable layout, so logs produced by different
+// versions can be diffed.
+type DecisionLog struct {
+	BlockIdentifier *RosettaTypes.BlockIdentifier `json:"block_identifier"`
+	Transactions    []*TransactionDecisions       `json:"transactions"`
+}
+
+// TransactionDecisions are the steps taken to convert a transaction.
+type TransactionDecisions struct {
+	Hash    string          `json:"hash"`
+	Receipt bool            `json:"receipt"`
+	Trace   bool            `json:"trace"`
+	Steps   []*DecisionStep `json:"steps"`
+}
+
+// DecisionStep is a single step of a transaction's conversion.
+// Operations are the indexes of the operations it emitted.
+type DecisionStep struct {
+	Step       string                 `json:"step"`
+	Inputs     map[string]interface{} `json:"inputs,omitempty"`
+	Operations []int64                `json:"operations"`
+	Decision   string                 `json:"decision,omitempty"`
+}
+
+// DebugBlock returns the block like Block, along with a log of every
+// decision made converting it. It is meant for replaying the
+// conversion of a suspicious block; Block never records decisions.
+func (ec *Client) DebugBlock(
+	ctx context.Context,
+	blockIdentifier *RosettaTypes.PartialBlockIdentifier,
+) (*RosettaTypes.Block, *DecisionLog, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, nil, err
+	}
+
+	decisions := &DecisionLog{Transactions: []*TransactionDecisions{}}
+	debug := *ec
+	debug.decisions = decisions
+
+	block, err := debug.block(ctx, blockIdentifier)
+	if err != nil {
+		return nil, nil, rpcError(err, true)
+	}
+	decisions.BlockIdentifier = block.BlockIdentifier
+
+	return block, decisions, nil
+}
+
+func (l *DecisionLog) addTransaction(tx *loadedTransaction) *TransactionDecisions {
+	decisions := &TransactionDecisions{
+		Hash:    tx.hash().Hex(),
+		Receipt: tx.Receipt != nil,
+		Trace:   tx.Trace != nil,
+		Steps:   []*DecisionStep{},
+	}
+	l.Transactions = append(l.Transactions, decisions)
+
+	return decisions
+}
+
+func (d *TransactionDecisions) add(
+	step string,
+	inputs map[string]interface{},
+	ops []*RosettaTypes.Operation,
+	decision string,
+) {
+	indexes := make([]int64, len(ops))
+	for i, op := range ops {
+		indexes[i] = op.OperationIdentifier.Index
+	}
+
+	d.Steps = append(d.Steps, &DecisionStep{
+		Step:       step,
+		Inputs:     inputs,
+		Operations: indexes,
+		Decision:   decision,
+	})
+}
+
+func (d *TransactionDecisions) fee(tx *loadedTransaction, ops []*RosettaTypes.Operation, decision string) {
+	d.add(FeeStep, map[string]interface{}{
+		"fee_amount": tx.FeeAmount.String(),
+		"recipient":  tx.Miner,
+	}, ops, decision)
+}
+
+func (d *TransactionDecisions) mint(tx *loadedTransaction, ops []*RosettaTypes.Operation) {
+	d.add(MintStep, map[string]interface{}{
+		"mint": tx.Mint.String(),
+	}, ops, "")
+}
+
+func (d *TransactionDecisions) erc20(tx *loadedTransaction, ops []*RosettaTypes.Operation) {
+	d.add(ERC20Step, map[string]interface{}{
+		"logs": len(tx.Receipt.Logs),
+	}, ops, "")
+}
+
+func (d *TransactionDecisions) skipTrace(err error) {
+	d.add(TraceStep, nil, nil, "skipped: "+err.Error())
+}
+
+func (d *TransactionDecisions) frame(
+	call *flatCall,
+	ops []*RosettaTypes.Operation,
+	decision string,
+) {
+	value := "0"
+	if call.Value != nil {
+		value = call.Value.String()
+	}
+
+	d.add(TraceFrameStep, map[string]interface{}{
+		"type":   call.Type,
+		"from":   call.From.Hex(),
+		"to":     call.To.Hex(),
+		"value":  value,
+		"revert": call.Revert,
+	}, ops, decision)
+}
+
+// frames records the operations traceFrameOps emitted for each call,
+// given the frameStarts it populated.
+func (d *TransactionDecisions) frames(
+	calls []*flatCall,
+	ops []*RosettaTypes.Operation,
+	frameStarts []int,
+) {
+	if len(calls) == 0 {
+		return
+	}
+
+	for i, call := range calls {
+		frameOps := ops[frameStarts[i]:frameStarts[i+1]]
+		decision := ""
+		switch {
+		case call.Type == "":
+			decision = "skipped: rejected transaction"
+		case len(frameOps) == 0:
+			decision = "skipped: no balance change"
+		}
+		d.frame(call, frameOps, decision)
+	}
+
+	if destroyed := ops[frameStarts[len(calls)]:]; len(destroyed) > 0 {
+		d.add(DestroyedAccountsStep, nil, destroyed, "")
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestDebugBlock(t *testing.T) {
+	token := "0xf8b089026cad7ddd8cb8d79036a1ff1d4233d64a"
+	supportedTokens := map[string]bool{
+		token: true,
+	}
+
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: mockCurrencyFetcher,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+		supportedTokens: supportedTokens,
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x12f062",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_1241186.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Len(t, r[0].Args, 2)
+			assert.Equal(
+				t,
+				common.HexToHash("0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009").Hex(),
+				r[0].Args[0],
+			)
+			assert.Equal(t, tc, r[0].Args[1])
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_trace_1241186.json",
+			)
+			assert.NoError(t, err)
+
+			call := new(Call)
+			assert.NoError(t, call.UnmarshalJSON(file))
+			*(r[0].Result.(**Call)) = call
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Equal(
+				t,
+				"0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009",
+				r[0].Args[0],
+			)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
+			) // nolint
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
+	mockCurrencyFetcher.On(
+		"FetchCurrency",
+		ctx,
+		uint64(1241186),
+		mock.Anything,
+	).Return(
+		&RosettaTypes.Currency{
+			Symbol:   TokenSymbol,
+			Decimals: TokenDecimals,
+			Metadata: map[string]interface{}{"token_address": token}},
+		nil,
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1241186.json")
+	assert.NoError(t, err)
+	var correctResp *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+
+	resp, decisions, err := c.DebugBlock(
+		ctx,
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(1241186),
+		},
+	)
+	assert.NoError(t, err)
+
+	// The block is the same as the one returned by Block
+	jsonResp, err := jsonifyBlock(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, correctResp.Block, jsonResp)
+	assert.Equal(t, resp.BlockIdentifier, decisions.BlockIdentifier)
+
+	// Every emitted operation is referenced by exactly one step
+	assert.Len(t, decisions.Transactions, len(resp.Transactions))
+	for i, tx := range resp.Transactions {
+		txDecisions := decisions.Transactions[i]
+		assert.Equal(t, tx.TransactionIdentifier.Hash, txDecisions.Hash)
+		assert.True(t, txDecisions.Receipt)
+		assert.True(t, txDecisions.Trace)
+
+		references := map[int64]int{}
+		steps := map[string]bool{}
+		for _, step := range txDecisions.Steps {
+			steps[step.Step] = true
+			for _, index := range step.Operations {
+				references[index]++
+			}
+		}
+		assert.Len(t, references, len(tx.Operations))
+		for _, op := range tx.Operations {
+			assert.Equal(t, 1, references[op.OperationIdentifier.Index])
+		}
+		assert.True(t, steps[FeeStep])
+		assert.True(t, steps[ERC20Step])
+		assert.True(t, steps[TraceFrameStep])
+	}
+
+	// The log is suitable for diffing as JSON
+	_, err = json.Marshal(decisions)
+	assert.NoError(t, err)
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+	mockCurrencyFetcher.AssertExpectations(t)
+}
+
+func TestDecisionLog_Frames(t *testing.T) {
+	file, err := ioutil.ReadFile("testdata/tx_trace_multicall.json")
+	assert.NoError(t, err)
+
+	var call *Call
+	assert.NoError(t, json.Unmarshal(file, &call))
+
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+	calls := flattenTraces(call, []*flatCall{})
+	frameStarts := make([]int, len(calls)+1)
+	ops := traceFrameOps(block, calls, 2, frameStarts)
+	assert.Equal(t, traceOps(block, calls, 2), ops)
+
+	decisions := &TransactionDecisions{}
+	decisions.frames(calls, ops, frameStarts)
+	assert.Len(t, decisions.Steps, len(calls))
+
+	// Each value transfer is attributed to the frame that made it
+	var referenced []int64
+	for i, step := range decisions.Steps {
+		assert.Equal(t, TraceFrameStep, step.Step)
+		assert.Equal(t, calls[i].To.Hex(), step.Inputs["to"])
+		if calls[i].Value.Sign() == 0 {
+			assert.Empty(t, step.Operations)
+			continue
+		}
+		assert.Len(t, step.Operations, 2)
+		referenced = append(referenced, step.Operations...)
+	}
+	assert.Len(t, referenced, len(ops))
+	for i, index := range referenced {
+		assert.Equal(t, ops[i].OperationIdentifier.Index, index)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"fmt"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// DecodeTransactionMethod is the call method that decodes a signed
+// transaction without submitting it. It makes no node calls, so it
+// is also available in offline mode.
+const DecodeTransactionMethod = "rosetta_decodeTransaction"
+
+// selectorSize is the number of bytes in a function selector.
+const selectorSize = 4
+
+// DecodeTransactionInput is the input to DecodeTransactionMethod.
+type DecodeTransactionInput struct {
+	SignedTransaction string `json:"signed_transaction"`
+}
+
+// DecodeTransaction decodes a hex-encoded signed legacy or typed
+// transaction, recovers its sender and returns its fields.
+func DecodeTransaction(params map[string]interface{}) (map[string]interface{}, error) {
+	var input DecodeTransactionInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	if len(input.SignedTransaction) == 0 {
+		return nil, fmt.Errorf("%w: signed_transaction missing from params", ErrCallParametersInvalid)
+	}
+
+	data, err := hexutil.Decode(input.SignedTransaction)
+	if err != nil {
+		return nil, fmt.Errorf("%w: signed_transaction is not valid hex: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	// UnmarshalBinary accepts both RLP-encoded legacy transactions
+	// and EIP-2718 typed transaction envelopes.
+	tx := new(ethTypes.Transaction)
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("%w: unable to decode signed_transaction: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	from, err := ethTypes.Sender(ethTypes.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to recover sender: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	result := map[string]interface{}{
+		"hash":      tx.Hash().Hex(),
+		"type":      hexutil.EncodeUint64(uint64(tx.Type())),
+		"from":      from.Hex(),
+		"value":     hexutil.EncodeBig(tx.Value()),
+		"nonce":     hexutil.EncodeUint64(tx.Nonce()),
+		"gas_limit": hexutil.EncodeUint64(tx.Gas()),
+		"chain_id":  hexutil.EncodeBig(tx.ChainId()),
+	}
+	if tx.To() != nil {
+		result["to"] = tx.To().Hex()
+	}
+	if tx.Type() == ethTypes.DynamicFeeTxType {
+		result["max_fee_per_gas"] = hexutil.EncodeBig(tx.GasFeeCap())
+		result["max_priority_fee_per_gas"] = hexutil.EncodeBig(tx.GasTipCap())
+	} else {
+		result["gas_price"] = hexutil.EncodeBig(tx.GasPrice())
+	}
+	if len(tx.Data()) >= selectorSize {
+		result["data_selector"] = hexutil.Encode(tx.Data()[:selectorSize])
+	}
+
+	return result, nil
+}
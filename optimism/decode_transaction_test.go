@@ -0,0 +1,123 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	gethCommon "github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	key, err := crypto.HexToECDSA("8f2a55949038a9610f50fb23b5883af3b4ecb3c3bb792cbcefbd1542c692be63")
+	assert.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	to := gethCommon.HexToAddress("0x4200000000000000000000000000000000000042")
+	chainID := big.NewInt(10)
+	data := hexutil.MustDecode("0xa9059cbb0000000000000000000000002f93b2f047e05cdf602820ac4b3178efc2b43d55")
+
+	sign := func(txData ethTypes.TxData) (string, string) {
+		tx, err := ethTypes.SignNewTx(key, ethTypes.LatestSignerForChainID(chainID), txData)
+		assert.NoError(t, err)
+		raw, err := tx.MarshalBinary()
+		assert.NoError(t, err)
+		return hexutil.Encode(raw), tx.Hash().Hex()
+	}
+	legacyRaw, legacyHash := sign(&ethTypes.LegacyTx{
+		Nonce:    3,
+		GasPrice: big.NewInt(1000000),
+		Gas:      21000,
+		To:       &to,
+		Value:    big.NewInt(100),
+	})
+	dynamicFeeRaw, dynamicFeeHash := sign(&ethTypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     4,
+		GasTipCap: big.NewInt(1000),
+		GasFeeCap: big.NewInt(2000000),
+		Gas:       60000,
+		To:        &to,
+		Data:      data,
+	})
+
+	var tests = map[string]struct {
+		params map[string]interface{}
+
+		expectedResult map[string]interface{}
+		expectedErr    error
+	}{
+		"legacy": {
+			params: map[string]interface{}{"signed_transaction": legacyRaw},
+			expectedResult: map[string]interface{}{
+				"hash":      legacyHash,
+				"type":      "0x0",
+				"from":      from,
+				"to":        to.Hex(),
+				"value":     "0x64",
+				"nonce":     "0x3",
+				"gas_limit": "0x5208",
+				"gas_price": "0xf4240",
+				"chain_id":  "0xa",
+			},
+		},
+		"dynamic fee": {
+			params: map[string]interface{}{"signed_transaction": dynamicFeeRaw},
+			expectedResult: map[string]interface{}{
+				"hash":                     dynamicFeeHash,
+				"type":                     "0x2",
+				"from":                     from,
+				"to":                       to.Hex(),
+				"value":                    "0x0",
+				"nonce":                    "0x4",
+				"gas_limit":                "0xea60",
+				"max_fee_per_gas":          "0x1e8480",
+				"max_priority_fee_per_gas": "0x3e8",
+				"chain_id":                 "0xa",
+				"data_selector":            "0xa9059cbb",
+			},
+		},
+		"garbage": {
+			params:      map[string]interface{}{"signed_transaction": "0xdeadbeef"},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"not hex": {
+			params:      map[string]interface{}{"signed_transaction": "hello"},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"missing": {
+			params:      map[string]interface{}{},
+			expectedErr: ErrCallParametersInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := DecodeTransaction(test.params)
+			if test.expectedErr != nil {
+				assert.Nil(t, result)
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedResult, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// mintOps returns the operation crediting the sender of a deposit
+// transaction with the ETH locked for it on L1. The mint is applied
+// before execution and kept even if the deposit reverts, so it is
+// always successful. Any value sent on to the recipient is covered
+// by the trace of the deposit.
+func mintOps(tx *loadedTransaction, startIndex int) []*RosettaTypes.Operation {
+	if tx.Mint == nil || tx.Mint.Sign() == 0 {
+		return []*RosettaTypes.Operation{}
+	}
+
+	return []*RosettaTypes.Operation{
+		{
+			OperationIdentifier: &RosettaTypes.OperationIdentifier{
+				Index: int64(startIndex),
+			},
+			Type:   MintOpType,
+			Status: RosettaTypes.String(SuccessStatus),
+			Account: &RosettaTypes.AccountIdentifier{
+				Address: MustChecksum(tx.From.String()),
+			},
+			Amount: &RosettaTypes.Amount{
+				Value:    tx.Mint.String(),
+				Currency: Currency,
+			},
+		},
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestBlock_Deposit(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: mockCurrencyFetcher,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	mockDepositBlock(ctx, t, mockJSONRPC)
+
+	resp, err := c.Block(
+		ctx,
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(1241187),
+		},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Transactions, 1)
+
+	// The deposit is credited with the minted ETH, which the
+	// trace then moves to the recipient. No fee is debited.
+	sender := "0x977F82a600a1414E583f7F13623F1ac5D58B1C0b"
+	recipient := "0x4200000000000000000000000000000000000007"
+	mint := big.NewInt(100000000000000000).String()
+	tx := resp.Transactions[0]
+	assert.Equal(t, depositTxHash, tx.TransactionIdentifier.Hash)
+	assert.Equal(t, DepositTxTypeName, tx.Metadata["type_name"])
+
+	var tests = []struct {
+		opType  string
+		account string
+		value   string
+	}{
+		{opType: MintOpType, account: sender, value: mint},
+		{opType: CallOpType, account: sender, value: "-" + mint},
+		{opType: CallOpType, account: recipient, value: mint},
+	}
+	assert.Len(t, tx.Operations, len(tests))
+	for i, test := range tests {
+		op := tx.Operations[i]
+		assert.Equal(t, int64(i), op.OperationIdentifier.Index)
+		assert.Equal(t, test.opType, op.Type)
+		assert.Equal(t, SuccessStatus, *op.Status)
+		assert.Equal(t, test.account, op.Account.Address)
+		assert.Equal(t, test.value, op.Amount.Value)
+		assert.Equal(t, Currency, op.Amount.Currency)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+	mockCurrencyFetcher.AssertExpectations(t)
+}
+
+func TestMintOps(t *testing.T) {
+	var tests = map[string]struct {
+		mint *big.Int
+
+		expectedOps int
+	}{
+		"mint": {
+			mint:        big.NewInt(1000),
+			expectedOps: 1,
+		},
+		"no mint": {
+			expectedOps: 0,
+		},
+		"zero mint": {
+			mint:        big.NewInt(0),
+			expectedOps: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, err := ioutil.ReadFile("testdata/block_deposit.json")
+			assert.NoError(t, err)
+
+			var body rpcBlock
+			assert.NoError(t, json.Unmarshal(file, &body))
+			tx := body.Transactions[0].LoadedTransaction()
+			tx.Mint = test.mint
+
+			ops := mintOps(tx, 3)
+			assert.Len(t, ops, test.expectedOps)
+			for _, op := range ops {
+				assert.Equal(t, int64(3), op.OperationIdentifier.Index)
+				assert.Equal(t, test.mint.String(), op.Amount.Value)
+			}
+		})
+	}
+}
+
+// depositTxHash is the hash of the deposit in block 1241187,
+// computed over its 0x7e envelope rather than a legacy encoding.
+const depositTxHash = "0xb6b4b9f90ec76f29c29c868d8afddcb0c8bdfdc8f71b5a4f2bba2ff00086270e"
+
+// mockDepositBlock mocks the requests made to convert
+// block 1241187, which holds a single deposit.
+func mockDepositBlock(ctx context.Context, t *testing.T, mockJSONRPC *mocks.JSONRPC) {
+	mockDepositBlockWithReceipt(ctx, t, mockJSONRPC, "testdata/tx_receipt_deposit.json")
+}
+
+// mockDepositBlockWithReceipt is mockDepositBlock with the
+// receipt of the deposit read from receiptFile.
+func mockDepositBlockWithReceipt(
+	ctx context.Context,
+	t *testing.T,
+	mockJSONRPC *mocks.JSONRPC,
+	receiptFile string,
+) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x12f063",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_deposit.json")
+			assert.NoError(t, err)
+
+			*r = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction" &&
+				rpcs[0].Args[0] == depositTxHash
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			file, err := ioutil.ReadFile("testdata/tx_trace_deposit.json")
+			assert.NoError(t, err)
+
+			call := new(Call)
+			assert.NoError(t, call.UnmarshalJSON(file))
+			*(r[0].Result.(**Call)) = call
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt" &&
+				rpcs[0].Args[0] == depositTxHash
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			file, err := ioutil.ReadFile(receiptFile)
+			assert.NoError(t, err)
+
+			// The result is decoded in the dialect of the client
+			assert.NoError(t, json.Unmarshal(file, r[0].Result))
+		},
+	).Once()
+}
@@ -0,0 +1,249 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+)
+
+// Dialect is the flavour of JSON-RPC spoken by the node. Legacy
+// (pre-Bedrock) l2geth replicas and op-geth nodes serve the same
+// blocks in slightly different shapes.
+type Dialect string
+
+const (
+	// AutoDialect detects the dialect of the node on first use.
+	AutoDialect Dialect = "auto"
+
+	// LegacyDialect is spoken by pre-Bedrock l2geth replicas.
+	LegacyDialect Dialect = "legacy"
+
+	// BedrockDialect is spoken by op-geth.
+	BedrockDialect Dialect = "bedrock"
+)
+
+// ParseDialect returns the Dialect named s. An empty s selects
+// AutoDialect.
+func ParseDialect(s string) (Dialect, error) {
+	switch d := Dialect(s); d {
+	case "":
+		return AutoDialect, nil
+	case AutoDialect, LegacyDialect, BedrockDialect:
+		return d, nil
+	default:
+		return "", fmt.Errorf("%s is not a valid dialect", s)
+	}
+}
+
+// nodeDialect hides the differences between the RPC dialects.
+// Deposits are served as legacy unsigned transactions by l2geth and
+// as typed transactions with a mint by op-geth; both are handled by
+// the same transaction decoding, so only the following differ.
+type nodeDialect interface {
+	name() Dialect
+
+	// receiptResult returns the value a receipt response is decoded
+	// into, storing the decoded receipt in receipt.
+	receiptResult(receipt **types.Receipt) interface{}
+
+	// receiptsResult is receiptResult for lists of receipts.
+	receiptsResult(receipts *[]*types.Receipt) interface{}
+
+	// rollupNamespace reports whether the node serves rollup_*
+	// methods, such as rollup_getInfo.
+	rollupNamespace() bool
+
+	// blockRange reports whether the node serves eth_getBlockRange,
+	// with the L1 origin of each transaction in its blocks.
+	blockRange() bool
+
+	// graphQL reports whether the node is expected to serve GraphQL.
+	graphQL() bool
+}
+
+type legacyDialect struct{}
+
+func (legacyDialect) name() Dialect { return LegacyDialect }
+
+// l2geth always reports the L1 fee fields types.Receipt requires.
+func (legacyDialect) receiptResult(receipt **types.Receipt) interface{} { return receipt }
+
+func (legacyDialect) receiptsResult(receipts *[]*types.Receipt) interface{} { return receipts }
+
+func (legacyDialect) rollupNamespace() bool { return true }
+
+func (legacyDialect) blockRange() bool { return true }
+
+func (legacyDialect) graphQL() bool { return true }
+
+type bedrockDialect struct{}
+
+func (bedrockDialect) name() Dialect { return BedrockDialect }
+
+func (bedrockDialect) receiptResult(receipt **types.Receipt) interface{} {
+	return &bedrockReceipt{receipt: receipt}
+}
+
+func (bedrockDialect) receiptsResult(receipts *[]*types.Receipt) interface{} {
+	return &bedrockReceipts{receipts: receipts}
+}
+
+func (bedrockDialect) rollupNamespace() bool { return false }
+
+func (bedrockDialect) blockRange() bool { return false }
+
+func (bedrockDialect) graphQL() bool { return false }
+
+// bedrockReceiptDefaults are the L1 fee fields types.Receipt
+// requires but op-geth omits, from deposit receipts and, since
+// Ecotone, l1FeeScalar from all receipts.
+var bedrockReceiptDefaults = map[string]json.RawMessage{
+	"l1GasPrice":  json.RawMessage(`"0x0"`),
+	"l1GasUsed":   json.RawMessage(`"0x0"`),
+	"l1Fee":       json.RawMessage(`"0x0"`),
+	"l1FeeScalar": json.RawMessage(`"0"`),
+}
+
+// bedrockReceipt decodes an op-geth receipt into a types.Receipt,
+// treating missing L1 fee fields as zero.
+type bedrockReceipt struct {
+	receipt **types.Receipt
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *bedrockReceipt) UnmarshalJSON(msg []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &fields); err != nil {
+		return err
+	}
+	if fields == nil {
+		*r.receipt = nil
+		return nil
+	}
+
+	for key, value := range bedrockReceiptDefaults {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	receipt := new(types.Receipt)
+	if err := receipt.UnmarshalJSON(normalized); err != nil {
+		return err
+	}
+	*r.receipt = receipt
+
+	return nil
+}
+
+// bedrockReceipts decodes a list of op-geth receipts.
+type bedrockReceipts struct {
+	receipts *[]*types.Receipt
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (r *bedrockReceipts) UnmarshalJSON(msg []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(msg, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*r.receipts = nil
+		return nil
+	}
+
+	receipts := make([]*types.Receipt, len(raw))
+	for i := range raw {
+		if err := json.Unmarshal(raw[i], &bedrockReceipt{receipt: &receipts[i]}); err != nil {
+			return err
+		}
+	}
+	*r.receipts = receipts
+
+	return nil
+}
+
+// dialectResolver resolves the dialect of the node once. It is
+// shared by the copies of a Client.
+type dialectResolver struct {
+	mu      sync.Mutex
+	dialect nodeDialect
+}
+
+func newDialectResolver(d Dialect) *dialectResolver {
+	switch d {
+	case LegacyDialect:
+		return &dialectResolver{dialect: legacyDialect{}}
+	case BedrockDialect:
+		return &dialectResolver{dialect: bedrockDialect{}}
+	default:
+		return &dialectResolver{}
+	}
+}
+
+// nodeDialect returns the dialect of the node, detecting it on first
+// use if it was not configured. Clients created without NewClient
+// speak LegacyDialect. Only l2geth serves rollup_getInfo, so a node
+// that rejects it is taken to be op-geth. Detection is retried if the
+// probe fails for any other reason.
+func (ec *Client) nodeDialect(ctx context.Context) (nodeDialect, error) {
+	if ec.dialect == nil {
+		return legacyDialect{}, nil
+	}
+
+	ec.dialect.mu.Lock()
+	defer ec.dialect.mu.Unlock()
+	if ec.dialect.dialect != nil {
+		return ec.dialect.dialect, nil
+	}
+
+	var info *RollupInfo
+	err := ec.c.CallContext(ctx, &info, "rollup_getInfo")
+	switch {
+	case err == nil:
+		ec.dialect.dialect = legacyDialect{}
+	case isMethodNotFound(err):
+		ec.dialect.dialect = bedrockDialect{}
+	default:
+		return nil, fmt.Errorf("%w: unable to detect node dialect", err)
+	}
+	log.Printf("detected %s node dialect", ec.dialect.dialect.name())
+
+	return ec.dialect.dialect, nil
+}
+
+// Dialect returns the dialect of the node, detecting it if needed.
+func (ec *Client) Dialect(ctx context.Context) (Dialect, error) {
+	if err := ec.checkClosed(); err != nil {
+		return "", err
+	}
+
+	d, err := ec.nodeDialect(ctx)
+	if err != nil {
+		return "", rpcError(err, false)
+	}
+
+	return d.name(), nil
+}
@@ -0,0 +1,423 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestParseDialect(t *testing.T) {
+	tests := map[string]struct {
+		input string
+
+		expected    Dialect
+		expectedErr bool
+	}{
+		"empty":   {input: "", expected: AutoDialect},
+		"auto":    {input: "auto", expected: AutoDialect},
+		"legacy":  {input: "legacy", expected: LegacyDialect},
+		"bedrock": {input: "bedrock", expected: BedrockDialect},
+		"invalid": {input: "op-erigon", expectedErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			d, err := ParseDialect(test.input)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, d)
+		})
+	}
+}
+
+// mockRollupInfo mocks rollup_getInfo returning err, or the
+// rollup_info.json fixture if err is nil.
+func mockRollupInfo(ctx context.Context, t *testing.T, mockJSONRPC *mocks.JSONRPC, err error) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"rollup_getInfo",
+	).Return(
+		err,
+	).Run(
+		func(args mock.Arguments) {
+			if err != nil {
+				return
+			}
+
+			file, err := ioutil.ReadFile("testdata/rollup_info.json")
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(file, args.Get(1)))
+		},
+	).Once()
+}
+
+func TestDialect_Detect(t *testing.T) {
+	tests := map[string]struct {
+		probeErr error
+
+		expected Dialect
+	}{
+		"l2geth": {
+			expected: LegacyDialect,
+		},
+		"op-geth": {
+			probeErr: &jsonError{code: -32601, message: "the method rollup_getInfo does not exist/is not available"},
+			expected: BedrockDialect,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC, dialect: newDialectResolver(AutoDialect)}
+			ctx := context.Background()
+
+			mockRollupInfo(ctx, t, mockJSONRPC, test.probeErr)
+
+			// The node is only probed once
+			for i := 0; i < 2; i++ {
+				d, err := c.Dialect(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, d)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDialect_DetectError(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, dialect: newDialectResolver(AutoDialect)}
+	ctx := context.Background()
+
+	// A failed probe is retried on the next call
+	nodeErr := errors.New("connection refused")
+	mockRollupInfo(ctx, t, mockJSONRPC, nodeErr)
+	d, err := c.Dialect(ctx)
+	assert.True(t, errors.Is(err, nodeErr))
+	assert.Empty(t, d)
+
+	mockRollupInfo(ctx, t, mockJSONRPC, nil)
+	d, err = c.Dialect(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, LegacyDialect, d)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestDialect_Configured(t *testing.T) {
+	tests := map[string]struct {
+		dialect *dialectResolver
+
+		expected Dialect
+	}{
+		"default": {
+			expected: LegacyDialect,
+		},
+		"legacy": {
+			dialect:  newDialectResolver(LegacyDialect),
+			expected: LegacyDialect,
+		},
+		"bedrock": {
+			dialect:  newDialectResolver(BedrockDialect),
+			expected: BedrockDialect,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC, dialect: test.dialect}
+
+			d, err := c.Dialect(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, d)
+
+			// The node is never probed
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDialect_Closed(t *testing.T) {
+	c := &Client{closed: 1}
+
+	d, err := c.Dialect(context.Background())
+	assert.Empty(t, d)
+	assert.True(t, errors.Is(err, ErrClientClosed))
+}
+
+func TestDialect_Receipts(t *testing.T) {
+	tests := map[string]struct {
+		dialect nodeDialect
+		file    string
+
+		expectedL1Fee *big.Int
+		expectedErr   bool
+	}{
+		"legacy": {
+			dialect:       legacyDialect{},
+			file:          "testdata/tx_receipt_deposit.json",
+			expectedL1Fee: big.NewInt(0),
+		},
+		"legacy rejects op-geth deposit": {
+			dialect:     legacyDialect{},
+			file:        "testdata/tx_receipt_bedrock_deposit.json",
+			expectedErr: true,
+		},
+		"bedrock deposit": {
+			dialect:       bedrockDialect{},
+			file:          "testdata/tx_receipt_bedrock_deposit.json",
+			expectedL1Fee: big.NewInt(0),
+		},
+		"bedrock without l1FeeScalar": {
+			dialect:       bedrockDialect{},
+			file:          "testdata/tx_receipt_bedrock.json",
+			expectedL1Fee: big.NewInt(0x1a4b2c3d),
+		},
+		"bedrock with l2geth receipt": {
+			dialect:       bedrockDialect{},
+			file:          "testdata/tx_receipt_deposit.json",
+			expectedL1Fee: big.NewInt(0),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, err := ioutil.ReadFile(test.file)
+			assert.NoError(t, err)
+
+			var receipt *types.Receipt
+			err = json.Unmarshal(file, test.dialect.receiptResult(&receipt))
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedL1Fee.String(), receipt.L1Fee.String())
+			assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+			var receipts []*types.Receipt
+			list := append([]byte("["), append(file, []byte(", null]")...)...)
+			assert.NoError(t, json.Unmarshal(list, test.dialect.receiptsResult(&receipts)))
+			assert.Len(t, receipts, 2)
+			assert.Equal(t, receipt.TxHash, receipts[0].TxHash)
+			assert.Nil(t, receipts[1])
+		})
+	}
+}
+
+// bedrockBlockTxHashes are the hashes op-geth reports for the
+// transactions of block 6953772: the L1 attributes deposit and
+// an EIP-1559 transfer.
+var bedrockBlockTxHashes = []string{
+	"0x24ad516d1769e0f40852e40dcc87c3e65b0ff21c4915a2b054b6ca0ee76213a7",
+	"0x58a183a03128a7b45f7fb28e0f9121f133a1ca3e92c8100c4177c3f49ddee9c3",
+}
+
+// matchTxHashBatch matches a batch of method requests whose first
+// argument is each of txHashes, in order.
+func matchTxHashBatch(method string, txHashes []string) interface{} {
+	return mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+		if len(rpcs) != len(txHashes) {
+			return false
+		}
+		for i, req := range rpcs {
+			if req.Method != method || req.Args[0] != txHashes[i] {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// mockBedrockBlock mocks the requests made to convert block
+// 6953772, as served by op-geth.
+func mockBedrockBlock(ctx context.Context, t *testing.T, mockJSONRPC *mocks.JSONRPC) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x6a1b2c",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			file, err := ioutil.ReadFile("testdata/block_bedrock_6953772.json")
+			assert.NoError(t, err)
+
+			*args.Get(1).(*json.RawMessage) = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		matchTxHashBatch("debug_traceTransaction", bedrockBlockTxHashes),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+			for i, txHash := range bedrockBlockTxHashes {
+				file, err := ioutil.ReadFile("testdata/tx_trace_" + txHash + ".json")
+				assert.NoError(t, err)
+
+				call := new(Call)
+				assert.NoError(t, call.UnmarshalJSON(file))
+				*(r[i].Result.(**Call)) = call
+			}
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		matchTxHashBatch("eth_getTransactionReceipt", bedrockBlockTxHashes),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+			for i, txHash := range bedrockBlockTxHashes {
+				file, err := ioutil.ReadFile("testdata/tx_receipt_" + txHash + ".json")
+				assert.NoError(t, err)
+
+				// The result is decoded in the dialect of the client
+				assert.NoError(t, json.Unmarshal(file, r[i].Result))
+			}
+		},
+	).Once()
+}
+
+func TestDialect_Block(t *testing.T) {
+	tests := map[string]struct {
+		probeErr  error
+		index     int64
+		mockBlock func(context.Context, *testing.T, *mocks.JSONRPC)
+
+		expected         Dialect
+		expectedTxHashes []string
+		expectedOpTypes  [][]string
+		expectedFees     []string
+	}{
+		"l2geth": {
+			index:            1241187,
+			mockBlock:        mockDepositBlock,
+			expected:         LegacyDialect,
+			expectedTxHashes: []string{depositTxHash},
+			expectedOpTypes:  [][]string{{MintOpType, CallOpType, CallOpType}},
+			expectedFees:     []string{""},
+		},
+		"op-geth": {
+			probeErr:         &jsonError{code: -32601, message: "the method rollup_getInfo does not exist/is not available"},
+			index:            6953772,
+			mockBlock:        mockBedrockBlock,
+			expected:         BedrockDialect,
+			expectedTxHashes: bedrockBlockTxHashes,
+			expectedOpTypes: [][]string{
+				{},
+				{FeeOpType, FeeOpType, CallOpType, CallOpType},
+			},
+			// The L2 fee at the effective gas price plus the L1 fee
+			// of the receipt looked up by the transfer's hash
+			expectedFees: []string{"", "20717712989134"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+			tc, err := testTraceConfig()
+			assert.NoError(t, err)
+			c := &Client{
+				c:               mockJSONRPC,
+				currencyFetcher: mockCurrencyFetcher,
+				tc:              tc,
+				p:               params.GoerliChainConfig,
+				traceSemaphore:  semaphore.NewWeighted(100),
+				dialect:         newDialectResolver(AutoDialect),
+			}
+
+			ctx := context.Background()
+			mockRollupInfo(ctx, t, mockJSONRPC, test.probeErr)
+			test.mockBlock(ctx, t, mockJSONRPC)
+
+			block, err := c.Block(ctx, &RosettaTypes.PartialBlockIdentifier{
+				Index: RosettaTypes.Int64(test.index),
+			})
+			assert.NoError(t, err)
+			assert.Len(t, block.Transactions, len(test.expectedTxHashes))
+			for i, tx := range block.Transactions {
+				assert.Equal(t, test.expectedTxHashes[i], tx.TransactionIdentifier.Hash)
+
+				opTypes := []string{}
+				for _, op := range tx.Operations {
+					opTypes = append(opTypes, op.Type)
+				}
+				assert.Equal(t, test.expectedOpTypes[i], opTypes)
+				if test.expectedFees[i] != "" {
+					assert.Equal(t, "-"+test.expectedFees[i], tx.Operations[0].Amount.Value)
+					assert.Equal(t, test.expectedFees[i], tx.Operations[1].Amount.Value)
+				}
+			}
+
+			d, err := c.Dialect(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, d)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockCurrencyFetcher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRollupInfo_BedrockDialect(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, dialect: newDialectResolver(BedrockDialect)}
+
+	// op-geth has no rollup namespace, so it is not asked
+	info, err := c.RollupInfo(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, info)
+
+	mockJSONRPC.AssertExpectations(t)
+}
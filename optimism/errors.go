@@ -14,7 +14,10 @@
 
 package optimism
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Client errors
 var (
@@ -22,4 +25,29 @@ var (
 	ErrCallParametersInvalid = errors.New("call parameters invalid")
 	ErrCallOutputMarshal     = errors.New("call output marshal")
 	ErrCallMethodInvalid     = errors.New("call method invalid")
+	ErrClientClosed          = errors.New("client closed")
+	ErrNotERC20              = errors.New("contract does not implement ERC20")
+
+	ErrInvalidTokenContractAddress = errors.New("invalid token contract address")
+	ErrInvalidRawTransaction       = errors.New("invalid raw transaction")
+	ErrHistoricalStateUnavailable  = errors.New("historical state unavailable")
+	ErrBloomMismatch               = errors.New("logs bloom mismatch")
+	ErrInvalidGraphQLVariable      = errors.New("invalid graphQL variable")
+	ErrInvalidAddress              = errors.New("invalid address")
+	ErrTraceCapacityBusy           = errors.New("trace capacity busy")
+	ErrChainIDChanged              = errors.New("chain id changed")
+	ErrChainIDMismatch             = errors.New("chain id mismatch")
+	ErrMethodUnsupported           = errors.New("method unsupported by node")
+	ErrTransactionHashMismatch     = errors.New("transaction hash mismatch")
+
+	// ErrStatePruned is the ErrHistoricalStateUnavailable returned when
+	// the node has pruned the state of the requested block, so the
+	// request must be routed to an archive node.
+	ErrStatePruned = fmt.Errorf("%w: state pruned", ErrHistoricalStateUnavailable)
+
+	ErrBlockNotFound   = errors.New("block not found")
+	ErrNodeUnavailable = errors.New("node unavailable")
+	ErrRateLimited     = errors.New("rate limited")
+	ErrRequestCanceled = errors.New("request canceled")
+	ErrRequestTimeout  = errors.New("request timed out")
 )
@@ -0,0 +1,90 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	ethereum "github.com/ethereum-optimism/optimism/l2geth"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// FeeData are the fees suggested for an EIP-1559 transaction.
+type FeeData struct {
+	// BaseFee is the base fee of the latest block. It is nil if the
+	// chain has no base fee, e.g. before its London upgrade.
+	BaseFee *big.Int
+
+	// GasTipCap is the suggested priority fee per gas.
+	GasTipCap *big.Int
+}
+
+// SuggestGasTipCap retrieves the currently suggested priority fee per
+// gas of an EIP-1559 transaction with eth_maxPriorityFeePerGas.
+func (ec *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	var hex hexutil.Big
+	if err := ec.c.CallContext(ctx, &hex, "eth_maxPriorityFeePerGas"); err != nil {
+		return nil, rpcError(err, false)
+	}
+	return (*big.Int)(&hex), nil
+}
+
+// SuggestFeeData retrieves the base fee of the latest block and the
+// suggested priority fee per gas in a single batch request.
+func (ec *Client) SuggestFeeData(ctx context.Context) (*FeeData, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	var (
+		raw json.RawMessage
+		tip hexutil.Big
+	)
+	reqs := []rpc.BatchElem{
+		{Method: "eth_getBlockByNumber", Args: []interface{}{toBlockNumArg(nil), false}, Result: &raw},
+		{Method: "eth_maxPriorityFeePerGas", Result: &tip},
+	}
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, rpcError(err, true)
+	}
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			return nil, rpcError(fmt.Errorf("%w: %s failed", reqs[i].Error, reqs[i].Method), true)
+		}
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, rpcError(ethereum.NotFound, true)
+	}
+
+	var head struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, err
+	}
+
+	return &FeeData{
+		BaseFee:   (*big.Int)(head.BaseFee),
+		GasTipCap: (*big.Int)(&tip),
+	}, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSuggestGasTipCap(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_maxPriorityFeePerGas",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*hexutil.Big)
+
+			*r = *(*hexutil.Big)(big.NewInt(1000000))
+		},
+	).Once()
+
+	resp, err := c.SuggestGasTipCap(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000000), resp)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestSuggestFeeData(t *testing.T) {
+	errTip := errors.New("unexpected tip")
+
+	tests := map[string]struct {
+		block    string
+		tipError error
+
+		expected    *FeeData
+		expectedErr error
+	}{
+		"london": {
+			block: `{"number":"0x12f063","baseFeePerGas":"0x3b9aca00"}`,
+			expected: &FeeData{
+				BaseFee:   big.NewInt(1000000000),
+				GasTipCap: big.NewInt(1000000),
+			},
+		},
+		"no base fee": {
+			block: `{"number":"0x12f063"}`,
+			expected: &FeeData{
+				GasTipCap: big.NewInt(1000000),
+			},
+		},
+		"no block": {
+			block:       `null`,
+			expectedErr: ErrBlockNotFound,
+		},
+		"tip unsupported": {
+			block: `{"number":"0x12f063","baseFeePerGas":"0x3b9aca00"}`,
+			tipError: &jsonError{
+				code:    -32601,
+				message: "the method eth_maxPriorityFeePerGas does not exist/is not available",
+			},
+			expectedErr: ErrMethodUnsupported,
+		},
+		"tip error": {
+			block:       `{"number":"0x12f063","baseFeePerGas":"0x3b9aca00"}`,
+			tipError:    errTip,
+			expectedErr: errTip,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 2 && rpcs[0].Method == "eth_getBlockByNumber" &&
+						rpcs[1].Method == "eth_maxPriorityFeePerGas"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					assert.Equal(t, []interface{}{"latest", false}, r[0].Args)
+
+					*(r[0].Result.(*json.RawMessage)) = json.RawMessage(test.block)
+					*(r[1].Result.(*hexutil.Big)) = *(*hexutil.Big)(big.NewInt(1000000))
+					r[1].Error = test.tipError
+				},
+			).Once()
+
+			resp, err := c.SuggestFeeData(ctx)
+			if test.expectedErr != nil {
+				assert.Nil(t, resp)
+				assert.ErrorIs(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, resp)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+)
+
+// FeeRecipientKey is the block metadata key holding the
+// address credited with the fees of the block's transactions.
+const FeeRecipientKey = "fee_recipient"
+
+// FeeRecipientOverride credits fees to Recipient for blocks in
+// [StartHeight, EndHeight]. An EndHeight of 0 leaves the range open.
+// This is used to correct the recipient of historical blocks.
+type FeeRecipientOverride struct {
+	StartHeight uint64 `json:"start_height"`
+	EndHeight   uint64 `json:"end_height"`
+	Recipient   string `json:"recipient"`
+}
+
+// CheckFeeRecipientOverrides returns a copy of overrides with their
+// recipients checksummed. It returns an error if any recipient is not
+// a valid address or any range ends before it starts.
+func CheckFeeRecipientOverrides(overrides []FeeRecipientOverride) ([]FeeRecipientOverride, error) {
+	checked := make([]FeeRecipientOverride, len(overrides))
+	for i, o := range overrides {
+		recipient, ok := ChecksumAddress(o.Recipient)
+		if !ok {
+			return nil, fmt.Errorf("fee recipient override %d: %s is not a valid address", i, o.Recipient)
+		}
+		if o.EndHeight != 0 && o.EndHeight < o.StartHeight {
+			return nil, fmt.Errorf(
+				"fee recipient override %d: end height %d is before start height %d",
+				i,
+				o.EndHeight,
+				o.StartHeight,
+			)
+		}
+
+		o.Recipient = recipient
+		checked[i] = o
+	}
+
+	return checked, nil
+}
+
+func (o *FeeRecipientOverride) contains(number *big.Int) bool {
+	// Without a height (pending), only open ranges apply
+	if number == nil {
+		return o.EndHeight == 0
+	}
+
+	height := number.Uint64()
+	return height >= o.StartHeight && (o.EndHeight == 0 || height <= o.EndHeight)
+}
+
+// feeRecipient resolves the address credited with transaction fees
+// in the block at number. A nil number resolves the recipient for the
+// pending block. The first match wins, in order of precedence:
+//
+//  1. the first configured FeeRecipientOverride containing the block
+//  2. the sequencer fee vault, from feeVaultHeight onwards
+//  3. the header coinbase
+//
+// l2geth leaves the coinbase as the zero address once fees are sent to
+// the vault, so the vault has to be assumed from its activation height.
+func (ec *Client) feeRecipient(number *big.Int, coinbase common.Address) string {
+	for i := range ec.feeRecipientOverrides {
+		if ec.feeRecipientOverrides[i].contains(number) {
+			return ec.feeRecipientOverrides[i].Recipient
+		}
+	}
+
+	if number == nil || number.Uint64() >= ec.feeVaultHeight {
+		return sequencerFeeVaultAddr
+	}
+
+	return coinbase.Hex()
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeRecipient(t *testing.T) {
+	coinbase := common.HexToAddress("0x00000398232E2064F896018496b4b44b3D62751F")
+	overrides := []FeeRecipientOverride{
+		{
+			StartHeight: 100,
+			EndHeight:   200,
+			Recipient:   "0x1111111111111111111111111111111111111111",
+		},
+		{
+			StartHeight: 150,
+			Recipient:   "0x2222222222222222222222222222222222222222",
+		},
+	}
+
+	var tests = map[string]struct {
+		overrides      []FeeRecipientOverride
+		feeVaultHeight uint64
+		number         *big.Int
+
+		recipient string
+	}{
+		"fee vault": {
+			number:    big.NewInt(10),
+			recipient: sequencerFeeVaultAddr,
+		},
+		"coinbase before fee vault": {
+			feeVaultHeight: 50,
+			number:         big.NewInt(10),
+			recipient:      coinbase.Hex(),
+		},
+		"fee vault activation height": {
+			feeVaultHeight: 50,
+			number:         big.NewInt(50),
+			recipient:      sequencerFeeVaultAddr,
+		},
+		"override before fee vault": {
+			overrides:      overrides,
+			feeVaultHeight: 500,
+			number:         big.NewInt(100),
+			recipient:      "0x1111111111111111111111111111111111111111",
+		},
+		"override range end": {
+			overrides: overrides,
+			number:    big.NewInt(200),
+			recipient: "0x1111111111111111111111111111111111111111",
+		},
+		"open override after range": {
+			overrides: overrides,
+			number:    big.NewInt(201),
+			recipient: "0x2222222222222222222222222222222222222222",
+		},
+		"outside overrides": {
+			overrides: overrides,
+			number:    big.NewInt(99),
+			recipient: sequencerFeeVaultAddr,
+		},
+		"pending block": {
+			overrides:      overrides,
+			feeVaultHeight: 500,
+			recipient:      "0x2222222222222222222222222222222222222222",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{
+				feeRecipientOverrides: test.overrides,
+				feeVaultHeight:        test.feeVaultHeight,
+			}
+
+			assert.Equal(t, test.recipient, c.feeRecipient(test.number, coinbase))
+		})
+	}
+}
+
+func TestCheckFeeRecipientOverrides(t *testing.T) {
+	var tests = map[string]struct {
+		override FeeRecipientOverride
+
+		recipient string
+		err       string
+	}{
+		"checksummed": {
+			override: FeeRecipientOverride{
+				StartHeight: 100,
+				EndHeight:   200,
+				Recipient:   "0x00000398232e2064f896018496b4b44b3d62751f",
+			},
+			recipient: "0x00000398232E2064F896018496b4b44b3D62751F",
+		},
+		"open range": {
+			override: FeeRecipientOverride{
+				StartHeight: 100,
+				Recipient:   "0x00000398232E2064F896018496b4b44b3D62751F",
+			},
+			recipient: "0x00000398232E2064F896018496b4b44b3D62751F",
+		},
+		"empty recipient": {
+			override: FeeRecipientOverride{StartHeight: 100},
+			err:      " is not a valid address",
+		},
+		"invalid recipient": {
+			override: FeeRecipientOverride{Recipient: "0x1234"},
+			err:      "0x1234 is not a valid address",
+		},
+		"range ends before it starts": {
+			override: FeeRecipientOverride{
+				StartHeight: 200,
+				EndHeight:   100,
+				Recipient:   "0x00000398232E2064F896018496b4b44b3D62751F",
+			},
+			err: "end height 100 is before start height 200",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			checked, err := CheckFeeRecipientOverrides([]FeeRecipientOverride{test.override})
+			if len(test.err) > 0 {
+				assert.Nil(t, checked)
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.recipient, checked[0].Recipient)
+		})
+	}
+}
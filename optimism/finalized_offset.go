@@ -0,0 +1,77 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	ethereum "github.com/ethereum-optimism/optimism/l2geth"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+)
+
+// FinalizedOffsetMethod is the call method that returns the block
+// Offset blocks below the current finalized head. Indexers that want
+// to stay clear of reorgs can follow it instead of the tip.
+const FinalizedOffsetMethod = "rosetta_getFinalizedBlockByOffset"
+
+// FinalizedOffsetInput is the input to FinalizedOffsetMethod.
+type FinalizedOffsetInput struct {
+	Offset        int64 `json:"offset"`
+	ShowTxDetails bool  `json:"show_transaction_details"`
+}
+
+// finalizedOffsetBlock resolves the finalized head and returns
+// the block Offset blocks below it.
+func (ec *Client) finalizedOffsetBlock(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input FinalizedOffsetInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	if input.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset %d is negative", ErrCallParametersInvalid, input.Offset)
+	}
+
+	var raw json.RawMessage
+	if err := ec.c.CallContext(ctx, &raw, "eth_getBlockByNumber", FinalizedBlockTag, false); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, ethereum.NotFound
+	}
+
+	var head *types.Header
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, err
+	}
+
+	finalized := head.Number.Int64()
+	if input.Offset > finalized {
+		return nil, fmt.Errorf(
+			"%w: offset %d is below genesis from finalized block %d",
+			ErrCallParametersInvalid,
+			input.Offset,
+			finalized,
+		)
+	}
+
+	index := finalized - input.Offset
+	return ec.blockByNumber(ctx, &index, input.ShowTxDetails)
+}
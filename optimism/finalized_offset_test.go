@@ -0,0 +1,131 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestCall_FinalizedOffset(t *testing.T) {
+	var tests = map[string]struct {
+		offset int64
+
+		expectedBlock string
+		expectedErr   error
+	}{
+		"finalized minus 2": {
+			offset:        2,
+			expectedBlock: "0x2aee",
+		},
+		"finalized": {
+			offset:        0,
+			expectedBlock: "0x2af0",
+		},
+		"negative offset": {
+			offset:      -1,
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"below genesis": {
+			offset:      10993,
+			expectedErr: ErrCallParametersInvalid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: cf,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			if test.offset >= 0 {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"eth_getBlockByNumber",
+					FinalizedBlockTag,
+					false,
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						r := args.Get(1).(*json.RawMessage)
+						file, err := ioutil.ReadFile("testdata/block_10992.json")
+						assert.NoError(t, err)
+						*r = json.RawMessage(file)
+					},
+				).Once()
+			}
+			if test.expectedErr == nil {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"eth_getBlockByNumber",
+					test.expectedBlock,
+					true,
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						r := args.Get(1).(*map[string]interface{})
+						*r = map[string]interface{}{"number": test.expectedBlock}
+					},
+				).Once()
+			}
+
+			resp, err := c.Call(
+				ctx,
+				&RosettaTypes.CallRequest{
+					Method: FinalizedOffsetMethod,
+					Parameters: map[string]interface{}{
+						"offset":                   test.offset,
+						"show_transaction_details": true,
+					},
+				},
+			)
+			if test.expectedErr != nil {
+				assert.Nil(t, resp)
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{"number": test.expectedBlock}, resp.Result)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"golang.org/x/sync/semaphore"
+)
+
+// nullJSONRPC answers every request with null, as a node that knows
+// nothing would. Fuzzed calls get past the node without a mock
+// expecting each request.
+type nullJSONRPC struct{}
+
+func (nullJSONRPC) CallContext(context.Context, interface{}, string, ...interface{}) error {
+	return nil
+}
+
+func (nullJSONRPC) BatchCallContext(context.Context, []rpc.BatchElem) error {
+	return nil
+}
+
+func (nullJSONRPC) Close() {}
+
+// nullGraphQL answers every query with null data.
+type nullGraphQL struct{}
+
+func (nullGraphQL) Query(context.Context, string, map[string]interface{}) (string, error) {
+	return `{"data":null}`, nil
+}
+
+func fuzzClient(t testing.TB) *Client {
+	cf, err := newERC20CurrencyFetcher(nullJSONRPC{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc, err := testTraceConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Client{
+		c:               nullJSONRPC{},
+		g:               nullGraphQL{},
+		currencyFetcher: cf,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+		indexAllTokens:  true,
+	}
+}
+
+// fuzzBlock returns a block holding the single
+// transaction fuzzed traces and receipts belong to.
+func fuzzBlock() *types.Block {
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+
+	return types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+}
+
+// addFixtures seeds f with the contents of the testdata
+// files matching pattern.
+func addFixtures(f *testing.F, pattern string) {
+	files, err := filepath.Glob(filepath.Join("testdata", pattern))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+}
+
+func FuzzCallParameters(f *testing.F) {
+	seeds := map[string]string{
+		"eth_getBlockByNumber":      `{"index":10992,"show_transaction_details":true}`,
+		"block_with_receipts":       `{"index":10992}`,
+		"eth_getTransactionReceipt": `{"tx_hash":"0xb358c6958b1cab722752939cbb92e3fec6b6023de360305910ce80c56c3dad9d","tx_hashes":["0x00"]}`,
+		"eth_call":                  `{"block_index":11408349,"to":"0x4200000000000000000000000000000000000006","data":"0x70a08231"}`,
+		"eth_getBalance":            `{"address":"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55","index":1}`,
+		"eth_getCode":               `{"address":"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"}`,
+		CallDiffMethod:              `{"to":"0x4200000000000000000000000000000000000006","data":"0x70a08231","before":{"index":1},"after":{"index":2}}`,
+		"eth_estimateGas":           `{"from":"0xE550f300E477C60CE7e7172d12e5a27e9379D2e3","to":"0xaD6D458402F60fD3Bd25163575031ACDce07538D"}`,
+		"eth_getLogs":               `{"from_block":1,"to_block":"0x2","address":["0x4200000000000000000000000000000000000006"],"topics":[null,["0x00"]]}`,
+		GetProofMethod:              `{"address":"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55","storage_keys":["0x0"],"include_account":true,"include_storage":true,"index":1}`,
+		GetStorageAtMethod:          `{"address":"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55","position":"0x0","index":1}`,
+		"debug_traceTransaction":    `{"tx_hash":"0xb358c6958b1cab722752939cbb92e3fec6b6023de360305910ce80c56c3dad9d"}`,
+		"eth_chainId":               `{}`,
+		"net_version":               `{}`,
+		DecodeTransactionMethod:     `{"signed_transaction":"0xf86b"}`,
+		FinalizedOffsetMethod:       `{"offset":3}`,
+		BlockOperationsMethod:       `{"index":10992,"operation_types":["FEE"]}`,
+		BalanceHistoryMethod:        `{"address":"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55","indices":[1,2,3]}`,
+		SendBundleMethod:            `{"signed_transactions":["0xf86a80843b9aca00825208941ff502f9fe838cd772874cb67d0d96b93fd1d6d78725d4b6199a415d8029a01d110bf9fd468f7d00b3ce530832e99818835f45e9b08c66f8d9722264bb36c7a02711f47ec99f9ac585840daef41b7118b52ec72f02fcb30d874d36b10b668b59"],"block_index":1}`, // nolint
+		AllowanceMethod:             `{"owner":"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55","spender":"0x7492ce19d83b3a0BaC1BEBC9706ce0dF4ADD105F","token":"0xDA10009cBd5D07dd0CeCc66161FC93D7c9000da1","index":1}`,
+	}
+	for _, method := range CallMethods {
+		f.Add(method, []byte(seeds[method]))
+	}
+
+	c := fuzzClient(f)
+	f.Fuzz(func(t *testing.T, method string, data []byte) {
+		var parameters map[string]interface{}
+		if err := json.Unmarshal(data, &parameters); err != nil {
+			return
+		}
+
+		resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+			Method:     method,
+			Parameters: parameters,
+		})
+		if err == nil && resp == nil {
+			t.Fatalf("%s returned neither a response nor an error", method)
+		}
+	})
+}
+
+func FuzzTraceDecode(f *testing.F) {
+	addFixtures(f, "tx_trace_*.json")
+	addFixtures(f, "block_trace_*.json")
+
+	block := fuzzBlock()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		call := new(Call)
+		if err := call.UnmarshalJSON(data); err != nil {
+			return
+		}
+
+		calls := flattenTraces(call, []*flatCall{})
+		ops := traceOps(block, calls, 0)
+		for i, op := range ops {
+			if op.OperationIdentifier.Index != int64(i) {
+				t.Fatalf("operation %d has index %d", i, op.OperationIdentifier.Index)
+			}
+		}
+	})
+}
+
+func FuzzReceiptDecode(f *testing.F) {
+	addFixtures(f, "tx_receipt_*.json")
+
+	c := fuzzClient(f)
+	block := fuzzBlock()
+	f.Fuzz(func(t *testing.T, data []byte) {
+		receipt := new(types.Receipt)
+		if err := receipt.UnmarshalJSON(data); err != nil {
+			return
+		}
+
+		// The trace may be missing, e.g. if the tracer timed out
+		tx := &loadedTransaction{Receipt: receipt}
+		if _, err := c.erc20TokenOps(context.Background(), block, tx, 0); err != nil {
+			return
+		}
+		c.erc20Approvals(receipt)
+	})
+}
+
+func FuzzBlockDecode(f *testing.F) {
+	addFixtures(f, "block_[0-9]*.json")
+	addFixtures(f, "block_goerli_*.json")
+	addFixtures(f, "block_deposit.json")
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var body rpcBlock
+		if err := json.Unmarshal(data, &body); err != nil {
+			return
+		}
+
+		for _, tx := range body.Transactions {
+			tx.LoadedTransaction()
+		}
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// genesisCache holds the identifier of the genesis block once it
+// has been fetched. It is shared by the copies of a Client.
+type genesisCache struct {
+	mu         sync.Mutex
+	identifier *RosettaTypes.BlockIdentifier
+}
+
+// GenesisBlockIdentifier returns the identifier of block 0 as served
+// by the node. It is only fetched once by clients created with
+// NewClient.
+func (ec *Client) GenesisBlockIdentifier(ctx context.Context) (*RosettaTypes.BlockIdentifier, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if ec.genesis == nil {
+		return ec.genesisBlockIdentifier(ctx)
+	}
+
+	ec.genesis.mu.Lock()
+	defer ec.genesis.mu.Unlock()
+	if ec.genesis.identifier == nil {
+		identifier, err := ec.genesisBlockIdentifier(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ec.genesis.identifier = identifier
+	}
+
+	return ec.genesis.identifier, nil
+}
+
+func (ec *Client) genesisBlockIdentifier(ctx context.Context) (*RosettaTypes.BlockIdentifier, error) {
+	header, err := ec.blockHeader(ctx, big.NewInt(GenesisBlockIndex))
+	if err != nil {
+		return nil, rpcError(err, true)
+	}
+
+	return &RosettaTypes.BlockIdentifier{
+		Hash:  header.Hash().Hex(),
+		Index: GenesisBlockIndex,
+	}, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockGenesisHeader(ctx context.Context, mockJSONRPC *mocks.JSONRPC) *mock.Call {
+	return mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x0",
+		false,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			*args.Get(1).(**types.Header) = &types.Header{Number: big.NewInt(0)}
+		},
+	)
+}
+
+func TestGenesisBlockIdentifier(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, genesis: &genesisCache{}}
+	ctx := context.Background()
+
+	// Only fetched once
+	mockGenesisHeader(ctx, mockJSONRPC).Once()
+	for i := 0; i < 2; i++ {
+		genesis, err := c.GenesisBlockIdentifier(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, blockAt(0), genesis)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestGenesisBlockIdentifier_Concurrent(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, genesis: &genesisCache{}}
+	ctx := context.Background()
+
+	// Concurrent callers share a single fetch
+	mockGenesisHeader(ctx, mockJSONRPC).Once()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			genesis, err := c.GenesisBlockIdentifier(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, blockAt(0), genesis)
+		}()
+	}
+	wg.Wait()
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestGenesisBlockIdentifier_Uncached(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	mockGenesisHeader(ctx, mockJSONRPC).Twice()
+	for i := 0; i < 2; i++ {
+		genesis, err := c.GenesisBlockIdentifier(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, blockAt(0), genesis)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestGenesisBlockIdentifier_Error(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, genesis: &genesisCache{}}
+	ctx := context.Background()
+
+	// Errors are not cached
+	nodeErr := errors.New("connection refused")
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x0",
+		false,
+	).Return(
+		nodeErr,
+	).Once()
+	genesis, err := c.GenesisBlockIdentifier(ctx)
+	assert.True(t, errors.Is(err, nodeErr))
+	assert.Nil(t, genesis)
+
+	mockGenesisHeader(ctx, mockJSONRPC).Once()
+	genesis, err = c.GenesisBlockIdentifier(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, blockAt(0), genesis)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestGenesisBlockIdentifier_Closed(t *testing.T) {
+	c := &Client{closed: 1}
+
+	genesis, err := c.GenesisBlockIdentifier(context.Background())
+	assert.Nil(t, genesis)
+	assert.True(t, errors.Is(err, ErrClientClosed))
+}
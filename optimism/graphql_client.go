@@ -18,11 +18,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"path"
 	"time"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
 )
 
 const (
@@ -38,10 +42,27 @@ type GraphQLClient struct {
 	url    string
 }
 
-// Query makes a query to the graphQL endpoint.
-func (g *GraphQLClient) Query(ctx context.Context, input string) (string, error) {
-	query := map[string]string{
-		"query": input,
+// graphQLRequest is the body of a request to the graphQL endpoint.
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Query makes a query to the graphQL endpoint. Caller-supplied values
+// must be passed in variables and referenced from input as $name, never
+// interpolated into input, so they can't alter the query.
+func (g *GraphQLClient) Query(
+	ctx context.Context,
+	input string,
+	variables map[string]interface{},
+) (string, error) {
+	if err := validateGraphQLVariables(variables); err != nil {
+		return "", err
+	}
+
+	query := &graphQLRequest{
+		Query:     input,
+		Variables: variables,
 	}
 
 	jsonValue, err := json.Marshal(query)
@@ -73,6 +94,37 @@ func (g *GraphQLClient) Query(ctx context.Context, input string) (string, error)
 	return string(data), nil
 }
 
+// validateGraphQLVariables checks the format of the address and hash
+// variables before they are sent to the node.
+func validateGraphQLVariables(variables map[string]interface{}) error {
+	for name, value := range variables {
+		var valid bool
+		switch name {
+		case "address":
+			address, ok := value.(string)
+			_, valid = ChecksumAddress(address)
+			valid = valid && ok
+		case "hash":
+			hash, _ := value.(string)
+			decoded, err := hexutil.Decode(hash)
+			valid = err == nil && len(decoded) == common.HashLength
+		default:
+			valid = true
+		}
+
+		if !valid {
+			return fmt.Errorf("%w: %s %v", ErrInvalidGraphQLVariable, name, value)
+		}
+	}
+
+	return nil
+}
+
+// Close releases any idle connections held by the client.
+func (g *GraphQLClient) Close() {
+	g.client.CloseIdleConnections()
+}
+
 func newGraphQLClient(baseURL string, timeout time.Duration) (*GraphQLClient, error) {
 	// Compute GraphQL Endpoint
 	u, err := url.Parse(baseURL)
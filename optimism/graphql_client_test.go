@@ -0,0 +1,97 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBalanceQuery = `query($address: Address!, $hash: Bytes32) {
+	block(hash: $hash) {
+		hash
+		number
+		account(address: $address) {
+			balance
+			transactionCount
+			code
+		}
+	}
+}`
+
+func TestGraphQLClient_Query(t *testing.T) {
+	var tests = map[string]struct {
+		variables map[string]interface{}
+
+		expectedErr error
+	}{
+		"valid": {
+			variables: map[string]interface{}{
+				"address": "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+				"hash":    "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+			},
+		},
+		"malicious address": {
+			variables: map[string]interface{}{
+				"address": `0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55") { code } } block { hash`,
+				"hash":    "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+			},
+			expectedErr: ErrInvalidGraphQLVariable,
+		},
+		"malicious hash": {
+			variables: map[string]interface{}{
+				"address": "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+				"hash":    `0xba9d"}`,
+			},
+			expectedErr: ErrInvalidGraphQLVariable,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var received *graphQLRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				_, _ = w.Write([]byte(`{"data":{}}`))
+			}))
+			defer server.Close()
+
+			g, err := newGraphQLClient(server.URL, time.Second)
+			assert.NoError(t, err)
+			defer g.Close()
+
+			resp, err := g.Query(context.Background(), testBalanceQuery, test.variables)
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+				assert.Empty(t, resp)
+				assert.Nil(t, received)
+				return
+			}
+
+			// Values are sent as variables, never as part of the query
+			assert.NoError(t, err)
+			assert.Equal(t, `{"data":{}}`, resp)
+			assert.Equal(t, testBalanceQuery, received.Query)
+			assert.Equal(t, test.variables, received.Variables)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithHTTPHeaders attaches headers to every JSON-RPC and GraphQL
+// request made to the node, e.g. an API key required by a managed
+// node provider. They replace any headers set by a previous call, so
+// an empty map clears them. Only the header names are logged, as
+// values are often secrets.
+func (ec *Client) WithHTTPHeaders(headers map[string]string) *Client {
+	if ec.httpHeaders == nil {
+		return ec
+	}
+
+	if len(headers) > 0 {
+		names := make([]string, 0, len(headers))
+		for name := range headers {
+			names = append(names, http.CanonicalHeaderKey(name))
+		}
+		sort.Strings(names)
+		log.Printf("sending HTTP headers %s to the node", strings.Join(names, ", "))
+	}
+
+	ec.httpHeaders.set(headers)
+	return ec
+}
+
+// httpHeaders are the extra headers shared by the
+// transports of the JSON-RPC and GraphQL clients.
+type httpHeaders struct {
+	mu     sync.RWMutex
+	header http.Header
+}
+
+func (h *httpHeaders) set(headers map[string]string) {
+	header := make(http.Header, len(headers))
+	for name, value := range headers {
+		header.Set(name, value)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.header = header
+}
+
+// headerTransport is an http.RoundTripper that adds
+// httpHeaders to each request before sending it.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers *httpHeaders
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.headers.mu.RLock()
+	header := t.headers.header
+	t.headers.mu.RUnlock()
+
+	if len(header) == 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the request it is given.
+	req = req.Clone(req.Context())
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	return t.base.RoundTrip(req)
+}
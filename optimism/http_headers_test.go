@@ -0,0 +1,126 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHTTPHeaders(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = map[string]string{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.URL.Path] = r.Header.Get("X-Api-Key")
+		mu.Unlock()
+
+		if strings.HasSuffix(r.URL.Path, graphQLPath) {
+			_, _ = w.Write([]byte(`{"data":{}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0xa"}`))
+	}))
+	defer server.Close()
+
+	// The server reports chain id 10 to every request
+	mainnet, _ := ChainConfigForID(10)
+	c, err := NewClient(server.URL, mainnet, ClientOptions{
+		EnableGethTracer: true,
+	})
+	assert.NoError(t, err)
+	defer c.Close()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	c.WithHTTPHeaders(map[string]string{"x-api-key": "secret-key"})
+	assert.Contains(t, logs.String(), "X-Api-Key")
+	assert.NotContains(t, logs.String(), "secret-key")
+
+	ctx := context.Background()
+	var chainID string
+	assert.NoError(t, c.c.CallContext(ctx, &chainID, "eth_chainId"))
+	assert.Equal(t, "0xa", chainID)
+
+	_, err = c.g.Query(ctx, "{ block { number } }", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"/":        "secret-key",
+		"/graphql": "secret-key",
+	}, received)
+
+	// An empty map clears the headers
+	c.WithHTTPHeaders(map[string]string{})
+	assert.NoError(t, c.c.CallContext(ctx, &chainID, "eth_chainId"))
+	_, err = c.g.Query(ctx, "{ block { number } }", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"/":        "",
+		"/graphql": "",
+	}, received)
+}
+
+func TestHeaderTransport(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	headers := &httpHeaders{}
+	client := &http.Client{
+		Transport: &headerTransport{base: http.DefaultTransport, headers: headers},
+	}
+
+	// No headers are added until they are set
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Empty(t, received.Get("Authorization"))
+
+	headers.set(map[string]string{"Authorization": "Bearer token"})
+	req, err = http.NewRequest(http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	resp, err = client.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "Bearer token", received.Get("Authorization"))
+
+	// The caller's request is left unmodified
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
+
+func TestWithHTTPHeaders_NoTransport(t *testing.T) {
+	c := &Client{}
+	assert.Equal(t, c, c.WithHTTPHeaders(map[string]string{"X-Api-Key": "secret-key"}))
+}
@@ -0,0 +1,156 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+)
+
+// L1AttributesKey is the transaction metadata key holding the decoded
+// L1 attributes set by the L1 attributes deposit of a block.
+const L1AttributesKey = "l1_attributes"
+
+const (
+	// The L1 attributes deposit calls setL1BlockValues with ABI
+	// encoded arguments before Ecotone, and setL1BlockValuesEcotone
+	// with tightly packed arguments since.
+	l1AttributesLen        = selectorSize + 8*32 // nolint:gomnd
+	l1AttributesEcotoneLen = selectorSize + 160  // nolint:gomnd
+)
+
+var (
+	l1BlockAddr = common.HexToAddress("0x4200000000000000000000000000000000000015")
+
+	// keccak(setL1BlockValues(uint64,uint64,uint256,bytes32,uint64,bytes32,uint256,uint256))
+	setL1BlockValuesSelector = []byte{0x01, 0x5d, 0x8e, 0xb9}
+	// keccak(setL1BlockValuesEcotone())
+	setL1BlockValuesEcotoneSelector = []byte{0x44, 0x0a, 0x5e, 0x20}
+)
+
+// l1Attributes decodes the L1 attributes set by tx, if it is a
+// deposit to the L1Block predeploy. The decoder is selected by the
+// selector of the calldata.
+func l1Attributes(tx *loadedTransaction) (map[string]interface{}, bool, error) {
+	to := tx.Transaction.To()
+	if tx.Type != depositTxType || to == nil || *to != l1BlockAddr {
+		return nil, false, nil
+	}
+
+	data := tx.Transaction.Data()
+	switch {
+	case bytes.HasPrefix(data, setL1BlockValuesSelector):
+		attributes, err := decodeL1Attributes(data)
+		return attributes, true, err
+	case bytes.HasPrefix(data, setL1BlockValuesEcotoneSelector):
+		attributes, err := decodeL1AttributesEcotone(data)
+		return attributes, true, err
+	default:
+		return nil, false, nil
+	}
+}
+
+// decodeL1Attributes decodes the ABI encoded arguments of
+// setL1BlockValues.
+func decodeL1Attributes(data []byte) (map[string]interface{}, error) {
+	if len(data) != l1AttributesLen {
+		return nil, fmt.Errorf("setL1BlockValues calldata is %d bytes, expected %d", len(data), l1AttributesLen)
+	}
+
+	word := func(i int) []byte {
+		return data[selectorSize+i*32 : selectorSize+(i+1)*32]
+	}
+	uint64Word := func(i int) (uint64, error) {
+		value := new(big.Int).SetBytes(word(i))
+		if !value.IsUint64() {
+			return 0, fmt.Errorf("setL1BlockValues argument %d overflows uint64", i)
+		}
+		return value.Uint64(), nil
+	}
+
+	number, err := uint64Word(0)
+	if err != nil {
+		return nil, err
+	}
+	timestamp, err := uint64Word(1)
+	if err != nil {
+		return nil, err
+	}
+	sequenceNumber, err := uint64Word(4) // nolint:gomnd
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"number":          hexutil.EncodeUint64(number),
+		"timestamp":       hexutil.EncodeUint64(timestamp),
+		"base_fee":        hexutil.EncodeBig(new(big.Int).SetBytes(word(2))),
+		"hash":            common.BytesToHash(word(3)).Hex(), // nolint:gomnd
+		"sequence_number": hexutil.EncodeUint64(sequenceNumber),
+		"batcher_hash":    common.BytesToHash(word(5)).Hex(),                 // nolint:gomnd
+		"l1_fee_overhead": hexutil.EncodeBig(new(big.Int).SetBytes(word(6))), // nolint:gomnd
+		"l1_fee_scalar":   hexutil.EncodeBig(new(big.Int).SetBytes(word(7))), // nolint:gomnd
+	}, nil
+}
+
+// decodeL1AttributesEcotone decodes the packed arguments of
+// setL1BlockValuesEcotone: the base fee scalar and blob base fee
+// scalar (uint32), the sequence number, timestamp and number
+// (uint64), the base fee and blob base fee (uint256), and the hash
+// and batcher hash (bytes32).
+func decodeL1AttributesEcotone(data []byte) (map[string]interface{}, error) {
+	if len(data) != l1AttributesEcotoneLen {
+		return nil, fmt.Errorf(
+			"setL1BlockValuesEcotone calldata is %d bytes, expected %d",
+			len(data),
+			l1AttributesEcotoneLen,
+		)
+	}
+
+	r := bytes.NewReader(data[selectorSize:])
+	var (
+		baseFeeScalar, blobBaseFeeScalar uint32
+		sequenceNumber, timestamp, num   uint64
+		baseFee, blobBaseFee             [32]byte
+		hash, batcherHash                common.Hash
+	)
+	for _, field := range []interface{}{
+		&baseFeeScalar, &blobBaseFeeScalar,
+		&sequenceNumber, &timestamp, &num,
+		&baseFee, &blobBaseFee,
+		&hash, &batcherHash,
+	} {
+		if err := binary.Read(r, binary.BigEndian, field); err != nil {
+			return nil, fmt.Errorf("%w: cannot decode setL1BlockValuesEcotone calldata", err)
+		}
+	}
+
+	return map[string]interface{}{
+		"number":               hexutil.EncodeUint64(num),
+		"timestamp":            hexutil.EncodeUint64(timestamp),
+		"base_fee":             hexutil.EncodeBig(new(big.Int).SetBytes(baseFee[:])),
+		"blob_base_fee":        hexutil.EncodeBig(new(big.Int).SetBytes(blobBaseFee[:])),
+		"hash":                 hash.Hex(),
+		"sequence_number":      hexutil.EncodeUint64(sequenceNumber),
+		"batcher_hash":         batcherHash.Hex(),
+		"base_fee_scalar":      hexutil.EncodeUint64(uint64(baseFeeScalar)),
+		"blob_base_fee_scalar": hexutil.EncodeUint64(uint64(blobBaseFeeScalar)),
+	}, nil
+}
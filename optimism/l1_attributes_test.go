@@ -0,0 +1,142 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestL1Attributes(t *testing.T) {
+	legacyInput := "0x015d8eb9" +
+		"000000000000000000000000000000000000000000000000000000000109d8fe" +
+		"00000000000000000000000000000000000000000000000000000000647f5ea7" +
+		"00000000000000000000000000000000000000000000000000000003f2f3b1f2" +
+		"a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"0000000000000000000000006887246668a3b87f54deb3b94ba47a6f63f32985" +
+		"00000000000000000000000000000000000000000000000000000000000000bc" +
+		"00000000000000000000000000000000000000000000000000000000000a6fe0"
+	ecotoneInput := "0x440a5e20" +
+		"00000558" + "000c5fc5" +
+		"0000000000000001" + "0000000065f23e01" + "000000000012f063" +
+		"000000000000000000000000000000000000000000000000000000003b9aca00" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4" +
+		"0000000000000000000000006887246668a3b87f54deb3b94ba47a6f63f32985"
+
+	var tests = map[string]struct {
+		txType uint64
+		to     common.Address
+		input  string
+
+		expectedAttributes map[string]interface{}
+		expectedOk         bool
+		expectedErr        bool
+	}{
+		"legacy": {
+			txType:     depositTxType,
+			to:         l1BlockAddr,
+			input:      legacyInput,
+			expectedOk: true,
+			expectedAttributes: map[string]interface{}{
+				"number":          "0x109d8fe",
+				"timestamp":       "0x647f5ea7",
+				"base_fee":        "0x3f2f3b1f2",
+				"hash":            "0xa1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4",
+				"sequence_number": "0x4",
+				"batcher_hash":    "0x0000000000000000000000006887246668a3b87f54deb3b94ba47a6f63f32985",
+				"l1_fee_overhead": "0xbc",
+				"l1_fee_scalar":   "0xa6fe0",
+			},
+		},
+		"ecotone": {
+			txType:     depositTxType,
+			to:         l1BlockAddr,
+			input:      ecotoneInput,
+			expectedOk: true,
+			expectedAttributes: map[string]interface{}{
+				"number":               "0x12f063",
+				"timestamp":            "0x65f23e01",
+				"base_fee":             "0x3b9aca00",
+				"blob_base_fee":        "0x1",
+				"hash":                 "0xa1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4a1b2c3d4",
+				"sequence_number":      "0x1",
+				"batcher_hash":         "0x0000000000000000000000006887246668a3b87f54deb3b94ba47a6f63f32985",
+				"base_fee_scalar":      "0x558",
+				"blob_base_fee_scalar": "0xc5fc5",
+			},
+		},
+		"truncated legacy": {
+			txType:      depositTxType,
+			to:          l1BlockAddr,
+			input:       legacyInput[:len(legacyInput)-2],
+			expectedOk:  true,
+			expectedErr: true,
+		},
+		"truncated ecotone": {
+			txType:      depositTxType,
+			to:          l1BlockAddr,
+			input:       ecotoneInput[:len(ecotoneInput)-2],
+			expectedOk:  true,
+			expectedErr: true,
+		},
+		"unknown selector": {
+			txType: depositTxType,
+			to:     l1BlockAddr,
+			input:  "0xd764ad0b",
+		},
+		"other deposit": {
+			txType: depositTxType,
+			to:     common.HexToAddress("0x4200000000000000000000000000000000000007"),
+			input:  ecotoneInput,
+		},
+		"not a deposit": {
+			txType: dynamicFeeTxType,
+			to:     l1BlockAddr,
+			input:  ecotoneInput,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := &loadedTransaction{
+				Transaction: types.NewTransaction(
+					0,
+					test.to,
+					big.NewInt(0),
+					1000000,
+					big.NewInt(0),
+					hexutil.MustDecode(test.input),
+				),
+				Type: test.txType,
+			}
+
+			attributes, ok, err := l1Attributes(tx)
+			assert.Equal(t, test.expectedOk, ok)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedAttributes, attributes)
+		})
+	}
+}
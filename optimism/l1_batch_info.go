@@ -0,0 +1,141 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+)
+
+// maxL1BatchInfoRange is the largest number of blocks
+// L1BatchInfo fetches at once.
+const maxL1BatchInfoRange = 1000
+
+// L1BatchInfo is the L1 origin of an L2 block: the L1 block it was
+// derived from and its position in the canonical transaction chain
+// its batch was submitted to.
+type L1BatchInfo struct {
+	L2BlockNumber int64       `json:"l2_block_number"`
+	L2BlockHash   common.Hash `json:"l2_block_hash"`
+
+	L1BlockNumber uint64 `json:"l1_block_number"`
+	L1Timestamp   uint64 `json:"l1_timestamp"`
+
+	// CTCIndex is the index of the block's transaction in the
+	// canonical transaction chain, which orders the batches.
+	CTCIndex uint64 `json:"ctc_index"`
+
+	// QueueOrigin is "sequencer" for sequenced transactions and "l1"
+	// for enqueued ones, which also have a QueueIndex.
+	QueueOrigin string  `json:"queue_origin"`
+	QueueIndex  *uint64 `json:"queue_index,omitempty"`
+}
+
+type rangeBlock struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         common.Hash    `json:"hash"`
+	Transactions []*rangeTx     `json:"transactions"`
+}
+
+type rangeTx struct {
+	L1BlockNumber *hexutil.Uint64 `json:"l1BlockNumber"`
+	L1Timestamp   hexutil.Uint64  `json:"l1Timestamp"`
+	Index         *hexutil.Uint64 `json:"index"`
+	QueueOrigin   string          `json:"queueOrigin"`
+	QueueIndex    *hexutil.Uint64 `json:"queueIndex"`
+}
+
+// L1BatchInfo returns the L1 origin of the blocks from through to
+// (inclusive) with a single eth_getBlockRange call. Blocks without
+// transactions, such as genesis, have no L1 origin and are left out.
+// Only l2geth serves it; ErrMethodUnsupported is returned for other
+// nodes.
+func (ec *Client) L1BatchInfo(ctx context.Context, from int64, to int64) ([]*L1BatchInfo, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if from < 0 || to < from || to-from >= maxL1BatchInfoRange {
+		return nil, fmt.Errorf(
+			"%w: invalid block range [%d, %d], at most %d blocks are allowed",
+			ErrCallParametersInvalid,
+			from,
+			to,
+			maxL1BatchInfoRange,
+		)
+	}
+
+	d, err := ec.nodeDialect(ctx)
+	if err != nil {
+		return nil, rpcError(err, false)
+	}
+	if !d.blockRange() {
+		return nil, fmt.Errorf("%w: eth_getBlockRange is not served by %s nodes", ErrMethodUnsupported, d.name())
+	}
+
+	var blocks []*rangeBlock
+	err = ec.c.CallContext(
+		ctx,
+		&blocks,
+		"eth_getBlockRange",
+		hexutil.EncodeUint64(uint64(from)),
+		hexutil.EncodeUint64(uint64(to)),
+		true,
+	)
+	if err != nil {
+		if isMethodNotFound(err) {
+			return nil, fmt.Errorf("%w: %v", ErrMethodUnsupported, err)
+		}
+		return nil, rpcError(err, true)
+	}
+
+	infos := make([]*L1BatchInfo, 0, len(blocks))
+	for _, block := range blocks {
+		if len(block.Transactions) == 0 {
+			continue
+		}
+
+		// l2geth blocks hold a single transaction, so it
+		// carries the L1 origin of the whole block.
+		tx := block.Transactions[0]
+		if tx.L1BlockNumber == nil || tx.Index == nil {
+			return nil, fmt.Errorf(
+				"%w: block %d has no L1 origin",
+				ErrMethodUnsupported,
+				block.Number,
+			)
+		}
+
+		info := &L1BatchInfo{
+			L2BlockNumber: int64(block.Number),
+			L2BlockHash:   block.Hash,
+			L1BlockNumber: uint64(*tx.L1BlockNumber),
+			L1Timestamp:   uint64(tx.L1Timestamp),
+			CTCIndex:      uint64(*tx.Index),
+			QueueOrigin:   tx.QueueOrigin,
+		}
+		if tx.QueueIndex != nil {
+			queueIndex := uint64(*tx.QueueIndex)
+			info.QueueIndex = &queueIndex
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
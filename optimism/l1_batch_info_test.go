@@ -0,0 +1,157 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockBlockRange(ctx context.Context, t *testing.T, mockJSONRPC *mocks.JSONRPC, err error) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockRange",
+		"0x12f061",
+		"0x12f063",
+		true,
+	).Return(
+		err,
+	).Run(
+		func(args mock.Arguments) {
+			if err != nil {
+				return
+			}
+
+			file, err := ioutil.ReadFile("testdata/block_range.json")
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(file, args.Get(1)))
+		},
+	).Once()
+}
+
+func TestL1BatchInfo(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{
+		c:       mockJSONRPC,
+		dialect: newDialectResolver(LegacyDialect),
+	}
+	ctx := context.Background()
+	mockBlockRange(ctx, t, mockJSONRPC, nil)
+
+	infos, err := c.L1BatchInfo(ctx, 1241185, 1241187)
+	assert.NoError(t, err)
+
+	queueIndex := uint64(6699)
+	assert.Equal(t, []*L1BatchInfo{
+		{
+			L2BlockNumber: 1241185,
+			L2BlockHash:   common.HexToHash("0x4f0a3f1c2bd7c1b6e6ab6c44f3c3c3d97a3b9dbb2ad1c0b3b7e0d3e5c6b7a801"),
+			L1BlockNumber: 29998302,
+			L1Timestamp:   1645628932,
+			CTCIndex:      1241184,
+			QueueOrigin:   "sequencer",
+		},
+		{
+			L2BlockNumber: 1241186,
+			L2BlockHash:   common.HexToHash("0x0d35e7b4046195842623ec858648497936c906523ea0d477083d0457b7b8a6b2"),
+			L1BlockNumber: 29998303,
+			L1Timestamp:   1645628947,
+			CTCIndex:      1241185,
+			QueueOrigin:   "sequencer",
+		},
+		{
+			L2BlockNumber: 1241187,
+			L2BlockHash:   common.HexToHash("0x8c1b1e2f0d8a4d7b5b13f0c3c5e7a9f2d4b6a8c0e2f4a6b8d0c2e4f6a8b0c2d4"),
+			L1BlockNumber: 29998304,
+			L1Timestamp:   1645628962,
+			CTCIndex:      1241186,
+			QueueOrigin:   "l1",
+			QueueIndex:    &queueIndex,
+		},
+	}, infos)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestL1BatchInfo_MethodNotFound(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{
+		c:       mockJSONRPC,
+		dialect: newDialectResolver(LegacyDialect),
+	}
+	ctx := context.Background()
+	mockBlockRange(ctx, t, mockJSONRPC, &jsonError{
+		code:    methodNotFoundCode,
+		message: "the method eth_getBlockRange does not exist/is not available",
+	})
+
+	infos, err := c.L1BatchInfo(ctx, 1241185, 1241187)
+	assert.True(t, errors.Is(err, ErrMethodUnsupported))
+	assert.Nil(t, infos)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestL1BatchInfo_Bedrock(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{
+		c:       mockJSONRPC,
+		dialect: newDialectResolver(BedrockDialect),
+	}
+
+	infos, err := c.L1BatchInfo(context.Background(), 1241185, 1241187)
+	assert.True(t, errors.Is(err, ErrMethodUnsupported))
+	assert.Nil(t, infos)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestL1BatchInfo_InvalidRange(t *testing.T) {
+	tests := map[string]struct {
+		from int64
+		to   int64
+	}{
+		"negative": {from: -1, to: 2},
+		"reversed": {from: 3, to: 2},
+		"too wide": {from: 0, to: maxL1BatchInfoRange},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{
+				c:       mockJSONRPC,
+				dialect: newDialectResolver(LegacyDialect),
+			}
+
+			infos, err := c.L1BatchInfo(context.Background(), test.from, test.to)
+			assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+			assert.Nil(t, infos)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
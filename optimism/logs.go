@@ -0,0 +1,228 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/coinbase/rosetta-ethereum/optimism/utilities/artifacts"
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethCommon "github.com/ethereum/go-ethereum/common"
+)
+
+// ABIRegistry resolves the event definition used to decode a log
+// emitted by a contract.
+type ABIRegistry interface {
+	Event(contract common.Address, topic common.Hash) (*abi.Event, bool)
+}
+
+// eventRegistry is an ABIRegistry that matches events by
+// signature, regardless of the emitting contract.
+type eventRegistry struct {
+	events map[common.Hash]abi.Event
+}
+
+// NewABIRegistry returns an ABIRegistry that decodes all
+// events defined in the provided ABIs.
+func NewABIRegistry(abis ...abi.ABI) ABIRegistry {
+	events := map[common.Hash]abi.Event{}
+	for _, parsed := range abis {
+		for _, event := range parsed.Events {
+			events[common.Hash(event.ID)] = event
+		}
+	}
+
+	return &eventRegistry{events: events}
+}
+
+// Event implements ABIRegistry.
+func (r *eventRegistry) Event(contract common.Address, topic common.Hash) (*abi.Event, bool) {
+	event, ok := r.events[topic]
+	if !ok {
+		return nil, false
+	}
+
+	return &event, true
+}
+
+// defaultABIRegistry decodes standard ERC20 events.
+var defaultABIRegistry = NewABIRegistry(artifacts.ERC20ABI)
+
+// GetLogsInput is the input to the call
+// method "eth_getLogs".
+type GetLogsInput struct {
+	FromBlock *int64     `json:"from_block,omitempty"`
+	ToBlock   *int64     `json:"to_block,omitempty"`
+	BlockHash string     `json:"block_hash,omitempty"`
+	Addresses []string   `json:"addresses,omitempty"`
+	Topics    [][]string `json:"topics,omitempty"`
+	Decode    bool       `json:"decode"`
+}
+
+// getLogs returns the logs matching the filter in params, decoding
+// them with the client's ABIRegistry when requested.
+func (ec *Client) getLogs(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input GetLogsInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	filter := map[string]interface{}{}
+	if len(input.BlockHash) > 0 {
+		if input.FromBlock != nil || input.ToBlock != nil {
+			return nil, fmt.Errorf("%w: block_hash cannot be used with a block range", ErrCallParametersInvalid)
+		}
+		filter["blockHash"] = input.BlockHash
+	}
+	if input.FromBlock != nil {
+		filter["fromBlock"] = toBlockNumArg(big.NewInt(*input.FromBlock))
+	}
+	if input.ToBlock != nil {
+		filter["toBlock"] = toBlockNumArg(big.NewInt(*input.ToBlock))
+	}
+	if len(input.Addresses) > 0 {
+		for _, address := range input.Addresses {
+			if _, ok := ChecksumAddress(address); !ok {
+				return nil, fmt.Errorf("%w: invalid address %s", ErrCallParametersInvalid, address)
+			}
+		}
+		filter["address"] = input.Addresses
+	}
+	if len(input.Topics) > 0 {
+		filter["topics"] = input.Topics
+	}
+
+	var logs []*types.Log
+	if err := ec.c.CallContext(ctx, &logs, "eth_getLogs", filter); err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(logs))
+	for i, log := range logs {
+		result, err := ec.logResult(log, input.Decode)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+
+	return map[string]interface{}{
+		"logs": results,
+	}, nil
+}
+
+// logResult returns the log as a map, with the decoded event
+// name and arguments added when decode is set and the event
+// is known to the ABIRegistry.
+func (ec *Client) logResult(log *types.Log, decode bool) (map[string]interface{}, error) {
+	// We cannot use RosettaTypes.MarshalMap because geth uses a custom
+	// marshaler to convert *types.Log to JSON.
+	logBytes, err := log.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(logBytes, &result); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+	}
+
+	if !decode || len(log.Topics) == 0 {
+		return result, nil
+	}
+
+	registry := ec.abiRegistry
+	if registry == nil {
+		registry = defaultABIRegistry
+	}
+
+	event, ok := registry.Event(log.Address, log.Topics[0])
+	if !ok {
+		return result, nil
+	}
+
+	args, err := decodeEventArgs(event, log)
+	if err != nil {
+		// Logs that share a signature with a known event but a different
+		// layout (such as ERC721 Transfer) are left undecoded.
+		return result, nil
+	}
+
+	result["event"] = event.Name
+	result["args"] = args
+	return result, nil
+}
+
+// decodeEventArgs decodes the indexed and non-indexed
+// arguments of a log.
+func decodeEventArgs(event *abi.Event, log *types.Log) (map[string]interface{}, error) {
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if len(log.Topics)-1 != len(indexed) {
+		return nil, fmt.Errorf("expected %d indexed arguments but got %d", len(indexed), len(log.Topics)-1)
+	}
+
+	args := map[string]interface{}{}
+	if err := event.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+		return nil, err
+	}
+
+	topics := make([]gethCommon.Hash, len(indexed))
+	for i := range indexed {
+		topics[i] = gethCommon.Hash(log.Topics[i+1])
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, topics); err != nil {
+		return nil, err
+	}
+
+	for name, value := range args {
+		args[name] = formatEventArg(value)
+	}
+
+	return args, nil
+}
+
+// formatEventArg converts decoded ABI values into
+// their JSON-friendly string representations.
+func formatEventArg(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case gethCommon.Address:
+		return MustChecksum(v.Hex())
+	case gethCommon.Hash:
+		return v.Hex()
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case []byte:
+		return hexutil.Encode(v)
+	default:
+		return v
+	}
+}
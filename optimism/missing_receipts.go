@@ -0,0 +1,53 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"strings"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+)
+
+// ReceiptMissingKey is the transaction metadata key set when a
+// transaction was converted without its receipt.
+const ReceiptMissingKey = "receipt_missing"
+
+// MissingReceiptOverride lists a transaction whose receipt the node
+// is known to be unable to return. Such transactions are converted
+// from the transaction envelope alone instead of failing the block.
+type MissingReceiptOverride struct {
+	BlockHash string `json:"block_hash"`
+	TxHash    string `json:"tx_hash"`
+}
+
+func missingReceiptKey(blockHash string, txHash string) string {
+	return strings.ToLower(blockHash) + ":" + strings.ToLower(txHash)
+}
+
+// newMissingReceipts indexes overrides by block and transaction hash.
+func newMissingReceipts(overrides []MissingReceiptOverride) map[string]bool {
+	missingReceipts := map[string]bool{}
+	for _, override := range overrides {
+		missingReceipts[missingReceiptKey(override.BlockHash, override.TxHash)] = true
+	}
+
+	return missingReceipts
+}
+
+// receiptMissing returns true if the receipt of txHash in blockHash
+// is listed as permanently missing.
+func (ec *Client) receiptMissing(blockHash common.Hash, txHash common.Hash) bool {
+	return ec.missingReceipts[missingReceiptKey(blockHash.Hex(), txHash.Hex())]
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"log"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// WithCurrencyByChainID sets the native currency of operations and
+// balances to the currency of the chain id of the node in currencies,
+// so one binary can serve chains with different native currencies.
+// The chain id is the one detected from eth_chainId when the client
+// was created. Currency is kept if the chain id is not in currencies.
+func (ec *Client) WithCurrencyByChainID(currencies map[uint64]*RosettaTypes.Currency) *Client {
+	if ec.p == nil || ec.p.ChainID == nil {
+		return ec
+	}
+
+	chainID := ec.p.ChainID.Uint64()
+	currency, ok := currencies[chainID]
+	if !ok || currency == nil {
+		return ec
+	}
+
+	log.Printf("using native currency %s for chain id %d", currency.Symbol, chainID)
+	ec.currency = currency
+	return ec
+}
+
+// nativeCurrency returns the native currency of the chain.
+func (ec *Client) nativeCurrency() *RosettaTypes.Currency {
+	if ec.currency != nil {
+		return ec.currency
+	}
+
+	return Currency
+}
+
+// setNativeCurrency replaces Currency in ops with the native
+// currency of the chain, if it was overridden.
+func (ec *Client) setNativeCurrency(ops []*RosettaTypes.Operation) {
+	if ec.currency == nil {
+		return
+	}
+
+	for _, op := range ops {
+		if op.Amount != nil && op.Amount.Currency == Currency {
+			op.Amount.Currency = ec.currency
+		}
+	}
+}
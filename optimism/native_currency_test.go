@@ -0,0 +1,110 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCurrencyByChainID(t *testing.T) {
+	devETH := &RosettaTypes.Currency{Symbol: "devETH", Decimals: 18}
+	currencies := map[uint64]*RosettaTypes.Currency{
+		10: Currency,
+		17: devETH,
+	}
+
+	tests := map[string]struct {
+		chainID uint64
+
+		expected *RosettaTypes.Currency
+	}{
+		"op mainnet": {
+			chainID:  10,
+			expected: Currency,
+		},
+		"devnet": {
+			chainID:  17,
+			expected: devETH,
+		},
+		"unmapped chain": {
+			chainID:  69,
+			expected: Currency,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			ctx := context.Background()
+
+			mockChainID(ctx, mockJSONRPC, test.chainID, nil)
+			p, err := resolveChainConfig(ctx, mockJSONRPC, nil, false)
+			assert.NoError(t, err)
+
+			c := (&Client{c: mockJSONRPC, p: p}).WithCurrencyByChainID(currencies)
+			assert.Equal(t, test.expected, c.nativeCurrency())
+
+			ops := []*RosettaTypes.Operation{
+				{
+					Type:   FeeOpType,
+					Amount: &RosettaTypes.Amount{Value: "-1", Currency: Currency},
+				},
+				{
+					Type:   PaymentOpType,
+					Amount: &RosettaTypes.Amount{Value: "1", Currency: OPTokenCurrency},
+				},
+				{
+					Type: CallOpType,
+				},
+			}
+			c.setNativeCurrency(ops)
+			assert.Equal(t, test.expected, ops[0].Amount.Currency)
+			assert.Equal(t, OPTokenCurrency, ops[1].Amount.Currency)
+			assert.Nil(t, ops[2].Amount)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewBalanceQuery_NativeCurrency(t *testing.T) {
+	devETH := &RosettaTypes.Currency{Symbol: "devETH", Decimals: 18}
+	account := &RosettaTypes.AccountIdentifier{
+		Address: common.BigToAddress(big.NewInt(6)).Hex(),
+	}
+
+	// The native currency is served from the native balance
+	query, err := newBalanceQuery(account, []*RosettaTypes.Currency{devETH}, devETH)
+	assert.NoError(t, err)
+	assert.Equal(t, []*RosettaTypes.Currency{devETH}, query.currencies)
+	assert.Equal(t, []string{""}, query.contractAddresses)
+
+	// and used by default
+	query, err = newBalanceQuery(account, nil, devETH)
+	assert.NoError(t, err)
+	assert.Equal(t, []*RosettaTypes.Currency{devETH, OPTokenCurrency}, query.currencies)
+
+	// Currency is not native on the chain
+	_, err = newBalanceQuery(account, []*RosettaTypes.Currency{Currency}, devETH)
+	assert.ErrorIs(t, err, ErrInvalidTokenContractAddress)
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// chainNetworks maps the chain ids of the networks served by
+// rosetta-ethereum to their network names.
+var chainNetworks = map[uint64]string{
+	10:  MainnetNetwork,
+	420: TestnetNetwork,
+	5:   GoerliNetwork,
+}
+
+// NetworkForChainID returns the network name of the chain with
+// chainID. Chains without one are named by NetworkName.
+func NetworkForChainID(chainID uint64) string {
+	if network, ok := chainNetworks[chainID]; ok {
+		return network
+	}
+
+	return NetworkName(chainID)
+}
+
+// NetworkList returns the network identifier of the chain the node is
+// on. Nodes that report their rollup mode (sequencer or verifier) with
+// rollup_getInfo are further identified by a sub-network named after it.
+func (ec *Client) NetworkList(ctx context.Context) ([]*RosettaTypes.NetworkIdentifier, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	info, err := ec.rollupInfo(ctx)
+	if err != nil {
+		return nil, rpcError(err, false)
+	}
+
+	network := &RosettaTypes.NetworkIdentifier{
+		Blockchain: Blockchain,
+		Network:    NetworkForChainID(ec.p.ChainID.Uint64()),
+	}
+	if info != nil && len(info.Mode) > 0 {
+		network.SubNetworkIdentifier = &RosettaTypes.SubNetworkIdentifier{
+			Network: info.Mode,
+		}
+	}
+
+	return []*RosettaTypes.NetworkIdentifier{network}, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkList(t *testing.T) {
+	tests := map[string]struct {
+		params  *params.ChainConfig
+		dialect Dialect
+
+		expected *RosettaTypes.NetworkIdentifier
+	}{
+		"mainnet verifier": {
+			params:  knownChainConfigs[10],
+			dialect: LegacyDialect,
+			expected: &RosettaTypes.NetworkIdentifier{
+				Blockchain: Blockchain,
+				Network:    MainnetNetwork,
+				SubNetworkIdentifier: &RosettaTypes.SubNetworkIdentifier{
+					Network: "verifier",
+				},
+			},
+		},
+		"goerli verifier": {
+			params:  knownChainConfigs[420],
+			dialect: LegacyDialect,
+			expected: &RosettaTypes.NetworkIdentifier{
+				Blockchain: Blockchain,
+				Network:    TestnetNetwork,
+				SubNetworkIdentifier: &RosettaTypes.SubNetworkIdentifier{
+					Network: "verifier",
+				},
+			},
+		},
+		"mainnet bedrock": {
+			params:  knownChainConfigs[10],
+			dialect: BedrockDialect,
+			expected: &RosettaTypes.NetworkIdentifier{
+				Blockchain: Blockchain,
+				Network:    MainnetNetwork,
+			},
+		},
+		"devnet bedrock": {
+			params:  knownChainConfigs[901],
+			dialect: BedrockDialect,
+			expected: &RosettaTypes.NetworkIdentifier{
+				Blockchain: Blockchain,
+				Network:    "901",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{
+				c:       mockJSONRPC,
+				p:       test.params,
+				dialect: newDialectResolver(test.dialect),
+			}
+			ctx := context.Background()
+			if test.dialect == LegacyDialect {
+				mockRollupInfo(ctx, t, mockJSONRPC, nil)
+			}
+
+			networks, err := c.NetworkList(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, []*RosettaTypes.NetworkIdentifier{test.expected}, networks)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNetworkList_NodeError(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{
+		c: mockJSONRPC,
+		p: knownChainConfigs[10],
+	}
+	ctx := context.Background()
+	mockRollupInfo(ctx, t, mockJSONRPC, errors.New("connection refused"))
+
+	networks, err := c.NetworkList(ctx)
+	assert.True(t, errors.Is(err, ErrNodeUnavailable))
+	assert.Nil(t, networks)
+
+	mockJSONRPC.AssertExpectations(t)
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// NetworkStatus returns the /network/status response of the node: its
+// current block and sync status from Status, the (cached) genesis
+// block, and the oldest block it serves if it prunes history.
+func (ec *Client) NetworkStatus(ctx context.Context) (*RosettaTypes.NetworkStatusResponse, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	currentBlock, currentTime, syncStatus, peers, err := ec.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	genesisBlock, err := ec.GenesisBlockIdentifier(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only reported when the node prunes history
+	oldestBlock, err := ec.OldestBlockIdentifier(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if oldestBlock != nil && oldestBlock.Index == genesisBlock.Index {
+		oldestBlock = nil
+	}
+
+	return &RosettaTypes.NetworkStatusResponse{
+		CurrentBlockIdentifier: currentBlock,
+		CurrentBlockTimestamp:  currentTime,
+		GenesisBlockIdentifier: genesisBlock,
+		OldestBlockIdentifier:  oldestBlock,
+		SyncStatus:             syncStatus,
+		Peers:                  peers,
+	}, nil
+}
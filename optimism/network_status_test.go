@@ -0,0 +1,164 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockStatus mocks the requests made by Status for a synced node.
+func mockStatus(ctx context.Context, t *testing.T, mockJSONRPC *mocks.JSONRPC) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"latest",
+		false,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			file, err := ioutil.ReadFile("testdata/basic_header.json")
+			assert.NoError(t, err)
+
+			header := new(types.Header)
+			assert.NoError(t, header.UnmarshalJSON(file))
+			*args.Get(1).(**types.Header) = header
+		},
+	).Once()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_syncing",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			file, err := ioutil.ReadFile("testdata/syncing_false.json")
+			assert.NoError(t, err)
+
+			*args.Get(1).(*json.RawMessage) = json.RawMessage(file)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"admin_peers",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			file, err := ioutil.ReadFile("testdata/admin_peers.json")
+			assert.NoError(t, err)
+
+			assert.NoError(t, json.Unmarshal(file, args.Get(1)))
+		},
+	).Once()
+}
+
+func TestNetworkStatus(t *testing.T) {
+	tests := map[string]struct {
+		oldestBlock *RosettaTypes.BlockIdentifier
+
+		expectedOldestBlock *RosettaTypes.BlockIdentifier
+	}{
+		"probe disabled": {},
+		"archive node": {
+			oldestBlock: blockAt(0),
+		},
+		"pruned node": {
+			oldestBlock:         blockAt(1000000),
+			expectedOldestBlock: blockAt(1000000),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC, genesis: &genesisCache{}}
+			if test.oldestBlock != nil {
+				c.oldestBlock = &oldestBlockProbe{
+					maxCalls:   minOldestBlockProbeCalls,
+					identifier: test.oldestBlock,
+					expires:    time.Now().Add(time.Hour),
+				}
+			}
+			ctx := context.Background()
+
+			// The genesis block is only fetched once.
+			mockGenesisHeader(ctx, mockJSONRPC).Once()
+			for i := 0; i < 2; i++ {
+				mockStatus(ctx, t, mockJSONRPC)
+
+				status, err := c.NetworkStatus(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, &RosettaTypes.BlockIdentifier{
+					Hash:  "0x48269a339ce1489cff6bab70eff432289c4f490b81dbd00ff1f81c68de06b842",
+					Index: 8916656,
+				}, status.CurrentBlockIdentifier)
+				assert.Equal(t, int64(1603225195000), status.CurrentBlockTimestamp)
+				assert.Equal(t, blockAt(0), status.GenesisBlockIdentifier)
+				assert.Equal(t, test.expectedOldestBlock, status.OldestBlockIdentifier)
+				assert.Equal(t, &RosettaTypes.SyncStatus{
+					CurrentIndex: RosettaTypes.Int64(8916656),
+					TargetIndex:  RosettaTypes.Int64(8916656),
+					Synced:       RosettaTypes.Bool(true),
+				}, status.SyncStatus)
+				assert.Len(t, status.Peers, 2)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNetworkStatus_GenesisError(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, genesis: &genesisCache{}}
+	ctx := context.Background()
+
+	mockStatus(ctx, t, mockJSONRPC)
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x0",
+		false,
+	).Return(
+		errors.New("connection refused"),
+	).Once()
+
+	status, err := c.NetworkStatus(ctx)
+	assert.Nil(t, status)
+	assert.True(t, errors.Is(err, ErrNodeUnavailable))
+
+	mockJSONRPC.AssertExpectations(t)
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+)
+
+// oldestBlockTTL is how long the oldest block found by the probe
+// is reported before the node is probed again. Pruning nodes move
+// it forward as they go.
+const oldestBlockTTL = 10 * time.Minute
+
+// minOldestBlockProbeCalls is the fewest requests a probe can make:
+// the head, one state lookup and the header of the block found.
+const minOldestBlockProbeCalls = 3
+
+// oldestBlockProbe finds the oldest block whose state the node
+// still serves, caching it for oldestBlockTTL.
+type oldestBlockProbe struct {
+	maxCalls int
+
+	mu         sync.Mutex
+	identifier *RosettaTypes.BlockIdentifier
+	expires    time.Time
+}
+
+// WithOldestBlockProbe makes OldestBlockIdentifier binary-search for
+// the oldest block whose state the node can serve, for nodes that
+// prune history. Each probe makes at most maxCalls requests; if the
+// search has not converged by then, the oldest block known to be
+// served is reported. A maxCalls of 0 disables the probe.
+func (ec *Client) WithOldestBlockProbe(maxCalls int) *Client {
+	if maxCalls <= 0 {
+		return ec
+	}
+	if maxCalls < minOldestBlockProbeCalls {
+		maxCalls = minOldestBlockProbeCalls
+	}
+
+	log.Printf("probing for the oldest block with at most %d calls", maxCalls)
+	ec.oldestBlock = &oldestBlockProbe{maxCalls: maxCalls}
+	return ec
+}
+
+// OldestBlockIdentifier returns the oldest block whose state the node
+// serves, or nil if the probe is not enabled with WithOldestBlockProbe.
+func (ec *Client) OldestBlockIdentifier(ctx context.Context) (*RosettaTypes.BlockIdentifier, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	probe := ec.oldestBlock
+	if probe == nil {
+		return nil, nil
+	}
+
+	probe.mu.Lock()
+	defer probe.mu.Unlock()
+	if probe.identifier != nil && time.Now().Before(probe.expires) {
+		return probe.identifier, nil
+	}
+
+	identifier, err := ec.probeOldestBlock(ctx, probe.maxCalls)
+	if err != nil {
+		return nil, rpcError(err, true)
+	}
+	probe.identifier = identifier
+	probe.expires = time.Now().Add(oldestBlockTTL)
+
+	return identifier, nil
+}
+
+// probeOldestBlock binary-searches between genesis and the head for
+// the oldest block with available state, making at most maxCalls
+// requests. The head is assumed to be available.
+func (ec *Client) probeOldestBlock(ctx context.Context, maxCalls int) (*RosettaTypes.BlockIdentifier, error) {
+	head, err := ec.blockHeader(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	calls := 1
+
+	// Blocks below low are unavailable and high is available. The last
+	// call is kept to fetch the header of the block found.
+	low, high := int64(GenesisBlockIndex), head.Number.Int64()
+	for low < high && calls < maxCalls-1 {
+		mid := low + (high-low)/2
+		available, err := ec.stateAvailable(ctx, mid)
+		if err != nil {
+			return nil, err
+		}
+		calls++
+
+		if available {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	if low < high {
+		log.Printf("oldest block probe stopped after %d calls, somewhere in [%d, %d]", calls, low, high)
+	}
+
+	header, err := ec.blockHeader(ctx, big.NewInt(high))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RosettaTypes.BlockIdentifier{
+		Hash:  header.Hash().Hex(),
+		Index: header.Number.Int64(),
+	}, nil
+}
+
+// stateAvailable returns true if the node serves the state at index,
+// by reading the balance of the zero address.
+func (ec *Client) stateAvailable(ctx context.Context, index int64) (bool, error) {
+	var balance hexutil.Big
+	err := ec.c.CallContext(ctx, &balance, "eth_getBalance", common.Address{}, hexutil.EncodeUint64(uint64(index)))
+	if errors.Is(historicalStateError(err), ErrHistoricalStateUnavailable) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
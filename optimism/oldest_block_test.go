@@ -0,0 +1,214 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// prunedNode mocks a node at head that serves the state of
+// blocks from oldest on, counting the requests made to it.
+type prunedNode struct {
+	head   int64
+	oldest int64
+	err    error
+
+	calls int
+}
+
+func (n *prunedNode) mock(ctx context.Context, mockJSONRPC *mocks.JSONRPC) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		mock.Anything,
+		false,
+	).Return(
+		func(_ context.Context, result interface{}, _ string, args ...interface{}) error {
+			n.calls++
+
+			number := n.head
+			if args[0] != "latest" {
+				number = int64(hexutil.MustDecodeUint64(args[0].(string)))
+			}
+			*result.(**types.Header) = &types.Header{Number: big.NewInt(number)}
+			return nil
+		},
+	)
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBalance",
+		common.Address{},
+		mock.Anything,
+	).Return(
+		func(_ context.Context, result interface{}, _ string, args ...interface{}) error {
+			n.calls++
+
+			if n.err != nil {
+				return n.err
+			}
+			number := int64(hexutil.MustDecodeUint64(args[1].(string)))
+			if number < n.oldest {
+				return &jsonError{code: -32000, message: "missing trie node 8cd64ff4bb74ab9bd9ec0d3f4c3e9ea4f1f6d3cc2d0fa8e3e6b2bd0f4bcbd93c (path )"}
+			}
+			*result.(*hexutil.Big) = hexutil.Big(*big.NewInt(0))
+			return nil
+		},
+	)
+}
+
+func blockAt(index int64) *RosettaTypes.BlockIdentifier {
+	return &RosettaTypes.BlockIdentifier{
+		Hash:  (&types.Header{Number: big.NewInt(index)}).Hash().Hex(),
+		Index: index,
+	}
+}
+
+func TestOldestBlockIdentifier(t *testing.T) {
+	tests := map[string]struct {
+		node     *prunedNode
+		maxCalls int
+
+		expected    *RosettaTypes.BlockIdentifier
+		expectedErr bool
+	}{
+		"pruned below 1,000,000": {
+			node:     &prunedNode{head: 1200000, oldest: 1000000},
+			maxCalls: 64,
+			expected: blockAt(1000000),
+		},
+		"archive node": {
+			node:     &prunedNode{head: 1200000},
+			maxCalls: 64,
+			expected: blockAt(0),
+		},
+		"only head available": {
+			node:     &prunedNode{head: 1200000, oldest: 1200000},
+			maxCalls: 64,
+			expected: blockAt(1200000),
+		},
+		"out of calls": {
+			// The search narrows [0, 1200000] to [975001, 1050000]
+			// in 4 lookups, and reports the upper bound.
+			node:     &prunedNode{head: 1200000, oldest: 1000000},
+			maxCalls: 6,
+			expected: blockAt(1050000),
+		},
+		"node error": {
+			node:        &prunedNode{head: 1200000, err: errors.New("connection refused")},
+			maxCalls:    64,
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := (&Client{c: mockJSONRPC}).WithOldestBlockProbe(test.maxCalls)
+			ctx := context.Background()
+			test.node.mock(ctx, mockJSONRPC)
+
+			oldest, err := c.OldestBlockIdentifier(ctx)
+			if test.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, oldest)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, oldest)
+			assert.LessOrEqual(t, test.node.calls, test.maxCalls)
+		})
+	}
+}
+
+func TestOldestBlockIdentifier_Cached(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithOldestBlockProbe(64)
+	ctx := context.Background()
+	node := &prunedNode{head: 1200000, oldest: 1000000}
+	node.mock(ctx, mockJSONRPC)
+
+	oldest, err := c.OldestBlockIdentifier(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, blockAt(1000000), oldest)
+	calls := node.calls
+
+	// Cached until the TTL expires
+	oldest, err = c.OldestBlockIdentifier(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, blockAt(1000000), oldest)
+	assert.Equal(t, calls, node.calls)
+
+	// The node kept pruning in the meantime
+	node.oldest = 1100000
+	c.oldestBlock.expires = time.Now().Add(-time.Second)
+	oldest, err = c.OldestBlockIdentifier(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, blockAt(1100000), oldest)
+	assert.Greater(t, node.calls, calls)
+}
+
+func TestOldestBlockIdentifier_Disabled(t *testing.T) {
+	tests := map[string]int{
+		"not set": -1,
+		"zero":    0,
+	}
+
+	for name, maxCalls := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+			if maxCalls >= 0 {
+				c = c.WithOldestBlockProbe(maxCalls)
+			}
+
+			oldest, err := c.OldestBlockIdentifier(context.Background())
+			assert.NoError(t, err)
+			assert.Nil(t, oldest)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWithOldestBlockProbe_MinCalls(t *testing.T) {
+	c := (&Client{}).WithOldestBlockProbe(1)
+	assert.Equal(t, minOldestBlockProbeCalls, c.oldestBlock.maxCalls)
+}
+
+func TestOldestBlockIdentifier_Closed(t *testing.T) {
+	c := (&Client{closed: 1}).WithOldestBlockProbe(64)
+
+	oldest, err := c.OldestBlockIdentifier(context.Background())
+	assert.Nil(t, oldest)
+	assert.True(t, errors.Is(err, ErrClientClosed))
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"encoding/binary"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// OperationIDKey is the operation metadata key holding
+	// the operation's deterministic identifier.
+	OperationIDKey = "operation_id"
+
+	// OperationIDScheme describes how OperationID is computed.
+	// It is reported in /network/options.
+	OperationIDScheme = "keccak256(block_hash || transaction_hash || uint64_be(operation_index))"
+)
+
+// OperationID returns an identifier for an operation that is unique
+// across the network and stable across re-fetches of the block.
+func OperationID(blockHash common.Hash, txHash common.Hash, index int64) string {
+	var indexBytes [8]byte
+	binary.BigEndian.PutUint64(indexBytes[:], uint64(index))
+
+	return crypto.Keccak256Hash(blockHash.Bytes(), txHash.Bytes(), indexBytes[:]).Hex()
+}
+
+// setOperationIDs adds OperationIDKey to the metadata of
+// every operation in tx.
+func setOperationIDs(blockHash common.Hash, tx *RosettaTypes.Transaction) {
+	txHash := common.HexToHash(tx.TransactionIdentifier.Hash)
+	for _, op := range tx.Operations {
+		// Operations derived from the same call frame share
+		// their metadata map, so each gets its own copy.
+		metadata := make(map[string]interface{}, len(op.Metadata)+1)
+		for k, v := range op.Metadata {
+			metadata[k] = v
+		}
+		metadata[OperationIDKey] = OperationID(blockHash, txHash, op.OperationIdentifier.Index)
+		op.Metadata = metadata
+	}
+}
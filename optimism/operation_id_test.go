@@ -0,0 +1,87 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationID(t *testing.T) {
+	blockHash := common.HexToHash("0xb358c6958b1cab722752939cbb92e3fec6b6023de360305910ce80c56c3dad9d")
+	txHash := common.HexToHash("0x5c32a8b02b84a9e8ae4de14fd6d1ae1d4b0a6f3c1c0b6cbbd4e3f1a60f7fd5cd")
+
+	assert.Equal(t, OperationID(blockHash, txHash, 0), OperationID(blockHash, txHash, 0))
+	assert.NotEqual(t, OperationID(blockHash, txHash, 0), OperationID(blockHash, txHash, 1))
+	assert.NotEqual(t, OperationID(blockHash, txHash, 0), OperationID(txHash, blockHash, 0))
+	assert.Len(t, OperationID(blockHash, txHash, 0), 66)
+}
+
+func TestOperationIDs_Fixtures(t *testing.T) {
+	files, err := filepath.Glob("testdata/block_response_*.json")
+	assert.NoError(t, err)
+
+	seen := map[string]string{}
+	for _, file := range files {
+		contents, err := ioutil.ReadFile(file)
+		assert.NoError(t, err)
+
+		var resp *RosettaTypes.BlockResponse
+		if err := json.Unmarshal(contents, &resp); err != nil || resp.Block == nil {
+			continue
+		}
+
+		blockHash := common.HexToHash(resp.Block.BlockIdentifier.Hash)
+		for _, tx := range resp.Block.Transactions {
+			for _, op := range tx.Operations {
+				id, ok := op.Metadata[OperationIDKey].(string)
+				if !ok {
+					// Fixtures not used by block tests predate operation ids
+					continue
+				}
+
+				previous, ok := seen[id]
+				assert.False(t, ok, "%s in %s already seen in %s", id, file, previous)
+				seen[id] = file
+			}
+
+			// Converting the same transaction again yields the same ids
+			converted := &RosettaTypes.Transaction{
+				TransactionIdentifier: tx.TransactionIdentifier,
+				Operations:            make([]*RosettaTypes.Operation, len(tx.Operations)),
+			}
+			for i, op := range tx.Operations {
+				converted.Operations[i] = &RosettaTypes.Operation{
+					OperationIdentifier: op.OperationIdentifier,
+				}
+			}
+			setOperationIDs(blockHash, converted)
+			setOperationIDs(blockHash, converted)
+			for i, op := range converted.Operations {
+				if expected, ok := tx.Operations[i].Metadata[OperationIDKey]; ok {
+					assert.Equal(t, expected, op.Metadata[OperationIDKey])
+				}
+			}
+		}
+	}
+
+	assert.NotEmpty(t, seen)
+}
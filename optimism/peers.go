@@ -0,0 +1,61 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+
+	"github.com/ethereum-optimism/optimism/l2geth/p2p"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// peers returns the peers of the node from admin_peers, or nil if
+// admin calls are skipped. Nodes that don't expose the admin API,
+// such as hosted providers, are not asked again.
+func (ec *Client) peers(ctx context.Context) ([]*RosettaTypes.Peer, error) {
+	if atomic.LoadUint32(&ec.skipAdminCalls) == 1 {
+		return nil, nil
+	}
+
+	var info []*p2p.PeerInfo
+	if err := ec.c.CallContext(ctx, &info, "admin_peers"); err != nil {
+		if isMethodNotFound(err) {
+			log.Printf("admin_peers is not available, not reporting peers: %v", err)
+			atomic.StoreUint32(&ec.skipAdminCalls, 1)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	peers := make([]*RosettaTypes.Peer, len(info))
+	for i, peerInfo := range info {
+		peers[i] = &RosettaTypes.Peer{
+			PeerID: peerInfo.ID,
+			Metadata: map[string]interface{}{
+				"name":           peerInfo.Name,
+				"enode":          peerInfo.Enode,
+				"caps":           peerInfo.Caps,
+				"remote_address": peerInfo.Network.RemoteAddress,
+				"inbound":        peerInfo.Network.Inbound,
+				"protocols":      peerInfo.Protocols,
+			},
+		}
+	}
+
+	return peers, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPeers_MethodNotFound(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"admin_peers",
+	).Return(
+		&jsonError{code: -32601, message: "The method admin_peers does not exist/is not available"},
+	).Once()
+
+	// The node is only asked once
+	for i := 0; i < 2; i++ {
+		peers, err := c.peers(ctx)
+		assert.NoError(t, err)
+		assert.Nil(t, peers)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestPeers_Skipped(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, skipAdminCalls: 1}
+
+	peers, err := c.peers(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, peers)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestPeers_Error(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	nodeErr := errors.New("connection reset")
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"admin_peers",
+	).Return(
+		nodeErr,
+	).Once()
+
+	peers, err := c.peers(ctx)
+	assert.True(t, errors.Is(err, nodeErr))
+	assert.Nil(t, peers)
+	assert.Equal(t, uint32(0), c.skipAdminCalls)
+
+	mockJSONRPC.AssertExpectations(t)
+}
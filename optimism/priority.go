@@ -0,0 +1,50 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import "context"
+
+type lowPriorityKey struct{}
+
+// WithLowPriority marks requests made with the returned context as
+// speculative work, such as prefetching blocks. They only trace when
+// the trace semaphore has spare capacity and nobody is waiting for
+// it, and fail with ErrTraceCapacityBusy otherwise, so they never
+// delay interactive requests.
+func WithLowPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lowPriorityKey{}, true)
+}
+
+// IsLowPriority reports whether ctx was marked with WithLowPriority.
+func IsLowPriority(ctx context.Context) bool {
+	lowPriority, _ := ctx.Value(lowPriorityKey{}).(bool)
+	return lowPriority
+}
+
+// acquireTrace acquires the trace semaphore, waiting for it unless
+// ctx is low priority. semaphore.Weighted serves waiters in order and
+// TryAcquire fails while any are queued, so low priority requests
+// never take capacity an interactive request is waiting for.
+func (ec *Client) acquireTrace(ctx context.Context) error {
+	if !IsLowPriority(ctx) {
+		return ec.traceSemaphore.Acquire(ctx, semaphoreTraceWeight)
+	}
+
+	if !ec.traceSemaphore.TryAcquire(semaphoreTraceWeight) {
+		return ErrTraceCapacityBusy
+	}
+
+	return nil
+}
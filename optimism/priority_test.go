@@ -0,0 +1,51 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestAcquireTrace_LowPriority(t *testing.T) {
+	c := &Client{traceSemaphore: semaphore.NewWeighted(1)}
+	ctx := context.Background()
+	lowCtx := WithLowPriority(ctx)
+
+	// Spare capacity is used by low priority requests
+	assert.NoError(t, c.acquireTrace(lowCtx))
+
+	// Interactive requests wait for it, while low
+	// priority requests give up immediately
+	err := c.acquireTrace(lowCtx)
+	assert.True(t, errors.Is(err, ErrTraceCapacityBusy))
+
+	acquired := make(chan error)
+	go func() {
+		acquired <- c.acquireTrace(ctx)
+	}()
+	c.traceSemaphore.Release(semaphoreTraceWeight)
+	assert.NoError(t, <-acquired)
+}
+
+func TestIsLowPriority(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, IsLowPriority(ctx))
+	assert.True(t, IsLowPriority(WithLowPriority(ctx)))
+}
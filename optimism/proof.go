@@ -0,0 +1,199 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+const (
+	// includeProofKey is the account identifier metadata key used to
+	// request the Merkle proof of the account in a balance response.
+	includeProofKey = "include_proof"
+
+	// ProofUnavailableKey is the balance metadata key set when a proof
+	// was requested but the node does not implement eth_getProof.
+	ProofUnavailableKey = "proof_unavailable"
+
+	// GetProofMethod is the call method that returns the Merkle proof
+	// of an account, of some of its storage slots, or of both.
+	GetProofMethod = "eth_getProof"
+)
+
+// GetProofInput is the input to GetProofMethod. At least one of
+// IncludeAccount and IncludeStorage must be set, and IncludeStorage
+// requires StorageKeys. The proof is of the state at Index, or at
+// the current block if it is unset.
+type GetProofInput struct {
+	Address        string   `json:"address"`
+	StorageKeys    []string `json:"storage_keys,omitempty"`
+	IncludeAccount bool     `json:"include_account"`
+	IncludeStorage bool     `json:"include_storage"`
+	Index          *int64   `json:"index,omitempty"`
+}
+
+// accountProof is the result of eth_getProof.
+type accountProof struct {
+	Address      common.Address `json:"address"`
+	AccountProof []string       `json:"accountProof"`
+	Balance      *hexutil.Big   `json:"balance"`
+	CodeHash     common.Hash    `json:"codeHash"`
+	Nonce        hexutil.Uint64 `json:"nonce"`
+	StorageHash  common.Hash    `json:"storageHash"`
+	StorageProof []storageProof `json:"storageProof"`
+}
+
+// storageProof is the proof of a storage slot in eth_getProof.
+type storageProof struct {
+	Key   string       `json:"key"`
+	Value *hexutil.Big `json:"value"`
+	Proof []string     `json:"proof"`
+}
+
+// includeProof returns true if the account identifier requests
+// the Merkle proof of the account.
+func includeProof(account *RosettaTypes.AccountIdentifier) bool {
+	include, _ := account.Metadata[includeProofKey].(bool)
+	return include
+}
+
+// accountProofRequest returns the batch request for the proof of
+// address at blockNum. No storage slots are proven.
+func accountProofRequest(address string, blockNum string, result **accountProof) rpc.BatchElem {
+	return rpc.BatchElem{
+		Method: "eth_getProof",
+		Args:   []interface{}{address, []string{}, blockNum},
+		Result: result,
+	}
+}
+
+// addProofMetadata adds the account proof, its storage hash and the
+// state root of head to metadata, or ProofUnavailableKey if there
+// is no proof.
+func addProofMetadata(metadata map[string]interface{}, head *types.Header, proof *accountProof) {
+	if proof == nil {
+		metadata[ProofUnavailableKey] = true
+		return
+	}
+
+	metadata["account_proof"] = proof.AccountProof
+	metadata["storage_hash"] = proof.StorageHash.Hex()
+	metadata["state_root"] = head.Root.Hex()
+}
+
+// parseStorageKey parses a hex storage slot, which may
+// be shorter than 32 bytes, such as 0x0.
+func parseStorageKey(key string) (common.Hash, error) {
+	if !strings.HasPrefix(key, "0x") {
+		return common.Hash{}, fmt.Errorf("storage key %s is not hex", key)
+	}
+
+	slot, ok := new(big.Int).SetString(key[2:], 16)
+	if !ok || slot.Sign() < 0 || slot.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("invalid storage key %s", key)
+	}
+
+	return common.BigToHash(slot), nil
+}
+
+// getProof returns the parts of the eth_getProof result
+// requested by params.
+func (ec *Client) getProof(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input GetProofInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	if !input.IncludeAccount && !input.IncludeStorage {
+		return nil, fmt.Errorf("%w: include_account or include_storage is required", ErrCallParametersInvalid)
+	}
+	if input.IncludeStorage && len(input.StorageKeys) == 0 {
+		return nil, fmt.Errorf("%w: storage_keys are required to include storage", ErrCallParametersInvalid)
+	}
+	if input.Index != nil && *input.Index < 0 {
+		return nil, fmt.Errorf("%w: index %d is negative", ErrCallParametersInvalid, *input.Index)
+	}
+
+	address, err := ValidateAddress("address", input.Address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	// Only the requested slots are proven, so an
+	// account-only proof skips the storage trie.
+	keys := []string{}
+	if input.IncludeStorage {
+		for _, key := range input.StorageKeys {
+			slot, err := parseStorageKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+			}
+			keys = append(keys, slot.Hex())
+		}
+	}
+
+	blockNum := toBlockNumArg(nil)
+	if input.Index != nil {
+		blockNum = toBlockNumArg(big.NewInt(*input.Index))
+	}
+
+	var proof *accountProof
+	if err := ec.c.CallContext(ctx, &proof, "eth_getProof", address.Hex(), keys, blockNum); err != nil {
+		if isMethodNotFound(err) {
+			return nil, fmt.Errorf("%w: %v", ErrMethodUnsupported, err)
+		}
+		return nil, historicalStateError(err)
+	}
+	if proof == nil {
+		return nil, fmt.Errorf("%w: no proof of %s", ErrHistoricalStateUnavailable, address.Hex())
+	}
+
+	// The storage hash is kept in both modes, as it is the
+	// root storage proofs are verified against.
+	result := map[string]interface{}{
+		"address":      address.Hex(),
+		"storage_hash": proof.StorageHash.Hex(),
+	}
+	if input.IncludeAccount {
+		result["account_proof"] = proof.AccountProof
+		result["balance"] = proof.Balance.ToInt().String()
+		result["code_hash"] = proof.CodeHash.Hex()
+		result["nonce"] = uint64(proof.Nonce)
+	}
+	if input.IncludeStorage {
+		storage := make([]map[string]interface{}, len(proof.StorageProof))
+		for i, slot := range proof.StorageProof {
+			storage[i] = map[string]interface{}{
+				"key":   slot.Key,
+				"value": slot.Value.ToInt().String(),
+				"proof": slot.Proof,
+			}
+		}
+		result["storage_proof"] = storage
+	}
+
+	return result, nil
+}
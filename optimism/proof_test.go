@@ -0,0 +1,198 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const proofAddress = "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+
+func TestGetProof(t *testing.T) {
+	accountProof := []string{
+		"0xf90211a0b1d2c8e1f4a3c2b7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b480",
+		"0xf8718080a0c3e2d1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4c38080",
+	}
+	storageProof := []map[string]interface{}{
+		{
+			"key":   "0x0000000000000000000000000000000000000000000000000000000000000000",
+			"value": "1000",
+			"proof": []string{
+				"0xf8518080a0d4e3f2a1b0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c58080",
+			},
+		},
+	}
+	storageHash := "0x9c1b5f3a7b0cde0c2ed4b5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d3e4f5a6"
+
+	var tests = map[string]struct {
+		params map[string]interface{}
+
+		expectedKeys  []string
+		expectedBlock string
+		proofErr      error
+
+		expectedResult map[string]interface{}
+		expectedErr    error
+	}{
+		"account only": {
+			params: map[string]interface{}{
+				"address":         proofAddress,
+				"include_account": true,
+			},
+			expectedKeys:  []string{},
+			expectedBlock: "latest",
+			expectedResult: map[string]interface{}{
+				"address":       proofAddress,
+				"storage_hash":  storageHash,
+				"account_proof": accountProof,
+				"balance":       "1000000000000000000",
+				"code_hash":     "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+				"nonce":         uint64(5),
+			},
+		},
+		"storage only": {
+			params: map[string]interface{}{
+				"address":         proofAddress,
+				"storage_keys":    []string{"0x0"},
+				"include_storage": true,
+				"index":           10992,
+			},
+			expectedKeys:  []string{"0x0000000000000000000000000000000000000000000000000000000000000000"},
+			expectedBlock: "0x2af0",
+			expectedResult: map[string]interface{}{
+				"address":       proofAddress,
+				"storage_hash":  storageHash,
+				"storage_proof": storageProof,
+			},
+		},
+		"storage keys ignored for account only": {
+			params: map[string]interface{}{
+				"address":         proofAddress,
+				"storage_keys":    []string{"0x0"},
+				"include_account": true,
+			},
+			expectedKeys:  []string{},
+			expectedBlock: "latest",
+			expectedResult: map[string]interface{}{
+				"address":       proofAddress,
+				"storage_hash":  storageHash,
+				"account_proof": accountProof,
+				"balance":       "1000000000000000000",
+				"code_hash":     "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+				"nonce":         uint64(5),
+			},
+		},
+		"nothing requested": {
+			params: map[string]interface{}{
+				"address": proofAddress,
+			},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"storage without keys": {
+			params: map[string]interface{}{
+				"address":         proofAddress,
+				"include_storage": true,
+			},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"invalid storage key": {
+			params: map[string]interface{}{
+				"address":         proofAddress,
+				"storage_keys":    []string{"slot"},
+				"include_storage": true,
+			},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"invalid address": {
+			params: map[string]interface{}{
+				"address":         "0x2f93",
+				"include_account": true,
+			},
+			expectedErr: ErrCallParametersInvalid,
+		},
+		"method not found": {
+			params: map[string]interface{}{
+				"address":         proofAddress,
+				"include_account": true,
+			},
+			expectedKeys:  []string{},
+			expectedBlock: "latest",
+			proofErr: &jsonError{
+				code:    methodNotFoundCode,
+				message: "the method eth_getProof does not exist/is not available",
+			},
+			expectedErr: ErrMethodUnsupported,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+			ctx := context.Background()
+
+			if test.expectedBlock != "" {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"eth_getProof",
+					proofAddress,
+					test.expectedKeys,
+					test.expectedBlock,
+				).Return(
+					test.proofErr,
+				).Run(
+					func(args mock.Arguments) {
+						if test.proofErr != nil {
+							return
+						}
+
+						file, err := ioutil.ReadFile("testdata/proof.json")
+						assert.NoError(t, err)
+						assert.NoError(t, json.Unmarshal(file, args.Get(1)))
+					},
+				).Once()
+			}
+
+			resp, err := c.Call(
+				ctx,
+				&RosettaTypes.CallRequest{
+					Method:     GetProofMethod,
+					Parameters: test.params,
+				},
+			)
+			if test.expectedErr != nil {
+				assert.Nil(t, resp)
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedResult, resp.Result)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit caps the requests made to the node at rps per second,
+// allowing bursts of up to burst requests. Each JSON-RPC call, batch
+// and GraphQL query waits for a token until its context is done.
+// Unlike traceSemaphore, which bounds concurrent traces, this bounds
+// the rate of all requests. A non-positive rps leaves requests
+// unlimited.
+func (ec *Client) WithRateLimit(rps int, burst int) *Client {
+	if rps <= 0 {
+		return ec
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(rps), burst)
+	ec.wrapJSONRPC(func(c JSONRPC) JSONRPC {
+		return &rateLimitedJSONRPC{JSONRPC: c, limiter: limiter}
+	})
+	if ec.g != nil {
+		ec.g = &rateLimitedGraphQL{GraphQL: ec.g, limiter: limiter}
+	}
+
+	return ec
+}
+
+// rateLimitedJSONRPC is a JSONRPC that waits on a limiter
+// before each request. A batch counts as a single request.
+type rateLimitedJSONRPC struct {
+	JSONRPC
+	limiter *rate.Limiter
+}
+
+func (c *rateLimitedJSONRPC) CallContext(
+	ctx context.Context,
+	result interface{},
+	method string,
+	args ...interface{},
+) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	return c.JSONRPC.CallContext(ctx, result, method, args...)
+}
+
+func (c *rateLimitedJSONRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	return c.JSONRPC.BatchCallContext(ctx, b)
+}
+
+// rateLimitedGraphQL is a GraphQL that waits on
+// a limiter before each query.
+type rateLimitedGraphQL struct {
+	GraphQL
+	limiter *rate.Limiter
+}
+
+func (g *rateLimitedGraphQL) Query(
+	ctx context.Context,
+	input string,
+	variables map[string]interface{},
+) (string, error) {
+	if err := g.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+
+	return g.GraphQL.Query(ctx, input, variables)
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+	assert.NoError(t, err)
+
+	c := (&Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: cf,
+		traceSemaphore:  semaphore.NewWeighted(100),
+	}).WithRateLimit(20, 1)
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_blockNumber",
+	).Return(
+		nil,
+	).Times(3)
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.Anything,
+	).Return(
+		nil,
+	).Once()
+	mockGraphQL.On(
+		"Query",
+		ctx,
+		"{ block { number } }",
+		map[string]interface{}(nil),
+	).Return(
+		"{}",
+		nil,
+	).Once()
+
+	// With a burst of 1, the first request is immediate and each
+	// following one waits 1/20s for a token.
+	start := time.Now()
+	var gaps []time.Duration
+	last := start
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, c.c.CallContext(ctx, nil, "eth_blockNumber"))
+		gaps = append(gaps, time.Since(last))
+		last = time.Now()
+	}
+	assert.NoError(t, c.c.BatchCallContext(ctx, nil))
+	_, err = c.g.Query(ctx, "{ block { number } }", nil)
+	assert.NoError(t, err)
+
+	// Allow some slack for the timer resolution
+	assert.Less(t, int64(gaps[0]), int64(25*time.Millisecond))
+	for _, gap := range gaps[1:] {
+		assert.GreaterOrEqual(t, int64(gap), int64(40*time.Millisecond))
+	}
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(180*time.Millisecond))
+
+	// Requests no longer wait once their context is done
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	err = c.c.CallContext(canceled, nil, "eth_blockNumber")
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestWithRateLimit_Disabled(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := (&Client{c: mockJSONRPC}).WithRateLimit(0, 1)
+	assert.Equal(t, mockJSONRPC, c.c)
+}
+
+func TestWithRateLimit_Close(t *testing.T) {
+	c, err := NewClient("http://localhost:8545", params.MainnetChainConfig, ClientOptions{
+		EnableGethTracer: true,
+	})
+	assert.NoError(t, err)
+	gc := c.gc
+	assert.NotNil(t, gc)
+
+	// The GraphQL client is wrapped, but Close still releases it
+	c.WithRateLimit(10, 1)
+	assert.IsType(t, &rateLimitedGraphQL{}, c.g)
+	assert.Equal(t, gc, c.gc)
+	assert.NoError(t, c.Close())
+}
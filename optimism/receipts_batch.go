@@ -0,0 +1,150 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	// receiptsBatchSize is the number of receipts requested
+	// per batch by transactionReceipts.
+	receiptsBatchSize = 100
+
+	// receiptsBatchConcurrency is the number of receipt
+	// batches transactionReceipts keeps in flight.
+	receiptsBatchConcurrency = 4
+)
+
+// transactionReceipts fetches the receipts of txHashes in batches
+// of receiptsBatchSize, running at most receiptsBatchConcurrency
+// batches at once. Receipts are returned in the order of txHashes,
+// with nil for transactions the node has no receipt for.
+func (ec *Client) transactionReceipts(
+	ctx context.Context,
+	txHashes []common.Hash,
+) ([]*types.Receipt, error) {
+	d, err := ec.nodeDialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make([]*types.Receipt, len(txHashes))
+	sem := semaphore.NewWeighted(receiptsBatchConcurrency)
+	g, gctx := errgroup.WithContext(ctx)
+	for _, batch := range batchRanges(len(txHashes), receiptsBatchSize) {
+		start, end := batch[0], batch[1]
+		if err := sem.Acquire(gctx, 1); err != nil {
+			break
+		}
+
+		g.Go(func() error {
+			defer sem.Release(1)
+
+			reqs := make([]rpc.BatchElem, end-start)
+			for i := range reqs {
+				reqs[i] = rpc.BatchElem{
+					Method: "eth_getTransactionReceipt",
+					Args:   []interface{}{txHashes[start+i].Hex()},
+					Result: d.receiptResult(&receipts[start+i]),
+				}
+			}
+			if err := ec.c.BatchCallContext(gctx, reqs); err != nil {
+				return err
+			}
+			for i := range reqs {
+				if reqs[i].Error != nil {
+					return reqs[i].Error
+				}
+			}
+
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return receipts, nil
+}
+
+// receiptsCallResult fetches txHashes and encodes the receipts
+// as the result of the bulk "eth_getTransactionReceipt" call.
+// If includeRevertReason is set, the receipts of failed transactions
+// include their revert reason.
+func (ec *Client) receiptsCallResult(
+	ctx context.Context,
+	txHashes []string,
+	includeRevertReason bool,
+) (map[string]interface{}, error) {
+	hashes := make([]common.Hash, len(txHashes))
+	for i, txHash := range txHashes {
+		hashes[i] = common.HexToHash(txHash)
+	}
+
+	receipts, err := ec.transactionReceipts(ctx, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(receipts))
+	for i, receipt := range receipts {
+		if receipt == nil {
+			continue
+		}
+
+		// We cannot use RosettaTypes.MarshalMap because geth uses a custom
+		// marshaler to convert *types.Receipt to JSON.
+		jsonOutput, err := receipt.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+		}
+
+		var receiptMap map[string]interface{}
+		if err := json.Unmarshal(jsonOutput, &receiptMap); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+		}
+
+		mismatch, err := ec.receiptBloomMismatch(receipt)
+		if err != nil {
+			return nil, err
+		}
+		if mismatch {
+			receiptMap[BloomMismatchKey] = true
+		}
+
+		if includeRevertReason && receipt.Status == types.ReceiptStatusFailed {
+			if err := ec.addRevertReason(ctx, receipt.TxHash, receiptMap); err != nil {
+				return nil, err
+			}
+		}
+		results[i] = receiptMap
+	}
+
+	return map[string]interface{}{
+		"receipts": results,
+	}, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestCall_GetTransactionReceipts(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+
+	c := &Client{
+		c:              mockJSONRPC,
+		g:              mockGraphQL,
+		traceSemaphore: semaphore.NewWeighted(100),
+	}
+
+	// Every tenth transaction has no receipt.
+	txHashes := make([]interface{}, 250)
+	for i := range txHashes {
+		txHashes[i] = common.BigToHash(big.NewInt(int64(i + 1))).Hex()
+	}
+	missing := func(txHash string) bool {
+		return common.HexToHash(txHash).Big().Int64()%10 == 0
+	}
+
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+	)
+	mockJSONRPC.On(
+		"BatchCallContext",
+		mock.Anything,
+		mock.MatchedBy(func(reqs []rpc.BatchElem) bool {
+			return len(reqs) > 0 && reqs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			reqs := args.Get(1).([]rpc.BatchElem)
+			mu.Lock()
+			batchSizes = append(batchSizes, len(reqs))
+			mu.Unlock()
+
+			for i := range reqs {
+				txHash := reqs[i].Args[0].(string)
+				if missing(txHash) {
+					continue
+				}
+				*(reqs[i].Result.(**types.Receipt)) = &types.Receipt{
+					Status: types.ReceiptStatusSuccessful,
+					TxHash: common.HexToHash(txHash),
+				}
+			}
+		},
+	)
+
+	resp, err := c.Call(
+		context.Background(),
+		&RosettaTypes.CallRequest{
+			Method: "eth_getTransactionReceipt",
+			Parameters: map[string]interface{}{
+				"tx_hashes": txHashes,
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	sort.Ints(batchSizes)
+	assert.Equal(t, []int{50, 100, 100}, batchSizes)
+
+	receipts := resp.Result["receipts"].([]interface{})
+	assert.Len(t, receipts, len(txHashes))
+	for i, receipt := range receipts {
+		txHash := txHashes[i].(string)
+		if missing(txHash) {
+			assert.Nil(t, receipt, fmt.Sprintf("receipt %d", i))
+			continue
+		}
+		assert.Equal(
+			t,
+			txHash,
+			receipt.(map[string]interface{})["transactionHash"],
+			fmt.Sprintf("receipt %d", i),
+		)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestCall_GetTransactionReceiptsError(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+
+	c := &Client{
+		c:              mockJSONRPC,
+		g:              mockGraphQL,
+		traceSemaphore: semaphore.NewWeighted(100),
+	}
+
+	receiptErr := errors.New("receipt unavailable")
+	mockJSONRPC.On(
+		"BatchCallContext",
+		mock.Anything,
+		mock.Anything,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			reqs := args.Get(1).([]rpc.BatchElem)
+			reqs[len(reqs)-1].Error = receiptErr
+		},
+	).Once()
+
+	resp, err := c.Call(
+		context.Background(),
+		&RosettaTypes.CallRequest{
+			Method: "eth_getTransactionReceipt",
+			Parameters: map[string]interface{}{
+				"tx_hashes": []interface{}{
+					"0xb358c6958b1cab722752939cbb92e3fec6b6023de360305910ce80c56c3dad9d",
+					"0x7e03dc7d5ac2b8e8fc4e1e6ec3b0ae9e21bc32d98d8ff9c7e6f0e2cb1b2b1ec0",
+				},
+			},
+		},
+	)
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, receiptErr))
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const (
+	// RevertReasonKey is the receipt field holding the decoded
+	// Error(string) reason of a failed transaction.
+	RevertReasonKey = "revert_reason"
+
+	// RevertDataKey is the receipt field holding the raw revert
+	// output of a failed transaction, when it is not an Error(string).
+	RevertDataKey = "revert_data"
+)
+
+// revertTrace is the subset of a call trace needed to recover the
+// revert output of the top-level call.
+type revertTrace struct {
+	Output       string `json:"output"`
+	Error        string `json:"error"`
+	RevertReason string `json:"revertReason"`
+}
+
+// revertReason re-executes txHash with debug_traceTransaction and
+// returns the decoded revert reason of its top-level call, and the raw
+// revert output if it could not be decoded. Both are empty when the
+// transaction did not revert with any output.
+func (ec *Client) revertReason(
+	ctx context.Context,
+	txHash common.Hash,
+) (string, string, error) {
+	var trace revertTrace
+	if err := ec.c.CallContext(ctx, &trace, "debug_traceTransaction", txHash.Hex(), ec.tc); err != nil {
+		return "", "", err
+	}
+
+	// The native callTracer decodes the reason itself.
+	if len(trace.RevertReason) > 0 {
+		return trace.RevertReason, "", nil
+	}
+
+	output, err := hexutil.Decode(trace.Output)
+	if err != nil || len(output) == 0 {
+		return "", "", nil
+	}
+
+	reason, err := abi.UnpackRevert(output)
+	if err != nil {
+		return "", trace.Output, nil
+	}
+
+	return reason, "", nil
+}
+
+// addRevertReason sets the revert reason (or raw revert data) of the
+// failed transaction txHash on receiptMap.
+func (ec *Client) addRevertReason(
+	ctx context.Context,
+	txHash common.Hash,
+	receiptMap map[string]interface{},
+) error {
+	reason, data, err := ec.revertReason(ctx, txHash)
+	if err != nil {
+		return err
+	}
+
+	if len(reason) > 0 {
+		receiptMap[RevertReasonKey] = reason
+	}
+	if len(data) > 0 {
+		receiptMap[RevertDataKey] = data
+	}
+
+	return nil
+}
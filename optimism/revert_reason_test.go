@@ -0,0 +1,216 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// encodeRevertReason ABI-encodes reason as Error(string) revert output.
+func encodeRevertReason(reason string) string {
+	data := common.FromHex("0x08c379a0")
+	data = append(data, common.LeftPadBytes([]byte{0x20}, 32)...)
+	data = append(data, common.LeftPadBytes([]byte{byte(len(reason))}, 32)...)
+	data = append(data, common.RightPadBytes([]byte(reason), 32)...)
+	return hexutil.Encode(data)
+}
+
+func TestCall_GetTransactionReceipt_RevertReason(t *testing.T) {
+	txHash := common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a")
+
+	tests := map[string]struct {
+		status         uint64
+		include        bool
+		trace          revertTrace
+		expectedReason interface{}
+		expectedData   interface{}
+	}{
+		"decoded reason": {
+			status:  types.ReceiptStatusFailed,
+			include: true,
+			trace: revertTrace{
+				Output: encodeRevertReason("insufficient balance"),
+				Error:  "execution reverted",
+			},
+			expectedReason: "insufficient balance",
+		},
+		"native tracer reason": {
+			status:  types.ReceiptStatusFailed,
+			include: true,
+			trace: revertTrace{
+				Output:       encodeRevertReason("paused"),
+				Error:        "execution reverted",
+				RevertReason: "paused",
+			},
+			expectedReason: "paused",
+		},
+		"custom error": {
+			status:  types.ReceiptStatusFailed,
+			include: true,
+			trace: revertTrace{
+				Output: "0x1425ea42",
+				Error:  "execution reverted",
+			},
+			expectedData: "0x1425ea42",
+		},
+		"no output": {
+			status:  types.ReceiptStatusFailed,
+			include: true,
+			trace: revertTrace{
+				Error: "out of gas",
+			},
+		},
+		"not requested": {
+			status: types.ReceiptStatusFailed,
+		},
+		"successful": {
+			status:  types.ReceiptStatusSuccessful,
+			include: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tc, err := testTraceConfig()
+			assert.NoError(t, err)
+
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC, tc: tc}
+			ctx := context.Background()
+
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getTransactionReceipt",
+				txHash,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(**types.Receipt)
+
+					file, err := ioutil.ReadFile("testdata/tx_receipt_1.json")
+					assert.NoError(t, err)
+
+					*r = new(types.Receipt)
+					assert.NoError(t, (*r).UnmarshalJSON(file))
+					(*r).Status = test.status
+				},
+			).Once()
+
+			if test.include && test.status == types.ReceiptStatusFailed {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"debug_traceTransaction",
+					txHash.Hex(),
+					c.tc,
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						r := args.Get(1).(*revertTrace)
+						*r = test.trace
+					},
+				).Once()
+			}
+
+			resp, err := c.Call(
+				ctx,
+				&RosettaTypes.CallRequest{
+					Method: "eth_getTransactionReceipt",
+					Parameters: map[string]interface{}{
+						"tx_hash":               txHash.Hex(),
+						"include_revert_reason": test.include,
+					},
+				},
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedReason, resp.Result[RevertReasonKey])
+			assert.Equal(t, test.expectedData, resp.Result[RevertDataKey])
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_GetTransactionReceipt_RevertReasonError(t *testing.T) {
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, tc: tc}
+	ctx := context.Background()
+	txHash := common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a")
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getTransactionReceipt",
+		txHash,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(**types.Receipt)
+
+			file, err := ioutil.ReadFile("testdata/tx_receipt_1.json")
+			assert.NoError(t, err)
+
+			*r = new(types.Receipt)
+			assert.NoError(t, (*r).UnmarshalJSON(file))
+			(*r).Status = types.ReceiptStatusFailed
+		},
+	).Once()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"debug_traceTransaction",
+		txHash.Hex(),
+		c.tc,
+	).Return(
+		&jsonError{code: -32000, message: "missing trie node"},
+	).Once()
+
+	resp, err := c.Call(
+		ctx,
+		&RosettaTypes.CallRequest{
+			Method: "eth_getTransactionReceipt",
+			Parameters: map[string]interface{}{
+				"tx_hash":               txHash.Hex(),
+				"include_revert_reason": true,
+			},
+		},
+	)
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrHistoricalStateUnavailable)
+
+	mockJSONRPC.AssertExpectations(t)
+}
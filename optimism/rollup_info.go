@@ -0,0 +1,122 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// RollupInfoMethod is the call method that returns the rollup sync
+// state of the node from rollup_getInfo.
+const RollupInfoMethod = "rollup_getInfo"
+
+// RollupInfoKey is the key of the rollup sync state in the
+// RollupInfoMethod call result. It is null on nodes that don't
+// serve rollup_getInfo.
+const RollupInfoKey = "rollup"
+
+// RollupInfo is the rollup sync state reported by l2geth.
+type RollupInfo struct {
+	// Mode is either "sequencer" or "verifier".
+	Mode string `json:"mode"`
+
+	// Syncing is set while the node is catching up with the
+	// sequencer or L1.
+	Syncing bool `json:"syncing"`
+
+	// EthContext is the L1 block the node has processed up to.
+	EthContext struct {
+		BlockNumber uint64 `json:"blockNumber"`
+		Timestamp   uint64 `json:"timestamp"`
+	} `json:"ethContext"`
+
+	// RollupContext holds the last processed index of the canonical
+	// transaction chain, the enqueue queue and the batched (verified)
+	// transactions.
+	RollupContext struct {
+		Index         uint64 `json:"index"`
+		QueueIndex    uint64 `json:"queueIndex"`
+		VerifiedIndex uint64 `json:"verifiedIndex"`
+	} `json:"rollupContext"`
+}
+
+// RollupInfo returns the rollup sync state of the node, or nil if
+// the node doesn't serve rollup_getInfo. Operators can use it to
+// confirm a verifier is tracking L1.
+func (ec *Client) RollupInfo(ctx context.Context) (*RollupInfo, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	info, err := ec.rollupInfo(ctx)
+	if err != nil {
+		return nil, rpcError(err, false)
+	}
+
+	return info, nil
+}
+
+// rollupInfo calls rollup_getInfo. Nodes that reject it, such as
+// plain geth backends, are not asked again.
+func (ec *Client) rollupInfo(ctx context.Context) (*RollupInfo, error) {
+	if atomic.LoadUint32(&ec.skipRollupInfo) == 1 {
+		return nil, nil
+	}
+
+	d, err := ec.nodeDialect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !d.rollupNamespace() {
+		return nil, nil
+	}
+
+	var info *RollupInfo
+	if err := ec.c.CallContext(ctx, &info, "rollup_getInfo"); err != nil {
+		if isMethodNotFound(err) {
+			log.Printf("rollup_getInfo is not available, not reporting rollup info: %v", err)
+			atomic.StoreUint32(&ec.skipRollupInfo, 1)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (ec *Client) rollupInfoCallResult(ctx context.Context) (map[string]interface{}, error) {
+	info, err := ec.rollupInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		RollupInfoKey: nil,
+	}
+	if info != nil {
+		resp, err := RosettaTypes.MarshalMap(info)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCallOutputMarshal, err.Error())
+		}
+		result[RollupInfoKey] = resp
+	}
+
+	return result, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCall_RollupInfo(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"rollup_getInfo",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(**RollupInfo)
+
+			file, err := ioutil.ReadFile("testdata/rollup_info.json")
+			assert.NoError(t, err)
+
+			*r = new(RollupInfo)
+			assert.NoError(t, json.Unmarshal(file, *r))
+		},
+	).Once()
+
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+		Method: RollupInfoMethod,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &RosettaTypes.CallResponse{
+		Result: map[string]interface{}{
+			RollupInfoKey: map[string]interface{}{
+				"mode":    "verifier",
+				"syncing": false,
+				"ethContext": map[string]interface{}{
+					"blockNumber": uint64(14254301),
+					"timestamp":   uint64(1645628907),
+				},
+				"rollupContext": map[string]interface{}{
+					"index":         uint64(1241186),
+					"queueIndex":    uint64(11473),
+					"verifiedIndex": uint64(0),
+				},
+			},
+		},
+	}, resp)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestCall_RollupInfo_MethodNotFound(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"rollup_getInfo",
+	).Return(
+		&jsonError{code: -32601, message: "the method rollup_getInfo does not exist/is not available"},
+	).Once()
+
+	// The node is only asked once
+	for i := 0; i < 2; i++ {
+		resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+			Method: RollupInfoMethod,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &RosettaTypes.CallResponse{
+			Result: map[string]interface{}{
+				RollupInfoKey: nil,
+			},
+		}, resp)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestRollupInfo(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"rollup_getInfo",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(**RollupInfo)
+
+			file, err := ioutil.ReadFile("testdata/rollup_info.json")
+			assert.NoError(t, err)
+
+			*r = new(RollupInfo)
+			assert.NoError(t, json.Unmarshal(file, *r))
+		},
+	).Once()
+
+	info, err := c.RollupInfo(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "verifier", info.Mode)
+	assert.False(t, info.Syncing)
+	assert.Equal(t, uint64(14254301), info.EthContext.BlockNumber)
+	assert.Equal(t, uint64(1645628907), info.EthContext.Timestamp)
+	assert.Equal(t, uint64(1241186), info.RollupContext.Index)
+	assert.Equal(t, uint64(11473), info.RollupContext.QueueIndex)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestRollupInfo_Closed(t *testing.T) {
+	c := &Client{closed: 1}
+
+	info, err := c.RollupInfo(context.Background())
+	assert.Nil(t, info)
+	assert.True(t, errors.Is(err, ErrClientClosed))
+}
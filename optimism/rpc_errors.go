@@ -0,0 +1,194 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"syscall"
+
+	ethereum "github.com/ethereum-optimism/optimism/l2geth"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+const (
+	// invalidParamsCode is the JSON-RPC error code
+	// returned for invalid method parameters.
+	invalidParamsCode = -32602
+
+	// limitExceededCode is the JSON-RPC error code
+	// returned when a request exceeds a rate limit.
+	limitExceededCode = -32005
+)
+
+var (
+	// clientErrors are already meaningful to callers,
+	// so they are never reclassified.
+	clientErrors = []error{
+		ErrBlockOrphaned,
+		ErrCallParametersInvalid,
+		ErrCallOutputMarshal,
+		ErrCallMethodInvalid,
+		ErrClientClosed,
+		ErrNotERC20,
+		ErrInvalidTokenContractAddress,
+		ErrHistoricalStateUnavailable,
+		ErrBloomMismatch,
+		ErrInvalidAddress,
+		ErrTraceCapacityBusy,
+		ErrChainIDChanged,
+		ErrMethodUnsupported,
+		ErrChainIDMismatch,
+		ErrTransactionHashMismatch,
+		ErrInvalidRawTransaction,
+		ErrInvalidGraphQLVariable,
+	}
+
+	blockNotFoundMessages = []string{
+		"header not found",
+		"block not found",
+		"unknown block",
+	}
+
+	rateLimitedMessages = []string{
+		"429 too many requests",
+		"rate limit",
+	}
+
+	nodeUnavailableMessages = []string{
+		"connection refused",
+		"connection reset",
+		"no such host",
+		"502 bad gateway",
+		"503 service unavailable",
+		"504 gateway timeout",
+	}
+)
+
+// RPCError is an error returned by the node, classified by Kind so
+// callers can tell failures apart with errors.Is. The original error,
+// usually an rpc.Error, is available with errors.As.
+type RPCError struct {
+	Kind error
+	Err  error
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Kind.Error(), e.Err.Error())
+}
+
+// Unwrap returns the error returned by the node.
+func (e *RPCError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of e, or
+// an error the kind wraps.
+func (e *RPCError) Is(target error) bool {
+	return errors.Is(e.Kind, target)
+}
+
+// rpcError classifies an error returned by the node. Errors that
+// don't match any kind are returned unchanged. If notFoundIsBlock is
+// set, ethereum.NotFound is classified as ErrBlockNotFound.
+func rpcError(err error, notFoundIsBlock bool) error {
+	if err == nil {
+		return nil
+	}
+
+	var classified *RPCError
+	if errors.As(err, &classified) {
+		return err
+	}
+	for _, clientErr := range clientErrors {
+		if errors.Is(err, clientErr) {
+			return err
+		}
+	}
+
+	if kind := rpcErrorKind(err, notFoundIsBlock); kind != nil {
+		return &RPCError{Kind: kind, Err: err}
+	}
+
+	return err
+}
+
+// callError classifies an error returned by the node for a /call
+// request. The parameters of the request are passed on to the node,
+// so parameters it rejects are ErrCallParametersInvalid. Other errors
+// are classified by rpcError.
+func callError(err error) error {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == invalidParamsCode {
+		return &RPCError{Kind: ErrCallParametersInvalid, Err: err}
+	}
+
+	return rpcError(err, false)
+}
+
+func rpcErrorKind(err error, notFoundIsBlock bool) error { // nolint:gocognit
+	// A caller going away is not a node failure, so it is
+	// kept apart from a request that ran out of time.
+	if errors.Is(err, context.Canceled) {
+		return ErrRequestCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrRequestTimeout
+	}
+
+	if notFoundIsBlock && errors.Is(err, ethereum.NotFound) {
+		return ErrBlockNotFound
+	}
+
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.ErrorCode() {
+		case limitExceededCode:
+			return ErrRateLimited
+		case methodNotFoundCode:
+			return ErrMethodUnsupported
+		}
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrNodeUnavailable
+	}
+
+	if isStatePruned(err) {
+		return ErrStatePruned
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, m := range blockNotFoundMessages {
+		if strings.Contains(msg, m) {
+			return ErrBlockNotFound
+		}
+	}
+	for _, m := range rateLimitedMessages {
+		if strings.Contains(msg, m) {
+			return ErrRateLimited
+		}
+	}
+	for _, m := range nodeUnavailableMessages {
+		if strings.Contains(msg, m) {
+			return ErrNodeUnavailable
+		}
+	}
+
+	return nil
+}
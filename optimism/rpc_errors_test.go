@@ -0,0 +1,251 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	ethereum "github.com/ethereum-optimism/optimism/l2geth"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+// jsonError mirrors the error the rpc package returns
+// for a JSON-RPC error response.
+type jsonError struct {
+	code    int
+	message string
+}
+
+func (e *jsonError) Error() string  { return e.message }
+func (e *jsonError) ErrorCode() int { return e.code }
+
+func TestRPCError(t *testing.T) {
+	var tests = map[string]struct {
+		err             error
+		notFoundIsBlock bool
+
+		expectedKind error
+	}{
+		"header not found": {
+			err:          &jsonError{code: -32000, message: "header not found"},
+			expectedKind: ErrBlockNotFound,
+		},
+		"not found as block": {
+			err:             ethereum.NotFound,
+			notFoundIsBlock: true,
+			expectedKind:    ErrBlockNotFound,
+		},
+		"limit exceeded": {
+			err:          &jsonError{code: -32005, message: "limit exceeded"},
+			expectedKind: ErrRateLimited,
+		},
+		"http 429": {
+			err:          errors.New("429 Too Many Requests"),
+			expectedKind: ErrRateLimited,
+		},
+		"method not found": {
+			err:          &jsonError{code: -32601, message: "the method foo does not exist/is not available"},
+			expectedKind: ErrMethodUnsupported,
+		},
+		"connection refused": {
+			err: &net.OpError{
+				Op:  "dial",
+				Net: "tcp",
+				Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED},
+			},
+			expectedKind: ErrNodeUnavailable,
+		},
+		"eof": {
+			err:          fmt.Errorf("post failed: %w", io.EOF),
+			expectedKind: ErrNodeUnavailable,
+		},
+		"http 503": {
+			err:          errors.New("503 Service Unavailable"),
+			expectedKind: ErrNodeUnavailable,
+		},
+		"missing trie node": {
+			err:          &jsonError{code: -32000, message: "missing trie node 5b1d08c8b8a1f87f27b0eb2b7f07a1c5b2ae3eb6a6b2e4d2a3e1c0f9b8a7d6c5 (path )"},
+			expectedKind: ErrStatePruned,
+		},
+		"context canceled": {
+			err:          &url.Error{Op: "Post", URL: "http://localhost:8545", Err: context.Canceled},
+			expectedKind: ErrRequestCanceled,
+		},
+		"context deadline exceeded": {
+			err:          &url.Error{Op: "Post", URL: "http://localhost:8545", Err: context.DeadlineExceeded},
+			expectedKind: ErrRequestTimeout,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := rpcError(test.err, test.notFoundIsBlock)
+			assert.True(t, errors.Is(err, test.expectedKind))
+			assert.True(t, errors.Is(err, test.err))
+		})
+	}
+}
+
+func TestCallError(t *testing.T) {
+	invalidParams := &jsonError{code: -32602, message: "invalid argument 0: hex string without 0x prefix"}
+
+	// Only parameters passed on from a /call request are the caller's
+	// fault, so the node rejecting its own requests is unclassified.
+	err := callError(invalidParams)
+	assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+	assert.True(t, errors.Is(err, invalidParams))
+	assert.Equal(t, invalidParams, rpcError(invalidParams, false))
+
+	err = callError(&jsonError{code: -32601, message: "the method foo does not exist/is not available"})
+	assert.True(t, errors.Is(err, ErrMethodUnsupported))
+	assert.False(t, errors.Is(err, ErrCallMethodInvalid))
+}
+
+func TestStatePruned(t *testing.T) {
+	pruned := &jsonError{
+		code:    -32000,
+		message: "missing trie node 5b1d08c8b8a1f87f27b0eb2b7f07a1c5b2ae3eb6a6b2e4d2a3e1c0f9b8a7d6c5 (path )",
+	}
+
+	for name, err := range map[string]error{
+		"historical state":  historicalStateError(pruned),
+		"rpc":               rpcError(pruned, false),
+		"rpc of historical": rpcError(historicalStateError(pruned), false),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.True(t, errors.Is(err, ErrStatePruned))
+			assert.True(t, errors.Is(err, ErrHistoricalStateUnavailable))
+			assert.Contains(t, err.Error(), "missing trie node")
+		})
+	}
+}
+
+func TestCall_StatePruned(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_call",
+		mock.Anything,
+		"0x2af0",
+	).Return(
+		&jsonError{code: -32000, message: "missing trie node 5b1d08c8b8a1f87f27b0eb2b7f07a1c5b2ae3eb6a6b2e4d2a3e1c0f9b8a7d6c5 (path )"},
+	).Once()
+
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+		Method: "eth_call",
+		Parameters: map[string]interface{}{
+			"index": 10992,
+			"to":    "0x4200000000000000000000000000000000000006",
+			"data":  "0x70a08231",
+		},
+	})
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrStatePruned))
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestRPCError_Unclassified(t *testing.T) {
+	var tests = map[string]error{
+		"execution reverted": &jsonError{code: 3, message: "execution reverted"},
+		"not found":          ethereum.NotFound,
+		"client error":       fmt.Errorf("%w: block not found in cache", ErrBlockOrphaned),
+	}
+
+	for name, err := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, err, rpcError(err, false))
+		})
+	}
+}
+
+func TestBlock_RPCErrors(t *testing.T) {
+	var tests = map[string]struct {
+		err error
+
+		expectedKind error
+	}{
+		"block not found": {
+			err:          &jsonError{code: -32000, message: "header not found"},
+			expectedKind: ErrBlockNotFound,
+		},
+		"rate limited": {
+			err:          &jsonError{code: -32005, message: "limit exceeded"},
+			expectedKind: ErrRateLimited,
+		},
+		"node unavailable": {
+			err:          errors.New("502 Bad Gateway"),
+			expectedKind: ErrNodeUnavailable,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+
+			c := &Client{
+				c:              mockJSONRPC,
+				g:              mockGraphQL,
+				traceSemaphore: semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByNumber",
+				"0x2af0",
+				true,
+			).Return(
+				test.err,
+			).Once()
+
+			block, err := c.Block(ctx, &RosettaTypes.PartialBlockIdentifier{
+				Index: RosettaTypes.Int64(10992),
+			})
+			assert.Nil(t, block)
+			assert.True(t, errors.Is(err, test.expectedKind))
+
+			var rpcErr rpc.Error
+			if errors.As(test.err, &rpcErr) {
+				assert.True(t, errors.As(err, &rpcErr))
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// SendBundleMethod is the call method that submits signed
+// transactions as a bundle to be included together in a target
+// block, on nodes serving a flashbots-style eth_sendBundle.
+const SendBundleMethod = "eth_sendBundle"
+
+// SendBundleInput is the input to SendBundleMethod. SignedTransactions
+// are hex-encoded, like the signed transaction of
+// DecodeTransactionMethod, and included in order.
+type SendBundleInput struct {
+	SignedTransactions []string `json:"signed_transactions"`
+	BlockIndex         int64    `json:"block_index"`
+}
+
+// bundleArgs are the arguments of eth_sendBundle.
+type bundleArgs struct {
+	Txs         []string `json:"txs"`
+	BlockNumber string   `json:"blockNumber"`
+}
+
+// bundleResult is the result of eth_sendBundle.
+type bundleResult struct {
+	BundleHash common.Hash `json:"bundleHash"`
+}
+
+// sendBundle submits the bundle described by params and returns its
+// hash. ErrMethodUnsupported is returned if the node has no bundle
+// endpoint.
+func (ec *Client) sendBundle(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, error) {
+	var input SendBundleInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	if len(input.SignedTransactions) == 0 {
+		return nil, fmt.Errorf("%w: signed_transactions missing from params", ErrCallParametersInvalid)
+	}
+	if input.BlockIndex <= 0 {
+		return nil, fmt.Errorf("%w: invalid block_index %d", ErrCallParametersInvalid, input.BlockIndex)
+	}
+
+	// Transactions are decoded so a malformed bundle is rejected
+	// here rather than by the node.
+	for i, signedTx := range input.SignedTransactions {
+		data, err := hexutil.Decode(signedTx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: signed transaction %d is not valid hex: %s", ErrCallParametersInvalid, i, err.Error())
+		}
+		if err := new(ethTypes.Transaction).UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("%w: unable to decode signed transaction %d: %s", ErrCallParametersInvalid, i, err.Error())
+		}
+	}
+
+	var result bundleResult
+	if err := ec.c.CallContext(ctx, &result, "eth_sendBundle", &bundleArgs{
+		Txs:         input.SignedTransactions,
+		BlockNumber: hexutil.EncodeUint64(uint64(input.BlockIndex)),
+	}); err != nil {
+		if isMethodNotFound(err) {
+			return nil, fmt.Errorf("%w: %v", ErrMethodUnsupported, err)
+		}
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"bundle_hash": result.BundleHash.Hex(),
+	}, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const bundleSignedTx = "0xf86a80843b9aca00825208941ff502f9fe838cd772874cb67d0d96b93fd1d6d78725d4b6199a415d8029a01d110bf9fd468f7d00b3ce530832e99818835f45e9b08c66f8d9722264bb36c7a02711f47ec99f9ac585840daef41b7118b52ec72f02fcb30d874d36b10b668b59" // nolint
+
+func TestCall_SendBundle(t *testing.T) {
+	bundleHash := common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a")
+
+	tests := map[string]struct {
+		nodeErr error
+
+		expectedResult map[string]interface{}
+		expectedErr    error
+	}{
+		"submitted": {
+			expectedResult: map[string]interface{}{
+				"bundle_hash": bundleHash.Hex(),
+			},
+		},
+		"method not found": {
+			nodeErr:     &jsonError{code: -32601, message: "the method eth_sendBundle does not exist/is not available"},
+			expectedErr: ErrMethodUnsupported,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_sendBundle",
+				&bundleArgs{
+					Txs:         []string{bundleSignedTx, bundleSignedTx},
+					BlockNumber: "0x12f063",
+				},
+			).Return(
+				test.nodeErr,
+			).Run(
+				func(args mock.Arguments) {
+					if test.nodeErr == nil {
+						args.Get(1).(*bundleResult).BundleHash = bundleHash
+					}
+				},
+			).Once()
+
+			resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+				Method: SendBundleMethod,
+				Parameters: map[string]interface{}{
+					"signed_transactions": []string{bundleSignedTx, bundleSignedTx},
+					"block_index":         1241187,
+				},
+			})
+			if test.expectedErr != nil {
+				assert.Nil(t, resp)
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &RosettaTypes.CallResponse{
+					Result:     test.expectedResult,
+					Idempotent: true,
+				}, resp)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_SendBundle_InvalidInput(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"no transactions": {
+			"block_index": 1,
+		},
+		"missing block index": {
+			"signed_transactions": []string{bundleSignedTx},
+		},
+		"invalid hex": {
+			"signed_transactions": []string{"0xzz"},
+			"block_index":         1,
+		},
+		"invalid transaction": {
+			"signed_transactions": []string{bundleSignedTx, "0xf86b"},
+			"block_index":         1,
+		},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+
+			resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+				Method:     SendBundleMethod,
+				Parameters: params,
+			})
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+)
+
+// checkTransactionChainID compares the chain id tx is signed for with
+// the net_version of the node, returning ErrChainIDMismatch if they
+// differ so a transaction signed for another chain is not broadcast.
+// Transactions without replay protection are valid on any chain and
+// are not checked.
+func (ec *Client) checkTransactionChainID(ctx context.Context, tx *types.Transaction) error {
+	if !ec.checkTxChainID || !tx.Protected() {
+		return nil
+	}
+
+	var version string
+	if err := ec.c.CallContext(ctx, &version, "net_version"); err != nil {
+		return fmt.Errorf("%w: unable to get net_version", err)
+	}
+	networkID, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid net_version %s", err, version)
+	}
+
+	txChainID := tx.ChainId()
+	if !txChainID.IsUint64() || txChainID.Uint64() != networkID {
+		return fmt.Errorf(
+			"%w: transaction %s is signed for chain %s, but the node is on chain %d",
+			ErrChainIDMismatch,
+			tx.Hash().Hex(),
+			txChainID.String(),
+			networkID,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSendTransaction_CheckChainID(t *testing.T) {
+	rawTx, err := ioutil.ReadFile("testdata/submitted_tx.json")
+	assert.NoError(t, err)
+	tx := new(types.Transaction)
+	assert.NoError(t, tx.UnmarshalJSON(rawTx))
+	assert.Equal(t, int64(3), tx.ChainId().Int64())
+
+	tests := map[string]struct {
+		check      bool
+		netVersion string
+
+		expectedErr error
+	}{
+		"matching chain id": {
+			check:      true,
+			netVersion: "3",
+		},
+		"mismatched chain id": {
+			check:       true,
+			netVersion:  "10",
+			expectedErr: ErrChainIDMismatch,
+		},
+		"check disabled": {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC, checkTxChainID: test.check}
+
+			ctx := context.Background()
+			if test.check {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					mock.Anything,
+					"net_version",
+				).Return(
+					nil,
+				).Run(
+					func(args mock.Arguments) {
+						*args.Get(1).(*string) = test.netVersion
+					},
+				).Once()
+			}
+			if test.expectedErr == nil {
+				mockJSONRPC.On(
+					"CallContext",
+					ctx,
+					nil,
+					"eth_sendRawTransaction",
+					mock.Anything,
+				).Return(
+					nil,
+				).Once()
+			}
+
+			err := c.SendTransaction(ctx, tx)
+			if test.expectedErr != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
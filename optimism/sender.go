@@ -0,0 +1,43 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"log"
+	"math/big"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+)
+
+// SenderUnrecoverableKey is the transaction metadata key set when the
+// sender of a transaction is unknown: the node did not report it and
+// it could not be recovered from the signature. Such transactions have
+// no fee operations, as the account to debit is unknown, so the balance
+// of their sender cannot be reconciled from the block alone. Operations
+// derived from traces and logs are unaffected.
+const SenderUnrecoverableKey = "sender_unrecoverable"
+
+// recoverSender recovers the sender of a transaction the node
+// did not report one for. It returns nil if recovery fails.
+func (ec *Client) recoverSender(number *big.Int, tx *types.Transaction) *common.Address {
+	from, err := types.Sender(types.MakeSigner(ec.p, number), tx)
+	if err != nil {
+		log.Printf("unable to recover sender of %s: %v", tx.Hash().Hex(), err)
+		return nil
+	}
+
+	return &from
+}
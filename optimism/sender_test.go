@@ -0,0 +1,219 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/params"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestBlock_SenderUnrecoverable(t *testing.T) {
+	token := "0xf8b089026cad7ddd8cb8d79036a1ff1d4233d64a"
+	supportedTokens := map[string]bool{
+		token: true,
+	}
+
+	mockJSONRPC := &mocks.JSONRPC{}
+	mockGraphQL := &mocks.GraphQL{}
+	mockCurrencyFetcher := &mocks.CurrencyFetcher{}
+
+	tc, err := testTraceConfig()
+	assert.NoError(t, err)
+	c := &Client{
+		c:               mockJSONRPC,
+		g:               mockGraphQL,
+		currencyFetcher: mockCurrencyFetcher,
+		tc:              tc,
+		p:               params.GoerliChainConfig,
+		traceSemaphore:  semaphore.NewWeighted(100),
+		supportedTokens: supportedTokens,
+	}
+
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x12f062",
+		true,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(*json.RawMessage)
+
+			file, err := ioutil.ReadFile("testdata/block_1241186.json")
+			assert.NoError(t, err)
+
+			// The node does not report the sender, and the Kovan
+			// transaction cannot be recovered with the Goerli signer.
+			var block map[string]interface{}
+			assert.NoError(t, json.Unmarshal(file, &block))
+			delete(block["transactions"].([]interface{})[0].(map[string]interface{}), "from")
+			*r, err = json.Marshal(block)
+			assert.NoError(t, err)
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "debug_traceTransaction"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Len(t, r[0].Args, 2)
+			assert.Equal(
+				t,
+				common.HexToHash("0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009").Hex(),
+				r[0].Args[0],
+			)
+			assert.Equal(t, tc, r[0].Args[1])
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_trace_1241186.json",
+			)
+			assert.NoError(t, err)
+
+			call := new(Call)
+			assert.NoError(t, call.UnmarshalJSON(file))
+			*(r[0].Result.(**Call)) = call
+		},
+	).Once()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+			return len(rpcs) == 1 && rpcs[0].Method == "eth_getTransactionReceipt"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).([]rpc.BatchElem)
+
+			assert.Len(t, r, 1)
+			assert.Equal(
+				t,
+				"0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009",
+				r[0].Args[0],
+			)
+
+			file, err := ioutil.ReadFile(
+				"testdata/tx_receipt_0xd919fe87c4bc24f767d1b7a165266658d542af9e3f9bc11dd1a2d1f4695df009.json",
+			) // nolint
+			assert.NoError(t, err)
+
+			receipt := new(types.Receipt)
+			assert.NoError(t, receipt.UnmarshalJSON(file))
+			*(r[0].Result.(**types.Receipt)) = receipt
+		},
+	).Once()
+	mockCurrencyFetcher.On(
+		"FetchCurrency",
+		ctx,
+		uint64(1241186),
+		mock.Anything,
+	).Return(
+		&RosettaTypes.Currency{
+			Symbol:   TokenSymbol,
+			Decimals: TokenDecimals,
+			Metadata: map[string]interface{}{"token_address": token}},
+		nil,
+	).Once()
+
+	correctRaw, err := ioutil.ReadFile("testdata/block_response_1241186.json")
+	assert.NoError(t, err)
+	var correctResp *RosettaTypes.BlockResponse
+	assert.NoError(t, json.Unmarshal(correctRaw, &correctResp))
+
+	resp, err := c.Block(
+		ctx,
+		&RosettaTypes.PartialBlockIdentifier{
+			Index: RosettaTypes.Int64(1241186),
+		},
+	)
+	assert.NoError(t, err)
+
+	// The block assembles without the fee operations of the transaction
+	assert.Len(t, resp.Transactions, 1)
+	tx := resp.Transactions[0]
+	assert.Equal(t, true, tx.Metadata[SenderUnrecoverableKey])
+	assert.Len(t, tx.Operations, len(correctResp.Block.Transactions[0].Operations)-2)
+	for i, op := range tx.Operations {
+		assert.Equal(t, int64(i), op.OperationIdentifier.Index)
+		assert.NotEqual(t, FeeOpType, op.Type)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+	mockGraphQL.AssertExpectations(t)
+}
+
+func TestRecoverSender(t *testing.T) {
+	file, err := ioutil.ReadFile("testdata/block_1241186.json")
+	assert.NoError(t, err)
+
+	var body rpcBlock
+	assert.NoError(t, json.Unmarshal(file, &body))
+	tx := body.Transactions[0].tx
+
+	var tests = map[string]struct {
+		chainID *big.Int
+
+		expectedSender *common.Address
+	}{
+		"recovered": {
+			chainID:        big.NewInt(69),
+			expectedSender: body.Transactions[0].From,
+		},
+		"wrong chain": {
+			chainID: params.GoerliChainConfig.ChainID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{
+				p: &params.ChainConfig{
+					ChainID:        test.chainID,
+					HomesteadBlock: big.NewInt(0),
+					EIP155Block:    big.NewInt(0),
+				},
+			}
+
+			assert.Equal(t, test.expectedSender, c.recoverSender(big.NewInt(1241186), tx))
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	ethereum "github.com/ethereum-optimism/optimism/l2geth"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+)
+
+// Snapshot pins reads to a single block, so that a block and the
+// balances at that block are consistent even across a reorg.
+type Snapshot struct {
+	ec *Client
+
+	// BlockIdentifier is the block all reads are pinned to.
+	BlockIdentifier *RosettaTypes.BlockIdentifier
+}
+
+// SnapshotAt resolves a *RosettaTypes.PartialBlockIdentifier to a block
+// hash and returns a Snapshot pinned to it. If neither the hash or index
+// is populated, the current block is used.
+func (ec *Client) SnapshotAt(
+	ctx context.Context,
+	blockIdentifier *RosettaTypes.PartialBlockIdentifier,
+) (*Snapshot, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	var (
+		head *types.Header
+		err  error
+	)
+	tag, tagged := blockTag(blockIdentifier)
+	switch {
+	case tagged:
+		err = ec.c.CallContext(ctx, &head, "eth_getBlockByNumber", tag, false)
+		if err == nil && head == nil {
+			err = ethereum.NotFound
+		}
+	case blockIdentifier != nil && blockIdentifier.Hash != nil:
+		err = ec.c.CallContext(ctx, &head, "eth_getBlockByHash", *blockIdentifier.Hash, false)
+		if err == nil && head == nil {
+			err = ethereum.NotFound
+		}
+	case blockIdentifier != nil && blockIdentifier.Index != nil:
+		head, err = ec.blockHeader(ctx, big.NewInt(*blockIdentifier.Index))
+	default:
+		head, err = ec.blockHeader(ctx, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to resolve snapshot block", err)
+	}
+
+	return &Snapshot{
+		ec: ec,
+		BlockIdentifier: &RosettaTypes.BlockIdentifier{
+			Hash:  head.Hash().Hex(),
+			Index: head.Number.Int64(),
+		},
+	}, nil
+}
+
+// partialBlockIdentifier reads by hash, so that reads return the
+// pinned block even if it is reorged out in the meantime.
+func (s *Snapshot) partialBlockIdentifier() *RosettaTypes.PartialBlockIdentifier {
+	hash := s.BlockIdentifier.Hash
+	index := s.BlockIdentifier.Index
+	return &RosettaTypes.PartialBlockIdentifier{
+		Hash:  &hash,
+		Index: &index,
+	}
+}
+
+// checkCanonical returns ErrBlockOrphaned if the canonical block at the
+// snapshot height is no longer the pinned one. Only the header is
+// fetched.
+func (s *Snapshot) checkCanonical(ctx context.Context) error {
+	head, err := s.ec.blockHeader(ctx, big.NewInt(s.BlockIdentifier.Index))
+	if err != nil {
+		return rpcError(err, true)
+	}
+	if hash := head.Hash().Hex(); hash != s.BlockIdentifier.Hash {
+		return fmt.Errorf(
+			"%w: snapshot block %s replaced by %s at height %d",
+			ErrBlockOrphaned,
+			s.BlockIdentifier.Hash,
+			hash,
+			s.BlockIdentifier.Index,
+		)
+	}
+
+	return nil
+}
+
+// Block returns the pinned block. It is checked to be canonical
+// before it is fetched, so orphaned blocks are not traced.
+func (s *Snapshot) Block(ctx context.Context) (*RosettaTypes.Block, error) {
+	if err := s.checkCanonical(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.ec.Block(ctx, s.partialBlockIdentifier())
+}
+
+// Balance returns the balance of a *RosettaTypes.AccountIdentifier
+// at the pinned block. The canonical header is fetched in the same
+// batch as the balance, which fails with ErrBlockOrphaned if the
+// pinned block was reorged out.
+func (s *Snapshot) Balance(
+	ctx context.Context,
+	account *RosettaTypes.AccountIdentifier,
+	currencies []*RosettaTypes.Currency,
+) (*RosettaTypes.AccountBalanceResponse, error) {
+	return s.ec.Balance(ctx, account, s.partialBlockIdentifier(), currencies)
+}
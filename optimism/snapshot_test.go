@@ -0,0 +1,196 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestSnapshot_Balance(t *testing.T) {
+	var tests = map[string]struct {
+		canonicalBlock string
+
+		expectedErr error
+	}{
+		"canonical": {
+			canonicalBlock: "testdata/block_10992.json",
+		},
+		"orphaned": {
+			canonicalBlock: "testdata/block_10991.json",
+			expectedErr:    ErrBlockOrphaned,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+			cf, err := newERC20CurrencyFetcher(mockJSONRPC)
+			assert.NoError(t, err)
+
+			c := &Client{
+				c:               mockJSONRPC,
+				g:               mockGraphQL,
+				currencyFetcher: cf,
+				traceSemaphore:  semaphore.NewWeighted(100),
+			}
+
+			ctx := context.Background()
+			hash := "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae"
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByHash",
+				hash,
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(**types.Header)
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+					assert.NoError(t, json.Unmarshal(file, r))
+				},
+			).Once()
+
+			snapshot, err := c.SnapshotAt(ctx, &RosettaTypes.PartialBlockIdentifier{
+				Hash: &hash,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, &RosettaTypes.BlockIdentifier{
+				Hash:  hash,
+				Index: 10992,
+			}, snapshot.BlockIdentifier)
+
+			// Later reads are made by hash, with the canonical header
+			// at the pinned height fetched in the same batch
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getBlockByHash",
+				&hash,
+				false,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).(*json.RawMessage)
+					file, err := ioutil.ReadFile("testdata/block_10992.json")
+					assert.NoError(t, err)
+					*r = json.RawMessage(file)
+				},
+			).Once()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(rpcs []rpc.BatchElem) bool {
+					return len(rpcs) == 4 && rpcs[3].Method == "eth_getBlockByNumber" && rpcs[3].Args[0] == "0x2af0"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					r := args.Get(1).([]rpc.BatchElem)
+					*(r[0].Result.(**hexutil.Big)) = (*hexutil.Big)(big.NewInt(100))
+					*(r[1].Result.(**hexutil.Uint64)) = new(hexutil.Uint64)
+					*(r[2].Result.(**string)) = RosettaTypes.String("0x")
+
+					file, err := ioutil.ReadFile(test.canonicalBlock)
+					assert.NoError(t, err)
+					assert.NoError(t, json.Unmarshal(file, r[3].Result))
+				},
+			).Once()
+
+			resp, err := snapshot.Balance(
+				ctx,
+				&RosettaTypes.AccountIdentifier{
+					Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+				},
+				[]*RosettaTypes.Currency{Currency},
+			)
+			if test.expectedErr != nil {
+				assert.Nil(t, resp)
+				assert.True(t, errors.Is(err, test.expectedErr))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, snapshot.BlockIdentifier, resp.BlockIdentifier)
+				assert.Equal(t, "100", resp.Balances[0].Value)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSnapshot_BlockOrphaned(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{
+		c:              mockJSONRPC,
+		traceSemaphore: semaphore.NewWeighted(100),
+	}
+
+	ctx := context.Background()
+	snapshot := &Snapshot{
+		ec: c,
+		BlockIdentifier: &RosettaTypes.BlockIdentifier{
+			Hash:  "0xba9ded5ca1ec9adb9451bf062c9de309d9552fa0f0254a7b982d3daf7ae436ae",
+			Index: 10992,
+		},
+	}
+
+	// Only the header is fetched, so the orphaned block is not traced
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"0x2af0",
+		false,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			r := args.Get(1).(**types.Header)
+			file, err := ioutil.ReadFile("testdata/block_10991.json")
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(file, r))
+		},
+	).Once()
+
+	block, err := snapshot.Block(ctx)
+	assert.Nil(t, block)
+	assert.True(t, errors.Is(err, ErrBlockOrphaned))
+
+	mockJSONRPC.AssertExpectations(t)
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+)
+
+// GetStorageAtMethod is the call method that returns
+// the raw value of a storage slot of a contract.
+const GetStorageAtMethod = "eth_getStorageAt"
+
+// GetStorageAtInput is the input to GetStorageAtMethod. Position is
+// the slot, as hex (0x-prefixed) or decimal. The slot is read at Index
+// or Hash, or at the current block if neither is set. The index takes
+// precedence over the hash.
+type GetStorageAtInput struct {
+	Address  string  `json:"address"`
+	Position string  `json:"position"`
+	Index    *int64  `json:"index,omitempty"`
+	Hash     *string `json:"hash,omitempty"`
+}
+
+// parseStoragePosition parses a hex or decimal storage slot.
+func parseStoragePosition(position string) (common.Hash, error) {
+	if strings.HasPrefix(position, "0x") {
+		return parseStorageKey(position)
+	}
+
+	slot, ok := new(big.Int).SetString(position, 10)
+	if !ok || slot.Sign() < 0 || slot.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("invalid storage position %s", position)
+	}
+
+	return common.BigToHash(slot), nil
+}
+
+// storageAtBlock returns the block argument of eth_getStorageAt for
+// input, and whether it pins a specific block.
+func storageAtBlock(input *GetStorageAtInput) (string, bool, error) {
+	switch {
+	case input.Index != nil && *input.Index < 0:
+		return "", false, fmt.Errorf("%w: index %d is negative", ErrCallParametersInvalid, *input.Index)
+	case input.Index != nil:
+		return toBlockNumArg(big.NewInt(*input.Index)), true, nil
+	case input.Hash != nil:
+		hash, err := hexutil.Decode(*input.Hash)
+		if err != nil || len(hash) != common.HashLength {
+			return "", false, fmt.Errorf("%w: invalid block hash %s", ErrCallParametersInvalid, *input.Hash)
+		}
+		return *input.Hash, true, nil
+	default:
+		return toBlockNumArg(nil), false, nil
+	}
+}
+
+// storageAt reads the storage slot described by params. The returned
+// bool is true if the slot was read at a specific block, so the result
+// never changes.
+func (ec *Client) storageAt(
+	ctx context.Context,
+	params map[string]interface{},
+) (map[string]interface{}, bool, error) {
+	var input GetStorageAtInput
+	if err := RosettaTypes.UnmarshalMap(params, &input); err != nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+
+	address, err := ValidateAddress("address", input.Address)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	position, err := parseStoragePosition(input.Position)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s", ErrCallParametersInvalid, err.Error())
+	}
+	blockArg, pinned, err := storageAtBlock(&input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var value hexutil.Bytes
+	if err := ec.c.CallContext(ctx, &value, "eth_getStorageAt", address.Hex(), position.Hex(), blockArg); err != nil {
+		return nil, false, historicalStateError(err)
+	}
+
+	return map[string]interface{}{
+		"value": common.BytesToHash(value).Hex(),
+	}, pinned, nil
+}
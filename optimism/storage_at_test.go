@@ -0,0 +1,202 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCall_GetStorageAt(t *testing.T) {
+	address := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+	blockHash := "0x5ddc90fb7dca0f218764a9666e1506bbd458a0f8e1cc444aacae3a9a7d6acdeb"
+
+	tests := map[string]struct {
+		params map[string]interface{}
+
+		expectedPosition   string
+		expectedBlock      string
+		expectedIdempotent bool
+	}{
+		"latest": {
+			params: map[string]interface{}{
+				"address":  address,
+				"position": "0x0",
+			},
+			expectedPosition: "0x0000000000000000000000000000000000000000000000000000000000000000",
+			expectedBlock:    "latest",
+		},
+		"historical index": {
+			params: map[string]interface{}{
+				"address":  address,
+				"position": "10",
+				"index":    1241187,
+			},
+			expectedPosition:   "0x000000000000000000000000000000000000000000000000000000000000000a",
+			expectedBlock:      "0x12f063",
+			expectedIdempotent: true,
+		},
+		"historical hash": {
+			params: map[string]interface{}{
+				"address":  address,
+				"position": "0x0a",
+				"hash":     blockHash,
+			},
+			expectedPosition:   "0x000000000000000000000000000000000000000000000000000000000000000a",
+			expectedBlock:      blockHash,
+			expectedIdempotent: true,
+		},
+		"genesis": {
+			params: map[string]interface{}{
+				"address":  address,
+				"position": "1",
+				"index":    0,
+			},
+			expectedPosition:   "0x0000000000000000000000000000000000000000000000000000000000000001",
+			expectedBlock:      "0x0",
+			expectedIdempotent: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			c := &Client{c: mockJSONRPC}
+			ctx := context.Background()
+
+			mockJSONRPC.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_getStorageAt",
+				address,
+				test.expectedPosition,
+				test.expectedBlock,
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					*args.Get(1).(*hexutil.Bytes) = hexutil.MustDecode("0x2a")
+				},
+			).Once()
+
+			resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+				Method:     GetStorageAtMethod,
+				Parameters: test.params,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, &RosettaTypes.CallResponse{
+				Result: map[string]interface{}{
+					"value": "0x000000000000000000000000000000000000000000000000000000000000002a",
+				},
+				Idempotent: test.expectedIdempotent,
+			}, resp)
+
+			mockJSONRPC.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCall_GetStorageAt_StatePruned(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC}
+	ctx := context.Background()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getStorageAt",
+		"0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+		"0x0000000000000000000000000000000000000000000000000000000000000000",
+		"0x1",
+	).Return(
+		&jsonError{code: -32000, message: "missing trie node 1a2b"},
+	).Once()
+
+	resp, err := c.Call(ctx, &RosettaTypes.CallRequest{
+		Method: GetStorageAtMethod,
+		Parameters: map[string]interface{}{
+			"address":  "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55",
+			"position": "0",
+			"index":    1,
+		},
+	})
+	assert.Nil(t, resp)
+	assert.True(t, errors.Is(err, ErrStatePruned))
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestCall_GetStorageAt_InvalidInput(t *testing.T) {
+	address := "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"
+
+	tests := map[string]map[string]interface{}{
+		"position too large": {
+			"address":  address,
+			"position": "0x1" + "0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		"decimal position too large": {
+			"address":  address,
+			"position": "115792089237316195423570985008687907853269984665640564039457584007913129639936",
+		},
+		"negative position": {
+			"address":  address,
+			"position": "-1",
+		},
+		"malformed position": {
+			"address":  address,
+			"position": "0xzz",
+		},
+		"missing position": {
+			"address": address,
+		},
+		"invalid address": {
+			"address":  "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D5",
+			"position": "0x0",
+		},
+		"negative index": {
+			"address":  address,
+			"position": "0x0",
+			"index":    -1,
+		},
+		"invalid hash": {
+			"address":  address,
+			"position": "0x0",
+			"hash":     "0x5ddc",
+		},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &Client{}
+
+			resp, err := c.Call(context.Background(), &RosettaTypes.CallRequest{
+				Method:     GetStorageAtMethod,
+				Parameters: params,
+			})
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrCallParametersInvalid))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+const (
+	// DefaultTipSourceTolerance is the number of blocks the node may
+	// be behind the tip source while still reported as synced.
+	DefaultTipSourceTolerance = 10
+
+	// tipSourceTimeout bounds requests to the tip source, so a slow
+	// tip source only delays Status briefly before it is ignored.
+	tipSourceTimeout = 10 * time.Second
+)
+
+// tipSource is a second node, usually the sequencer, whose head is
+// the target of a verifier that lags the chain tip.
+type tipSource struct {
+	c         JSONRPC
+	tolerance int64
+}
+
+// WithTipSource makes Status report the head of the node at endpoint,
+// e.g. the sequencer, as the target index. The local node is reported
+// as synced while it is at most tolerance blocks behind it. A
+// non-positive tolerance uses DefaultTipSourceTolerance. If the tip
+// source can't be reached, Status only reports the local node.
+func (ec *Client) WithTipSource(endpoint string, tolerance int64) (*Client, error) {
+	if len(endpoint) == 0 {
+		return ec, nil
+	}
+
+	c, err := rpc.DialHTTPWithClient(endpoint, &http.Client{
+		Timeout: tipSourceTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to dial tip source", err)
+	}
+
+	if tolerance <= 0 {
+		tolerance = DefaultTipSourceTolerance
+	}
+
+	log.Printf("reporting the head of %s as the target index", endpoint)
+	ec.tipSource = &tipSource{c: c, tolerance: tolerance}
+	return ec, nil
+}
+
+// head returns the number of the latest block of the tip source.
+func (t *tipSource) head(ctx context.Context) (int64, error) {
+	var head hexutil.Uint64
+	if err := t.c.CallContext(ctx, &head, "eth_blockNumber"); err != nil {
+		return -1, err
+	}
+
+	return int64(head), nil
+}
+
+// adjust raises the target index of status to the head of the tip
+// source and recomputes Synced from how far the current index is
+// behind it. A node that reports it is syncing is never synced.
+// status is left as is if the tip source fails.
+func (t *tipSource) adjust(ctx context.Context, status *RosettaTypes.SyncStatus, syncing bool) {
+	head, err := t.head(ctx)
+	if err != nil {
+		log.Printf("%s: unable to fetch the tip source head, reporting the local sync status", err)
+		return
+	}
+
+	if head > *status.TargetIndex {
+		status.TargetIndex = RosettaTypes.Int64(head)
+	}
+
+	lag := *status.TargetIndex - *status.CurrentIndex
+	status.Synced = RosettaTypes.Bool(!syncing && lag <= t.tolerance)
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	RosettaTypes "github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockLocalStatus mocks a local node at block 8916656 that
+// replies to eth_syncing with syncingFile and doesn't expose
+// the admin API.
+func mockLocalStatus(
+	ctx context.Context,
+	t *testing.T,
+	mockJSONRPC *mocks.JSONRPC,
+	syncingFile string,
+) {
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_getBlockByNumber",
+		"latest",
+		false,
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			header := args.Get(1).(**types.Header)
+			file, err := ioutil.ReadFile("testdata/basic_header.json")
+			assert.NoError(t, err)
+
+			*header = new(types.Header)
+			assert.NoError(t, (*header).UnmarshalJSON(file))
+		},
+	).Once()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"eth_syncing",
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			file, err := ioutil.ReadFile(syncingFile)
+			assert.NoError(t, err)
+
+			*args.Get(1).(*json.RawMessage) = json.RawMessage(file)
+		},
+	).Once()
+
+	mockJSONRPC.On(
+		"CallContext",
+		ctx,
+		mock.Anything,
+		"admin_peers",
+	).Return(
+		&jsonError{code: -32601, message: "The method admin_peers does not exist/is not available"},
+	).Once()
+}
+
+func TestStatus_TipSource(t *testing.T) {
+	tests := map[string]struct {
+		syncingFile string
+		tip         uint64
+		tipErr      error
+
+		expected *RosettaTypes.SyncStatus
+	}{
+		"lagging": {
+			syncingFile: "testdata/syncing_false.json",
+			tip:         8916700,
+			expected: &RosettaTypes.SyncStatus{
+				CurrentIndex: RosettaTypes.Int64(8916656),
+				TargetIndex:  RosettaTypes.Int64(8916700),
+				Synced:       RosettaTypes.Bool(false),
+			},
+		},
+		"within tolerance": {
+			syncingFile: "testdata/syncing_false.json",
+			tip:         8916666,
+			expected: &RosettaTypes.SyncStatus{
+				CurrentIndex: RosettaTypes.Int64(8916656),
+				TargetIndex:  RosettaTypes.Int64(8916666),
+				Synced:       RosettaTypes.Bool(true),
+			},
+		},
+		"tip source behind": {
+			syncingFile: "testdata/syncing_false.json",
+			tip:         8916600,
+			expected: &RosettaTypes.SyncStatus{
+				CurrentIndex: RosettaTypes.Int64(8916656),
+				TargetIndex:  RosettaTypes.Int64(8916656),
+				Synced:       RosettaTypes.Bool(true),
+			},
+		},
+		"syncing": {
+			syncingFile: "testdata/syncing_info.json",
+			tip:         8916770,
+			expected: &RosettaTypes.SyncStatus{
+				CurrentIndex: RosettaTypes.Int64(25),
+				TargetIndex:  RosettaTypes.Int64(8916770),
+				Synced:       RosettaTypes.Bool(false),
+			},
+		},
+		"failing": {
+			syncingFile: "testdata/syncing_false.json",
+			tipErr:      errors.New("connection refused"),
+			expected: &RosettaTypes.SyncStatus{
+				CurrentIndex: RosettaTypes.Int64(8916656),
+				TargetIndex:  RosettaTypes.Int64(8916656),
+				Synced:       RosettaTypes.Bool(true),
+			},
+		},
+		"failing while syncing": {
+			syncingFile: "testdata/syncing_info.json",
+			tipErr:      errors.New("connection refused"),
+			expected: &RosettaTypes.SyncStatus{
+				CurrentIndex: RosettaTypes.Int64(25),
+				TargetIndex:  RosettaTypes.Int64(8916760),
+				Synced:       RosettaTypes.Bool(false),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockTipSource := &mocks.JSONRPC{}
+			c := &Client{
+				c: mockJSONRPC,
+				tipSource: &tipSource{
+					c:         mockTipSource,
+					tolerance: DefaultTipSourceTolerance,
+				},
+			}
+			ctx := context.Background()
+
+			mockLocalStatus(ctx, t, mockJSONRPC, test.syncingFile)
+			mockTipSource.On(
+				"CallContext",
+				ctx,
+				mock.Anything,
+				"eth_blockNumber",
+			).Return(
+				test.tipErr,
+			).Run(
+				func(args mock.Arguments) {
+					if test.tipErr == nil {
+						*args.Get(1).(*hexutil.Uint64) = hexutil.Uint64(test.tip)
+					}
+				},
+			).Once()
+
+			block, _, syncStatus, _, err := c.Status(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, int64(8916656), block.Index)
+			assert.Equal(t, test.expected, syncStatus)
+
+			mockJSONRPC.AssertExpectations(t)
+			mockTipSource.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWithTipSource(t *testing.T) {
+	c := &Client{}
+
+	c, err := c.WithTipSource("", 5)
+	assert.NoError(t, err)
+	assert.Nil(t, c.tipSource)
+
+	c, err = c.WithTipSource("http://localhost:8545", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(DefaultTipSourceTolerance), c.tipSource.tolerance)
+
+	c, err = c.WithTipSource("http://localhost:8545", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), c.tipSource.tolerance)
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"strings"
+)
+
+// movesValue returns true if call changes an ETH balance, either by
+// transferring value or by minting or burning OVM_ETH.
+func movesValue(call *Call) bool {
+	if call.Value != nil && call.Value.Sign() != 0 {
+		return true
+	}
+
+	return call.Type == CallOpType &&
+		call.To == ovmEthAddr &&
+		(strings.HasPrefix(call.Input, burnSelector) || strings.HasPrefix(call.Input, mintSelector))
+}
+
+// limitTraceDepth returns a copy of the trace rooted at root without
+// the frames nested deeper than maxDepth (the root is at depth 0).
+// Frames that move value are kept regardless of depth, so balances
+// stay correct, but are flattened into their ancestor at maxDepth:
+// the operations of the calls between them are lost, and with them
+// where the value came from within the transaction. A maxDepth of 0
+// or less returns root as is.
+func limitTraceDepth(root *Call, maxDepth int) *Call {
+	if root == nil || maxDepth <= 0 {
+		return root
+	}
+
+	return limitCallDepth(root, 0, maxDepth)
+}
+
+func limitCallDepth(call *Call, depth int, maxDepth int) *Call {
+	limited := *call
+	limited.Calls = nil
+	for _, child := range call.Calls {
+		if child == nil {
+			continue
+		}
+
+		if depth < maxDepth {
+			limited.Calls = append(limited.Calls, limitCallDepth(child, depth+1, maxDepth))
+			continue
+		}
+
+		limited.Calls = appendValueCalls(limited.Calls, child, call)
+	}
+
+	return &limited
+}
+
+// appendValueCalls appends the frames of the trace rooted at call that
+// move value, without their children, to calls. Reverts are inherited
+// from the dropped frames, as flattenTraces would have done.
+func appendValueCalls(calls []*Call, call *Call, parent *Call) []*Call {
+	frame := *call
+	frame.Calls = nil
+	if parent.Revert {
+		frame.Revert = true
+		if len(frame.ErrorMessage) == 0 {
+			frame.ErrorMessage = parent.ErrorMessage
+		}
+	}
+
+	if movesValue(&frame) {
+		calls = append(calls, &frame)
+	}
+
+	for _, child := range call.Calls {
+		if child == nil {
+			continue
+		}
+		calls = appendValueCalls(calls, child, &frame)
+	}
+
+	return calls
+}
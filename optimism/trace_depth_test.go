@@ -0,0 +1,126 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// nestedTrace returns a trace of depth+1 frames, each calling the
+// next, whose values are given by values (zero if not present).
+func nestedTrace(depth int, values map[int]int64) *Call {
+	var child *Call
+	for i := depth; i >= 0; i-- {
+		call := &Call{
+			Type:    CallOpType,
+			From:    common.BigToAddress(big.NewInt(int64(i + 1))),
+			To:      common.BigToAddress(big.NewInt(int64(i + 2))),
+			Value:   big.NewInt(values[i]),
+			GasUsed: new(big.Int),
+		}
+		if child != nil {
+			call.Calls = []*Call{child}
+		}
+		child = call
+	}
+
+	return child
+}
+
+func maxCallDepth(call *Call) int {
+	depth := 0
+	for _, child := range call.Calls {
+		if d := maxCallDepth(child) + 1; d > depth {
+			depth = d
+		}
+	}
+
+	return depth
+}
+
+// valueOps returns the account and value of the operations that
+// change a balance.
+func valueOps(call *Call) [][2]string {
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), nil)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, []*types.Transaction{tx}, nil, nil)
+
+	var ops [][2]string
+	for _, op := range traceOps(block, flattenTraces(call, []*flatCall{}), 0) {
+		if op.Amount != nil {
+			ops = append(ops, [2]string{op.Account.Address, op.Amount.Value})
+		}
+	}
+
+	return ops
+}
+
+func TestLimitTraceDepth(t *testing.T) {
+	values := map[int]int64{0: 10, 7: 5, 40: 3}
+	trace := nestedTrace(50, values)
+
+	limited := limitTraceDepth(trace, 3)
+	assert.Equal(t, 50, maxCallDepth(trace), "the trace must not be modified")
+	assert.Equal(t, 4, maxCallDepth(limited))
+
+	calls := flattenTraces(limited, []*flatCall{})
+	assert.Len(t, calls, 6)
+	assert.Equal(t, big.NewInt(5), calls[4].Value)
+	assert.Equal(t, big.NewInt(3), calls[5].Value)
+	assert.Equal(t, valueOps(trace), valueOps(limited))
+}
+
+func TestLimitTraceDepth_Unlimited(t *testing.T) {
+	trace := nestedTrace(50, nil)
+	assert.Same(t, trace, limitTraceDepth(trace, 0))
+	assert.Nil(t, limitTraceDepth(nil, 3))
+}
+
+func TestLimitTraceDepth_InheritsRevert(t *testing.T) {
+	trace := nestedTrace(10, map[int]int64{8: 5})
+
+	// Revert the frame at depth 5, which is dropped.
+	reverted := trace
+	for i := 0; i < 5; i++ {
+		reverted = reverted.Calls[0]
+	}
+	reverted.Revert = true
+	reverted.ErrorMessage = "execution reverted"
+
+	calls := flattenTraces(limitTraceDepth(trace, 2), []*flatCall{})
+	assert.Len(t, calls, 4)
+	assert.False(t, calls[2].Revert)
+	assert.True(t, calls[3].Revert)
+	assert.Equal(t, "execution reverted", calls[3].ErrorMessage)
+	assert.Equal(t, big.NewInt(5), calls[3].Value)
+}
+
+func TestLimitTraceDepth_OVMETH(t *testing.T) {
+	trace := nestedTrace(5, nil)
+	mint := trace.Calls[0].Calls[0].Calls[0]
+	mint.To = ovmEthAddr
+	mint.Input = mintSelector +
+		"0000000000000000000000002f93b2f047e05cdf602820ac4b3178efc2b43d55" +
+		"00000000000000000000000000000000000000000000000000000000000003e8"
+
+	calls := flattenTraces(limitTraceDepth(trace, 1), []*flatCall{})
+	assert.Len(t, calls, 3)
+	assert.Equal(t, ovmEthAddr, calls[2].To)
+	assert.Equal(t, valueOps(trace), valueOps(limitTraceDepth(trace, 1)))
+}
@@ -0,0 +1,79 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"errors"
+	"expvar"
+)
+
+const (
+	// TraceDiagnosticsKey is the block metadata key populated
+	// when operations were derived from degraded traces.
+	TraceDiagnosticsKey = "trace_diagnostics"
+
+	// IntendedValueKey is the transaction metadata key holding the
+	// value of a failed transaction converted without its trace.
+	IntendedValueKey = "intended_value"
+)
+
+var (
+	errTraceTimedOut  = errors.New("trace timed out")
+	errTraceTruncated = errors.New("trace truncated")
+)
+
+// traceDiagnosticsMetrics holds running totals of TraceDiagnostics
+// across all parsed blocks, published under /debug/vars.
+var traceDiagnosticsMetrics = expvar.NewMap(TraceDiagnosticsKey)
+
+// TraceDiagnostics counts the tracer problems encountered while
+// converting a block. Any nonzero counter means some operations
+// in the block may be missing.
+type TraceDiagnostics struct {
+	TimedOutTraces      int64 `json:"timed_out_traces"`
+	TruncatedTraces     int64 `json:"truncated_traces"`
+	UnknownFrameTypes   int64 `json:"unknown_frame_types"`
+	SkippedTransactions int64 `json:"skipped_transactions"`
+}
+
+// empty returns true if no problems were recorded.
+func (d *TraceDiagnostics) empty() bool {
+	return *d == TraceDiagnostics{}
+}
+
+// metadata returns the diagnostics as block metadata.
+func (d *TraceDiagnostics) metadata() map[string]interface{} {
+	return map[string]interface{}{
+		"timed_out_traces":     d.TimedOutTraces,
+		"truncated_traces":     d.TruncatedTraces,
+		"unknown_frame_types":  d.UnknownFrameTypes,
+		"skipped_transactions": d.SkippedTransactions,
+	}
+}
+
+// publish adds the diagnostics to the process-wide metrics.
+func (d *TraceDiagnostics) publish() {
+	traceDiagnosticsMetrics.Add("timed_out_traces", d.TimedOutTraces)
+	traceDiagnosticsMetrics.Add("truncated_traces", d.TruncatedTraces)
+	traceDiagnosticsMetrics.Add("unknown_frame_types", d.UnknownFrameTypes)
+	traceDiagnosticsMetrics.Add("skipped_transactions", d.SkippedTransactions)
+	traceDiagnosticsMetrics.Add("degraded_blocks", 1)
+}
+
+// traceFrameType returns true if t is a call frame type
+// emitted by the tracer.
+func traceFrameType(t string) bool {
+	return CallType(t) || CreateType(t) || t == SelfDestructOpType
+}
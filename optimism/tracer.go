@@ -16,12 +16,16 @@ package optimism
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/l2geth/common"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	lru "github.com/hashicorp/golang-lru"
 )
@@ -32,16 +36,48 @@ const (
 	defaultTracerPath = "optimism/call_tracer.js"
 )
 
+// nativeCallTracer is the name of the call tracer built into geth.
+const nativeCallTracer = "callTracer"
+
+// CallTracerConfig is the tracerConfig of the native callTracer.
+type CallTracerConfig struct {
+	// OnlyTopCall skips the internal calls of each transaction,
+	// so their value transfers are not converted to operations.
+	OnlyTopCall bool `json:"onlyTopCall,omitempty"`
+
+	// WithLog adds the logs emitted by each call to its frame.
+	WithLog bool `json:"withLog,omitempty"`
+}
+
 type tracerSpec struct {
 	TracerPath    string
 	UseGethTracer bool
+
+	// UseNativeTracer selects the native callTracer,
+	// configured with NativeTracerConfig.
+	UseNativeTracer    bool
+	NativeTracerConfig CallTracerConfig
+}
+
+// traceConfig is the trace config passed to debug_traceTransaction.
+// TracerConfig is only understood by native tracers.
+type traceConfig struct {
+	*tracers.TraceConfig
+	TracerConfig *CallTracerConfig `json:"tracerConfig,omitempty"`
 }
 
-func loadTraceConfig(opt tracerSpec, timeout time.Duration) (*tracers.TraceConfig, error) {
+func loadTraceConfig(opt tracerSpec, timeout time.Duration) (*traceConfig, error) {
 	var loadedTracer string
-	if opt.UseGethTracer {
+	var tracerConfig *CallTracerConfig
+	switch {
+	case opt.UseGethTracer && opt.UseNativeTracer:
+		return nil, errors.New("the geth tracer and the native tracer cannot both be enabled")
+	case opt.UseGethTracer:
 		loadedTracer = "rosetta"
-	} else {
+	case opt.UseNativeTracer:
+		loadedTracer = nativeCallTracer
+		tracerConfig = &opt.NativeTracerConfig
+	default:
 		loadedFile, err := ioutil.ReadFile(opt.TracerPath)
 		if err != nil {
 			return nil, fmt.Errorf("%w: could not load tracer file", err)
@@ -49,9 +85,12 @@ func loadTraceConfig(opt tracerSpec, timeout time.Duration) (*tracers.TraceConfi
 		loadedTracer = string(loadedFile)
 	}
 	tracerTimeout := fmt.Sprintf("%ds", int(timeout.Seconds()))
-	return &tracers.TraceConfig{
-		Timeout: &tracerTimeout,
-		Tracer:  &loadedTracer,
+	return &traceConfig{
+		TraceConfig: &tracers.TraceConfig{
+			Timeout: &tracerTimeout,
+			Tracer:  &loadedTracer,
+		},
+		TracerConfig: tracerConfig,
 	}, nil
 }
 
@@ -67,7 +106,7 @@ type TraceCache interface {
 
 type traceCache struct {
 	client        JSONRPC
-	tc            *tracers.TraceConfig
+	tc            *traceConfig
 	tracerTimeout time.Duration
 	cache         *lru.Cache
 	m             sync.Mutex
@@ -130,3 +169,157 @@ func (t *traceCache) requestTrace(txhash common.Hash, entry *traceCacheEntry) {
 		t.m.Unlock()
 	}
 }
+
+// TraceProvider is the interface for fetching normalized call traces.
+// The default implementation issues debug_traceTransaction requests to
+// the node, but an external tracing service may be plugged in here
+// without touching Block conversion. TraceBlock may return a
+// *PartialTraceError for transactions that could not be traced, which
+// fails the block unless SkipDegradedTraces is set.
+type TraceProvider interface {
+	TraceBlock(ctx context.Context, blockHash common.Hash, txHashes []common.Hash) ([]*Call, error)
+	TraceTransaction(ctx context.Context, txHash common.Hash) (*Call, error)
+}
+
+// PartialTraceError is returned by TraceProvider.TraceBlock, along with
+// the traces that succeeded, when some transactions could not be traced
+// because the tracer hit its limits. Their traces are nil.
+type PartialTraceError struct {
+	TimedOut  []common.Hash
+	Truncated []common.Hash
+}
+
+func (e *PartialTraceError) Error() string {
+	return fmt.Sprintf(
+		"%d traces timed out and %d traces were truncated",
+		len(e.TimedOut),
+		len(e.Truncated),
+	)
+}
+
+// isTraceTimeout returns true if the tracer was stopped by
+// its per-transaction timeout.
+func isTraceTimeout(err error) bool {
+	return strings.Contains(err.Error(), "execution timeout")
+}
+
+// isTraceTruncated returns true if the trace result was cut short,
+// either by the node or in transit, and could not be decoded.
+func isTraceTruncated(err error) bool {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return true
+	}
+
+	return strings.Contains(err.Error(), "response too large")
+}
+
+// debugTraceProvider fetches traces using debug_traceTransaction
+// and the configured tracer.
+type debugTraceProvider struct {
+	c     JSONRPC
+	tc    *traceConfig
+	cache TraceCache
+}
+
+func (d *debugTraceProvider) TraceBlock(
+	ctx context.Context,
+	blockHash common.Hash,
+	txHashes []common.Hash,
+) ([]*Call, error) {
+	traces := make([]*Call, len(txHashes))
+	if len(txHashes) == 0 {
+		return traces, nil
+	}
+
+	if d.cache != nil {
+		for i := range txHashes {
+			result, err := d.cache.FetchTransaction(ctx, txHashes[i])
+			if err != nil {
+				return nil, err
+			}
+			traces[i] = result
+		}
+		return traces, nil
+	}
+
+	reqs := make([]rpc.BatchElem, len(txHashes))
+	// TODO(inphi): Run this sequentially to avoid DoS'ing l2geth
+	for i := range reqs {
+		reqs[i] = rpc.BatchElem{
+			Method: "debug_traceTransaction",
+			Args:   []interface{}{txHashes[i].Hex(), d.tc},
+			Result: &traces[i],
+		}
+	}
+	if err := d.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, err
+	}
+
+	partial := &PartialTraceError{}
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			// Traces that hit the tracer's limits are reported apart so
+			// the block can be parsed without them if allowed.
+			switch {
+			case isTraceTimeout(reqs[i].Error):
+				traces[i] = nil
+				partial.TimedOut = append(partial.TimedOut, txHashes[i])
+				continue
+			case isTraceTruncated(reqs[i].Error):
+				traces[i] = nil
+				partial.Truncated = append(partial.Truncated, txHashes[i])
+				continue
+			}
+			return nil, reqs[i].Error
+		}
+		if traces[i] == nil {
+			return nil, fmt.Errorf("got empty trace for %x", txHashes[i].Hex())
+		}
+	}
+
+	if len(partial.TimedOut) > 0 || len(partial.Truncated) > 0 {
+		return traces, partial
+	}
+
+	return traces, nil
+}
+
+func (d *debugTraceProvider) TraceTransaction(ctx context.Context, txHash common.Hash) (*Call, error) {
+	if d.cache != nil {
+		return d.cache.FetchTransaction(ctx, txHash)
+	}
+
+	var trace *Call
+	if err := d.c.CallContext(ctx, &trace, "debug_traceTransaction", txHash.Hex(), d.tc); err != nil {
+		return nil, err
+	}
+	if trace == nil {
+		return nil, fmt.Errorf("got empty trace for %x", txHash.Hex())
+	}
+
+	return trace, nil
+}
+
+// StructLogResult is the result of debug_traceTransaction when run
+// with the default struct logger.
+type StructLogResult struct {
+	Gas         uint64      `json:"gas"`
+	Failed      bool        `json:"failed"`
+	ReturnValue string      `json:"returnValue"`
+	StructLogs  []StructLog `json:"structLogs"`
+}
+
+// StructLog is a single opcode step emitted by the struct logger.
+// Stack, memory and storage are omitted when disabled in the request.
+type StructLog struct {
+	Pc      uint64            `json:"pc"`
+	Op      string            `json:"op"`
+	Gas     uint64            `json:"gas"`
+	GasCost uint64            `json:"gasCost"`
+	Depth   int               `json:"depth"`
+	Error   string            `json:"error,omitempty"`
+	Stack   []string          `json:"stack,omitempty"`
+	Memory  []string          `json:"memory,omitempty"`
+	Storage map[string]string `json:"storage,omitempty"`
+}
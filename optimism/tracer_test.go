@@ -2,6 +2,7 @@ package optimism
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -110,3 +111,35 @@ func TestTracerFetchErroneousCacheEntry(t *testing.T) {
 	_, ok := cache.(*traceCache).cache.Peek(common.Hash{}.String())
 	assert.False(t, ok)
 }
+
+func TestLoadTraceConfig_NativeTracer(t *testing.T) {
+	tc, err := loadTraceConfig(tracerSpec{
+		TracerPath:         "call_tracer.js",
+		UseNativeTracer:    true,
+		NativeTracerConfig: CallTracerConfig{WithLog: true},
+	}, time.Second*5)
+	assert.NoError(t, err)
+
+	// The embedded JS tracer is not sent to the node
+	encoded, err := json.Marshal(tc)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"Tracer": "callTracer",
+		"Timeout": "5s",
+		"Reexec": null,
+		"tracerConfig": {"withLog": true}
+	}`, string(encoded))
+
+	_, err = loadTraceConfig(tracerSpec{UseGethTracer: true, UseNativeTracer: true}, time.Second)
+	assert.Error(t, err)
+}
+
+func TestLoadTraceConfig_JSTracer(t *testing.T) {
+	tc, err := loadTraceConfig(tracerSpec{TracerPath: "call_tracer.js"}, time.Second)
+	assert.NoError(t, err)
+
+	// Tracers other than callTracer don't take a tracerConfig
+	encoded, err := json.Marshal(tc)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(encoded), "tracerConfig")
+}
@@ -53,6 +53,18 @@ const (
 
 	TokenDecimals = 18
 
+	// LegacyTxTypeName is the type name of untyped transactions.
+	LegacyTxTypeName = "legacy"
+
+	// AccessListTxTypeName is the type name of EIP-2930 transactions.
+	AccessListTxTypeName = "access_list"
+
+	// DynamicFeeTxTypeName is the type name of EIP-1559 transactions.
+	DynamicFeeTxTypeName = "dynamic_fee"
+
+	// DepositTxTypeName is the type name of Optimism deposit transactions.
+	DepositTxTypeName = "optimism_deposit"
+
 	// FeeOpType is used to represent fee operations.
 	FeeOpType = "FEE"
 
@@ -94,6 +106,10 @@ const (
 	// DelegateVotesOpType is used to represent OZ ERC20Votes votes delegation
 	DelegateVotesOpType = "DELEGATE_VOTES"
 
+	// MintOpType is used to represent ETH minted on L2
+	// by a deposit transaction from L1.
+	MintOpType = "MINT"
+
 	// SuccessStatus is the status of any
 	// Ethereum operation considered successful.
 	SuccessStatus = "SUCCESS"
@@ -183,6 +199,7 @@ var (
 		StaticCallOpType,
 		DestructOpType,
 		DelegateVotesOpType,
+		MintOpType,
 	}
 
 	// OperationStatuses are all supported operation statuses.
@@ -200,9 +217,27 @@ var (
 	// CallMethods are all supported call methods.
 	CallMethods = []string{
 		"eth_getBlockByNumber",
+		"block_with_receipts",
 		"eth_getTransactionReceipt",
 		"eth_call",
+		"eth_getBalance",
+		"eth_getCode",
+		CallDiffMethod,
 		"eth_estimateGas",
+		"eth_getLogs",
+		GetProofMethod,
+		GetStorageAtMethod,
+		"debug_traceTransaction",
+		"eth_chainId",
+		"net_version",
+		DecodeTransactionMethod,
+		FinalizedOffsetMethod,
+		AllowanceMethod,
+		BlockNoTraceMethod,
+		RollupInfoMethod,
+		BlockOperationsMethod,
+		BalanceHistoryMethod,
+		SendBundleMethod,
 	}
 )
 
@@ -215,7 +250,7 @@ type JSONRPC interface {
 
 // GraphQL is the interface for accessing go-ethereum's GraphQL endpoint.
 type GraphQL interface {
-	Query(ctx context.Context, input string) (string, error)
+	Query(ctx context.Context, input string, variables map[string]interface{}) (string, error)
 }
 
 // CallType returns a boolean indicating
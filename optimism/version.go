@@ -0,0 +1,149 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum-optimism/optimism/l2geth/common/hexutil"
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+)
+
+// Version is the version information reported by the node.
+type Version struct {
+	// Client is the node's web3_clientVersion,
+	// e.g. "Geth/v0.5.11/linux-amd64/go1.15.14".
+	Client string
+
+	// ChainID is the network id reported by net_version.
+	ChainID *big.Int
+
+	// Protocol is the eth_protocolVersion of the node.
+	Protocol uint64
+}
+
+// Version returns the client, network and protocol versions
+// of the node, fetched in a single batch request.
+func (ec *Client) Version(ctx context.Context) (*Version, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	var (
+		client   string
+		netID    string
+		protocol hexutil.Uint64
+	)
+	reqs := []rpc.BatchElem{
+		{Method: "web3_clientVersion", Result: &client},
+		{Method: "net_version", Result: &netID},
+		{Method: "eth_protocolVersion", Result: &protocol},
+	}
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, err
+	}
+	for i := range reqs {
+		if reqs[i].Error != nil {
+			return nil, fmt.Errorf("%s: %w", reqs[i].Method, reqs[i].Error)
+		}
+	}
+
+	chainID, ok := new(big.Int).SetString(netID, 10) // nolint:gomnd
+	if !ok {
+		return nil, fmt.Errorf("invalid net_version %q", netID)
+	}
+
+	return &Version{
+		Client:   client,
+		ChainID:  chainID,
+		Protocol: uint64(protocol),
+	}, nil
+}
+
+// ClientVersionInfo is the client version of the node and the RPC
+// modules it serves.
+type ClientVersionInfo struct {
+	// Client is the node's web3_clientVersion.
+	Client string `json:"client"`
+
+	// Modules are the versions of the RPC modules served by the
+	// node, by name, as reported by rpc_modules. It is nil if the
+	// node does not serve rpc_modules.
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+// clientVersionCache holds the ClientVersionInfo of the node once it
+// has been fetched. It is shared by the copies of a Client.
+type clientVersionCache struct {
+	mu   sync.Mutex
+	info *ClientVersionInfo
+}
+
+// ClientVersion returns the client version of the node and its RPC
+// modules. Clients created with NewClient only fetch it once, as it
+// is not expected to change while the process is running; failures
+// are not cached.
+func (ec *Client) ClientVersion(ctx context.Context) (*ClientVersionInfo, error) {
+	if err := ec.checkClosed(); err != nil {
+		return nil, err
+	}
+
+	if ec.clientVersion == nil {
+		return ec.fetchClientVersion(ctx)
+	}
+
+	ec.clientVersion.mu.Lock()
+	defer ec.clientVersion.mu.Unlock()
+	if ec.clientVersion.info == nil {
+		info, err := ec.fetchClientVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ec.clientVersion.info = info
+	}
+
+	return ec.clientVersion.info, nil
+}
+
+func (ec *Client) fetchClientVersion(ctx context.Context) (*ClientVersionInfo, error) {
+	var (
+		client  string
+		modules map[string]string
+	)
+	reqs := []rpc.BatchElem{
+		{Method: "web3_clientVersion", Result: &client},
+		{Method: "rpc_modules", Result: &modules},
+	}
+	if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+		return nil, rpcError(err, false)
+	}
+	if reqs[0].Error != nil {
+		return nil, rpcError(fmt.Errorf("%s: %w", reqs[0].Method, reqs[0].Error), false)
+	}
+
+	// Not all nodes serve rpc_modules, and the
+	// client version is still useful without it.
+	if reqs[1].Error != nil {
+		modules = nil
+	}
+
+	return &ClientVersionInfo{
+		Client:  client,
+		Modules: modules,
+	}, nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optimism
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/optimism"
+
+	"github.com/ethereum-optimism/optimism/l2geth/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestVersion(t *testing.T) {
+	var tests = map[string]struct {
+		errMethod string
+		netID     string
+
+		expectedVersion *Version
+		expectedErr     string
+	}{
+		"all versions": {
+			expectedVersion: &Version{
+				Client:   "Geth/v0.5.11-stable-4b5b5c9e/linux-amd64/go1.15.15",
+				ChainID:  big.NewInt(10),
+				Protocol: 65,
+			},
+		},
+		"protocol version unsupported": {
+			errMethod:   "eth_protocolVersion",
+			expectedErr: "eth_protocolVersion: method not found",
+		},
+		"invalid net version": {
+			netID:       "\"0xa\"",
+			expectedErr: "invalid net_version \"0xa\"",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockJSONRPC := &mocks.JSONRPC{}
+			mockGraphQL := &mocks.GraphQL{}
+
+			c := &Client{
+				c:              mockJSONRPC,
+				g:              mockGraphQL,
+				traceSemaphore: semaphore.NewWeighted(100),
+			}
+
+			file, err := ioutil.ReadFile("testdata/node_version.json")
+			assert.NoError(t, err)
+			var responses map[string]json.RawMessage
+			assert.NoError(t, json.Unmarshal(file, &responses))
+			if test.netID != "" {
+				responses["net_version"] = json.RawMessage(test.netID)
+			}
+
+			ctx := context.Background()
+			mockJSONRPC.On(
+				"BatchCallContext",
+				ctx,
+				mock.MatchedBy(func(reqs []rpc.BatchElem) bool {
+					return len(reqs) == 3 &&
+						reqs[0].Method == "web3_clientVersion" &&
+						reqs[1].Method == "net_version" &&
+						reqs[2].Method == "eth_protocolVersion"
+				}),
+			).Return(
+				nil,
+			).Run(
+				func(args mock.Arguments) {
+					reqs := args.Get(1).([]rpc.BatchElem)
+					for i := range reqs {
+						if reqs[i].Method == test.errMethod {
+							reqs[i].Error = errors.New("method not found")
+							continue
+						}
+						assert.NoError(t, json.Unmarshal(responses[reqs[i].Method], reqs[i].Result))
+					}
+				},
+			).Once()
+
+			version, err := c.Version(ctx)
+			if test.expectedErr != "" {
+				assert.Nil(t, version)
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedVersion, version)
+			}
+
+			mockJSONRPC.AssertExpectations(t)
+			mockGraphQL.AssertExpectations(t)
+		})
+	}
+}
+
+func mockClientVersion(
+	ctx context.Context,
+	t *testing.T,
+	mockJSONRPC *mocks.JSONRPC,
+	modulesErr error,
+) *mock.Call {
+	file, err := ioutil.ReadFile("testdata/node_version.json")
+	assert.NoError(t, err)
+	var responses map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(file, &responses))
+
+	return mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.MatchedBy(func(reqs []rpc.BatchElem) bool {
+			return len(reqs) == 2 &&
+				reqs[0].Method == "web3_clientVersion" &&
+				reqs[1].Method == "rpc_modules"
+		}),
+	).Return(
+		nil,
+	).Run(
+		func(args mock.Arguments) {
+			reqs := args.Get(1).([]rpc.BatchElem)
+			assert.NoError(t, json.Unmarshal(responses[reqs[0].Method], reqs[0].Result))
+			if modulesErr != nil {
+				reqs[1].Error = modulesErr
+				return
+			}
+			assert.NoError(t, json.Unmarshal(responses[reqs[1].Method], reqs[1].Result))
+		},
+	)
+}
+
+func TestClientVersion_Cached(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, clientVersion: &clientVersionCache{}}
+	ctx := context.Background()
+	mockClientVersion(ctx, t, mockJSONRPC, nil).Once()
+
+	expected := &ClientVersionInfo{
+		Client: "Geth/v0.5.11-stable-4b5b5c9e/linux-amd64/go1.15.15",
+		Modules: map[string]string{
+			"eth":    "1.0",
+			"net":    "1.0",
+			"rollup": "1.0",
+			"web3":   "1.0",
+		},
+	}
+	for i := 0; i < 3; i++ {
+		info, err := c.ClientVersion(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, info)
+	}
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestClientVersion_ModulesUnavailable(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, clientVersion: &clientVersionCache{}}
+	ctx := context.Background()
+	mockClientVersion(ctx, t, mockJSONRPC, &jsonError{
+		code:    methodNotFoundCode,
+		message: "the method rpc_modules does not exist/is not available",
+	}).Once()
+
+	info, err := c.ClientVersion(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &ClientVersionInfo{
+		Client: "Geth/v0.5.11-stable-4b5b5c9e/linux-amd64/go1.15.15",
+	}, info)
+
+	mockJSONRPC.AssertExpectations(t)
+}
+
+func TestClientVersion_ErrorNotCached(t *testing.T) {
+	mockJSONRPC := &mocks.JSONRPC{}
+	c := &Client{c: mockJSONRPC, clientVersion: &clientVersionCache{}}
+	ctx := context.Background()
+	mockJSONRPC.On(
+		"BatchCallContext",
+		ctx,
+		mock.Anything,
+	).Return(
+		errors.New("connection refused"),
+	).Once()
+	mockClientVersion(ctx, t, mockJSONRPC, nil).Once()
+
+	info, err := c.ClientVersion(ctx)
+	assert.True(t, errors.Is(err, ErrNodeUnavailable))
+	assert.Nil(t, info)
+
+	info, err = c.ClientVersion(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "Geth/v0.5.11-stable-4b5b5c9e/linux-amd64/go1.15.15", info.Client)
+
+	mockJSONRPC.AssertExpectations(t)
+}
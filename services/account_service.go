@@ -16,16 +16,19 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/coinbase/rosetta-ethereum/configuration"
+	"github.com/coinbase/rosetta-ethereum/optimism"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
 // AccountAPIService implements the server.AccountAPIServicer interface.
 type AccountAPIService struct {
-	config *configuration.Configuration
-	client Client
+	config    *configuration.Configuration
+	client    Client
+	syncGuard *syncGuard
 }
 
 // NewAccountAPIService returns a new *AccountAPIService.
@@ -34,8 +37,9 @@ func NewAccountAPIService(
 	client Client,
 ) *AccountAPIService {
 	return &AccountAPIService{
-		config: cfg,
-		client: client,
+		config:    cfg,
+		client:    client,
+		syncGuard: newSyncGuard(client, cfg.MaxSyncLag),
 	}
 }
 
@@ -48,14 +52,27 @@ func (s *AccountAPIService) AccountBalance(
 		return nil, ErrUnavailableOffline
 	}
 
+	// Without a block identifier, the balance at the tip is requested
+	if request.BlockIdentifier == nil {
+		if err := s.syncGuard.check(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	balanceResponse, err := s.client.Balance(
 		ctx,
 		request.AccountIdentifier,
 		request.BlockIdentifier,
 		request.Currencies,
 	)
+	if errors.Is(err, optimism.ErrInvalidTokenContractAddress) {
+		return nil, wrapErr(ErrInvalidTokenContractAddress, err)
+	}
+	if errors.Is(err, optimism.ErrInvalidAddress) {
+		return nil, wrapErr(ErrInvalidAddress, err)
+	}
 	if err != nil {
-		return nil, wrapErr(ErrGeth, err)
+		return nil, wrapNodeErr(err)
 	}
 
 	return balanceResponse, nil
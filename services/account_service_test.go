@@ -16,6 +16,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/coinbase/rosetta-ethereum/configuration"
@@ -111,3 +112,72 @@ func TestAccountBalance_Online(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestAccountBalance_InvalidTokenContractAddress(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode: configuration.Online,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewAccountAPIService(cfg, mockClient)
+
+	ctx := context.Background()
+
+	account := &types.AccountIdentifier{
+		Address: "hello",
+	}
+
+	mockClient.On(
+		"Balance",
+		ctx,
+		account,
+		(*types.PartialBlockIdentifier)(nil),
+		[]*types.Currency{mockCurrency},
+	).Return(nil, fmt.Errorf("%w: 0xdeadbeef", optimism.ErrInvalidTokenContractAddress)).Once()
+
+	bal, err := servicer.AccountBalance(ctx, &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+		Currencies: []*types.Currency{
+			mockCurrency,
+		},
+	})
+	assert.Nil(t, bal)
+	assert.Equal(t, ErrInvalidTokenContractAddress.Code, err.Code)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestAccountBalance_HistoricalStateUnavailable(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode: configuration.Online,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewAccountAPIService(cfg, mockClient)
+
+	ctx := context.Background()
+
+	account := &types.AccountIdentifier{
+		Address: "hello",
+	}
+	index := int64(100)
+	block := &types.PartialBlockIdentifier{
+		Index: &index,
+	}
+
+	mockClient.On(
+		"Balance",
+		ctx,
+		account,
+		block,
+		[]*types.Currency(nil),
+	).Return(nil, fmt.Errorf("%w: missing trie node", optimism.ErrHistoricalStateUnavailable)).Once()
+
+	bal, err := servicer.AccountBalance(ctx, &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+		BlockIdentifier:   block,
+	})
+	assert.Nil(t, bal)
+	assert.Equal(t, ErrHistoricalStateUnavailable.Code, err.Code)
+	assert.False(t, err.Retriable)
+
+	mockClient.AssertExpectations(t)
+}
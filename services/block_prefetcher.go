@@ -0,0 +1,228 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-ethereum/optimism"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// prefetchTimeout bounds the time spent fetching a single
+// speculative block.
+const prefetchTimeout = time.Minute
+
+// blockPrefetcher hides node latency from sequential syncers. Once
+// a block is requested by index right after its parent, the next
+// depth blocks are fetched one at a time in the background, with a
+// low priority context so they only trace with spare capacity, and
+// buffered until requested.
+//
+// The buffer only holds blocks ahead of the last block served. It is
+// discarded when a request breaks the sequence, and when a buffered
+// block's parent is not the last block served, as the chain has
+// reorganized since it was fetched.
+type blockPrefetcher struct {
+	client Client
+	depth  int64
+
+	mu       sync.Mutex
+	last     *types.BlockIdentifier
+	blocks   map[int64]*types.Block
+	inflight map[int64]chan struct{}
+	running  bool
+
+	// generation is incremented each time the buffer is discarded,
+	// so blocks fetched before then are not buffered.
+	generation uint64
+}
+
+func newBlockPrefetcher(client Client, depth int) *blockPrefetcher {
+	return &blockPrefetcher{
+		client:   client,
+		depth:    int64(depth),
+		blocks:   map[int64]*types.Block{},
+		inflight: map[int64]chan struct{}{},
+	}
+}
+
+// Block returns the block identified by blockIdentifier, from the
+// buffer if it was prefetched. Requests without an index, such as
+// for the tip or by hash only, are passed through to the client.
+func (p *blockPrefetcher) Block(
+	ctx context.Context,
+	blockIdentifier *types.PartialBlockIdentifier,
+) (*types.Block, error) {
+	if blockIdentifier == nil || blockIdentifier.Index == nil {
+		return p.client.Block(ctx, blockIdentifier)
+	}
+
+	block, ok := p.prefetched(ctx, *blockIdentifier.Index, blockIdentifier.Hash)
+	if !ok {
+		var err error
+		block, err = p.client.Block(ctx, blockIdentifier)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.served(block)
+	return block, nil
+}
+
+// prefetched removes the block at index from the buffer and returns
+// it, waiting for it if it is being fetched.
+func (p *blockPrefetcher) prefetched(
+	ctx context.Context,
+	index int64,
+	hash *string,
+) (*types.Block, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if pending, ok := p.inflight[index]; ok {
+		p.mu.Unlock()
+		select {
+		case <-pending:
+		case <-ctx.Done():
+		}
+		p.mu.Lock()
+	}
+
+	block, ok := p.blocks[index]
+	if !ok {
+		return nil, false
+	}
+	if hash != nil && *hash != block.BlockIdentifier.Hash {
+		return nil, false
+	}
+
+	if p.last != nil && p.last.Index == index-1 &&
+		(block.ParentBlockIdentifier == nil || block.ParentBlockIdentifier.Hash != p.last.Hash) {
+		p.discard()
+		return nil, false
+	}
+
+	delete(p.blocks, index)
+	return block, true
+}
+
+// served records that block was returned to a caller, and
+// prefetches the blocks after it if the access is sequential.
+func (p *blockPrefetcher) served(block *types.Block) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current := block.BlockIdentifier
+	sequential := p.last != nil && current.Index == p.last.Index+1
+	if !sequential {
+		p.discard()
+	}
+
+	p.last = current
+	for index := range p.blocks {
+		if index <= current.Index {
+			delete(p.blocks, index)
+		}
+	}
+
+	if sequential {
+		p.schedule()
+	}
+}
+
+// discard empties the buffer. Blocks being fetched are
+// dropped when they arrive.
+func (p *blockPrefetcher) discard() {
+	p.blocks = map[int64]*types.Block{}
+	p.generation++
+}
+
+// next returns the first block within depth of the last block
+// served that is neither buffered nor being fetched.
+func (p *blockPrefetcher) next() (int64, bool) {
+	if p.last == nil {
+		return 0, false
+	}
+
+	for index := p.last.Index + 1; index <= p.last.Index+p.depth; index++ {
+		_, buffered := p.blocks[index]
+		_, pending := p.inflight[index]
+		if !buffered && !pending {
+			return index, true
+		}
+	}
+
+	return 0, false
+}
+
+// schedule starts fetching the next block, unless a fetch is
+// already running. The block is marked in flight before returning
+// so a request for it waits instead of fetching it again.
+func (p *blockPrefetcher) schedule() {
+	if p.running {
+		return
+	}
+
+	index, ok := p.next()
+	if !ok {
+		return
+	}
+
+	p.running = true
+	p.inflight[index] = make(chan struct{})
+	go p.fill(index, p.generation)
+}
+
+// fill fetches blocks until the buffer is full or a fetch fails,
+// which includes low priority fetches finding no spare trace
+// capacity and fetches past the tip. The next sequential request
+// schedules fetching again.
+func (p *blockPrefetcher) fill(index int64, generation uint64) {
+	for {
+		ctx, cancel := context.WithTimeout(
+			optimism.WithLowPriority(context.Background()),
+			prefetchTimeout,
+		)
+		block, err := p.client.Block(ctx, &types.PartialBlockIdentifier{Index: &index})
+		cancel()
+
+		p.mu.Lock()
+		if err == nil && generation == p.generation &&
+			index > p.last.Index && index <= p.last.Index+p.depth {
+			p.blocks[index] = block
+		}
+		close(p.inflight[index])
+		delete(p.inflight, index)
+
+		var ok bool
+		if err == nil {
+			index, ok = p.next()
+		}
+		if !ok {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+
+		p.inflight[index] = make(chan struct{})
+		generation = p.generation
+		p.mu.Unlock()
+	}
+}
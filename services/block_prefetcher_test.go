@@ -0,0 +1,182 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/coinbase/rosetta-ethereum/configuration"
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/services"
+	"github.com/coinbase/rosetta-ethereum/optimism"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testBlock(index int64, parentHash string) *types.Block {
+	return &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: index,
+			Hash:  fmt.Sprintf("block %d", index),
+		},
+		ParentBlockIdentifier: &types.BlockIdentifier{
+			Index: index - 1,
+			Hash:  parentHash,
+		},
+	}
+}
+
+func atIndex(index int64) interface{} {
+	return mock.MatchedBy(func(blockIdentifier *types.PartialBlockIdentifier) bool {
+		return blockIdentifier != nil && blockIdentifier.Index != nil && *blockIdentifier.Index == index
+	})
+}
+
+var lowPriority = mock.MatchedBy(optimism.IsLowPriority)
+
+func blockRequest(index int64) *types.BlockRequest {
+	return &types.BlockRequest{
+		BlockIdentifier: &types.PartialBlockIdentifier{Index: &index},
+	}
+}
+
+func waitForPrefetch(t *testing.T, p *blockPrefetcher) {
+	assert.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		return !p.running
+	}, time.Second, time.Millisecond)
+}
+
+func TestBlockPrefetcher_Sequential(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:           configuration.Online,
+		PrefetchBlocks: 2,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewBlockAPIService(cfg, mockClient)
+	ctx := context.Background()
+
+	// The first two requests establish the sequence
+	mockClient.On("Block", ctx, atIndex(1)).Return(testBlock(1, "block 0"), nil).Once()
+	mockClient.On("Block", ctx, atIndex(2)).Return(testBlock(2, "block 1"), nil).Once()
+
+	// The following blocks are fetched in the background, past the
+	// tip the node returns no block
+	mockClient.On("Block", lowPriority, atIndex(3)).Return(testBlock(3, "block 2"), nil).Once()
+	mockClient.On("Block", lowPriority, atIndex(4)).Return(testBlock(4, "block 3"), nil).Once()
+	mockClient.On("Block", lowPriority, atIndex(5)).Return(nil, optimism.ErrBlockNotFound).Once()
+
+	for i := int64(1); i <= 4; i++ {
+		resp, err := servicer.Block(ctx, blockRequest(i))
+		assert.Nil(t, err)
+		assert.Equal(t, testBlock(i, fmt.Sprintf("block %d", i-1)), resp.Block)
+	}
+
+	// Blocks 3 and 4 were served without new requests
+	waitForPrefetch(t, servicer.prefetcher)
+	mockClient.AssertNotCalled(t, "Block", ctx, atIndex(3))
+	mockClient.AssertNotCalled(t, "Block", ctx, atIndex(4))
+	mockClient.AssertExpectations(t)
+}
+
+func TestBlockPrefetcher_Reorg(t *testing.T) {
+	mockClient := &mocks.Client{}
+	p := newBlockPrefetcher(mockClient, 1)
+	ctx := context.Background()
+
+	mockClient.On("Block", ctx, atIndex(1)).Return(testBlock(1, "block 0"), nil).Once()
+	mockClient.On("Block", ctx, atIndex(2)).Return(testBlock(2, "block 1"), nil).Once()
+	_, err := p.Block(ctx, &types.PartialBlockIdentifier{Index: types.Int64(1)})
+	assert.NoError(t, err)
+
+	// Block 3 is prefetched on top of a block 2 that has since
+	// been orphaned
+	mockClient.On("Block", lowPriority, atIndex(3)).Return(testBlock(3, "orphaned"), nil).Once()
+	_, err = p.Block(ctx, &types.PartialBlockIdentifier{Index: types.Int64(2)})
+	assert.NoError(t, err)
+	waitForPrefetch(t, p)
+
+	// It is discarded and fetched again
+	mockClient.On("Block", ctx, atIndex(3)).Return(testBlock(3, "block 2"), nil).Once()
+	mockClient.On("Block", lowPriority, atIndex(4)).Return(nil, optimism.ErrBlockNotFound).Once()
+	block, err := p.Block(ctx, &types.PartialBlockIdentifier{Index: types.Int64(3)})
+	assert.NoError(t, err)
+	assert.Equal(t, "block 2", block.ParentBlockIdentifier.Hash)
+
+	waitForPrefetch(t, p)
+	mockClient.AssertExpectations(t)
+}
+
+func TestBlockPrefetcher_NonSequential(t *testing.T) {
+	mockClient := &mocks.Client{}
+	p := newBlockPrefetcher(mockClient, 1)
+	ctx := context.Background()
+
+	mockClient.On("Block", ctx, atIndex(1)).Return(testBlock(1, "block 0"), nil).Once()
+	mockClient.On("Block", ctx, atIndex(2)).Return(testBlock(2, "block 1"), nil).Once()
+	mockClient.On("Block", lowPriority, atIndex(3)).Return(testBlock(3, "block 2"), nil).Once()
+	for i := int64(1); i <= 2; i++ {
+		_, err := p.Block(ctx, &types.PartialBlockIdentifier{Index: &i})
+		assert.NoError(t, err)
+	}
+	waitForPrefetch(t, p)
+	assert.Len(t, p.blocks, 1)
+
+	// Jumping elsewhere discards the buffer without prefetching
+	mockClient.On("Block", ctx, atIndex(10)).Return(testBlock(10, "block 9"), nil).Once()
+	_, err := p.Block(ctx, &types.PartialBlockIdentifier{Index: types.Int64(10)})
+	assert.NoError(t, err)
+	assert.Empty(t, p.blocks)
+
+	// Requests without an index are passed through
+	mockClient.On("Block", ctx, (*types.PartialBlockIdentifier)(nil)).Return(testBlock(11, "block 10"), nil).Once()
+	_, err = p.Block(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), p.last.Index)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestBlockPrefetcher_Busy(t *testing.T) {
+	mockClient := &mocks.Client{}
+	p := newBlockPrefetcher(mockClient, 2)
+	ctx := context.Background()
+
+	// Without spare trace capacity nothing is prefetched
+	mockClient.On("Block", ctx, atIndex(1)).Return(testBlock(1, "block 0"), nil).Once()
+	mockClient.On("Block", ctx, atIndex(2)).Return(testBlock(2, "block 1"), nil).Once()
+	mockClient.On("Block", lowPriority, atIndex(3)).Return(nil, optimism.ErrTraceCapacityBusy).Once()
+	for i := int64(1); i <= 2; i++ {
+		_, err := p.Block(ctx, &types.PartialBlockIdentifier{Index: &i})
+		assert.NoError(t, err)
+	}
+	waitForPrefetch(t, p)
+	assert.Empty(t, p.blocks)
+
+	// The interactive request fetches the block itself
+	mockClient.On("Block", ctx, atIndex(3)).Return(testBlock(3, "block 2"), nil).Once()
+	mockClient.On("Block", lowPriority, atIndex(4)).Return(nil, optimism.ErrTraceCapacityBusy).Once()
+	_, err := p.Block(ctx, &types.PartialBlockIdentifier{Index: types.Int64(3)})
+	assert.NoError(t, err)
+
+	waitForPrefetch(t, p)
+	mockClient.AssertExpectations(t)
+}
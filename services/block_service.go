@@ -26,8 +26,10 @@ import (
 
 // BlockAPIService implements the server.BlockAPIServicer interface.
 type BlockAPIService struct {
-	config *configuration.Configuration
-	client Client
+	config     *configuration.Configuration
+	client     Client
+	prefetcher *blockPrefetcher
+	syncGuard  *syncGuard
 }
 
 // NewBlockAPIService creates a new instance of a BlockAPIService.
@@ -35,10 +37,16 @@ func NewBlockAPIService(
 	cfg *configuration.Configuration,
 	client Client,
 ) *BlockAPIService {
-	return &BlockAPIService{
-		config: cfg,
-		client: client,
+	s := &BlockAPIService{
+		config:    cfg,
+		client:    client,
+		syncGuard: newSyncGuard(client, cfg.MaxSyncLag),
 	}
+	if cfg.PrefetchBlocks > 0 {
+		s.prefetcher = newBlockPrefetcher(client, cfg.PrefetchBlocks)
+	}
+
+	return s
 }
 
 // Block implements the /block endpoint.
@@ -50,12 +58,25 @@ func (s *BlockAPIService) Block(
 		return nil, ErrUnavailableOffline
 	}
 
-	block, err := s.client.Block(ctx, request.BlockIdentifier)
+	// Without a block identifier, the tip is requested
+	if request.BlockIdentifier == nil {
+		if err := s.syncGuard.check(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var block *types.Block
+	var err error
+	if s.prefetcher != nil {
+		block, err = s.prefetcher.Block(ctx, request.BlockIdentifier)
+	} else {
+		block, err = s.client.Block(ctx, request.BlockIdentifier)
+	}
 	if errors.Is(err, optimism.ErrBlockOrphaned) {
 		return nil, wrapErr(ErrBlockOrphaned, err)
 	}
 	if err != nil {
-		return nil, wrapErr(ErrGeth, err)
+		return nil, wrapNodeErr(err)
 	}
 
 	return &types.BlockResponse{
@@ -16,6 +16,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/coinbase/rosetta-ethereum/configuration"
@@ -103,5 +104,28 @@ func TestBlockService_Online(t *testing.T) {
 		assert.Equal(t, ErrBlockOrphaned.Retriable, err.Retriable)
 	})
 
+	t.Run("node errors", func(t *testing.T) {
+		nodeErrors := map[error]*types.Error{
+			optimism.ErrBlockNotFound:   ErrBlockNotFound,
+			optimism.ErrNodeUnavailable: ErrNodeUnavailable,
+			optimism.ErrRateLimited:     ErrRateLimited,
+			errors.New("unclassified"):  ErrGeth,
+		}
+		for clientErr, expectedErr := range nodeErrors {
+			pbIdentifier := types.ConstructPartialBlockIdentifier(block.BlockIdentifier)
+			mockClient.On("Block", ctx, pbIdentifier).Return(nil, &optimism.RPCError{
+				Kind: clientErr,
+				Err:  errors.New("node error"),
+			}).Once()
+			b, err := servicer.Block(ctx, &types.BlockRequest{
+				BlockIdentifier: pbIdentifier,
+			})
+
+			assert.Nil(t, b)
+			assert.Equal(t, expectedErr.Code, err.Code)
+			assert.Equal(t, expectedErr.Retriable, err.Retriable)
+		}
+	})
+
 	mockClient.AssertExpectations(t)
 }
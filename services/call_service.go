@@ -43,14 +43,32 @@ func (s *CallAPIService) Call(
 	ctx context.Context,
 	request *types.CallRequest,
 ) (*types.CallResponse, *types.Error) {
-	if s.config.Mode != configuration.Online {
+	var (
+		response *types.CallResponse
+		err      error
+	)
+	switch {
+	case request.Method == optimism.DecodeTransactionMethod:
+		// Decoding makes no node calls, so it is served offline too
+		var result map[string]interface{}
+		result, err = optimism.DecodeTransaction(request.Parameters)
+		if err == nil {
+			response = &types.CallResponse{
+				Result:     result,
+				Idempotent: true,
+			}
+		}
+	case s.config.Mode != configuration.Online:
 		return nil, ErrUnavailableOffline
+	default:
+		response, err = s.client.Call(ctx, request)
 	}
-
-	response, err := s.client.Call(ctx, request)
 	if errors.Is(err, optimism.ErrCallParametersInvalid) {
 		return nil, wrapErr(ErrCallParametersInvalid, err)
 	}
+	if errors.Is(err, optimism.ErrInvalidAddress) {
+		return nil, wrapErr(ErrInvalidAddress, err)
+	}
 	if errors.Is(err, optimism.ErrCallOutputMarshal) {
 		return nil, wrapErr(ErrCallOutputMarshal, err)
 	}
@@ -58,7 +76,7 @@ func (s *CallAPIService) Call(
 		return nil, wrapErr(ErrCallMethodInvalid, err)
 	}
 	if err != nil {
-		return nil, wrapErr(ErrGeth, err)
+		return nil, wrapNodeErr(err)
 	}
 
 	return response, nil
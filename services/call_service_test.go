@@ -20,6 +20,7 @@ import (
 
 	"github.com/coinbase/rosetta-ethereum/configuration"
 	mocks "github.com/coinbase/rosetta-ethereum/mocks/services"
+	"github.com/coinbase/rosetta-ethereum/optimism"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/stretchr/testify/assert"
@@ -65,3 +66,33 @@ func TestCall_Online(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestCall_DecodeTransaction_Offline(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode: configuration.Offline,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewCallAPIService(cfg, mockClient)
+	ctx := context.Background()
+
+	resp, err := servicer.Call(ctx, &types.CallRequest{
+		Method: optimism.DecodeTransactionMethod,
+		Parameters: map[string]interface{}{
+			"signed_transaction": "0xf86203830f4240825208944200000000000000000000000000000000000042648037a06649c58dbd3a8806f3d98f0b2a1e83373b60f3de2df7a9279cc9fd6978c650a6a022481a7a82f4ca4a569f2e8daa7ce7e8c0ad6d5674de62242c79c380c4f4eca7",
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "0x250663ddb79c97dbfc8cc34a44163c870fd7a8ad27d69d206d6e740737a44499", resp.Result["hash"])
+	assert.Equal(t, "0xFE3B557E8Fb62b89F4916B721be55cEb828dBd73", resp.Result["from"])
+
+	resp, err = servicer.Call(ctx, &types.CallRequest{
+		Method: optimism.DecodeTransactionMethod,
+		Parameters: map[string]interface{}{
+			"signed_transaction": "0xdeadbeef",
+		},
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, ErrCallParametersInvalid.Code, err.Code)
+
+	mockClient.AssertExpectations(t)
+}
@@ -54,8 +54,9 @@ var (
 
 // ConstructionAPIService implements the server.ConstructionAPIServicer interface.
 type ConstructionAPIService struct {
-	config *configuration.Configuration
-	client Client
+	config    *configuration.Configuration
+	client    Client
+	syncGuard *syncGuard
 }
 
 // NewConstructionAPIService creates a new instance of a ConstructionAPIService.
@@ -64,8 +65,9 @@ func NewConstructionAPIService(
 	client Client,
 ) *ConstructionAPIService {
 	return &ConstructionAPIService{
-		config: cfg,
-		client: client,
+		config:    cfg,
+		client:    client,
+		syncGuard: newSyncGuard(client, cfg.MaxSyncLag),
 	}
 }
 
@@ -256,9 +258,14 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 		return nil, wrapErr(ErrInvalidAddress, fmt.Errorf("%s is not a valid address", input.To))
 	}
 
+	// The nonce and gas price are read from the tip
+	if err := s.syncGuard.check(ctx); err != nil {
+		return nil, err
+	}
+
 	nonce, err := s.calculateNonce(ctx, input.Nonce, checkFrom)
 	if err != nil {
-		return nil, wrapErr(ErrGeth, err)
+		return nil, wrapNodeErr(err)
 	}
 
 	var gasLimit uint64
@@ -320,7 +327,7 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 	// TODO(inphi): Upgrade to use EIP1559 on mainnet once avaialble
 	gasPrice, err := s.client.SuggestGasPrice(ctx)
 	if err != nil {
-		return nil, wrapErr(ErrGeth, err)
+		return nil, wrapNodeErr(err)
 	}
 
 	metadata := &metadata{
@@ -632,12 +639,18 @@ func (s *ConstructionAPIService) ConstructionSubmit(
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
 
-	if err := s.client.SendTransaction(ctx, &signedTx); err != nil {
+	txHash, err := s.client.SendTransactionWithHash(ctx, &signedTx)
+	switch {
+	case errors.Is(err, optimism.ErrTransactionHashMismatch):
+		// The node accepted the transaction, so it must not be
+		// reported as failed: callers would broadcast it again.
+		log.Printf("%s: tracking submitted transaction by its node hash", err.Error())
+	case err != nil:
 		return nil, wrapErr(ErrBroadcastFailed, err)
 	}
 
 	txIdentifier := &types.TransactionIdentifier{
-		Hash: signedTx.Hash().Hex(),
+		Hash: txHash.Hex(),
 	}
 	return &types.TransactionIdentifierResponse{
 		TransactionIdentifier: txIdentifier,
@@ -667,7 +680,7 @@ func (s *ConstructionAPIService) calculateGasLimit(
 	})
 
 	if err != nil {
-		return 0, wrapErr(ErrGeth, err)
+		return 0, wrapNodeErr(err)
 	}
 
 	return gasLimit, nil
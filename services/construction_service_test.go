@@ -283,10 +283,11 @@ func TestConstructionService(t *testing.T) {
 
 	// Test Submit
 	mockClient.On(
-		"SendTransaction",
+		"SendTransactionWithHash",
 		ctx,
 		mock.Anything, // can't test ethTx here because it contains "time"
 	).Return(
+		common.HexToHash(transactionIdentifier.Hash),
 		nil,
 	)
 	submitResponse, err := servicer.ConstructionSubmit(ctx, &types.ConstructionSubmitRequest{
@@ -301,6 +302,43 @@ func TestConstructionService(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
+func TestConstructionSubmit_HashMismatch(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:   configuration.Online,
+		Params: params.TestnetChainConfig,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient)
+	ctx := context.Background()
+
+	// The node reports a different hash, after accepting the transaction
+	signedRaw := `{"nonce":"0x0","gasPrice":"0x3b9aca00","gas":"0x5208","to":"0x57b414a0332b5cab885a451c2a28a07d1e9b8a8d","value":"0x9864aac3510d02","input":"0x","v":"0x2a","r":"0x8c712c64bc65c4a88707fa93ecd090144dffb1bf133805a10a51d354c2f9f2b2","s":"0x5a63cea6989f4c58372c41f31164036a6b25dce1d5c05e1d31c16c0590c176e8","hash":null}` // nolint
+	nodeHash := common.HexToHash("0x5e77a04531c7c107af1882d76cbff9486d0a9aa53701c30888509d4f5f2b003a")
+	mockClient.On(
+		"SendTransactionWithHash",
+		ctx,
+		mock.Anything,
+	).Return(
+		nodeHash,
+		fmt.Errorf(
+			"%w: node returned %s for transaction %s",
+			optimism.ErrTransactionHashMismatch,
+			nodeHash.Hex(),
+			"0x424969b1a98757bcd748c60bad2a7de9745cfb26bfefb4550e780a098feada42",
+		),
+	).Once()
+
+	submitResponse, err := servicer.ConstructionSubmit(ctx, &types.ConstructionSubmitRequest{
+		SignedTransaction: signedRaw,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.TransactionIdentifierResponse{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: nodeHash.Hex()},
+	}, submitResponse)
+
+	mockClient.AssertExpectations(t)
+}
+
 func TestMetadata_Offline(t *testing.T) {
 	t.Run("unavailable in offline mode", func(t *testing.T) {
 		service := ConstructionAPIService{
@@ -15,6 +15,12 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"expvar"
+
+	"github.com/coinbase/rosetta-ethereum/optimism"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -44,6 +50,16 @@ var (
 		ErrInvalidSignature,
 		ErrFetchFunctionSignatureMethodID,
 		ErrInvalidTransaction,
+		ErrHistoricalStateUnavailable,
+		ErrBlockNotFound,
+		ErrNodeUnavailable,
+		ErrRateLimited,
+		ErrRequestTimeout,
+		ErrClientCanceled,
+		ErrNodeSyncing,
+		ErrChainIDChanged,
+		ErrNetworkNotSupported,
+		ErrMethodUnsupported,
 	}
 
 	// ErrUnimplemented is returned when an endpoint
@@ -213,6 +229,90 @@ var (
 		Code:    22, //nolint
 		Message: "Gas limit invalid",
 	}
+
+	// ErrHistoricalStateUnavailable is returned when the state
+	// of the requested block has been pruned by the node. The
+	// request will not succeed without an archive node.
+	ErrHistoricalStateUnavailable = &types.Error{
+		Code:    23, //nolint
+		Message: "Historical state unavailable, the node may have pruned the requested block",
+	}
+
+	// ErrBlockNotFound is returned when the node
+	// does not know the requested block.
+	ErrBlockNotFound = &types.Error{
+		Code:    24, //nolint
+		Message: "Block not found",
+	}
+
+	// ErrNodeUnavailable is returned when the node
+	// cannot be reached or is not serving requests.
+	ErrNodeUnavailable = &types.Error{
+		Code:      25, //nolint
+		Message:   "Node unavailable",
+		Retriable: true,
+	}
+
+	// ErrRateLimited is returned when the node
+	// rejects a request because of a rate limit.
+	ErrRateLimited = &types.Error{
+		Code:      26, //nolint
+		Message:   "Rate limited by node",
+		Retriable: true,
+	}
+
+	// ErrRequestTimeout is returned when a request to
+	// the node runs out of time.
+	ErrRequestTimeout = &types.Error{
+		Code:      27, //nolint
+		Message:   "Request timed out",
+		Retriable: true,
+	}
+
+	// ErrClientCanceled is returned when the client
+	// goes away before the request completes. It is
+	// not a node failure and is not counted in
+	// nodeErrorMetrics.
+	ErrClientCanceled = &types.Error{
+		Code:    28, //nolint
+		Message: "Client canceled request",
+	}
+
+	// ErrNodeSyncing is returned for requests about the
+	// current state of the chain while the node is too
+	// far behind its tip to answer them.
+	ErrNodeSyncing = &types.Error{
+		Code:      29, //nolint
+		Message:   "Node is syncing",
+		Retriable: true,
+	}
+
+	// ErrChainIDChanged is returned when the node reports a
+	// different chain id than it did earlier, for example because
+	// a load balancer routed the request to a node on another chain.
+	ErrChainIDChanged = &types.Error{
+		Code:    30, //nolint
+		Message: "Node chain id changed",
+	}
+
+	// ErrNetworkNotSupported is returned for requests addressed
+	// to a network this deployment does not serve. Its details
+	// list the supported networks.
+	ErrNetworkNotSupported = &types.Error{
+		Code:    31, //nolint
+		Message: "Network not supported",
+	}
+
+	// ErrMethodUnsupported is returned when the node does
+	// not serve a method the request depends on.
+	ErrMethodUnsupported = &types.Error{
+		Code:    32, //nolint
+		Message: "Method unsupported by node",
+	}
+
+	// nodeErrorMetrics counts the node errors returned
+	// by the services, keyed by error message.
+	nodeErrorMetrics = expvar.NewMap("node_errors")
 )
 
 // wrapErr adds details to the types.Error provided. We use a function
@@ -232,3 +332,36 @@ func wrapErr(rErr *types.Error, err error) *types.Error {
 
 	return newErr
 }
+
+// wrapNodeErr wraps an error returned by the client in the
+// *types.Error matching how the client classified it, or
+// ErrGeth if it was not classified.
+// Every error except ErrClientCanceled is counted in nodeErrorMetrics.
+func wrapNodeErr(err error) *types.Error {
+	var rErr *types.Error
+	switch {
+	case errors.Is(err, optimism.ErrRequestCanceled), errors.Is(err, context.Canceled):
+		return wrapErr(ErrClientCanceled, err)
+	case errors.Is(err, optimism.ErrHistoricalStateUnavailable):
+		// The node is working as configured, so this is not
+		// counted as a node error.
+		return wrapErr(ErrHistoricalStateUnavailable, err)
+	case errors.Is(err, optimism.ErrRequestTimeout), errors.Is(err, context.DeadlineExceeded):
+		rErr = ErrRequestTimeout
+	case errors.Is(err, optimism.ErrBlockNotFound):
+		rErr = ErrBlockNotFound
+	case errors.Is(err, optimism.ErrNodeUnavailable):
+		rErr = ErrNodeUnavailable
+	case errors.Is(err, optimism.ErrRateLimited):
+		rErr = ErrRateLimited
+	case errors.Is(err, optimism.ErrChainIDChanged):
+		rErr = ErrChainIDChanged
+	case errors.Is(err, optimism.ErrMethodUnsupported):
+		rErr = ErrMethodUnsupported
+	default:
+		rErr = ErrGeth
+	}
+
+	nodeErrorMetrics.Add(rErr.Message, 1)
+	return wrapErr(rErr, err)
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/coinbase/rosetta-ethereum/optimism"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func nodeErrorCount(message string) int64 {
+	v, ok := nodeErrorMetrics.Get(message).(*expvar.Int)
+	if !ok {
+		return 0
+	}
+
+	return v.Value()
+}
+
+func TestWrapNodeErr(t *testing.T) {
+	var tests = map[string]struct {
+		err error
+
+		expectedErr     *types.Error
+		expectedCounted bool
+	}{
+		"client canceled": {
+			err: &optimism.RPCError{
+				Kind: optimism.ErrRequestCanceled,
+				Err:  &url.Error{Op: "Post", URL: "http://localhost:8545", Err: context.Canceled},
+			},
+			expectedErr: ErrClientCanceled,
+		},
+		"unclassified client canceled": {
+			err:         fmt.Errorf("unable to get status: %w", context.Canceled),
+			expectedErr: ErrClientCanceled,
+		},
+		"deadline exceeded": {
+			err: &optimism.RPCError{
+				Kind: optimism.ErrRequestTimeout,
+				Err:  &url.Error{Op: "Post", URL: "http://localhost:8545", Err: context.DeadlineExceeded},
+			},
+			expectedErr:     ErrRequestTimeout,
+			expectedCounted: true,
+		},
+		"unclassified deadline exceeded": {
+			err:             fmt.Errorf("unable to get status: %w", context.DeadlineExceeded),
+			expectedErr:     ErrRequestTimeout,
+			expectedCounted: true,
+		},
+		"node unavailable": {
+			err:             &optimism.RPCError{Kind: optimism.ErrNodeUnavailable, Err: errors.New("connection refused")},
+			expectedErr:     ErrNodeUnavailable,
+			expectedCounted: true,
+		},
+		"method unsupported": {
+			err: &optimism.RPCError{
+				Kind: optimism.ErrMethodUnsupported,
+				Err:  errors.New("the method eth_sendBundle does not exist/is not available"),
+			},
+			expectedErr:     ErrMethodUnsupported,
+			expectedCounted: true,
+		},
+		"chain id changed": {
+			err:             fmt.Errorf("%w: node reported chain id 420, expected 10", optimism.ErrChainIDChanged),
+			expectedErr:     ErrChainIDChanged,
+			expectedCounted: true,
+		},
+		"state pruned": {
+			err: &optimism.RPCError{
+				Kind: optimism.ErrStatePruned,
+				Err:  errors.New("missing trie node 5b1d08c8b8a1f87f27b0eb2b7f07a1c5b2ae3eb6a6b2e4d2a3e1c0f9b8a7d6c5 (path )"),
+			},
+			expectedErr: ErrHistoricalStateUnavailable,
+		},
+		"unclassified": {
+			err:             errors.New("execution reverted"),
+			expectedErr:     ErrGeth,
+			expectedCounted: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			before := nodeErrorCount(test.expectedErr.Message)
+
+			rErr := wrapNodeErr(test.err)
+			assert.Equal(t, test.expectedErr.Code, rErr.Code)
+			assert.Equal(t, test.expectedErr.Retriable, rErr.Retriable)
+			assert.Equal(t, test.err.Error(), rErr.Details["context"])
+
+			counted := nodeErrorCount(test.expectedErr.Message) - before
+			if test.expectedCounted {
+				assert.Equal(t, int64(1), counted)
+			} else {
+				assert.Equal(t, int64(0), counted)
+			}
+		})
+	}
+}
+
+func TestRequestErrors_Retriable(t *testing.T) {
+	assert.True(t, ErrRequestTimeout.Retriable)
+	assert.False(t, ErrClientCanceled.Retriable)
+	assert.Contains(t, Errors, ErrRequestTimeout)
+	assert.Contains(t, Errors, ErrClientCanceled)
+}
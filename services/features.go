@@ -0,0 +1,218 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"github.com/coinbase/rosetta-ethereum/configuration"
+	"github.com/coinbase/rosetta-ethereum/optimism"
+)
+
+// FeaturesKey is the NetworkOptions version metadata key holding
+// whether each optional capability is enabled in this deployment.
+const FeaturesKey = "features"
+
+// feature is an optional capability listed under FeaturesKey.
+type feature struct {
+	name string
+
+	// fields are the Configuration fields that control the
+	// feature. Features without fields are fixed.
+	fields []string
+
+	enabled func(cfg *configuration.Configuration) bool
+}
+
+func fixed(enabled bool) func(*configuration.Configuration) bool {
+	return func(*configuration.Configuration) bool {
+		return enabled
+	}
+}
+
+// features lists every optional capability. Each Configuration field
+// must either control a feature or be listed in tuningFields.
+var features = []feature{
+	{
+		name:    "online",
+		fields:  []string{"Mode"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.Mode == configuration.Online },
+	},
+	{
+		name:    "graphql",
+		fields:  []string{"DisableGraphQL"},
+		enabled: func(cfg *configuration.Configuration) bool { return !cfg.DisableGraphQL },
+	},
+	{
+		name:    "trace_cache",
+		fields:  []string{"EnableTraceCache"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.EnableTraceCache },
+	},
+	{
+		name:    "geth_tracer",
+		fields:  []string{"EnableGethTracer"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.EnableGethTracer },
+	},
+	{
+		name:    "native_tracer",
+		fields:  []string{"EnableNativeTracer", "NativeTracerConfig"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.EnableNativeTracer },
+	},
+	{
+		name:    "index_all_tokens",
+		fields:  []string{"IndexAllTokens"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.IndexAllTokens },
+	},
+	{
+		name:    "lenient_token_balances",
+		fields:  []string{"LenientTokenBalances"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.LenientTokenBalances },
+	},
+	{
+		name:    "legacy_balance_metadata",
+		fields:  []string{"LegacyBalanceMetadata"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.LegacyBalanceMetadata },
+	},
+	{
+		name:    "legacy_tx_metadata",
+		fields:  []string{"LegacyTxMetadata"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.LegacyTxMetadata },
+	},
+	{
+		name:    "clique_sealer",
+		fields:  []string{"EnableCliqueSealer"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.EnableCliqueSealer },
+	},
+	{
+		name:    "fee_recipient_overrides",
+		fields:  []string{"FeeRecipientOverrides"},
+		enabled: func(cfg *configuration.Configuration) bool { return len(cfg.FeeRecipientOverrides) > 0 },
+	},
+	{
+		name:    "missing_receipt_overrides",
+		fields:  []string{"MissingReceiptOverrides", "OmitMissingReceiptFee"},
+		enabled: func(cfg *configuration.Configuration) bool { return len(cfg.MissingReceiptOverrides) > 0 },
+	},
+	{
+		name:   "bloom_check",
+		fields: []string{"BloomCheck"},
+		enabled: func(cfg *configuration.Configuration) bool {
+			return cfg.BloomCheck != optimism.BloomCheckDisabled
+		},
+	},
+	{
+		name:    "block_receipts",
+		fields:  []string{"PreferBlockReceipts"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.PreferBlockReceipts },
+	},
+	{
+		name:    "rate_limit",
+		fields:  []string{"RateLimit", "RateLimitBurst"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.RateLimit > 0 },
+	},
+	{
+		name:    "circuit_breaker",
+		fields:  []string{"CircuitBreakerThreshold", "CircuitBreakerCooldown"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.CircuitBreakerThreshold > 0 },
+	},
+	{
+		name:    "http_headers",
+		fields:  []string{"HTTPHeaders"},
+		enabled: func(cfg *configuration.Configuration) bool { return len(cfg.HTTPHeaders) > 0 },
+	},
+	{
+		name:    "block_prefetch",
+		fields:  []string{"PrefetchBlocks"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.PrefetchBlocks > 0 },
+	},
+	{
+		name:    "sync_guard",
+		fields:  []string{"MaxSyncLag"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.MaxSyncLag > 0 },
+	},
+	{
+		name:    "custom_chain_config",
+		fields:  []string{"ChainConfigJSON"},
+		enabled: func(cfg *configuration.Configuration) bool { return len(cfg.ChainConfigJSON) > 0 },
+	},
+	{
+		name:    "oldest_block_probe",
+		fields:  []string{"OldestBlockProbeCalls"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.OldestBlockProbeCalls > 0 },
+	},
+	{
+		name:    "chain_id_check",
+		fields:  []string{"ChainIDCheckInterval"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.ChainIDCheckInterval > 0 },
+	},
+	{
+		name:    "trace_depth_limit",
+		fields:  []string{"MaxTraceDepth"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.MaxTraceDepth > 0 },
+	},
+	{
+		name:    "tip_source",
+		fields:  []string{"TipSourceURL", "TipSourceTolerance"},
+		enabled: func(cfg *configuration.Configuration) bool { return len(cfg.TipSourceURL) > 0 },
+	},
+	{
+		name:    "tracing",
+		fields:  []string{"DisableTracing"},
+		enabled: func(cfg *configuration.Configuration) bool { return !cfg.DisableTracing },
+	},
+	{
+		name:    "skip_degraded_traces",
+		fields:  []string{"SkipDegradedTraces"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.SkipDegradedTraces },
+	},
+	{
+		name:    "transaction_chain_id_check",
+		fields:  []string{"CheckTransactionChainID"},
+		enabled: func(cfg *configuration.Configuration) bool { return cfg.CheckTransactionChainID },
+	},
+	{
+		name:    "peers",
+		fields:  []string{"SkipGethAdmin"},
+		enabled: func(cfg *configuration.Configuration) bool { return !cfg.SkipGethAdmin },
+	},
+	{name: "mempool", enabled: fixed(false)},
+	{name: "search", enabled: fixed(false)},
+	{name: "block_transaction", enabled: fixed(false)},
+}
+
+// tuningFields are the Configuration fields that identify the
+// network or tune the implementation without enabling a capability.
+var tuningFields = []string{
+	"Network",
+	"GenesisBlockIdentifier",
+	"GethURL",
+	"RemoteGeth",
+	"Port",
+	"GethArguments",
+	"L2GethHTTPTimeout",
+	"MaxConcurrentTraces",
+	"CurrencyCacheSize",
+	"FeeVaultHeight",
+	"NodeDialect",
+	"Params",
+}
+
+// featureMatrix returns whether each feature is enabled in cfg.
+func featureMatrix(cfg *configuration.Configuration) map[string]interface{} {
+	matrix := make(map[string]interface{}, len(features))
+	for _, f := range features {
+		matrix[f.name] = f.enabled(cfg)
+	}
+
+	return matrix
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coinbase/rosetta-ethereum/configuration"
+	"github.com/coinbase/rosetta-ethereum/optimism"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFeatures_CoverConfiguration fails when a Configuration field is
+// added without deciding whether it controls a feature.
+func TestFeatures_CoverConfiguration(t *testing.T) {
+	covered := map[string]bool{}
+	for _, field := range tuningFields {
+		covered[field] = true
+	}
+	names := map[string]bool{}
+	for _, f := range features {
+		assert.False(t, names[f.name], "duplicate feature %s", f.name)
+		names[f.name] = true
+
+		for _, field := range f.fields {
+			assert.False(t, covered[field], "%s is listed more than once", field)
+			covered[field] = true
+		}
+	}
+
+	cfgType := reflect.TypeOf(configuration.Configuration{})
+	for i := 0; i < cfgType.NumField(); i++ {
+		name := cfgType.Field(i).Name
+		assert.True(
+			t,
+			covered[name],
+			"Configuration.%s is neither a feature nor in tuningFields",
+			name,
+		)
+		delete(covered, name)
+	}
+	assert.Empty(t, covered, "fields that are not in Configuration")
+}
+
+func TestFeatureMatrix(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:                    configuration.Online,
+		EnableTraceCache:        true,
+		IndexAllTokens:          true,
+		MissingReceiptOverrides: []optimism.MissingReceiptOverride{{}},
+		BloomCheck:              optimism.BloomCheckDisabled,
+		RateLimit:               10,
+		HTTPHeaders:             map[string]string{"X-Api-Key": "secret-key"},
+		MaxSyncLag:              configuration.DefaultMaxSyncLag,
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"online":                     true,
+		"graphql":                    true,
+		"trace_cache":                true,
+		"geth_tracer":                false,
+		"native_tracer":              false,
+		"index_all_tokens":           true,
+		"lenient_token_balances":     false,
+		"legacy_balance_metadata":    false,
+		"legacy_tx_metadata":         false,
+		"clique_sealer":              false,
+		"fee_recipient_overrides":    false,
+		"missing_receipt_overrides":  true,
+		"bloom_check":                false,
+		"block_receipts":             false,
+		"rate_limit":                 true,
+		"circuit_breaker":            false,
+		"http_headers":               true,
+		"block_prefetch":             false,
+		"sync_guard":                 true,
+		"custom_chain_config":        false,
+		"oldest_block_probe":         false,
+		"chain_id_check":             false,
+		"trace_depth_limit":          false,
+		"tip_source":                 false,
+		"skip_degraded_traces":       false,
+		"tracing":                    true,
+		"transaction_chain_id_check": false,
+		"peers":                      true,
+		"mempool":                    false,
+		"search":                     false,
+		"block_transaction":          false,
+	}, featureMatrix(cfg))
+}
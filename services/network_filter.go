@@ -0,0 +1,80 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/coinbase/rosetta-sdk-go/server"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// SupportedNetworksKey is the ErrNetworkNotSupported details key
+// listing the supported networks.
+const SupportedNetworksKey = "supported_networks"
+
+// NetworkFilter rejects requests whose network identifier is not in
+// networks with ErrNetworkNotSupported, before they reach next.
+// Requests without a network identifier, such as /network/list, are
+// always passed on.
+func NetworkFilter(networks []*types.NetworkIdentifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			server.EncodeJSONResponse(wrapErr(ErrBadRequest, err), http.StatusInternalServerError, w)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		// Malformed requests are left to the asserter to reject
+		var request struct {
+			NetworkIdentifier *types.NetworkIdentifier `json:"network_identifier"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil || request.NetworkIdentifier == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !supportedNetwork(request.NetworkIdentifier, networks) {
+			rErr := wrapErr(ErrNetworkNotSupported, nil)
+			rErr.Details = map[string]interface{}{
+				SupportedNetworksKey: networks,
+			}
+			server.EncodeJSONResponse(rErr, http.StatusInternalServerError, w)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func supportedNetwork(network *types.NetworkIdentifier, networks []*types.NetworkIdentifier) bool {
+	for _, supported := range networks {
+		if types.Hash(network) == types.Hash(supported) {
+			return true
+		}
+	}
+
+	return false
+}
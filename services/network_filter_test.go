@@ -0,0 +1,112 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/coinbase/rosetta-ethereum/optimism"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkFilter(t *testing.T) {
+	mainnet := &types.NetworkIdentifier{
+		Blockchain: optimism.Blockchain,
+		Network:    optimism.MainnetNetwork,
+	}
+	verifier := &types.NetworkIdentifier{
+		Blockchain: optimism.Blockchain,
+		Network:    optimism.MainnetNetwork,
+		SubNetworkIdentifier: &types.SubNetworkIdentifier{
+			Network: "verifier",
+		},
+	}
+	networks := []*types.NetworkIdentifier{mainnet, verifier}
+
+	tests := map[string]struct {
+		body string
+
+		expectedServed bool
+	}{
+		"supported network": {
+			body:           `{"network_identifier": {"blockchain": "Optimism", "network": "Mainnet"}}`,
+			expectedServed: true,
+		},
+		"supported sub-network": {
+			body: `{"network_identifier": {"blockchain": "Optimism", "network": "Mainnet",
+				"sub_network_identifier": {"network": "verifier"}}}`,
+			expectedServed: true,
+		},
+		"no network": {
+			body:           `{"metadata": {}}`,
+			expectedServed: true,
+		},
+		"malformed request": {
+			body:           `{"network_identifier": `,
+			expectedServed: true,
+		},
+		"other network": {
+			body: `{"network_identifier": {"blockchain": "Optimism", "network": "Testnet"}}`,
+		},
+		"other sub-network": {
+			body: `{"network_identifier": {"blockchain": "Optimism", "network": "Mainnet",
+				"sub_network_identifier": {"network": "sequencer"}}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var served string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				served = string(body)
+			})
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/block", strings.NewReader(test.body))
+			NetworkFilter(networks, next).ServeHTTP(recorder, request)
+
+			if test.expectedServed {
+				// The body is still readable by the handler
+				assert.Equal(t, test.body, served)
+				return
+			}
+
+			assert.Empty(t, served)
+			assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+			var rErr types.Error
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rErr))
+			assert.Equal(t, ErrNetworkNotSupported.Code, rErr.Code)
+			assert.Equal(t, ErrNetworkNotSupported.Message, rErr.Message)
+
+			var supported struct {
+				Networks []*types.NetworkIdentifier `json:"supported_networks"`
+			}
+			details, err := json.Marshal(rErr.Details)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(details, &supported))
+			assert.Equal(t, networks, supported.Networks)
+		})
+	}
+}
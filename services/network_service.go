@@ -16,6 +16,7 @@ package services
 
 import (
 	"context"
+	"log"
 
 	"github.com/coinbase/rosetta-ethereum/configuration"
 	"github.com/coinbase/rosetta-ethereum/optimism"
@@ -41,26 +42,84 @@ func NewNetworkAPIService(
 	}
 }
 
-// NetworkList implements the /network/list endpoint
+// NetworkList implements the /network/list endpoint. In online mode,
+// the network is the one the node reports being on.
 func (s *NetworkAPIService) NetworkList(
 	ctx context.Context,
 	request *types.MetadataRequest,
 ) (*types.NetworkListResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return &types.NetworkListResponse{
+			NetworkIdentifiers: []*types.NetworkIdentifier{s.config.Network},
+		}, nil
+	}
+
+	networks, err := s.client.NetworkList(ctx)
+	if err != nil {
+		return nil, wrapNodeErr(err)
+	}
+
 	return &types.NetworkListResponse{
-		NetworkIdentifiers: []*types.NetworkIdentifier{s.config.Network},
+		NetworkIdentifiers: networks,
 	}, nil
 }
 
+// ChainIDKey is the NetworkOptions version metadata key holding the
+// chain id transactions are signed for. In online mode, it is the
+// chain id detected from the node.
+const ChainIDKey = "chain_id"
+
+// networkChainID returns the decimal chain id of cfg, or nil if
+// it has no chain config.
+func networkChainID(cfg *configuration.Configuration) interface{} {
+	if cfg.Params == nil || cfg.Params.ChainID == nil {
+		return nil
+	}
+
+	return cfg.Params.ChainID.String()
+}
+
+const (
+	// ClientVersionKey is the NetworkOptions version metadata key
+	// holding the web3_clientVersion of the node, in online mode.
+	ClientVersionKey = "client_version"
+
+	// RPCModulesKey is the NetworkOptions version metadata key holding
+	// the RPC modules served by the node, if it reports them.
+	RPCModulesKey = "rpc_modules"
+)
+
 // NetworkOptions implements the /network/options endpoint.
 func (s *NetworkAPIService) NetworkOptions(
 	ctx context.Context,
 	request *types.NetworkRequest,
 ) (*types.NetworkOptionsResponse, *types.Error) {
+	metadata := map[string]interface{}{
+		"operation_id_scheme": optimism.OperationIDScheme,
+		ChainIDKey:            networkChainID(s.config),
+		FeaturesKey:           featureMatrix(s.config),
+	}
+
+	// The options are still served if the node can't be reached,
+	// only without its version.
+	if s.config.Mode == configuration.Online {
+		info, err := s.client.ClientVersion(ctx)
+		if err != nil {
+			log.Printf("unable to get node client version: %v", err)
+		} else {
+			metadata[ClientVersionKey] = info.Client
+			if info.Modules != nil {
+				metadata[RPCModulesKey] = info.Modules
+			}
+		}
+	}
+
 	return &types.NetworkOptionsResponse{
 		Version: &types.Version{
 			NodeVersion:       optimism.NodeVersion,
 			RosettaVersion:    types.RosettaAPIVersion,
 			MiddlewareVersion: types.String(configuration.MiddlewareVersion),
+			Metadata:          metadata,
 		},
 		Allow: &types.Allow{
 			Errors:                  Errors,
@@ -81,20 +140,14 @@ func (s *NetworkAPIService) NetworkStatus(
 		return nil, ErrUnavailableOffline
 	}
 
-	currentBlock, currentTime, syncStatus, peers, err := s.client.Status(ctx)
+	networkStatus, err := s.client.NetworkStatus(ctx)
 	if err != nil {
-		return nil, wrapErr(ErrGeth, err)
+		return nil, wrapNodeErr(err)
 	}
 
-	if currentTime < asserter.MinUnixEpoch {
+	if networkStatus.CurrentBlockTimestamp < asserter.MinUnixEpoch {
 		return nil, ErrGethNotReady
 	}
 
-	return &types.NetworkStatusResponse{
-		CurrentBlockIdentifier: currentBlock,
-		CurrentBlockTimestamp:  currentTime,
-		GenesisBlockIdentifier: s.config.GenesisBlockIdentifier,
-		SyncStatus:             syncStatus,
-		Peers:                  peers,
-	}, nil
+	return networkStatus, nil
 }
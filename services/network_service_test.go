@@ -16,6 +16,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/coinbase/rosetta-ethereum/configuration"
@@ -27,12 +28,25 @@ import (
 )
 
 var (
-	middlewareVersion     = "0.0.4"
-	defaultNetworkOptions = &types.NetworkOptionsResponse{
+	middlewareVersion = "0.0.4"
+
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    optimism.MainnetNetwork,
+		Blockchain: optimism.Blockchain,
+	}
+)
+
+func defaultNetworkOptions(cfg *configuration.Configuration) *types.NetworkOptionsResponse {
+	return &types.NetworkOptionsResponse{
 		Version: &types.Version{
 			RosettaVersion:    types.RosettaAPIVersion,
 			NodeVersion:       "1.9.24",
 			MiddlewareVersion: &middlewareVersion,
+			Metadata: map[string]interface{}{
+				"operation_id_scheme": optimism.OperationIDScheme,
+				ChainIDKey:            networkChainID(cfg),
+				FeaturesKey:           featureMatrix(cfg),
+			},
 		},
 		Allow: &types.Allow{
 			OperationStatuses:       optimism.OperationStatuses,
@@ -42,12 +56,7 @@ var (
 			CallMethods:             optimism.CallMethods,
 		},
 	}
-
-	networkIdentifier = &types.NetworkIdentifier{
-		Network:    optimism.MainnetNetwork,
-		Blockchain: optimism.Blockchain,
-	}
-)
+}
 
 func TestNetworkEndpoints_Offline(t *testing.T) {
 	cfg := &configuration.Configuration{
@@ -71,25 +80,37 @@ func TestNetworkEndpoints_Offline(t *testing.T) {
 
 	networkOptions, err := servicer.NetworkOptions(ctx, nil)
 	assert.Nil(t, err)
-	assert.Equal(t, defaultNetworkOptions, networkOptions)
+	assert.Equal(t, defaultNetworkOptions(cfg), networkOptions)
+	assert.Nil(t, networkOptions.Version.Metadata[ChainIDKey])
+	assert.Equal(t, false, networkOptions.Version.Metadata[FeaturesKey].(map[string]interface{})["online"])
 
 	mockClient.AssertExpectations(t)
 }
 
 func TestNetworkEndpoints_Online(t *testing.T) {
+	params, _ := optimism.ChainConfigForID(10)
 	cfg := &configuration.Configuration{
 		Mode:                   configuration.Online,
 		Network:                networkIdentifier,
 		GenesisBlockIdentifier: optimism.MainnetGenesisBlockIdentifier,
+		Params:                 params,
 	}
 	mockClient := &mocks.Client{}
 	servicer := NewNetworkAPIService(cfg, mockClient)
 	ctx := context.Background()
 
+	verifier := &types.NetworkIdentifier{
+		Blockchain: optimism.Blockchain,
+		Network:    optimism.MainnetNetwork,
+		SubNetworkIdentifier: &types.SubNetworkIdentifier{
+			Network: "verifier",
+		},
+	}
+	mockClient.On("NetworkList", ctx).Return([]*types.NetworkIdentifier{verifier}, nil).Once()
 	networkList, err := servicer.NetworkList(ctx, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, []*types.NetworkIdentifier{
-		networkIdentifier,
+		verifier,
 	}, networkList.NetworkIdentifiers)
 
 	currentBlock := &types.BlockIdentifier{
@@ -109,29 +130,125 @@ func TestNetworkEndpoints_Online(t *testing.T) {
 		},
 	}
 
+	expectedStatus := &types.NetworkStatusResponse{
+		GenesisBlockIdentifier: optimism.MainnetGenesisBlockIdentifier,
+		CurrentBlockIdentifier: currentBlock,
+		CurrentBlockTimestamp:  currentTime,
+		Peers:                  peers,
+		SyncStatus:             syncStatus,
+	}
 	mockClient.On(
-		"Status",
+		"NetworkStatus",
 		ctx,
 	).Return(
-		currentBlock,
-		currentTime,
-		syncStatus,
-		peers,
+		expectedStatus,
 		nil,
 	)
 	networkStatus, err := servicer.NetworkStatus(ctx, nil)
 	assert.Nil(t, err)
-	assert.Equal(t, &types.NetworkStatusResponse{
-		GenesisBlockIdentifier: optimism.MainnetGenesisBlockIdentifier,
-		CurrentBlockIdentifier: currentBlock,
-		CurrentBlockTimestamp:  currentTime,
-		Peers:                  peers,
-		SyncStatus:             syncStatus,
-	}, networkStatus)
+	assert.Equal(t, expectedStatus, networkStatus)
 
+	modules := map[string]string{"eth": "1.0", "rollup": "1.0"}
+	mockClient.On("ClientVersion", ctx).Return(&optimism.ClientVersionInfo{
+		Client:  "Geth/v0.5.11-stable-4b5b5c9e/linux-amd64/go1.15.15",
+		Modules: modules,
+	}, nil).Once()
 	networkOptions, err := servicer.NetworkOptions(ctx, nil)
 	assert.Nil(t, err)
-	assert.Equal(t, defaultNetworkOptions, networkOptions)
+	expectedOptions := defaultNetworkOptions(cfg)
+	expectedOptions.Version.Metadata[ClientVersionKey] = "Geth/v0.5.11-stable-4b5b5c9e/linux-amd64/go1.15.15"
+	expectedOptions.Version.Metadata[RPCModulesKey] = modules
+	assert.Equal(t, expectedOptions, networkOptions)
+	assert.Equal(t, "10", networkOptions.Version.Metadata[ChainIDKey])
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestNetworkOptions_ClientVersion(t *testing.T) {
+	tests := map[string]struct {
+		info *optimism.ClientVersionInfo
+		err  error
+
+		expectedMetadata map[string]interface{}
+	}{
+		"without rpc modules": {
+			info: &optimism.ClientVersionInfo{Client: "Geth/v1.101106.0-stable/linux-amd64/go1.20.7"},
+			expectedMetadata: map[string]interface{}{
+				ClientVersionKey: "Geth/v1.101106.0-stable/linux-amd64/go1.20.7",
+			},
+		},
+		"node unavailable": {
+			err:              optimism.ErrNodeUnavailable,
+			expectedMetadata: map[string]interface{}{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &configuration.Configuration{
+				Mode:    configuration.Online,
+				Network: networkIdentifier,
+			}
+			mockClient := &mocks.Client{}
+			servicer := NewNetworkAPIService(cfg, mockClient)
+			ctx := context.Background()
+			mockClient.On("ClientVersion", ctx).Return(test.info, test.err).Once()
+
+			networkOptions, err := servicer.NetworkOptions(ctx, nil)
+			assert.Nil(t, err)
+			expected := defaultNetworkOptions(cfg)
+			for k, v := range test.expectedMetadata {
+				expected.Version.Metadata[k] = v
+			}
+			assert.Equal(t, expected, networkOptions)
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNetworkStatus_NotReady(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:    configuration.Online,
+		Network: networkIdentifier,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewNetworkAPIService(cfg, mockClient)
+	ctx := context.Background()
+
+	mockClient.On("NetworkStatus", ctx).Return(
+		&types.NetworkStatusResponse{
+			CurrentBlockIdentifier: &types.BlockIdentifier{Index: 0, Hash: "block 0"},
+			CurrentBlockTimestamp:  0,
+			GenesisBlockIdentifier: &types.BlockIdentifier{Index: 0, Hash: "block 0"},
+		},
+		nil,
+	)
+
+	networkStatus, err := servicer.NetworkStatus(ctx, nil)
+	assert.Nil(t, networkStatus)
+	assert.Equal(t, ErrGethNotReady, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestNetworkStatus_NodeError(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:    configuration.Online,
+		Network: networkIdentifier,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewNetworkAPIService(cfg, mockClient)
+	ctx := context.Background()
+
+	mockClient.On("NetworkStatus", ctx).Return(
+		nil,
+		&optimism.RPCError{Kind: optimism.ErrRequestTimeout, Err: errors.New("timeout")},
+	)
+
+	networkStatus, err := servicer.NetworkStatus(ctx, nil)
+	assert.Nil(t, networkStatus)
+	assert.Equal(t, ErrRequestTimeout.Code, err.Code)
 
 	mockClient.AssertExpectations(t)
 }
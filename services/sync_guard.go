@@ -0,0 +1,91 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// syncStatusTTL is how long the sync status of the
+// node is reused before it is fetched again.
+const syncStatusTTL = 5 * time.Second
+
+// syncGuard refuses requests about the current state of the chain,
+// such as the latest balance of an account, while the node is more
+// than maxLag blocks behind its tip, as it would answer them with
+// stale data. Requests at a given block are not affected.
+type syncGuard struct {
+	client Client
+	maxLag int64
+
+	mu        sync.Mutex
+	lag       int64
+	checkedAt time.Time
+}
+
+func newSyncGuard(client Client, maxLag int64) *syncGuard {
+	return &syncGuard{
+		client: client,
+		maxLag: maxLag,
+	}
+}
+
+// check returns ErrNodeSyncing if the node is too far behind its
+// tip. A maxLag of 0 disables the check.
+func (g *syncGuard) check(ctx context.Context) *types.Error {
+	if g.maxLag <= 0 {
+		return nil
+	}
+
+	lag, err := g.currentLag(ctx)
+	if err != nil {
+		return wrapNodeErr(err)
+	}
+	if lag > g.maxLag {
+		return wrapErr(ErrNodeSyncing, fmt.Errorf("node is %d blocks behind its tip", lag))
+	}
+
+	return nil
+}
+
+// currentLag returns how many blocks the node is behind its
+// tip, fetching its sync status at most once per syncStatusTTL.
+func (g *syncGuard) currentLag(ctx context.Context) (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.checkedAt.IsZero() && time.Since(g.checkedAt) < syncStatusTTL {
+		return g.lag, nil
+	}
+
+	_, _, syncStatus, _, err := g.client.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	g.lag = 0
+	if syncStatus != nil && syncStatus.Synced != nil && !*syncStatus.Synced &&
+		syncStatus.CurrentIndex != nil && syncStatus.TargetIndex != nil {
+		g.lag = *syncStatus.TargetIndex - *syncStatus.CurrentIndex
+	}
+	g.checkedAt = time.Now()
+
+	return g.lag, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2022 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/coinbase/rosetta-ethereum/configuration"
+	mocks "github.com/coinbase/rosetta-ethereum/mocks/services"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockStatus(ctx context.Context, mockClient *mocks.Client, syncStatus *types.SyncStatus) {
+	mockClient.On("Status", ctx).Return(
+		&types.BlockIdentifier{Index: 25, Hash: "block 25"},
+		int64(1603225195000),
+		syncStatus,
+		[]*types.Peer(nil),
+		nil,
+	).Once()
+}
+
+func TestSyncGuard(t *testing.T) {
+	ctx := context.Background()
+	tests := map[string]struct {
+		syncStatus *types.SyncStatus
+
+		expectedErr *types.Error
+	}{
+		"synced": {
+			syncStatus: &types.SyncStatus{
+				CurrentIndex: types.Int64(8916760),
+				TargetIndex:  types.Int64(8916760),
+				Synced:       types.Bool(true),
+			},
+		},
+		"slightly behind": {
+			syncStatus: &types.SyncStatus{
+				CurrentIndex: types.Int64(8916750),
+				TargetIndex:  types.Int64(8916760),
+				Synced:       types.Bool(false),
+			},
+		},
+		"far behind": {
+			syncStatus: &types.SyncStatus{
+				CurrentIndex: types.Int64(25),
+				TargetIndex:  types.Int64(8916760),
+				Synced:       types.Bool(false),
+			},
+			expectedErr: ErrNodeSyncing,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockClient := &mocks.Client{}
+			g := newSyncGuard(mockClient, 100)
+			mockStatus(ctx, mockClient, test.syncStatus)
+
+			// The status is fetched once and reused
+			for i := 0; i < 2; i++ {
+				err := g.check(ctx)
+				if test.expectedErr == nil {
+					assert.Nil(t, err)
+					continue
+				}
+
+				assert.Equal(t, test.expectedErr.Code, err.Code)
+				assert.Equal(t, test.expectedErr.Retriable, err.Retriable)
+				assert.Equal(t, "node is 8916735 blocks behind its tip", err.Details["context"])
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSyncGuard_Disabled(t *testing.T) {
+	mockClient := &mocks.Client{}
+	g := newSyncGuard(mockClient, 0)
+
+	assert.Nil(t, g.check(context.Background()))
+	mockClient.AssertExpectations(t)
+}
+
+func TestSyncGuard_StatusError(t *testing.T) {
+	ctx := context.Background()
+	mockClient := &mocks.Client{}
+	g := newSyncGuard(mockClient, 100)
+
+	mockClient.On("Status", ctx).Return(
+		nil,
+		int64(-1),
+		nil,
+		nil,
+		errors.New("unclassified"),
+	).Once()
+
+	err := g.check(ctx)
+	assert.Equal(t, ErrGeth.Code, err.Code)
+	mockClient.AssertExpectations(t)
+}
+
+func TestAccountBalance_Syncing(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:       configuration.Online,
+		MaxSyncLag: 100,
+	}
+	mockClient := &mocks.Client{}
+	servicer := NewAccountAPIService(cfg, mockClient)
+	ctx := context.Background()
+
+	mockStatus(ctx, mockClient, &types.SyncStatus{
+		CurrentIndex: types.Int64(25),
+		TargetIndex:  types.Int64(8916760),
+		Synced:       types.Bool(false),
+	})
+
+	// The current balance is refused while far behind
+	account := &types.AccountIdentifier{Address: "0x2f93B2f047E05cdf602820Ac4B3178efc2b43D55"}
+	resp, err := servicer.AccountBalance(ctx, &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+	})
+	assert.Nil(t, resp)
+	assert.Equal(t, ErrNodeSyncing.Code, err.Code)
+
+	// Historical balances are still served
+	blockIdentifier := &types.PartialBlockIdentifier{Index: types.Int64(10)}
+	expected := &types.AccountBalanceResponse{
+		BlockIdentifier: &types.BlockIdentifier{Index: 10, Hash: "block 10"},
+	}
+	mockClient.On("Balance", ctx, account, blockIdentifier, []*types.Currency(nil)).Return(expected, nil).Once()
+	resp, err = servicer.AccountBalance(ctx, &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+		BlockIdentifier:   blockIdentifier,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, expected, resp)
+
+	mockClient.AssertExpectations(t)
+}
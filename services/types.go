@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"math/big"
 
+	"github.com/coinbase/rosetta-ethereum/optimism"
+
 	"github.com/coinbase/rosetta-sdk-go/types"
 	ethereum "github.com/ethereum-optimism/optimism/l2geth"
 	"github.com/ethereum-optimism/optimism/l2geth/common"
@@ -37,6 +39,12 @@ type Client interface {
 		error,
 	)
 
+	NetworkStatus(context.Context) (*types.NetworkStatusResponse, error)
+
+	NetworkList(context.Context) ([]*types.NetworkIdentifier, error)
+
+	ClientVersion(context.Context) (*optimism.ClientVersionInfo, error)
+
 	Block(
 		context.Context,
 		*types.PartialBlockIdentifier,
@@ -55,7 +63,7 @@ type Client interface {
 
 	SuggestGasPrice(ctx context.Context) (*big.Int, error)
 
-	SendTransaction(ctx context.Context, tx *ethTypes.Transaction) error
+	SendTransactionWithHash(ctx context.Context, tx *ethTypes.Transaction) (common.Hash, error)
 
 	Call(
 		ctx context.Context,